@@ -29,6 +29,8 @@ var (
 	ScopeProvisionersPlugins       = ac.Scope("provisioners", "plugins")
 	ScopeProvisionersDatasources   = ac.Scope("provisioners", "datasources")
 	ScopeProvisionersNotifications = ac.Scope("provisioners", "notifications")
+	ScopeProvisionersPlaylists     = ac.Scope("provisioners", "playlists")
+	ScopeProvisionersIdentities    = ac.Scope("provisioners", "identities")
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
@@ -39,3 +39,19 @@ func (hs *HTTPServer) AdminProvisioningReloadNotifications(c *models.ReqContext)
 	}
 	return response.Success("Notifications config reloaded")
 }
+
+func (hs *HTTPServer) AdminProvisioningReloadPlaylists(c *models.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionPlaylists(c.Req.Context())
+	if err != nil {
+		return response.Error(500, "Failed to reload playlists config", err)
+	}
+	return response.Success("Playlists config reloaded")
+}
+
+func (hs *HTTPServer) AdminProvisioningReloadIdentities(c *models.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionIdentities(c.Req.Context())
+	if err != nil {
+		return response.Error(500, "Failed to reload identities config", err)
+	}
+	return response.Success("Identities config reloaded")
+}
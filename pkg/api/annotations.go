@@ -19,6 +19,51 @@ import (
 )
 
 func (hs *HTTPServer) GetAnnotations(c *models.ReqContext) response.Response {
+	query, err := itemQueryFromRequest(c)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid tag matcher", err)
+	}
+
+	repo := annotations.GetRepository()
+
+	items, err := repo.Find(c.Req.Context(), query)
+	if err != nil {
+		return response.Error(500, "Failed to get annotations", err)
+	}
+
+	for _, item := range items {
+		if item.Email != "" {
+			item.AvatarUrl = dtos.GetGravatarUrl(item.Email)
+		}
+	}
+
+	return response.JSON(200, items)
+}
+
+// GetAnnotationCounts returns the number of annotations in each interval of the time range.
+// It accepts the same filters as GetAnnotations.
+func (hs *HTTPServer) GetAnnotationCounts(c *models.ReqContext) response.Response {
+	itemQuery, err := itemQueryFromRequest(c)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid tag matcher", err)
+	}
+	query := &annotations.CountQuery{
+		ItemQuery: *itemQuery,
+		Interval:  c.QueryInt64("interval"),
+	}
+
+	buckets, err := annotations.GetRepository().Count(c.Req.Context(), query)
+	if err != nil {
+		if errors.Is(err, annotations.ErrTimerangeMissing) || errors.Is(err, annotations.ErrInvalidInterval) {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+		return response.Error(500, "Failed to count annotations", err)
+	}
+
+	return response.JSON(200, buckets)
+}
+
+func itemQueryFromRequest(c *models.ReqContext) (*annotations.ItemQuery, error) {
 	query := &annotations.ItemQuery{
 		From:         c.QueryInt64("from"),
 		To:           c.QueryInt64("to"),
@@ -31,23 +76,18 @@ func (hs *HTTPServer) GetAnnotations(c *models.ReqContext) response.Response {
 		Tags:         c.QueryStrings("tags"),
 		Type:         c.Query("type"),
 		MatchAny:     c.QueryBool("matchAny"),
+		Text:         c.Query("text"),
 		SignedInUser: c.SignedInUser,
 	}
 
-	repo := annotations.GetRepository()
-
-	items, err := repo.Find(c.Req.Context(), query)
-	if err != nil {
-		return response.Error(500, "Failed to get annotations", err)
-	}
-
-	for _, item := range items {
-		if item.Email != "" {
-			item.AvatarUrl = dtos.GetGravatarUrl(item.Email)
+	for _, m := range c.QueryStrings("matcher") {
+		matcher, err := annotations.ParseTagMatcher(m)
+		if err != nil {
+			return nil, err
 		}
+		query.TagMatchers = append(query.TagMatchers, matcher)
 	}
-
-	return response.JSON(200, items)
+	return query, nil
 }
 
 type AnnotationError struct {
@@ -222,7 +262,7 @@ func (hs *HTTPServer) UpdateAnnotation(c *models.ReqContext) response.Response {
 	}
 
 	if err := repo.Update(c.Req.Context(), &item); err != nil {
-		return response.Error(500, "Failed to update annotation", err)
+		return annotationRepositoryErrorResponse(err, "Failed to update annotation")
 	}
 
 	return response.Success("Annotation updated")
@@ -284,7 +324,7 @@ func (hs *HTTPServer) PatchAnnotation(c *models.ReqContext) response.Response {
 	}
 
 	if err := repo.Update(c.Req.Context(), &existing); err != nil {
-		return response.Error(500, "Failed to update annotation", err)
+		return annotationRepositoryErrorResponse(err, "Failed to update annotation")
 	}
 
 	return response.Success("Annotation patched")
@@ -345,7 +385,7 @@ func (hs *HTTPServer) MassDeleteAnnotations(c *models.ReqContext) response.Respo
 	err = repo.Delete(c.Req.Context(), deleteParams)
 
 	if err != nil {
-		return response.Error(500, "Failed to delete annotations", err)
+		return annotationRepositoryErrorResponse(err, "Failed to delete annotations")
 	}
 
 	return response.Success("Annotations deleted")
@@ -382,12 +422,20 @@ func (hs *HTTPServer) DeleteAnnotationByID(c *models.ReqContext) response.Respon
 		Id:    annotationID,
 	})
 	if err != nil {
-		return response.Error(500, "Failed to delete annotation", err)
+		return annotationRepositoryErrorResponse(err, "Failed to delete annotation")
 	}
 
 	return response.Success("Annotation deleted")
 }
 
+// annotationRepositoryErrorResponse returns 405 when the configured annotation store can't change annotations.
+func annotationRepositoryErrorResponse(err error, message string) response.Response {
+	if errors.Is(err, annotations.ErrNotSupported) {
+		return response.Error(http.StatusMethodNotAllowed, "The configured annotation store does not support changing annotations", err)
+	}
+	return response.Error(500, message, err)
+}
+
 func canSaveDashboardAnnotation(c *models.ReqContext, dashboardID int64) (bool, error) {
 	guard := guardian.New(c.Req.Context(), dashboardID, c.OrgId, c.SignedInUser)
 	if canEdit, err := guard.CanEdit(); err != nil || !canEdit {
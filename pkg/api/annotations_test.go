@@ -236,6 +236,59 @@ func TestAnnotationsAPIEndpoint(t *testing.T) {
 	})
 }
 
+func TestAnnotationsAPIEndpoint_NotSupported(t *testing.T) {
+	hs := setupSimpleHTTPServer(nil)
+	store := sqlstore.InitTestDB(t)
+	store.Cfg = hs.Cfg
+	hs.SQLStore = store
+
+	role := models.ROLE_ADMIN
+	updateCmd := dtos.UpdateAnnotationsCmd{Time: 1000, Text: "annotation text"}
+	patchCmd := dtos.PatchAnnotationsCmd{Time: 1000, Text: "annotation text"}
+	deleteCmd := dtos.MassDeleteAnnotationsCmd{DashboardId: 1, PanelId: 1}
+
+	putAnnotationScenario(t, "When calling PUT on", "/api/annotations/1", "/api/annotations/:annotationId", role, updateCmd, func(sc *scenarioContext) {
+		annotations.SetRepository(&readOnlyAnnotationsRepo{fakeAnnoRepo})
+		sc.fakeReqWithParams("PUT", sc.url, map[string]string{}).exec()
+		assert.Equal(t, http.StatusMethodNotAllowed, sc.resp.Code)
+	})
+
+	patchAnnotationScenario(t, "When calling PATCH on", "/api/annotations/1", "/api/annotations/:annotationId", role, patchCmd, func(sc *scenarioContext) {
+		annotations.SetRepository(&readOnlyAnnotationsRepo{fakeAnnoRepo})
+		sc.fakeReqWithParams("PATCH", sc.url, map[string]string{}).exec()
+		assert.Equal(t, http.StatusMethodNotAllowed, sc.resp.Code)
+	})
+
+	deleteAnnotationsScenario(t, "When calling POST on", "/api/annotations/mass-delete", "/api/annotations/mass-delete", role, deleteCmd, func(sc *scenarioContext) {
+		setUpACL()
+		annotations.SetRepository(&readOnlyAnnotationsRepo{fakeAnnoRepo})
+		sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+		assert.Equal(t, http.StatusMethodNotAllowed, sc.resp.Code)
+	})
+
+	mock := mockstore.NewSQLStoreMock()
+	loggedInUserScenarioWithRole(t, "When calling DELETE on", "DELETE", "/api/annotations/1", "/api/annotations/:annotationId", role, func(sc *scenarioContext) {
+		fakeAnnoRepo = NewFakeAnnotationsRepo()
+		annotations.SetRepository(&readOnlyAnnotationsRepo{fakeAnnoRepo})
+		sc.handlerFunc = hs.DeleteAnnotationByID
+		sc.fakeReqWithParams("DELETE", sc.url, map[string]string{}).exec()
+		assert.Equal(t, http.StatusMethodNotAllowed, sc.resp.Code)
+	}, mock)
+}
+
+// readOnlyAnnotationsRepo behaves like an annotation store that can't change annotations.
+type readOnlyAnnotationsRepo struct {
+	*fakeAnnotationsRepo
+}
+
+func (repo *readOnlyAnnotationsRepo) Update(context.Context, *annotations.Item) error {
+	return annotations.ErrNotSupported
+}
+
+func (repo *readOnlyAnnotationsRepo) Delete(context.Context, *annotations.DeleteParams) error {
+	return annotations.ErrNotSupported
+}
+
 type fakeAnnotationsRepo struct {
 	annotations map[int64]annotations.Item
 }
@@ -276,6 +329,9 @@ func (repo *fakeAnnotationsRepo) Find(_ context.Context, query *annotations.Item
 	annotations := []*annotations.ItemDTO{{Id: 1, DashboardId: 0}}
 	return annotations, nil
 }
+func (repo *fakeAnnotationsRepo) Count(_ context.Context, query *annotations.CountQuery) ([]*annotations.Bucket, error) {
+	return []*annotations.Bucket{{Time: query.From, Count: int64(len(repo.annotations))}}, nil
+}
 func (repo *fakeAnnotationsRepo) FindTags(_ context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
 	result := annotations.FindTagsResult{
 		Tags: []*annotations.TagsDTO{},
@@ -483,6 +539,33 @@ func TestAPI_Annotations_AccessControl(t *testing.T) {
 			},
 			want: http.StatusForbidden,
 		},
+		{
+			name: "AccessControl getting annotations with an invalid tag matcher is a bad request",
+			args: args{
+				permissions: []*accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsRead, Scope: accesscontrol.ScopeAnnotationsAll}},
+				url:         "/api/annotations?matcher=server",
+				method:      http.MethodGet,
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "AccessControl getting annotation counts with correct permissions is allowed",
+			args: args{
+				permissions: []*accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsRead, Scope: accesscontrol.ScopeAnnotationsAll}},
+				url:         "/api/annotations/counts?from=1000&to=2000&interval=100&matcher=server%3D~server-.%2A",
+				method:      http.MethodGet,
+			},
+			want: http.StatusOK,
+		},
+		{
+			name: "AccessControl getting annotation counts without permissions is forbidden",
+			args: args{
+				permissions: []*accesscontrol.Permission{},
+				url:         "/api/annotations/counts?from=1000&to=2000&interval=100",
+				method:      http.MethodGet,
+			},
+			want: http.StatusForbidden,
+		},
 		{
 			name: "AccessControl getting tags for annotations with correct permissions is allowed",
 			args: args{
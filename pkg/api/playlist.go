@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -68,13 +69,41 @@ func (hs *HTTPServer) GetPlaylist(c *models.ReqContext) response.Response {
 		return response.Error(500, "Playlist not found", err)
 	}
 
-	playlistDTOs, _ := hs.LoadPlaylistItemDTOs(c.Req.Context(), id)
+	return hs.playlistResponse(c.Req.Context(), cmd.Result)
+}
+
+func (hs *HTTPServer) GetPlaylistByUID(c *models.ReqContext) response.Response {
+	playlist, rsp := hs.getPlaylistByUID(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	return hs.playlistResponse(c.Req.Context(), playlist)
+}
+
+// getPlaylistByUID returns the playlist of the organization with the UID of the route,
+// or the error response if it is not found.
+func (hs *HTTPServer) getPlaylistByUID(c *models.ReqContext) (*models.Playlist, response.Response) {
+	query := models.GetPlaylistByUidQuery{UID: web.Params(c.Req)[":uid"], OrgId: c.OrgId}
+	if err := hs.SQLStore.GetPlaylistByUid(c.Req.Context(), &query); err != nil {
+		if errors.Is(err, models.ErrPlaylistNotFound) {
+			return nil, response.Error(http.StatusNotFound, "Playlist not found", err)
+		}
+		return nil, response.Error(http.StatusInternalServerError, "Failed to get playlist", err)
+	}
+	return query.Result, nil
+}
+
+func (hs *HTTPServer) playlistResponse(ctx context.Context, playlist *models.Playlist) response.Response {
+	playlistDTOs, _ := hs.LoadPlaylistItemDTOs(ctx, playlist.Id)
 
 	dto := &models.PlaylistDTO{
-		Id:       cmd.Result.Id,
-		Name:     cmd.Result.Name,
-		Interval: cmd.Result.Interval,
-		OrgId:    cmd.Result.OrgId,
+		Id:       playlist.Id,
+		UID:      playlist.UID,
+		Name:     playlist.Name,
+		Interval: playlist.Interval,
+		OrgId:    playlist.OrgId,
+		ReadOnly: playlist.ReadOnly,
 		Items:    playlistDTOs,
 	}
 
@@ -119,7 +148,20 @@ func (hs *HTTPServer) GetPlaylistItems(c *models.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "id is invalid", err)
 	}
 
-	playlistDTOs, err := hs.LoadPlaylistItemDTOs(c.Req.Context(), id)
+	return hs.playlistItemsResponse(c.Req.Context(), id)
+}
+
+func (hs *HTTPServer) GetPlaylistItemsByUID(c *models.ReqContext) response.Response {
+	playlist, rsp := hs.getPlaylistByUID(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	return hs.playlistItemsResponse(c.Req.Context(), playlist.Id)
+}
+
+func (hs *HTTPServer) playlistItemsResponse(ctx context.Context, id int64) response.Response {
+	playlistDTOs, err := hs.LoadPlaylistItemDTOs(ctx, id)
 
 	if err != nil {
 		return response.Error(500, "Could not load playlist items", err)
@@ -134,6 +176,19 @@ func (hs *HTTPServer) GetPlaylistDashboards(c *models.ReqContext) response.Respo
 		return response.Error(http.StatusBadRequest, "id is invalid", err)
 	}
 
+	return hs.playlistDashboardsResponse(c, playlistID)
+}
+
+func (hs *HTTPServer) GetPlaylistDashboardsByUID(c *models.ReqContext) response.Response {
+	playlist, rsp := hs.getPlaylistByUID(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	return hs.playlistDashboardsResponse(c, playlist.Id)
+}
+
+func (hs *HTTPServer) playlistDashboardsResponse(c *models.ReqContext, playlistID int64) response.Response {
 	playlists, err := hs.LoadPlaylistDashboards(c.Req.Context(), c.OrgId, c.SignedInUser, playlistID)
 	if err != nil {
 		return response.Error(500, "Could not load dashboards", err)
@@ -148,7 +203,29 @@ func (hs *HTTPServer) DeletePlaylist(c *models.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "id is invalid", err)
 	}
 
-	cmd := models.DeletePlaylistCommand{Id: id, OrgId: c.OrgId}
+	query := models.GetPlaylistByIdQuery{Id: id}
+	if err := hs.SQLStore.GetPlaylist(c.Req.Context(), &query); err != nil {
+		return response.Error(500, "Failed to delete playlist", err)
+	}
+
+	return hs.deletePlaylist(c, query.Result)
+}
+
+func (hs *HTTPServer) DeletePlaylistByUID(c *models.ReqContext) response.Response {
+	playlist, rsp := hs.getPlaylistByUID(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	return hs.deletePlaylist(c, playlist)
+}
+
+func (hs *HTTPServer) deletePlaylist(c *models.ReqContext, playlist *models.Playlist) response.Response {
+	if playlist.ReadOnly {
+		return response.Error(http.StatusForbidden, "Cannot delete provisioned playlist", nil)
+	}
+
+	cmd := models.DeletePlaylistCommand{Id: playlist.Id, OrgId: c.OrgId}
 	if err := hs.SQLStore.DeletePlaylist(c.Req.Context(), &cmd); err != nil {
 		return response.Error(500, "Failed to delete playlist", err)
 	}
@@ -164,6 +241,12 @@ func (hs *HTTPServer) CreatePlaylist(c *models.ReqContext) response.Response {
 	cmd.OrgId = c.OrgId
 
 	if err := hs.SQLStore.CreatePlaylist(c.Req.Context(), &cmd); err != nil {
+		switch {
+		case errors.Is(err, models.ErrPlaylistWithSameUIDExists):
+			return response.Error(http.StatusConflict, err.Error(), err)
+		case errors.Is(err, models.ErrPlaylistInvalidUID), errors.Is(err, models.ErrPlaylistUIDTooLong):
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
 		return response.Error(500, "Failed to create playlist", err)
 	}
 
@@ -171,16 +254,39 @@ func (hs *HTTPServer) CreatePlaylist(c *models.ReqContext) response.Response {
 }
 
 func (hs *HTTPServer) UpdatePlaylist(c *models.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	query := models.GetPlaylistByIdQuery{Id: id}
+	if err := hs.SQLStore.GetPlaylist(c.Req.Context(), &query); err != nil {
+		return response.Error(500, "Failed to save playlist", err)
+	}
+
+	return hs.updatePlaylist(c, query.Result)
+}
+
+func (hs *HTTPServer) UpdatePlaylistByUID(c *models.ReqContext) response.Response {
+	playlist, rsp := hs.getPlaylistByUID(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	return hs.updatePlaylist(c, playlist)
+}
+
+func (hs *HTTPServer) updatePlaylist(c *models.ReqContext, playlist *models.Playlist) response.Response {
+	if playlist.ReadOnly {
+		return response.Error(http.StatusForbidden, "Cannot update provisioned playlist", nil)
+	}
+
 	cmd := models.UpdatePlaylistCommand{}
 	if err := web.Bind(c.Req, &cmd); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	cmd.OrgId = c.OrgId
-	var err error
-	cmd.Id, err = strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
-	if err != nil {
-		return response.Error(http.StatusBadRequest, "id is invalid", err)
-	}
+	cmd.Id = playlist.Id
 
 	if err := hs.SQLStore.UpdatePlaylist(c.Req.Context(), &cmd); err != nil {
 		return response.Error(500, "Failed to save playlist", err)
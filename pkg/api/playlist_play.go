@@ -35,6 +35,30 @@ func (hs *HTTPServer) populateDashboardsByID(ctx context.Context, dashboardByIDs
 	return result, nil
 }
 
+func (hs *HTTPServer) populateDashboardsByUID(ctx context.Context, orgID int64, dashboardByUIDs []string, dashboardUIDOrder map[string]int) (dtos.PlaylistDashboardsSlice, error) {
+	result := make(dtos.PlaylistDashboardsSlice, 0)
+
+	if len(dashboardByUIDs) > 0 {
+		dashboardQuery := models.GetDashboardsQuery{DashboardUIds: dashboardByUIDs, OrgId: orgID}
+		if err := hs.SQLStore.GetDashboards(ctx, &dashboardQuery); err != nil {
+			return result, err
+		}
+
+		for _, item := range dashboardQuery.Result {
+			result = append(result, dtos.PlaylistDashboard{
+				Id:    item.Id,
+				Slug:  item.Slug,
+				Title: item.Title,
+				Uri:   "db/" + item.Slug,
+				Url:   models.GetDashboardUrl(item.Uid, item.Slug),
+				Order: dashboardUIDOrder[item.Uid],
+			})
+		}
+	}
+
+	return result, nil
+}
+
 func (hs *HTTPServer) populateDashboardsByTag(ctx context.Context, orgID int64, signedInUser *models.SignedInUser, dashboardByTag []string, dashboardTagOrder map[string]int) dtos.PlaylistDashboardsSlice {
 	result := make(dtos.PlaylistDashboardsSlice, 0)
 
@@ -69,18 +93,25 @@ func (hs *HTTPServer) LoadPlaylistDashboards(ctx context.Context, orgID int64, s
 	playlistItems, _ := hs.LoadPlaylistItems(ctx, playlistID)
 
 	dashboardByIDs := make([]int64, 0)
+	dashboardByUIDs := make([]string, 0)
 	dashboardByTag := make([]string, 0)
 	dashboardIDOrder := make(map[int64]int)
+	dashboardUIDOrder := make(map[string]int)
 	dashboardTagOrder := make(map[string]int)
 
 	for _, i := range playlistItems {
-		if i.Type == "dashboard_by_id" {
+		if i.Type == models.PlaylistItemTypeDashboardByID {
 			dashboardID, _ := strconv.ParseInt(i.Value, 10, 64)
 			dashboardByIDs = append(dashboardByIDs, dashboardID)
 			dashboardIDOrder[dashboardID] = i.Order
 		}
 
-		if i.Type == "dashboard_by_tag" {
+		if i.Type == models.PlaylistItemTypeDashboardByUID {
+			dashboardByUIDs = append(dashboardByUIDs, i.Value)
+			dashboardUIDOrder[i.Value] = i.Order
+		}
+
+		if i.Type == models.PlaylistItemTypeDashboardByTag {
 			dashboardByTag = append(dashboardByTag, i.Value)
 			dashboardTagOrder[i.Value] = i.Order
 		}
@@ -90,6 +121,8 @@ func (hs *HTTPServer) LoadPlaylistDashboards(ctx context.Context, orgID int64, s
 
 	var k, _ = hs.populateDashboardsByID(ctx, dashboardByIDs, dashboardIDOrder)
 	result = append(result, k...)
+	var u, _ = hs.populateDashboardsByUID(ctx, orgID, dashboardByUIDs, dashboardUIDOrder)
+	result = append(result, u...)
 	result = append(result, hs.populateDashboardsByTag(ctx, orgID, signedInUser, dashboardByTag, dashboardTagOrder)...)
 
 	sort.Sort(result)
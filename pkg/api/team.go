@@ -69,6 +69,10 @@ func (hs *HTTPServer) UpdateTeam(c *models.ReqContext) response.Response {
 		}
 	}
 
+	if resp := hs.checkTeamNotProvisioned(c, cmd.Id); resp != nil {
+		return resp
+	}
+
 	if err := hs.SQLStore.UpdateTeam(c.Req.Context(), &cmd); err != nil {
 		if errors.Is(err, models.ErrTeamNameTaken) {
 			return response.Error(400, "Team name taken", err)
@@ -94,6 +98,10 @@ func (hs *HTTPServer) DeleteTeamByID(c *models.ReqContext) response.Response {
 		}
 	}
 
+	if resp := hs.checkTeamNotProvisioned(c, teamId); resp != nil {
+		return resp
+	}
+
 	if err := hs.SQLStore.DeleteTeam(c.Req.Context(), &models.DeleteTeamCommand{OrgId: orgId, Id: teamId}); err != nil {
 		if errors.Is(err, models.ErrTeamNotFound) {
 			return response.Error(404, "Failed to delete Team. ID not found", nil)
@@ -103,6 +111,19 @@ func (hs *HTTPServer) DeleteTeamByID(c *models.ReqContext) response.Response {
 	return response.Success("Team deleted")
 }
 
+// checkTeamNotProvisioned returns an error response if the team is provisioned.
+// Provisioned teams and their members can only be changed in their provisioning config files.
+func (hs *HTTPServer) checkTeamNotProvisioned(c *models.ReqContext, teamID int64) response.Response {
+	query := models.IsIdentityProvisionedQuery{Kind: models.IdentityKindTeam, IdentityId: teamID}
+	if err := hs.SQLStore.IsIdentityProvisioned(c.Req.Context(), &query); err != nil {
+		return response.Error(500, "Failed to get Team", err)
+	}
+	if query.Result {
+		return response.Error(403, "Cannot change provisioned team", nil)
+	}
+	return nil
+}
+
 // GET /api/teams/search
 func (hs *HTTPServer) SearchTeams(c *models.ReqContext) response.Response {
 	perPage := c.QueryInt("perpage")
@@ -194,6 +215,12 @@ func (hs *HTTPServer) GetTeamByID(c *models.ReqContext) response.Response {
 		return response.Error(500, "Failed to get Team", err)
 	}
 
+	provisionedQuery := models.IsIdentityProvisionedQuery{Kind: models.IdentityKindTeam, IdentityId: teamId}
+	if err := hs.SQLStore.IsIdentityProvisioned(c.Req.Context(), &provisionedQuery); err != nil {
+		return response.Error(500, "Failed to get Team", err)
+	}
+	query.Result.IsProvisioned = provisionedQuery.Result
+
 	// Add accesscontrol metadata
 	query.Result.AccessControl = hs.getAccessControlMetadata(c, c.OrgId, "teams:id:", strconv.FormatInt(query.Result.Id, 10))
 
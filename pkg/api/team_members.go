@@ -76,6 +76,10 @@ func (hs *HTTPServer) AddTeamMember(c *models.ReqContext) response.Response {
 		}
 	}
 
+	if resp := hs.checkTeamNotProvisioned(c, cmd.TeamId); resp != nil {
+		return resp
+	}
+
 	isTeamMember, err := hs.SQLStore.IsTeamMember(c.OrgId, cmd.TeamId, cmd.UserId)
 	if err != nil {
 		return response.Error(500, "Failed to add team member.", err)
@@ -116,6 +120,10 @@ func (hs *HTTPServer) UpdateTeamMember(c *models.ReqContext) response.Response {
 		}
 	}
 
+	if resp := hs.checkTeamNotProvisioned(c, teamId); resp != nil {
+		return resp
+	}
+
 	isTeamMember, err := hs.SQLStore.IsTeamMember(orgId, teamId, userId)
 	if err != nil {
 		return response.Error(500, "Failed to update team member.", err)
@@ -159,6 +167,10 @@ func (hs *HTTPServer) RemoveTeamMember(c *models.ReqContext) response.Response {
 		}
 	}
 
+	if resp := hs.checkTeamNotProvisioned(c, teamId); resp != nil {
+		return resp
+	}
+
 	teamIDString := strconv.FormatInt(teamId, 10)
 	if _, err := hs.teamPermissionsService.SetUserPermission(c.Req.Context(), orgId, accesscontrol.User{ID: userId}, teamIDString, ""); err != nil {
 		if errors.Is(err, models.ErrTeamNotFound) {
@@ -45,7 +45,7 @@ type ConditionEvalJSON struct {
 type condition struct {
 	QueryRefID string
 	Reducer    classicReducer
-	Evaluator  evaluator
+	Evaluator  Evaluator
 	Operator   string
 }
 
@@ -209,7 +209,7 @@ func UnmarshalConditionsCmd(rawQuery map[string]interface{}, refID string) (*Con
 			return nil, fmt.Errorf("reducer '%v' in condition %v is not a valid reducer", cond.Reducer, i+1)
 		}
 
-		cond.Evaluator, err = newAlertEvaluator(cj.Evaluator)
+		cond.Evaluator, err = NewAlertEvaluator(cj.Evaluator)
 		if err != nil {
 			return nil, err
 		}
@@ -6,7 +6,8 @@ import (
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
 
-type evaluator interface {
+// Evaluator evaluates a reduced value against a condition such as a threshold.
+type Evaluator interface {
 	Eval(mathexp.Number) bool
 }
 
@@ -23,9 +24,9 @@ type rangedEvaluator struct {
 	Upper float64
 }
 
-// newAlertEvaluator is a factory function for returning
+// NewAlertEvaluator is a factory function for returning
 // an AlertEvaluator depending on evaluation operator.
-func newAlertEvaluator(model ConditionEvalJSON) (evaluator, error) {
+func NewAlertEvaluator(model ConditionEvalJSON) (Evaluator, error) {
 	switch model.Type {
 	case "gt", "lt":
 		return newThresholdEvaluator(model)
@@ -175,22 +175,29 @@ type ResampleCommand struct {
 	Downsampler   string
 	Upsampler     string
 	TimeRange     TimeRange
+	Options       mathexp.ResampleOptions
 	refID         string
 }
 
 // NewResampleCommand creates a new ResampleCMD.
-func NewResampleCommand(refID, rawWindow, varToResample string, downsampler string, upsampler string, tr TimeRange) (*ResampleCommand, error) {
-	// TODO: validate reducer here, before execution
+func NewResampleCommand(refID, rawWindow, varToResample string, downsampler string, upsampler string, tr TimeRange, opts mathexp.ResampleOptions) (*ResampleCommand, error) {
 	window, err := gtime.ParseDuration(rawWindow)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, window, err)
 	}
+	if window <= 0 {
+		return nil, fmt.Errorf(`resample "window" duration must be positive, got %q for refId %v`, rawWindow, refID)
+	}
+	if _, err := mathexp.GetReduceFunc(downsampler); err != nil {
+		return nil, fmt.Errorf("invalid resample downsampler for refId %v: %w", refID, err)
+	}
 	return &ResampleCommand{
 		Window:        window,
 		VarToResample: varToResample,
 		Downsampler:   downsampler,
 		Upsampler:     upsampler,
 		TimeRange:     tr,
+		Options:       opts,
 		refID:         refID,
 	}, nil
 }
@@ -235,7 +242,34 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T for refId %v", upsampler, rn.RefID)
 	}
 
-	return NewResampleCommand(rn.RefID, window, varToResample, downsampler, upsampler, rn.TimeRange)
+	var opts mathexp.ResampleOptions
+	if rawMaxGap, ok := rn.Query["maxGap"]; ok && rawMaxGap != "" {
+		maxGap, ok := rawMaxGap.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected resample maxGap to be a string, got type %T for refId %v", rawMaxGap, rn.RefID)
+		}
+		d, err := gtime.ParseDuration(maxGap)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse resample "maxGap" duration field %q for refId %v: %w`, maxGap, rn.RefID, err)
+		}
+		opts.MaxGap = d
+	}
+
+	if rawAlignment, ok := rn.Query["alignment"]; ok {
+		alignment, ok := rawAlignment.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected resample alignment to be a string, got type %T for refId %v", rawAlignment, rn.RefID)
+		}
+		switch alignment {
+		case "", "from":
+		case "clock":
+			opts.AlignToClock = true
+		default:
+			return nil, fmt.Errorf("resample alignment must be 'from' or 'clock', got %q for refId %v", alignment, rn.RefID)
+		}
+	}
+
+	return NewResampleCommand(rn.RefID, window, varToResample, downsampler, upsampler, rn.TimeRange, opts)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -253,7 +287,7 @@ func (gr *ResampleCommand) Execute(ctx context.Context, vars mathexp.Vars) (math
 		if !ok {
 			return newRes, fmt.Errorf("can only resample type series, got type %v", val.Type())
 		}
-		num, err := series.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, gr.TimeRange.From, gr.TimeRange.To)
+		num, err := series.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, gr.TimeRange.From, gr.TimeRange.To, gr.Options)
 		if err != nil {
 			return newRes, err
 		}
@@ -276,6 +310,10 @@ const (
 	TypeResample
 	// TypeClassicConditions is the CMDType for the classic condition operation.
 	TypeClassicConditions
+	// TypeThreshold is the CMDType for a threshold expression, optionally with a recovery threshold.
+	TypeThreshold
+	// TypeSQL is the CMDType for an SQL query over the results of other queries and expressions.
+	TypeSQL
 )
 
 func (gt CommandType) String() string {
@@ -288,6 +326,10 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeThreshold:
+		return "threshold"
+	case TypeSQL:
+		return "sql"
 	default:
 		return "unknown"
 	}
@@ -304,6 +346,10 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeResample, nil
 	case "classic_conditions":
 		return TypeClassicConditions, nil
+	case "threshold":
+		return TypeThreshold, nil
+	case "sql":
+		return TypeSQL, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
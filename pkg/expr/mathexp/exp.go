@@ -248,7 +248,15 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
-	unions := union(ar, br)
+	var unions []*Union
+	if node.Matching != nil {
+		unions, err = matchingUnion(ar, br, node.Matching)
+		if err != nil {
+			return res, err
+		}
+	} else {
+		unions = union(ar, br)
+	}
 	for _, uni := range unions {
 		var value Value
 		switch at := uni.A.(type) {
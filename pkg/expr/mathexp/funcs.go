@@ -1,6 +1,7 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
@@ -68,6 +69,72 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"sqrt": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             sqrt,
+	},
+	"exp": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             exp,
+	},
+	"pow": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar},
+		VariantReturn: true,
+		F:             pow,
+	},
+	"clamp": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clamp,
+		Check:         checkClamp,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"derivative": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      derivative,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+		Check:  checkWindowArg(1),
+	},
+	"moving_sum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      movingSum,
+		Check:  checkWindowArg(1),
+	},
+	"stddev_over": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      stddevOver,
+		Check:  checkWindowArg(1),
+	},
+	"quantile_over": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString, parse.TypeScalar},
+		Return: parse.TypeSeriesSet,
+		F:      quantileOver,
+		Check:  checkQuantileOver,
+	},
+	"time_shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      timeShift,
+		Check:  checkDurationArg(1),
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -303,3 +370,102 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// sqrt returns the square root for each result in NumberSet, SeriesSet, or Scalar
+func sqrt(e *State, varSet Results) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, math.Sqrt)
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// exp returns e**x for each result in NumberSet, SeriesSet, or Scalar
+func exp(e *State, varSet Results) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, math.Exp)
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// pow returns x**exponent for each result in NumberSet, SeriesSet, or Scalar.
+// If the exponent is null, NaN is returned for each value.
+func pow(e *State, varSet Results, exponent Results) (Results, error) {
+	newRes := Results{}
+	p, err := scalarArg(exponent, "pow", "exponent")
+	if err != nil {
+		return newRes, err
+	}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(f float64) float64 {
+			return math.Pow(f, p)
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// clamp limits each result in NumberSet, SeriesSet, or Scalar to the range [min, max].
+func clamp(e *State, varSet Results, minRes Results, maxRes Results) (Results, error) {
+	newRes := Results{}
+	lower, err := scalarArg(minRes, "clamp", "min")
+	if err != nil {
+		return newRes, err
+	}
+	upper, err := scalarArg(maxRes, "clamp", "max")
+	if err != nil {
+		return newRes, err
+	}
+	if lower > upper {
+		return newRes, fmt.Errorf("clamp: min %v must not be greater than max %v", lower, upper)
+	}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(f float64) float64 {
+			return math.Max(lower, math.Min(upper, f))
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// checkClamp verifies at parse time that constant min and max arguments of clamp are in order.
+func checkClamp(t *parse.Tree, f *parse.FuncNode) error {
+	lower, lok := f.Args[1].(*parse.ScalarNode)
+	upper, uok := f.Args[2].(*parse.ScalarNode)
+	if lok && uok && lower.Float64 > upper.Float64 {
+		return fmt.Errorf("parse: min argument of clamp must not be greater than max, got %v and %v", lower.Float64, upper.Float64)
+	}
+	return nil
+}
+
+// scalarArg returns the value of a function argument that must be a single Scalar.
+// A null scalar is returned as NaN.
+func scalarArg(res Results, funcName, argName string) (float64, error) {
+	if len(res.Values) != 1 {
+		return 0, fmt.Errorf("%s: expected a single scalar for %s, got %v values", funcName, argName, len(res.Values))
+	}
+	s, ok := res.Values[0].(Scalar)
+	if !ok {
+		return 0, fmt.Errorf("%s: expected a scalar for %s, got type %v", funcName, argName, res.Values[0].Type())
+	}
+	f := s.GetFloat64Value()
+	if f == nil {
+		return math.NaN(), nil
+	}
+	return *f, nil
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 )
 
@@ -159,3 +161,102 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestClampAndPowFuncs(t *testing.T) {
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name: "clamp on series",
+			expr: "clamp($A, 0, 10)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeSeries("", nil,
+							tp{time.Unix(5, 0), float64Pointer(-5)},
+							tp{time.Unix(10, 0), float64Pointer(5)},
+							tp{time.Unix(15, 0), float64Pointer(15)},
+							tp{time.Unix(20, 0), nil}),
+					},
+				},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(5, 0), float64Pointer(0)},
+						tp{time.Unix(10, 0), float64Pointer(5)},
+						tp{time.Unix(15, 0), float64Pointer(10)},
+						tp{time.Unix(20, 0), float64Pointer(math.NaN())}),
+				},
+			},
+		},
+		{
+			name:      "clamp with negative min on scalar",
+			expr:      "clamp(-20, -10, 10)",
+			vars:      Vars{},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   Results{[]Value{NewScalar("", float64Pointer(-10))}},
+		},
+		{
+			name:     "clamp with min greater than max - should error",
+			expr:     "clamp($A, 10, 0)",
+			vars:     Vars{},
+			newErrIs: require.Error,
+		},
+		{
+			name: "pow on number",
+			expr: "pow($A, 2)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeNumber("", nil, float64Pointer(3)),
+					},
+				},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   Results{[]Value{makeNumber("", nil, float64Pointer(9))}},
+		},
+		{
+			name:      "sqrt on scalar",
+			expr:      "sqrt(16)",
+			vars:      Vars{},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   Results{[]Value{NewScalar("", float64Pointer(4))}},
+		},
+		{
+			name:     "pow with series exponent - should error",
+			expr:     "pow(2, $A)",
+			vars:     Vars{},
+			newErrIs: require.Error,
+		},
+	}
+
+	opt := cmp.Comparer(func(x, y float64) bool {
+		return (math.IsNaN(x) && math.IsNaN(y)) || x == y
+	})
+	options := append([]cmp.Option{opt}, data.FrameTestCompareOptions()...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars)
+				tt.execErrIs(t, err)
+				if diff := cmp.Diff(tt.results, res, options...); diff != "" {
+					t.Errorf("Result mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// The functions in this file operate on the points of a Series over time and therefore
+// only accept SeriesSet arguments. Series are expected to be sorted by time, oldest first.
+
+// rate returns the per-second rate of increase between consecutive points of each Series.
+// A decrease in value is treated as a counter reset, in which case the increase is the new value.
+// The first point of each Series is dropped since it has no predecessor.
+func rate(e *State, varSet Results) (Results, error) {
+	return perPointPair(e, varSet, "rate", func(prevT, curT time.Time, prev, cur float64) float64 {
+		diff := cur - prev
+		if diff < 0 {
+			diff = cur
+		}
+		return perSecond(diff, curT.Sub(prevT))
+	})
+}
+
+// derivative returns the per-second change between consecutive points of each Series.
+// Unlike rate, decreases are kept as negative values.
+// The first point of each Series is dropped since it has no predecessor.
+func derivative(e *State, varSet Results) (Results, error) {
+	return perPointPair(e, varSet, "derivative", func(prevT, curT time.Time, prev, cur float64) float64 {
+		return perSecond(cur-prev, curT.Sub(prevT))
+	})
+}
+
+// delta returns the difference between consecutive points of each Series.
+// The first point of each Series is dropped since it has no predecessor.
+func delta(e *State, varSet Results) (Results, error) {
+	return perPointPair(e, varSet, "delta", func(_, _ time.Time, prev, cur float64) float64 {
+		return cur - prev
+	})
+}
+
+// movingAvg returns the average of the values within the trailing window of each point.
+func movingAvg(e *State, varSet Results, rawWindow string) (Results, error) {
+	return perWindow(e, varSet, "moving_avg", rawWindow, func(vals []float64) float64 {
+		return windowSum(vals) / float64(len(vals))
+	})
+}
+
+// movingSum returns the sum of the values within the trailing window of each point.
+func movingSum(e *State, varSet Results, rawWindow string) (Results, error) {
+	return perWindow(e, varSet, "moving_sum", rawWindow, windowSum)
+}
+
+// stddevOver returns the population standard deviation of the values within the trailing
+// window of each point.
+func stddevOver(e *State, varSet Results, rawWindow string) (Results, error) {
+	return perWindow(e, varSet, "stddev_over", rawWindow, func(vals []float64) float64 {
+		return math.Sqrt(variance(vals))
+	})
+}
+
+// quantileOver returns the q-quantile (0 <= q <= 1) of the values within the trailing window
+// of each point. Values between ranks are linearly interpolated.
+func quantileOver(e *State, varSet Results, rawWindow string, qRes Results) (Results, error) {
+	q, err := scalarArg(qRes, "quantile_over", "quantile")
+	if err != nil {
+		return Results{}, err
+	}
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return Results{}, fmt.Errorf("quantile_over: quantile must be between 0 and 1, got %v", q)
+	}
+	return perWindow(e, varSet, "quantile_over", rawWindow, func(vals []float64) float64 {
+		return quantile(vals, q)
+	})
+}
+
+// timeShift adds the duration to the timestamp of every point of each Series. A negative
+// duration moves points back in time.
+func timeShift(e *State, varSet Results, rawShift string) (Results, error) {
+	newRes := Results{}
+	shift, err := gtime.ParseDuration(rawShift)
+	if err != nil {
+		return newRes, fmt.Errorf("time_shift: failed to parse duration %q: %w", rawShift, err)
+	}
+	for _, val := range varSet.Values {
+		series, ok := val.(Series)
+		if !ok {
+			return newRes, fmt.Errorf("time_shift: expected type series, got type %v", val.Type())
+		}
+		newSeries := NewSeries(e.RefID, series.GetLabels(), series.Len())
+		for i := 0; i < series.Len(); i++ {
+			t, f := series.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(shift), f)
+		}
+		newRes.Values = append(newRes.Values, newSeries)
+	}
+	return newRes, nil
+}
+
+// perPointPair passes each pair of consecutive non-null points of each Series to pairF
+// and sets the result at the time of the second point. Null points are kept as null.
+func perPointPair(e *State, varSet Results, funcName string, pairF func(prevT, curT time.Time, prev, cur float64) float64) (Results, error) {
+	newRes := Results{}
+	for _, val := range varSet.Values {
+		series, ok := val.(Series)
+		if !ok {
+			return newRes, fmt.Errorf("%s: expected type series, got type %v", funcName, val.Type())
+		}
+		newSeries := NewSeries(e.RefID, series.GetLabels(), 0)
+		var prevT time.Time
+		var prev *float64
+		for i := 0; i < series.Len(); i++ {
+			t, f := series.GetPoint(i)
+			if f == nil {
+				if prev != nil {
+					newSeries.AppendPoint(t, nil)
+				}
+				continue
+			}
+			if prev != nil {
+				nF := pairF(prevT, t, *prev, *f)
+				newSeries.AppendPoint(t, &nF)
+			}
+			prevT, prev = t, f
+		}
+		newRes.Values = append(newRes.Values, newSeries)
+	}
+	return newRes, nil
+}
+
+// perWindow calls windowF for each point of each Series with the non-null values
+// whose time is within (t - window, t], where t is the time of the point.
+// If there are no such values the point is set to NaN.
+func perWindow(e *State, varSet Results, funcName, rawWindow string, windowF func(vals []float64) float64) (Results, error) {
+	newRes := Results{}
+	window, err := parseWindow(rawWindow)
+	if err != nil {
+		return newRes, fmt.Errorf("%s: %w", funcName, err)
+	}
+	for _, val := range varSet.Values {
+		series, ok := val.(Series)
+		if !ok {
+			return newRes, fmt.Errorf("%s: expected type series, got type %v", funcName, val.Type())
+		}
+		newSeries := NewSeries(e.RefID, series.GetLabels(), series.Len())
+		start := 0
+		for i := 0; i < series.Len(); i++ {
+			t := series.GetTime(i)
+			for start < i && !series.GetTime(start).After(t.Add(-window)) {
+				start++
+			}
+			vals := make([]float64, 0, i-start+1)
+			for j := start; j <= i; j++ {
+				if f := series.GetValue(j); f != nil {
+					vals = append(vals, *f)
+				}
+			}
+			nF := math.NaN()
+			if len(vals) > 0 {
+				nF = windowF(vals)
+			}
+			newSeries.SetPoint(i, t, &nF)
+		}
+		newRes.Values = append(newRes.Values, newSeries)
+	}
+	return newRes, nil
+}
+
+func perSecond(diff float64, d time.Duration) float64 {
+	if d <= 0 {
+		return math.NaN()
+	}
+	return diff / d.Seconds()
+}
+
+func windowSum(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum
+}
+
+// quantile returns the q-quantile of vals. vals is sorted in place.
+func quantile(vals []float64, q float64) float64 {
+	if len(vals) == 0 {
+		return math.NaN()
+	}
+	sort.Float64s(vals)
+	rank := q * float64(len(vals)-1)
+	lower := math.Floor(rank)
+	upper := math.Ceil(rank)
+	weight := rank - lower
+	return vals[int(lower)]*(1-weight) + vals[int(upper)]*weight
+}
+
+func parseWindow(rawWindow string) (time.Duration, error) {
+	window, err := gtime.ParseDuration(rawWindow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse window duration %q: %w", rawWindow, err)
+	}
+	if window <= 0 {
+		return 0, fmt.Errorf("window duration must be positive, got %q", rawWindow)
+	}
+	return window, nil
+}
+
+// checkWindowArg returns a parse time check that the string argument at argIdx is a valid window duration.
+func checkWindowArg(argIdx int) func(*parse.Tree, *parse.FuncNode) error {
+	return func(t *parse.Tree, f *parse.FuncNode) error {
+		if _, err := parseWindow(f.Args[argIdx].(*parse.StringNode).Text); err != nil {
+			return fmt.Errorf("parse: invalid argument %v for %s: %w", argIdx, f.Name, err)
+		}
+		return nil
+	}
+}
+
+// checkDurationArg returns a parse time check that the string argument at argIdx is a valid duration.
+func checkDurationArg(argIdx int) func(*parse.Tree, *parse.FuncNode) error {
+	return func(t *parse.Tree, f *parse.FuncNode) error {
+		raw := f.Args[argIdx].(*parse.StringNode).Text
+		if _, err := gtime.ParseDuration(raw); err != nil {
+			return fmt.Errorf("parse: invalid argument %v for %s: failed to parse duration %q: %w", argIdx, f.Name, raw, err)
+		}
+		return nil
+	}
+}
+
+// checkQuantileOver verifies the window and, when it is a constant, the quantile argument of quantile_over.
+func checkQuantileOver(t *parse.Tree, f *parse.FuncNode) error {
+	if err := checkWindowArg(1)(t, f); err != nil {
+		return err
+	}
+	if q, ok := f.Args[2].(*parse.ScalarNode); ok && (q.Float64 < 0 || q.Float64 > 1) {
+		return fmt.Errorf("parse: quantile argument of quantile_over must be between 0 and 1, got %v", q.Float64)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowFuncs(t *testing.T) {
+	counter := Vars{
+		"A": Results{
+			[]Value{
+				makeSeries("", data.Labels{"id": "1"},
+					tp{time.Unix(0, 0), float64Pointer(0)},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(50)},
+					tp{time.Unix(30, 0), float64Pointer(10)},
+					tp{time.Unix(40, 0), float64Pointer(30)}),
+			},
+		},
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "rate handles counter resets",
+			expr:      "rate($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"id": "1"},
+						tp{time.Unix(10, 0), float64Pointer(2)},
+						tp{time.Unix(20, 0), float64Pointer(3)},
+						tp{time.Unix(30, 0), float64Pointer(1)},
+						tp{time.Unix(40, 0), float64Pointer(2)}),
+				},
+			},
+		},
+		{
+			name:      "derivative keeps decreases",
+			expr:      "derivative($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"id": "1"},
+						tp{time.Unix(10, 0), float64Pointer(2)},
+						tp{time.Unix(20, 0), float64Pointer(3)},
+						tp{time.Unix(30, 0), float64Pointer(-4)},
+						tp{time.Unix(40, 0), float64Pointer(2)}),
+				},
+			},
+		},
+		{
+			name: "delta skips null points",
+			expr: "delta($A)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeSeries("", nil,
+							tp{time.Unix(0, 0), float64Pointer(1)},
+							tp{time.Unix(10, 0), nil},
+							tp{time.Unix(20, 0), float64Pointer(4)}),
+					},
+				},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(10, 0), nil},
+						tp{time.Unix(20, 0), float64Pointer(3)}),
+				},
+			},
+		},
+		{
+			name:      "moving_avg over trailing window",
+			expr:      `moving_avg($A, "20s")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"id": "1"},
+						tp{time.Unix(0, 0), float64Pointer(0)},
+						tp{time.Unix(10, 0), float64Pointer(10)},
+						tp{time.Unix(20, 0), float64Pointer(35)},
+						tp{time.Unix(30, 0), float64Pointer(30)},
+						tp{time.Unix(40, 0), float64Pointer(20)}),
+				},
+			},
+		},
+		{
+			name:      "moving_sum over trailing window",
+			expr:      `moving_sum($A, "20s")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"id": "1"},
+						tp{time.Unix(0, 0), float64Pointer(0)},
+						tp{time.Unix(10, 0), float64Pointer(20)},
+						tp{time.Unix(20, 0), float64Pointer(70)},
+						tp{time.Unix(30, 0), float64Pointer(60)},
+						tp{time.Unix(40, 0), float64Pointer(40)}),
+				},
+			},
+		},
+		{
+			name:      "stddev_over over trailing window",
+			expr:      `stddev_over($A, "20s")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"id": "1"},
+						tp{time.Unix(0, 0), float64Pointer(0)},
+						tp{time.Unix(10, 0), float64Pointer(10)},
+						tp{time.Unix(20, 0), float64Pointer(15)},
+						tp{time.Unix(30, 0), float64Pointer(20)},
+						tp{time.Unix(40, 0), float64Pointer(10)}),
+				},
+			},
+		},
+		{
+			name:      "quantile_over interpolates between ranks",
+			expr:      `quantile_over($A, "1m", 0.5)`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"id": "1"},
+						tp{time.Unix(0, 0), float64Pointer(0)},
+						tp{time.Unix(10, 0), float64Pointer(10)},
+						tp{time.Unix(20, 0), float64Pointer(20)},
+						tp{time.Unix(30, 0), float64Pointer(15)},
+						tp{time.Unix(40, 0), float64Pointer(20)}),
+				},
+			},
+		},
+		{
+			name:      "time_shift moves points forward",
+			expr:      `time_shift($A, "1m")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"id": "1"},
+						tp{time.Unix(60, 0), float64Pointer(0)},
+						tp{time.Unix(70, 0), float64Pointer(20)},
+						tp{time.Unix(80, 0), float64Pointer(50)},
+						tp{time.Unix(90, 0), float64Pointer(10)},
+						tp{time.Unix(100, 0), float64Pointer(30)}),
+				},
+			},
+		},
+		{
+			name:      "moving_avg in a binary expression",
+			expr:      `$A - moving_avg($A, "10s")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"id": "1"},
+						tp{time.Unix(0, 0), float64Pointer(0)},
+						tp{time.Unix(10, 0), float64Pointer(0)},
+						tp{time.Unix(20, 0), float64Pointer(0)},
+						tp{time.Unix(30, 0), float64Pointer(0)},
+						tp{time.Unix(40, 0), float64Pointer(0)}),
+				},
+			},
+		},
+		{
+			name: "rate on number - should error",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeNumber("", nil, float64Pointer(1)),
+					},
+				},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "rate on scalar - should error",
+			expr:     "rate(1)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg with invalid window - should error",
+			expr:     `moving_avg($A, "five minutes")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg with negative window - should error",
+			expr:     `moving_avg($A, "-5m")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "quantile_over with out of range quantile - should error",
+			expr:     `quantile_over($A, "5m", 2)`,
+			newErrIs: require.Error,
+		},
+	}
+
+	opt := cmp.Comparer(func(x, y float64) bool {
+		return (math.IsNaN(x) && math.IsNaN(y)) || x == y
+	})
+	options := append([]cmp.Option{opt}, data.FrameTestCompareOptions()...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars)
+				tt.execErrIs(t, err)
+				if err != nil {
+					return
+				}
+				if diff := cmp.Diff(tt.results, res, options...); diff != "" {
+					t.Errorf("Result mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ package parse
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -298,6 +299,9 @@ type BinaryNode struct {
 	Args     [2]Node
 	Operator item
 	OpStr    string
+	// Matching is the explicit label matching of the operands, or nil
+	// when the operands are matched by their labels (see mathexp's union).
+	Matching *LabelMatching
 }
 
 func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
@@ -306,19 +310,73 @@ func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
 
 // String returns the string representation of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) String() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s %s %s", b.Args[0], b.Operator.val, b.Matching, b.Args[1])
+	}
 	return fmt.Sprintf("%s %s %s", b.Args[0], b.Operator.val, b.Args[1])
 }
 
 // StringAST returns the string representation of abstract syntax tree of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) StringAST() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s(%s, %s)", b.Operator.val, b.Matching, b.Args[0], b.Args[1])
+	}
 	return fmt.Sprintf("%s(%s, %s)", b.Operator.val, b.Args[0], b.Args[1])
 }
 
 // Check performs parse time checking on the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) Check(t *Tree) error {
+	if b.Matching == nil {
+		return nil
+	}
+	for _, arg := range b.Args {
+		if rt := arg.Return(); rt != TypeNumberSet && rt != TypeSeriesSet {
+			return fmt.Errorf(`parse: type error in %s, label matching requires both operands to be of type series or number, got %s`, b, rt)
+		}
+	}
 	return nil
 }
 
+// MatchGroup is the side of a binary operation that may have several items
+// matching a single item of the other side.
+type MatchGroup int
+
+const (
+	// GroupNone requires a one-to-one match between the items of both sides.
+	GroupNone MatchGroup = iota
+	// GroupLeft allows many items of the left side to match one item of the right side.
+	GroupLeft
+	// GroupRight allows many items of the right side to match one item of the left side.
+	GroupRight
+)
+
+// LabelMatching describes how the items of the two operands of a binary operation
+// are matched by their labels, e.g. "on(pod)" or "ignoring(code) group_left(team)".
+type LabelMatching struct {
+	// On is true when items are matched on Labels only, and false when Labels are ignored.
+	On     bool
+	Labels []string
+	Group  MatchGroup
+	// Include lists the labels of the "one" side that are copied to the result when grouping.
+	Include []string
+}
+
+// String returns the string representation of the LabelMatching.
+func (m *LabelMatching) String() string {
+	s := "ignoring"
+	if m.On {
+		s = "on"
+	}
+	s = fmt.Sprintf("%s(%s)", s, strings.Join(m.Labels, ", "))
+	switch m.Group {
+	case GroupLeft:
+		s += fmt.Sprintf(" group_left(%s)", strings.Join(m.Include, ", "))
+	case GroupRight:
+		s += fmt.Sprintf(" group_right(%s)", strings.Join(m.Include, ", "))
+	}
+	return s
+}
+
 // Return returns the result type of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) Return() ReturnType {
 	t0 := b.Args[0].Return()
@@ -401,6 +459,8 @@ const (
 	TypeSeriesSet
 	// TypeVariantSet is a collection of the same type Number, Series, or Scalar.
 	TypeVariantSet
+	// TypeTableData is a table of data, such as the result of an SQL expression.
+	TypeTableData
 )
 
 // String returns a string representation of the ReturnType.
@@ -416,6 +476,8 @@ func (f ReturnType) String() string {
 		return "scalar"
 	case TypeVariantSet:
 		return "variant"
+	case TypeTableData:
+		return "tableData"
 	default:
 		return "unknown"
 	}
@@ -175,6 +175,10 @@ F -> v | "(" O ")" | "!" O | "-" O
 v -> number | func(..) | queryVar
 Func -> name "(" param {"," param} ")"
 param -> number | "string" | queryVar
+
+Every binary operator may be followed by a label matching clause:
+matching -> ("on" | "ignoring") labels [("group_left" | "group_right") [labels]]
+labels -> "(" [label {"," label}] ")"
 */
 
 // expr:
@@ -185,7 +189,7 @@ func (t *Tree) O() Node {
 	for {
 		switch t.peek().typ {
 		case itemOr:
-			n = newBinary(t.next(), n, t.A())
+			n = t.binary(n, t.A)
 		default:
 			return n
 		}
@@ -198,7 +202,7 @@ func (t *Tree) A() Node {
 	for {
 		switch t.peek().typ {
 		case itemAnd:
-			n = newBinary(t.next(), n, t.C())
+			n = t.binary(n, t.C)
 		default:
 			return n
 		}
@@ -211,7 +215,7 @@ func (t *Tree) C() Node {
 	for {
 		switch t.peek().typ {
 		case itemEq, itemNotEq, itemGreater, itemGreaterEq, itemLess, itemLessEq:
-			n = newBinary(t.next(), n, t.P())
+			n = t.binary(n, t.P)
 		default:
 			return n
 		}
@@ -224,7 +228,7 @@ func (t *Tree) P() Node {
 	for {
 		switch t.peek().typ {
 		case itemPlus, itemMinus:
-			n = newBinary(t.next(), n, t.M())
+			n = t.binary(n, t.M)
 		default:
 			return n
 		}
@@ -237,7 +241,7 @@ func (t *Tree) M() Node {
 	for {
 		switch t.peek().typ {
 		case itemMult, itemDiv, itemMod:
-			n = newBinary(t.next(), n, t.E())
+			n = t.binary(n, t.E)
 		default:
 			return n
 		}
@@ -250,13 +254,102 @@ func (t *Tree) E() Node {
 	for {
 		switch t.peek().typ {
 		case itemPow:
-			n = newBinary(t.next(), n, t.F())
+			n = t.binary(n, t.F)
 		default:
 			return n
 		}
 	}
 }
 
+// binary consumes a binary operator and its optional label matching clause, and
+// returns a BinaryNode of left and the operand parsed by right.
+func (t *Tree) binary(left Node, right func() Node) Node {
+	op := t.next()
+	matching := t.matching()
+	n := newBinary(op, left, right())
+	n.Matching = matching
+	return n
+}
+
+// matching is the optional label matching clause of a binary operator in the grammar.
+func (t *Tree) matching() *LabelMatching {
+	token := t.peek()
+	if token.typ != itemFunc {
+		return nil
+	}
+	m := &LabelMatching{}
+	switch token.val {
+	case "on":
+		m.On = true
+	case "ignoring":
+	case "group_left", "group_right":
+		t.errorf("%s must follow on(...) or ignoring(...)", token.val)
+	default:
+		return nil
+	}
+	t.next()
+	m.Labels = t.labelList(token.val)
+
+	token = t.peek()
+	if token.typ != itemFunc {
+		return m
+	}
+	switch token.val {
+	case "group_left":
+		m.Group = GroupLeft
+	case "group_right":
+		m.Group = GroupRight
+	default:
+		return m
+	}
+	t.next()
+	if t.peek().typ == itemLeftParen {
+		m.Include = t.labelList(token.val)
+	}
+	if m.On {
+		for _, l := range m.Include {
+			for _, ml := range m.Labels {
+				if l == ml {
+					t.errorf("label %q must not occur in both on(...) and %s(...)", l, token.val)
+				}
+			}
+		}
+	}
+	return m
+}
+
+// labelList is "(" [label {"," label}] ")" in the grammar. A label is either a name or a quoted string.
+func (t *Tree) labelList(context string) []string {
+	labels := []string{}
+	t.expect(itemLeftParen, context)
+	if t.peek().typ == itemRightParen {
+		t.next()
+		return labels
+	}
+	for {
+		switch token := t.next(); token.typ {
+		case itemFunc:
+			labels = append(labels, token.val)
+		case itemString:
+			s, err := strconv.Unquote(token.val)
+			if err != nil {
+				t.errorf("Unquoting error: %s", err)
+			}
+			labels = append(labels, s)
+		default:
+			t.unexpected(token, context)
+		}
+		switch token := t.next(); token.typ {
+		case itemComma:
+			// continue with the next label
+		case itemRightParen:
+			return labels
+		default:
+			t.unexpected(token, context)
+		}
+	}
+}
+
 // F is v | "(" O ")" | "!" O | "-" O in the grammar.
 func (t *Tree) F() Node {
 	switch token := t.peek(); token.typ {
@@ -332,6 +425,14 @@ func (t *Tree) Func() (f *FuncNode) {
 		case itemRightParen:
 			return
 		}
+		switch token = t.next(); token.typ {
+		case itemComma:
+			// continue with the next argument
+		case itemRightParen:
+			return
+		default:
+			t.unexpected(token, "func")
+		}
 	}
 }
 
@@ -81,6 +81,126 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// numberValues returns the values of the field which are neither null nor NaN, the
+// same values the legacy classic reducers use. If there are no such values, ok is false.
+func numberValues(fv *Float64Field) (vals []float64, ok bool) {
+	vals = make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			continue
+		}
+		vals = append(vals, *v)
+	}
+	return vals, len(vals) > 0
+}
+
+// numberReducer returns a ReducerFunc that passes the non-null values of the field to reduce.
+// NaN is returned if the field has no values other than null or NaN.
+func numberReducer(reduce func(vals []float64) float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		vals, ok := numberValues(fv)
+		if !ok {
+			nan := math.NaN()
+			return &nan
+		}
+		f := reduce(vals)
+		return &f
+	}
+}
+
+// Percentile returns a ReducerFunc for the p-th percentile (0 <= p <= 100).
+// Values between ranks are linearly interpolated.
+func Percentile(p float64) ReducerFunc {
+	return numberReducer(func(vals []float64) float64 {
+		return quantile(vals, p/100)
+	})
+}
+
+var Median = Percentile(50)
+
+var Variance = numberReducer(variance)
+
+var Stddev = numberReducer(func(vals []float64) float64 {
+	return math.Sqrt(variance(vals))
+})
+
+var Range = numberReducer(func(vals []float64) float64 {
+	lower, upper := vals[0], vals[0]
+	for _, v := range vals {
+		lower = math.Min(lower, v)
+		upper = math.Max(upper, v)
+	}
+	return upper - lower
+})
+
+// Diff is the difference between the last and the first value.
+var Diff = numberReducer(func(vals []float64) float64 {
+	return vals[len(vals)-1] - vals[0]
+})
+
+var DiffAbs = numberReducer(func(vals []float64) float64 {
+	return math.Abs(vals[len(vals)-1] - vals[0])
+})
+
+// PercentDiff is the difference between the last and the first value as a percentage of the first value.
+var PercentDiff = numberReducer(func(vals []float64) float64 {
+	return (vals[len(vals)-1] - vals[0]) / math.Abs(vals[0]) * 100
+})
+
+var PercentDiffAbs = numberReducer(func(vals []float64) float64 {
+	return math.Abs((vals[len(vals)-1] - vals[0]) / vals[0] * 100)
+})
+
+// Delta is the cumulative increase of the values. A decrease is treated as a counter reset,
+// in which case the value after the reset is added.
+var Delta = numberReducer(func(vals []float64) float64 {
+	var d float64
+	for i := 1; i < len(vals); i++ {
+		if step := vals[i] - vals[i-1]; step >= 0 {
+			d += step
+		} else {
+			d += vals[i]
+		}
+	}
+	return d
+})
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+// variance returns the population variance of vals.
+func variance(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	mean := sum / float64(len(vals))
+	var sq float64
+	for _, v := range vals {
+		sq += (v - mean) * (v - mean)
+	}
+	return sq / float64(len(vals))
+}
+
 func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 	switch strings.ToLower(rFunc) {
 	case "sum":
@@ -95,6 +215,36 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	case "median":
+		return Median, nil
+	case "p50":
+		return Percentile(50), nil
+	case "p90":
+		return Percentile(90), nil
+	case "p95":
+		return Percentile(95), nil
+	case "p99":
+		return Percentile(99), nil
+	case "stddev":
+		return Stddev, nil
+	case "variance":
+		return Variance, nil
+	case "range":
+		return Range, nil
+	case "diff":
+		return Diff, nil
+	case "diff_abs":
+		return DiffAbs, nil
+	case "percent_diff":
+		return PercentDiff, nil
+	case "percent_diff_abs":
+		return PercentDiffAbs, nil
+	case "delta":
+		return Delta, nil
+	case "count_non_null":
+		return CountNonNull, nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
@@ -514,3 +514,168 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesReduceStatistics(t *testing.T) {
+	var counter = Vars{
+		"A": Results{
+			[]Value{
+				makeSeries("temp", nil,
+					tp{time.Unix(5, 0), float64Pointer(4)},
+					tp{time.Unix(10, 0), float64Pointer(8)},
+					tp{time.Unix(15, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), float64Pointer(6)}),
+			},
+		},
+	}
+	var counterWithNulls = Vars{
+		"A": Results{
+			[]Value{
+				makeSeries("temp", nil,
+					tp{time.Unix(5, 0), nil},
+					tp{time.Unix(10, 0), float64Pointer(4)},
+					tp{time.Unix(15, 0), NaN},
+					tp{time.Unix(20, 0), float64Pointer(8)},
+					tp{time.Unix(25, 0), float64Pointer(2)},
+					tp{time.Unix(30, 0), nil}),
+			},
+		},
+	}
+	var seriesNonNumbersOnly = Vars{
+		"A": Results{
+			[]Value{
+				makeSeries("temp", nil,
+					tp{time.Unix(5, 0), nil},
+					tp{time.Unix(10, 0), NaN}),
+			},
+		},
+	}
+
+	var tests = []struct {
+		name    string
+		red     string
+		vars    Vars
+		results Results
+	}{
+		{
+			name:    "first",
+			red:     "first",
+			vars:    counter,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(4))}},
+		},
+		{
+			name:    "first empty series",
+			red:     "first",
+			vars:    seriesEmpty,
+			results: Results{[]Value{makeNumber("", nil, NaN)}},
+		},
+		{
+			name:    "median of even number of values",
+			red:     "median",
+			vars:    counter,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(5))}},
+		},
+		{
+			name:    "p90 interpolates between ranks",
+			red:     "p90",
+			vars:    counter,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(7.4))}},
+		},
+		{
+			name:    "p99 skips nil values",
+			red:     "p99",
+			vars:    seriesWithNil,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(2))}},
+		},
+		{
+			name:    "median skips nil and NaN values",
+			red:     "median",
+			vars:    counterWithNulls,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(4))}},
+		},
+		{
+			name:    "median series with only nil values",
+			red:     "median",
+			vars:    seriesNonNumbersOnly,
+			results: Results{[]Value{makeNumber("", nil, NaN)}},
+		},
+		{
+			name:    "variance",
+			red:     "variance",
+			vars:    counter,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(5))}},
+		},
+		{
+			name:    "stddev",
+			red:     "stddev",
+			vars:    counter,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(math.Sqrt(5)))}},
+		},
+		{
+			name:    "range",
+			red:     "range",
+			vars:    counter,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(6))}},
+		},
+		{
+			name:    "diff",
+			red:     "diff",
+			vars:    counter,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(2))}},
+		},
+		{
+			name:    "percent_diff",
+			red:     "percent_diff",
+			vars:    counter,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(50))}},
+		},
+		{
+			name:    "diff skips nil and NaN values",
+			red:     "diff",
+			vars:    counterWithNulls,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(-2))}},
+		},
+		{
+			name:    "percent_diff skips nil and NaN values",
+			red:     "percent_diff",
+			vars:    counterWithNulls,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(-50))}},
+		},
+		{
+			name:    "delta treats decreases as counter resets",
+			red:     "delta",
+			vars:    counter,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(10))}},
+		},
+		{
+			name:    "delta empty series",
+			red:     "delta",
+			vars:    seriesEmpty,
+			results: Results{[]Value{makeNumber("", nil, NaN)}},
+		},
+		{
+			name:    "count_non_null",
+			red:     "count_non_null",
+			vars:    seriesNonNumbers,
+			results: Results{[]Value{makeNumber("", nil, float64Pointer(2))}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Results{}
+			seriesSet := tt.vars["A"]
+			for _, series := range seriesSet.Values {
+				ns, err := series.Value().(*Series).Reduce("", tt.red, nil)
+				require.NoError(t, err)
+				results.Values = append(results.Values, ns)
+			}
+			opt := cmp.Comparer(func(x, y float64) bool {
+				return (math.IsNaN(x) && math.IsNaN(y)) || math.Abs(x-y) < 1e-9
+			})
+			options := append([]cmp.Option{opt}, data.FrameTestCompareOptions()...)
+			if diff := cmp.Diff(tt.results, results, options...); diff != "" {
+				t.Errorf("Result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
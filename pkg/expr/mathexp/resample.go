@@ -7,15 +7,54 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
-// Resample turns the Series into a Number based on the given reduction function
-func (s Series) Resample(refID string, interval time.Duration, downsampler string, upsampler string, from, to time.Time) (Series, error) {
+// ResampleOptions holds the optional settings of Series.Resample.
+type ResampleOptions struct {
+	// MaxGap is the longest gap between observations that upsampling fills.
+	// Samples in longer gaps are null. Zero means there is no limit.
+	MaxGap time.Duration
+	// AlignToClock aligns the samples to multiples of the interval in UTC, e.g. on the hour
+	// for an interval of 1h, rather than to the start of the time range.
+	AlignToClock bool
+}
+
+// Resample turns the Series into a Series with a point every interval between from and to.
+// When there are several points in an interval they are reduced with the downsampler, which
+// may be any reducer supported by GetReduceFunc. When there are none the value is set by
+// the upsampler:
+//   - pad: the last observation is carried forward
+//   - backfilling: the next observation is carried backwards
+//   - linear: the value is interpolated between the last and the next observation
+//   - fillna: the value is null
+func (s Series) Resample(refID string, interval time.Duration, downsampler string, upsampler string, from, to time.Time, opts ResampleOptions) (Series, error) {
+	if opts.AlignToClock {
+		aligned := from.Truncate(interval)
+		if aligned.Before(from) {
+			aligned = aligned.Add(interval)
+		}
+		from = aligned
+	}
 	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
 	}
+	reduce, err := GetReduceFunc(downsampler)
+	if err != nil {
+		return s, fmt.Errorf("downsampling %v not implemented", downsampler)
+	}
+	switch upsampler {
+	case "pad", "backfilling", "linear", "fillna":
+	default:
+		return s, fmt.Errorf("upsampling %v not implemented", upsampler)
+	}
+	withinGap := func(gap time.Duration) bool {
+		return opts.MaxGap <= 0 || gap <= opts.MaxGap
+	}
+
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
 	bookmark := 0
 	var lastSeen *float64
+	var lastSeenTime time.Time
+	hasLastSeen := false
 	idx := 0
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
@@ -31,46 +70,36 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler strin
 			}
 			bookmark++
 			sIdx++
-			lastSeen = v
+			lastSeen, lastSeenTime, hasLastSeen = v, st, true
 			vals = append(vals, v)
 		}
 		var value *float64
 		if len(vals) == 0 { // upsampling
+			hasNext := sIdx < s.Len()
 			switch upsampler {
 			case "pad":
-				if lastSeen != nil {
+				if hasLastSeen && withinGap(t.Sub(lastSeenTime)) {
 					value = lastSeen
-				} else {
-					value = nil
 				}
 			case "backfilling":
-				if sIdx == s.Len() { // no vals left
-					value = nil
-				} else {
-					_, value = s.GetPoint(sIdx)
+				if hasNext {
+					nextTime, next := s.GetPoint(sIdx)
+					if withinGap(nextTime.Sub(t)) {
+						value = next
+					}
+				}
+			case "linear":
+				if hasLastSeen && hasNext {
+					nextTime, next := s.GetPoint(sIdx)
+					if lastSeen != nil && next != nil && withinGap(nextTime.Sub(lastSeenTime)) {
+						value = interpolate(lastSeenTime, *lastSeen, nextTime, *next, t)
+					}
 				}
-			case "fillna":
-				value = nil
-			default:
-				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
 		} else { // downsampling
 			fVec := data.NewField("", s.GetLabels(), vals)
 			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case "sum":
-				tmp = Sum(&ff)
-			case "mean":
-				tmp = Avg(&ff)
-			case "min":
-				tmp = Min(&ff)
-			case "max":
-				tmp = Max(&ff)
-			default:
-				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
-			}
-			value = tmp
+			value = reduce(&ff)
 		}
 		resampled.SetPoint(idx, t, value)
 		t = t.Add(interval)
@@ -78,3 +107,12 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler strin
 	}
 	return resampled, nil
 }
+
+// interpolate returns the value at t on the line between the points (t1, v1) and (t2, v2).
+func interpolate(t1 time.Time, v1 float64, t2 time.Time, v2 float64, t time.Time) *float64 {
+	f := v1
+	if span := t2.Sub(t1); span > 0 {
+		f = v1 + (v2-v1)*float64(t.Sub(t1))/float64(span)
+	}
+	return &f
+}
@@ -248,7 +248,7 @@ func TestResampleSeries(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			series, err := tt.seriesToResample.Resample("", tt.interval, tt.downsampler, tt.upsampler, tt.timeRange.From, tt.timeRange.To)
+			series, err := tt.seriesToResample.Resample("", tt.interval, tt.downsampler, tt.upsampler, tt.timeRange.From, tt.timeRange.To, ResampleOptions{})
 			if tt.series.Frame == nil {
 				require.Error(t, err)
 			} else {
@@ -258,3 +258,102 @@ func TestResampleSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestResampleSeriesOptions(t *testing.T) {
+	irregular := makeSeries("", nil, tp{
+		time.Unix(10, 0), float64Pointer(10),
+	}, tp{
+		time.Unix(12, 0), float64Pointer(20),
+	}, tp{
+		time.Unix(52, 0), float64Pointer(60),
+	})
+
+	var tests = []struct {
+		name        string
+		interval    time.Duration
+		downsampler string
+		upsampler   string
+		from, to    time.Time
+		opts        ResampleOptions
+		series      Series
+	}{
+		{
+			name:        "linear interpolation",
+			interval:    time.Second * 10,
+			downsampler: "mean",
+			upsampler:   "linear",
+			from:        time.Unix(0, 0),
+			to:          time.Unix(60, 0),
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), nil},
+				tp{time.Unix(10, 0), float64Pointer(10)},
+				tp{time.Unix(20, 0), float64Pointer(20)},
+				tp{time.Unix(30, 0), float64Pointer(38)},
+				tp{time.Unix(40, 0), float64Pointer(48)},
+				tp{time.Unix(50, 0), float64Pointer(58)},
+				tp{time.Unix(60, 0), float64Pointer(60)},
+			),
+		},
+		{
+			name:        "last observation carried forward with max gap",
+			interval:    time.Second * 10,
+			downsampler: "last",
+			upsampler:   "pad",
+			from:        time.Unix(0, 0),
+			to:          time.Unix(60, 0),
+			opts:        ResampleOptions{MaxGap: time.Second * 20},
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), nil},
+				tp{time.Unix(10, 0), float64Pointer(10)},
+				tp{time.Unix(20, 0), float64Pointer(20)},
+				tp{time.Unix(30, 0), float64Pointer(20)},
+				tp{time.Unix(40, 0), nil},
+				tp{time.Unix(50, 0), nil},
+				tp{time.Unix(60, 0), float64Pointer(60)},
+			),
+		},
+		{
+			name:        "linear interpolation does not fill gaps longer than max gap",
+			interval:    time.Second * 10,
+			downsampler: "max",
+			upsampler:   "linear",
+			from:        time.Unix(10, 0),
+			to:          time.Unix(60, 0),
+			opts:        ResampleOptions{MaxGap: time.Second * 30},
+			series: makeSeries("", nil,
+				tp{time.Unix(10, 0), float64Pointer(10)},
+				tp{time.Unix(20, 0), float64Pointer(20)},
+				tp{time.Unix(30, 0), nil},
+				tp{time.Unix(40, 0), nil},
+				tp{time.Unix(50, 0), nil},
+				tp{time.Unix(60, 0), float64Pointer(60)},
+			),
+		},
+		{
+			name:        "aligned to the clock with any reducer",
+			interval:    time.Second * 20,
+			downsampler: "count",
+			upsampler:   "fillna",
+			from:        time.Unix(5, 0),
+			to:          time.Unix(60, 0),
+			opts:        ResampleOptions{AlignToClock: true},
+			series: makeSeries("", nil,
+				tp{time.Unix(20, 0), float64Pointer(2)},
+				tp{time.Unix(40, 0), nil},
+				tp{time.Unix(60, 0), float64Pointer(1)},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := irregular.Resample("", tt.interval, tt.downsampler, tt.upsampler, tt.from, tt.to, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.series, series)
+		})
+	}
+
+	t.Run("unknown downsampler", func(t *testing.T) {
+		_, err := irregular.Resample("", time.Second*10, "unknown", "pad", time.Unix(0, 0), time.Unix(60, 0), ResampleOptions{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+package mathexp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// matchingUnion creates Union objects based on an explicit label matching of the two
+// sides of a binary operation such as "$A / on(deployment) group_left $B". Items are
+// paired when their labels are equal after applying the on(...) or ignoring(...) clause.
+// Unlike union, it returns an error when the matching is ambiguous rather than
+// silently dropping or duplicating items.
+func matchingUnion(aResults, bResults Results, m *parse.LabelMatching) ([]*Union, error) {
+	unions := []*Union{}
+	switch m.Group {
+	case parse.GroupNone:
+		// both sides are checked so that duplicates are reported even if they have no match
+		if _, err := signatures(aResults, m, "left"); err != nil {
+			return nil, err
+		}
+		bSigs, err := signatures(bResults, m, "right")
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range aResults.Values {
+			// the result has the matching labels, which with ignoring(...) are all the others
+			labels := matchLabels(a.GetLabels(), m)
+			b, ok := bSigs[labelsKey(labels)]
+			if !ok {
+				continue
+			}
+			unions = append(unions, &Union{Labels: labels, A: a, B: b})
+		}
+	case parse.GroupLeft:
+		bSigs, err := signatures(bResults, m, "right")
+		if err != nil {
+			return nil, err
+		}
+		return groupUnions(aResults, bSigs, m, func(many, one Value, labels data.Labels) *Union {
+			return &Union{Labels: labels, A: many, B: one}
+		})
+	case parse.GroupRight:
+		aSigs, err := signatures(aResults, m, "left")
+		if err != nil {
+			return nil, err
+		}
+		return groupUnions(bResults, aSigs, m, func(many, one Value, labels data.Labels) *Union {
+			return &Union{Labels: labels, A: one, B: many}
+		})
+	default:
+		return nil, fmt.Errorf("unknown label matching group %v", m.Group)
+	}
+	return unions, nil
+}
+
+// groupUnions pairs each item of the "many" side with the item of the "one" side
+// that has the same matching labels. The labels of the result are the labels of the
+// "many" item, plus the labels listed in the group_left(...) or group_right(...)
+// clause, which are taken from the "one" item.
+func groupUnions(many Results, oneSigs map[string]Value, m *parse.LabelMatching, newUnion func(many, one Value, labels data.Labels) *Union) ([]*Union, error) {
+	unions := []*Union{}
+	seen := map[string]struct{}{}
+	for _, val := range many.Values {
+		one, ok := oneSigs[labelsKey(matchLabels(val.GetLabels(), m))]
+		if !ok {
+			continue
+		}
+		labels := val.GetLabels().Copy()
+		if labels == nil {
+			labels = data.Labels{}
+		}
+		oneLabels := one.GetLabels()
+		for _, l := range m.Include {
+			if v, ok := oneLabels[l]; ok {
+				labels[l] = v
+			} else {
+				delete(labels, l)
+			}
+		}
+		key := labelsKey(labels)
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("multiple matches for labels %v: the grouping labels must result in unique labels for each item", labels)
+		}
+		seen[key] = struct{}{}
+		unions = append(unions, newUnion(val, one, labels))
+	}
+	return unions, nil
+}
+
+// signatures returns the items of res by the key of their matching labels.
+// It returns an error if several items have the same matching labels.
+func signatures(res Results, m *parse.LabelMatching, side string) (map[string]Value, error) {
+	sigs := make(map[string]Value, len(res.Values))
+	for _, val := range res.Values {
+		sig := matchLabels(val.GetLabels(), m)
+		key := labelsKey(sig)
+		if _, ok := sigs[key]; ok {
+			if m.Group == parse.GroupNone {
+				return nil, fmt.Errorf("found duplicate items for the matching labels %v on the %s side of the operation: use group_left or group_right to match many-to-one", sig, side)
+			}
+			return nil, fmt.Errorf("found duplicate items for the matching labels %v on the %s side of the operation: only one item per match group is allowed on the side of the operation that is not grouped", sig, side)
+		}
+		sigs[key] = val
+	}
+	return sigs, nil
+}
+
+// matchLabels returns the labels that are used to match items, that is the labels
+// listed in an on(...) clause or all but the labels listed in an ignoring(...) clause.
+func matchLabels(labels data.Labels, m *parse.LabelMatching) data.Labels {
+	sig := data.Labels{}
+	if m.On {
+		for _, l := range m.Labels {
+			if v, ok := labels[l]; ok {
+				sig[l] = v
+			}
+		}
+		return sig
+	}
+	for k, v := range labels {
+		sig[k] = v
+	}
+	for _, l := range m.Labels {
+		delete(sig, l)
+	}
+	return sig
+}
+
+// labelsKey returns a string that uniquely identifies the set of labels.
+func labelsKey(labels data.Labels) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte(0xff)
+		sb.WriteString(labels[k])
+		sb.WriteByte(0xff)
+	}
+	return sb.String()
+}
@@ -242,3 +242,165 @@ func Test_union(t *testing.T) {
 		})
 	}
 }
+
+func TestLabelMatching(t *testing.T) {
+	podErrors := Vars{
+		"A": Results{
+			Values: Values{
+				makeNumber("", data.Labels{"pod": "a-1", "deployment": "a"}, float64Pointer(1)),
+				makeNumber("", data.Labels{"pod": "a-2", "deployment": "a"}, float64Pointer(3)),
+				makeNumber("", data.Labels{"pod": "b-1", "deployment": "b"}, float64Pointer(2)),
+			},
+		},
+		"B": Results{
+			Values: Values{
+				makeNumber("", data.Labels{"deployment": "a", "team": "red"}, float64Pointer(4)),
+				makeNumber("", data.Labels{"deployment": "b", "team": "blue"}, float64Pointer(8)),
+				makeNumber("", data.Labels{"deployment": "c", "team": "blue"}, float64Pointer(16)),
+			},
+		},
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  assert.ErrorAssertionFunc
+		execErrIs assert.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "group_left keeps the labels of the left side",
+			expr:      "$A / on(deployment) group_left $B",
+			vars:      podErrors,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: Results{
+				Values: Values{
+					makeNumber("", data.Labels{"pod": "a-1", "deployment": "a"}, float64Pointer(0.25)),
+					makeNumber("", data.Labels{"pod": "a-2", "deployment": "a"}, float64Pointer(0.75)),
+					makeNumber("", data.Labels{"pod": "b-1", "deployment": "b"}, float64Pointer(0.25)),
+				},
+			},
+		},
+		{
+			name:      "group_left copies the included labels from the right side",
+			expr:      "$A / on(deployment) group_left(team) $B",
+			vars:      podErrors,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: Results{
+				Values: Values{
+					makeNumber("", data.Labels{"pod": "a-1", "deployment": "a", "team": "red"}, float64Pointer(0.25)),
+					makeNumber("", data.Labels{"pod": "a-2", "deployment": "a", "team": "red"}, float64Pointer(0.75)),
+					makeNumber("", data.Labels{"pod": "b-1", "deployment": "b", "team": "blue"}, float64Pointer(0.25)),
+				},
+			},
+		},
+		{
+			name:      "group_right keeps the operand order",
+			expr:      "$B - ignoring(team, pod) group_right $A",
+			vars:      podErrors,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: Results{
+				Values: Values{
+					makeNumber("", data.Labels{"pod": "a-1", "deployment": "a"}, float64Pointer(3)),
+					makeNumber("", data.Labels{"pod": "a-2", "deployment": "a"}, float64Pointer(1)),
+					makeNumber("", data.Labels{"pod": "b-1", "deployment": "b"}, float64Pointer(6)),
+				},
+			},
+		},
+		{
+			name: "one-to-one on labels",
+			expr: `$A + on("id") $B`,
+			vars: Vars{
+				"A": Results{Values: Values{
+					makeNumber("", data.Labels{"id": "1", "host": "x"}, float64Pointer(1)),
+					makeNumber("", data.Labels{"id": "2", "host": "x"}, float64Pointer(2)),
+				}},
+				"B": Results{Values: Values{
+					makeNumber("", data.Labels{"id": "2", "host": "y"}, float64Pointer(10)),
+				}},
+			},
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: Results{
+				Values: Values{
+					makeNumber("", data.Labels{"id": "2"}, float64Pointer(12)),
+				},
+			},
+		},
+		{
+			name: "one-to-one ignoring labels",
+			expr: "$A * ignoring(host) $B",
+			vars: Vars{
+				"A": Results{Values: Values{
+					makeNumber("", data.Labels{"id": "1", "host": "x"}, float64Pointer(2)),
+				}},
+				"B": Results{Values: Values{
+					makeNumber("", data.Labels{"id": "1"}, float64Pointer(3)),
+				}},
+			},
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: Results{
+				Values: Values{
+					makeNumber("", data.Labels{"id": "1"}, float64Pointer(6)),
+				},
+			},
+		},
+		{
+			name:      "one-to-one with duplicates is ambiguous",
+			expr:      "$A / on(deployment) $B",
+			vars:      podErrors,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.Error,
+		},
+		{
+			name:      "group_left with duplicates on the right side is ambiguous",
+			expr:      "$B / on(team) group_left $A",
+			vars:      podErrors,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.Error,
+		},
+		{
+			name:      "group_left that results in duplicate labels is ambiguous",
+			expr:      "$A / on(deployment) group_left(pod) $B",
+			vars:      podErrors,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.Error,
+		},
+		{
+			name:     "group_left without on or ignoring is a parse error",
+			expr:     "$A / group_left $B",
+			newErrIs: assert.Error,
+		},
+		{
+			name:     "label in both on and group_left is a parse error",
+			expr:     "$A / on(deployment) group_left(deployment) $B",
+			newErrIs: assert.Error,
+		},
+		{
+			name:     "label matching with a scalar is a parse error",
+			expr:     "$A / on(deployment) 2",
+			newErrIs: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e == nil {
+				return
+			}
+			res, err := e.Execute("", tt.vars)
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.results, res)
+		})
+	}
+}
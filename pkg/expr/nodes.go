@@ -119,6 +119,10 @@ func buildCMDNode(dp *simple.DirectedGraph, rn *rawNode) (*CMDNode, error) {
 		node.Command, err = UnmarshalResampleCommand(rn)
 	case TypeClassicConditions:
 		node.Command, err = classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
+	case TypeThreshold:
+		node.Command, err = UnmarshalThresholdCommand(rn)
+	case TypeSQL:
+		node.Command, err = UnmarshalSQLCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in '%v' not implemented", commandType, rn.RefID)
 	}
@@ -146,6 +150,11 @@ type DSNode struct {
 	intervalMS int64
 	maxDP      int64
 	request    Request
+
+	// isInputToSQLExpr is true when the results are used by an SQL expression, in which
+	// case tables which are neither series nor numbers are returned as is instead of failing
+	// the conversion to series.
+	isInputToSQLExpr bool
 }
 
 // NodeType returns the data pipeline node type.
@@ -264,6 +273,13 @@ func (dn *DSNode) Execute(ctx context.Context, vars mathexp.Vars, s *Service) (m
 				logger.Warn("ignoring InfluxDB data frame due to missing numeric fields", "frame", frame)
 				continue
 			}
+			// Tables which are neither series nor numbers are kept as is, only SQL expressions
+			// can use them.
+			if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeNot && dn.isInputToSQLExpr {
+				logger.Debug("expression datasource query (table)", "query", refID)
+				vals = append(vals, mathexp.TableData{Frame: frame})
+				continue
+			}
 			series, err := WideToMany(frame)
 			if err != nil {
 				return mathexp.Results{}, err
@@ -91,6 +91,109 @@ func TestService(t *testing.T) {
 	}
 }
 
+func TestServiceQueryUsedByMathAndSQL(t *testing.T) {
+	dsDF := data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+		data.NewField("value", data.Labels{"host": "web01"}, []*float64{fp(2), fp(3)}))
+
+	s := Service{
+		cfg:            setting.NewCfg(),
+		dataService:    &mockEndpoint{Frames: []*data.Frame{dsDF}},
+		secretsService: secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore()),
+	}
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &models.DataSource{
+				OrgId: 1,
+				Uid:   "test",
+				Type:  "test",
+			},
+			JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+		},
+		{
+			RefID:      "B",
+			DataSource: DataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`),
+		},
+		{
+			RefID:      "C",
+			DataSource: DataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "sql", "expression": "SELECT host, max(value) AS value FROM A GROUP BY host" }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries})
+	require.NoError(t, err)
+
+	res, err := s.ExecutePipeline(context.Background(), pl)
+	require.NoError(t, err)
+
+	require.NoError(t, res.Responses["B"].Error)
+	require.Len(t, res.Responses["B"].Frames, 1)
+	bValues := res.Responses["B"].Frames[0].Fields[1]
+	require.Equal(t, fp(4), bValues.At(0))
+	require.Equal(t, fp(6), bValues.At(1))
+
+	require.NoError(t, res.Responses["C"].Error)
+	require.Len(t, res.Responses["C"].Frames, 1)
+	cValue := res.Responses["C"].Frames[0].Fields[0]
+	require.Equal(t, data.Labels{"host": "web01"}, cValue.Labels)
+	require.Equal(t, fp(3), cValue.At(0))
+}
+
+func TestServiceTableQuery(t *testing.T) {
+	dsDF := data.NewFrame("test",
+		data.NewField("host", nil, []string{"web01", "web02"}),
+		data.NewField("cpu", nil, []*float64{fp(2), fp(3)}),
+		data.NewField("mem", nil, []*float64{fp(20), fp(30)}))
+
+	s := Service{
+		cfg:            setting.NewCfg(),
+		dataService:    &mockEndpoint{Frames: []*data.Frame{dsDF}},
+		secretsService: secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore()),
+	}
+
+	execute := func(t *testing.T, expr string) (*backend.QueryDataResponse, error) {
+		t.Helper()
+		queries := []Query{
+			{
+				RefID: "A",
+				DataSource: &models.DataSource{
+					OrgId: 1,
+					Uid:   "test",
+					Type:  "test",
+				},
+				JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			},
+			{
+				RefID:      "B",
+				DataSource: DataSourceModel(),
+				JSON:       json.RawMessage(expr),
+			},
+		}
+		pl, err := s.BuildPipeline(&Request{Queries: queries})
+		require.NoError(t, err)
+		return s.ExecutePipeline(context.Background(), pl)
+	}
+
+	t.Run("tables are kept as is for SQL expressions", func(t *testing.T) {
+		res, err := execute(t, `{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "sql", "expression": "SELECT host, mem AS value FROM A WHERE host = 'web02'" }`)
+		require.NoError(t, err)
+		require.NoError(t, res.Responses["B"].Error)
+		require.Len(t, res.Responses["B"].Frames, 1)
+		value := res.Responses["B"].Frames[0].Fields[0]
+		require.Equal(t, data.Labels{"host": "web02"}, value.Labels)
+		require.Equal(t, fp(30), value.At(0))
+	})
+
+	t.Run("tables can not be used by math expressions", func(t *testing.T) {
+		_, err := execute(t, `{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`)
+		require.ErrorContains(t, err, "input data must be a wide series")
+	})
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
@@ -0,0 +1,336 @@
+// Package sql runs SQL queries over data frames. The frames are loaded as tables into an
+// in-memory SQLite database that only lives for the duration of the query.
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Query loads the frames of each table into an in-memory database and runs the query,
+// which must be a single read only statement such as SELECT. The result is returned as
+// a frame with the given name.
+//
+// The columns of a table are the fields of its frames plus a string column for each label
+// key of these fields. Frames of the same table are appended to each other; a column that
+// is missing from a frame is null for its rows.
+func Query(ctx context.Context, name, query string, tables map[string][]*data.Frame) (*data.Frame, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	// every connection to :memory: has its own database, so everything must use the same one
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	names := make([]string, 0, len(tables))
+	for tableName := range tables {
+		names = append(names, tableName)
+	}
+	sort.Strings(names)
+	for _, tableName := range names {
+		if err := loadTable(ctx, conn, tableName, tables[tableName]); err != nil {
+			return nil, err
+		}
+	}
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected sql driver connection %T", driverConn)
+		}
+		c.RegisterAuthorizer(readOnlyAuthorizer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	return rowsToFrame(name, rows)
+}
+
+// sqliteRecursive is the SQLITE_RECURSIVE authorizer action, which the driver does not export.
+const sqliteRecursive = 33
+
+// readOnlyAuthorizer denies everything but reading the tables, so that a query can
+// neither change the database nor attach another one, e.g. from a file.
+func readOnlyAuthorizer(action int, _, _, _ string) int {
+	switch action {
+	case sqlite3.SQLITE_SELECT, sqlite3.SQLITE_READ, sqlite3.SQLITE_FUNCTION, sqliteRecursive:
+		return sqlite3.SQLITE_OK
+	default:
+		return sqlite3.SQLITE_DENY
+	}
+}
+
+type column struct {
+	name      string
+	fieldType data.FieldType
+	values    []interface{}
+}
+
+func loadTable(ctx context.Context, conn *sql.Conn, tableName string, frames []*data.Frame) error {
+	var columns []*column
+	byName := map[string]*column{}
+	rowCount := 0
+
+	getColumn := func(name string, ft data.FieldType) (*column, error) {
+		ft = ft.NonNullableType()
+		c, ok := byName[name]
+		if !ok {
+			c = &column{name: name, fieldType: ft, values: make([]interface{}, rowCount)}
+			byName[name] = c
+			columns = append(columns, c)
+			return c, nil
+		}
+		if c.fieldType != ft {
+			return nil, fmt.Errorf("column %q of table %q has type %s and %s", name, tableName, c.fieldType.ItemTypeString(), ft.ItemTypeString())
+		}
+		return c, nil
+	}
+
+	for _, frame := range frames {
+		frameColumns := make([]*column, len(frame.Fields))
+		labelColumns := map[*column]string{}
+		for i, field := range frame.Fields {
+			fieldName := field.Name
+			if fieldName == "" {
+				fieldName = fmt.Sprintf("field%d", i+1)
+			}
+			c, err := getColumn(fieldName, field.Type())
+			if err != nil {
+				return err
+			}
+			frameColumns[i] = c
+			for k, v := range field.Labels {
+				c, err := getColumn(k, data.FieldTypeString)
+				if err != nil {
+					return err
+				}
+				if existing, ok := labelColumns[c]; ok && existing != v {
+					return fmt.Errorf("label %q of table %q has different values within the same frame", k, tableName)
+				}
+				labelColumns[c] = v
+			}
+		}
+
+		for row := 0; row < frame.Rows(); row++ {
+			for _, c := range columns {
+				c.values = append(c.values, nil)
+			}
+			for i, field := range frame.Fields {
+				if v, ok := field.ConcreteAt(row); ok {
+					frameColumns[i].values[rowCount] = v
+				}
+			}
+			for c, v := range labelColumns {
+				c.values[rowCount] = v
+			}
+			rowCount++
+		}
+	}
+
+	if len(columns) == 0 {
+		return fmt.Errorf("table %q has no columns", tableName)
+	}
+
+	defs := make([]string, len(columns))
+	colNames := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, c := range columns {
+		colNames[i] = quoteIdentifier(c.name)
+		defs[i] = colNames[i] + " " + sqliteType(c.fieldType)
+		placeholders[i] = "?"
+	}
+	createStmt := fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdentifier(tableName), strings.Join(defs, ", "))
+	if _, err := conn.ExecContext(ctx, createStmt); err != nil {
+		return fmt.Errorf("failed to create table %q: %w", tableName, err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	insertStmt := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdentifier(tableName), strings.Join(colNames, ", "), strings.Join(placeholders, ", "))
+	stmt, err := tx.PrepareContext(ctx, insertStmt)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	args := make([]interface{}, len(columns))
+	for row := 0; row < rowCount; row++ {
+		for i, c := range columns {
+			args[i] = c.values[row]
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to insert into table %q: %w", tableName, err)
+		}
+	}
+	if err := stmt.Close(); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func sqliteType(ft data.FieldType) string {
+	switch {
+	case ft == data.FieldTypeFloat64 || ft == data.FieldTypeFloat32:
+		return "REAL"
+	case ft.Numeric():
+		return "INTEGER"
+	case ft.Time():
+		return "TIMESTAMP"
+	case ft == data.FieldTypeBool:
+		return "BOOLEAN"
+	default:
+		return "TEXT"
+	}
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// rowsToFrame converts the result of a query to a frame. SQLite columns have no fixed type,
+// so the type of each field is derived from the values of its column.
+func rowsToFrame(name string, rows *sql.Rows) (*data.Frame, error) {
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]interface{}, len(colTypes))
+	scanArgs := make([]interface{}, len(colTypes))
+	for rows.Next() {
+		row := make([]interface{}, len(colTypes))
+		for i := range row {
+			scanArgs[i] = &row[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+		for i, v := range row {
+			values[i] = append(values[i], v)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	frame := data.NewFrame(name)
+	for i, ct := range colTypes {
+		frame.Fields = append(frame.Fields, valuesToField(ct.Name(), ct.DatabaseTypeName(), values[i]))
+	}
+	return frame, nil
+}
+
+func valuesToField(name, dbType string, values []interface{}) *data.Field {
+	kind := ""
+	for _, v := range values {
+		k := ""
+		switch v.(type) {
+		case nil:
+			continue
+		case int64:
+			k = "int"
+		case float64:
+			k = "float"
+		case bool:
+			k = "bool"
+		case time.Time:
+			k = "time"
+		default:
+			k = "string"
+		}
+		switch {
+		case kind == "" || kind == k:
+			kind = k
+		case (kind == "int" && k == "float") || (kind == "float" && k == "int"):
+			kind = "float"
+		default:
+			kind = "string"
+		}
+	}
+	if kind == "" {
+		switch strings.ToUpper(dbType) {
+		case "INTEGER", "REAL":
+			kind = "float"
+		default:
+			kind = "string"
+		}
+	}
+
+	switch kind {
+	case "int":
+		vals := make([]*int64, len(values))
+		for i, v := range values {
+			if v != nil {
+				n := v.(int64)
+				vals[i] = &n
+			}
+		}
+		return data.NewField(name, nil, vals)
+	case "float":
+		vals := make([]*float64, len(values))
+		for i, v := range values {
+			switch n := v.(type) {
+			case int64:
+				f := float64(n)
+				vals[i] = &f
+			case float64:
+				vals[i] = &n
+			}
+		}
+		return data.NewField(name, nil, vals)
+	case "bool":
+		vals := make([]*bool, len(values))
+		for i, v := range values {
+			if v != nil {
+				b := v.(bool)
+				vals[i] = &b
+			}
+		}
+		return data.NewField(name, nil, vals)
+	case "time":
+		vals := make([]*time.Time, len(values))
+		for i, v := range values {
+			if v != nil {
+				t := v.(time.Time)
+				vals[i] = &t
+			}
+		}
+		return data.NewField(name, nil, vals)
+	default:
+		vals := make([]*string, len(values))
+		for i, v := range values {
+			switch s := v.(type) {
+			case nil:
+			case []byte:
+				str := string(s)
+				vals[i] = &str
+			default:
+				str := fmt.Sprintf("%v", s)
+				vals[i] = &str
+			}
+		}
+		return data.NewField(name, nil, vals)
+	}
+}
@@ -0,0 +1,84 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	hosts := data.NewFrame("",
+		data.NewField("host", nil, []string{"web01", "web02", "db01"}),
+		data.NewField("team", nil, []*string{strPtr("frontend"), strPtr("frontend"), nil}),
+	)
+	cpu := []*data.Frame{
+		data.NewFrame("",
+			data.NewField("value", data.Labels{"host": "web01"}, []float64{90}),
+		),
+		data.NewFrame("",
+			data.NewField("value", data.Labels{"host": "web02"}, []float64{70}),
+		),
+		data.NewFrame("",
+			data.NewField("value", data.Labels{"host": "db01"}, []float64{50}),
+		),
+	}
+
+	t.Run("joins tables", func(t *testing.T) {
+		frame, err := Query(context.Background(), "C", `
+			SELECT h.team, max(c.value) AS max_cpu, count(*) AS hosts
+			FROM cpu c JOIN "hosts" h ON h.host = c.host
+			WHERE h.team IS NOT NULL
+			GROUP BY h.team`,
+			map[string][]*data.Frame{"hosts": {hosts}, "cpu": cpu})
+		require.NoError(t, err)
+		require.Equal(t, "C", frame.Name)
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, "team", frame.Fields[0].Name)
+		require.Equal(t, strPtr("frontend"), frame.Fields[0].At(0))
+		require.Equal(t, float64Ptr(90), frame.Fields[1].At(0))
+		require.Equal(t, int64Ptr(2), frame.Fields[2].At(0))
+	})
+
+	t.Run("keeps times", func(t *testing.T) {
+		ts := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+		series := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{ts, ts.Add(time.Minute)}),
+			data.NewField("value", data.Labels{"host": "web01"}, []float64{1, 2}),
+		)
+		frame, err := Query(context.Background(), "B", "SELECT time, value FROM A ORDER BY time DESC LIMIT 1",
+			map[string][]*data.Frame{"A": {series}})
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+		tm, ok := frame.Fields[0].At(0).(*time.Time)
+		require.True(t, ok)
+		require.True(t, ts.Add(time.Minute).Equal(*tm))
+	})
+
+	t.Run("rejects statements that are not read only", func(t *testing.T) {
+		for _, q := range []string{
+			"DELETE FROM A",
+			"DROP TABLE A",
+			"ATTACH DATABASE 'test.db' AS other",
+			"PRAGMA table_info(A)",
+		} {
+			_, err := Query(context.Background(), "B", q, map[string][]*data.Frame{"A": {hosts}})
+			require.Error(t, err, q)
+		}
+	})
+
+	t.Run("error on conflicting column types", func(t *testing.T) {
+		_, err := Query(context.Background(), "B", "SELECT * FROM A", map[string][]*data.Frame{"A": {
+			data.NewFrame("", data.NewField("v", nil, []float64{1})),
+			data.NewFrame("", data.NewField("v", nil, []string{"1"})),
+		}})
+		require.Error(t, err)
+	})
+}
+
+func strPtr(s string) *string       { return &s }
+func float64Ptr(f float64) *float64 { return &f }
+func int64Ptr(i int64) *int64       { return &i }
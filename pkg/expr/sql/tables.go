@@ -0,0 +1,159 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenType int
+
+const (
+	tokenWord tokenType = iota
+	tokenQuotedIdentifier
+	tokenString
+	tokenSymbol
+)
+
+type token struct {
+	typ tokenType
+	val string
+}
+
+// keywords that can follow a table name in a FROM or JOIN clause and therefore are not table aliases.
+var clauseKeywords = map[string]bool{
+	"WHERE": true, "GROUP": true, "ORDER": true, "LIMIT": true, "HAVING": true, "WINDOW": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "OUTER": true, "CROSS": true,
+	"NATURAL": true, "ON": true, "USING": true, "UNION": true, "EXCEPT": true, "INTERSECT": true,
+	"OFFSET": true, "AS": true,
+}
+
+// TablesList returns the names of the tables the SQL query reads from, in order of appearance
+// and without duplicates. Names of common table expressions defined by the query are excluded.
+func TablesList(query string) ([]string, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ctes := map[string]bool{}
+	for i := 0; i+2 < len(tokens); i++ {
+		// a common table expression is the only construct of the form: name AS (
+		if isIdentifier(tokens[i]) && isKeyword(tokens[i+1], "AS") && tokens[i+2].val == "(" {
+			ctes[strings.ToLower(tokens[i].val)] = true
+		}
+	}
+
+	var tables []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if ctes[strings.ToLower(name)] || seen[name] {
+			return
+		}
+		seen[name] = true
+		tables = append(tables, name)
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		fromClause := isKeyword(tokens[i], "FROM")
+		if !fromClause && !isKeyword(tokens[i], "JOIN") {
+			continue
+		}
+		for i+1 < len(tokens) && isIdentifier(tokens[i+1]) {
+			i++
+			name := tokens[i].val
+			// schema qualified names, e.g. main.A
+			for i+2 < len(tokens) && tokens[i+1].val == "." && isIdentifier(tokens[i+2]) {
+				name = tokens[i+2].val
+				i += 2
+			}
+			add(name)
+
+			// optional alias
+			if i+2 < len(tokens) && isKeyword(tokens[i+1], "AS") && isIdentifier(tokens[i+2]) {
+				i += 2
+			} else if i+1 < len(tokens) && isIdentifier(tokens[i+1]) && !clauseKeywords[strings.ToUpper(tokens[i+1].val)] {
+				i++
+			}
+
+			// only a FROM clause may list several tables
+			if !fromClause || i+1 >= len(tokens) || tokens[i+1].val != "," {
+				break
+			}
+			i++
+		}
+	}
+	return tables, nil
+}
+
+func isKeyword(t token, keyword string) bool {
+	return t.typ == tokenWord && strings.EqualFold(t.val, keyword)
+}
+
+func isIdentifier(t token) bool {
+	return t.typ == tokenWord || t.typ == tokenQuotedIdentifier
+}
+
+// tokenize splits an SQL query into words, quoted identifiers, string literals and symbols.
+// Comments and whitespace are dropped. The quotes of identifiers are removed.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			j := i + 2
+			for j+1 < len(runes) && (runes[j] != '*' || runes[j+1] != '/') {
+				j++
+			}
+			if j+1 >= len(runes) {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i = j + 1
+		case r == '\'' || r == '"' || r == '`' || r == '[':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == closing {
+					// a doubled quote is an escaped quote
+					if closing != ']' && j+1 < len(runes) && runes[j+1] == closing {
+						sb.WriteRune(closing)
+						j++
+						continue
+					}
+					break
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated quote %q", string(r))
+			}
+			typ := tokenQuotedIdentifier
+			if r == '\'' {
+				typ = tokenString
+			}
+			tokens = append(tokens, token{typ: typ, val: sb.String()})
+			i = j
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (runes[j] == '_' || runes[j] == '$' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, token{typ: tokenWord, val: string(runes[i:j])})
+			i = j - 1
+		default:
+			tokens = append(tokens, token{typ: tokenSymbol, val: string(r)})
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,69 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTablesList(t *testing.T) {
+	var tests = []struct {
+		name    string
+		query   string
+		tables  []string
+		isError bool
+	}{
+		{
+			name:   "single table",
+			query:  "SELECT * FROM A",
+			tables: []string{"A"},
+		},
+		{
+			name:   "joins and aliases",
+			query:  `SELECT a.value FROM A AS a LEFT JOIN "B" b ON a.host = b.host INNER JOIN [C] ON 1 = 1`,
+			tables: []string{"A", "B", "C"},
+		},
+		{
+			name:   "comma separated tables",
+			query:  "select * from A a, B where a.x = B.x",
+			tables: []string{"A", "B"},
+		},
+		{
+			name:   "sub query",
+			query:  "SELECT * FROM (SELECT * FROM A) AS x JOIN (SELECT host FROM B GROUP BY host) y USING (host)",
+			tables: []string{"A", "B"},
+		},
+		{
+			name:   "common table expressions are not tables",
+			query:  "WITH top AS (SELECT * FROM A ORDER BY value DESC LIMIT 5) SELECT * FROM top JOIN B ON top.host = B.host",
+			tables: []string{"A", "B"},
+		},
+		{
+			name:   "strings and comments are ignored",
+			query:  "SELECT 'FROM X' AS s /* FROM Y */ FROM A -- FROM Z\nWHERE 1",
+			tables: []string{"A"},
+		},
+		{
+			name:   "duplicates are removed",
+			query:  "SELECT * FROM A UNION SELECT * FROM A",
+			tables: []string{"A"},
+		},
+		{
+			name:    "unterminated quote",
+			query:   "SELECT * FROM 'A",
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := TablesList(tt.query)
+			if tt.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.tables, tables)
+		})
+	}
+}
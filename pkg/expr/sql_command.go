@@ -0,0 +1,122 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/sql"
+)
+
+// SQLCommand is an expression command that runs an SQL SELECT statement over the results
+// of other queries or expressions. Each input is a table named by its refId.
+type SQLCommand struct {
+	Query       string
+	varsToQuery []string
+	refID       string
+}
+
+// NewSQLCommand creates a new SQLCommand. It returns an error if the tables used
+// by the query can not be determined.
+func NewSQLCommand(refID, query string) (*SQLCommand, error) {
+	tables, err := sql.TablesList(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sql query for refId %v: %w", refID, err)
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("sql query for refId %v does not read from any query or expression", refID)
+	}
+	return &SQLCommand{
+		Query:       query,
+		varsToQuery: tables,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalSQLCommand creates a SQLCommand from Grafana's frontend query.
+func UnmarshalSQLCommand(rn *rawNode) (*SQLCommand, error) {
+	rawExpr, ok := rn.Query["expression"]
+	if !ok {
+		return nil, fmt.Errorf("sql command for refId %v is missing an expression", rn.RefID)
+	}
+	expression, ok := rawExpr.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected sql command for refId %v expression to be a string, got %T", rn.RefID, rawExpr)
+	}
+	return NewSQLCommand(rn.RefID, expression)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gr *SQLCommand) NeedsVars() []string {
+	return gr.varsToQuery
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute. If the result is a table with a single numeric column, it is
+// returned as numbers labelled by the string columns, so it can be used as an alert
+// condition. Otherwise it is returned as a single table.
+func (gr *SQLCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
+	tables := make(map[string][]*data.Frame, len(gr.varsToQuery))
+	for _, ref := range gr.varsToQuery {
+		results, ok := vars[ref]
+		if !ok {
+			return mathexp.Results{}, fmt.Errorf("sql command for refId %v uses unknown table %v", gr.refID, ref)
+		}
+		tables[ref] = valuesToTableFrames(results.Values)
+	}
+
+	frame, err := sql.Query(ctx, gr.refID, gr.Query, tables)
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to execute sql query for refId %v: %w", gr.refID, err)
+	}
+
+	if isNumberTable(frame) {
+		numberSet, err := extractNumberSet(frame)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		vals := make([]mathexp.Value, 0, len(numberSet))
+		for _, n := range numberSet {
+			vals = append(vals, n)
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+	return mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}}, nil
+}
+
+// valuesToTableFrames returns the frames of the table of values. The fields of
+// series and numbers are renamed to "time" and "value" so that they can be
+// referred to regardless of the query they come from. Their labels are
+// added as columns of the table by sql.Query.
+func valuesToTableFrames(values mathexp.Values) []*data.Frame {
+	frames := make([]*data.Frame, 0, len(values))
+	for _, v := range values {
+		switch val := v.(type) {
+		case mathexp.Series:
+			times := make([]time.Time, val.Len())
+			vals := make([]*float64, val.Len())
+			for i := 0; i < val.Len(); i++ {
+				times[i], vals[i] = val.GetPoint(i)
+			}
+			frames = append(frames, data.NewFrame("",
+				data.NewField("time", nil, times),
+				data.NewField("value", val.GetLabels(), vals),
+			))
+		case mathexp.Number:
+			frames = append(frames, data.NewFrame("",
+				data.NewField("value", val.GetLabels(), []*float64{val.GetFloat64Value()}),
+			))
+		case mathexp.Scalar:
+			frames = append(frames, data.NewFrame("",
+				data.NewField("value", nil, []*float64{val.GetFloat64Value()}),
+			))
+		default:
+			frames = append(frames, v.AsDataFrame())
+		}
+	}
+	return frames
+}
@@ -0,0 +1,62 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+func TestSQLCommand(t *testing.T) {
+	fp := func(f float64) *float64 { return &f }
+	cpu := mathexp.NewSeries("A", data.Labels{"host": "web01"}, 2)
+	cpu.SetPoint(0, time.Unix(0, 0), fp(80))
+	cpu.SetPoint(1, time.Unix(60, 0), fp(95))
+
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{cpu}},
+		"B": mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: data.NewFrame("",
+			data.NewField("host", nil, []string{"web01", "web02"}),
+			data.NewField("owner", nil, []string{"alice", "bob"}),
+		)}}},
+	}
+
+	t.Run("needs the tables of the query", func(t *testing.T) {
+		cmd, err := NewSQLCommand("C", "SELECT * FROM A JOIN B ON A.host = B.host")
+		require.NoError(t, err)
+		require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+	})
+
+	t.Run("error without tables", func(t *testing.T) {
+		_, err := NewSQLCommand("C", "SELECT 1")
+		require.Error(t, err)
+	})
+
+	t.Run("single numeric column results in numbers", func(t *testing.T) {
+		cmd, err := NewSQLCommand("C", "SELECT B.owner, max(A.value) AS cpu FROM A JOIN B ON A.host = B.host GROUP BY B.owner")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), vars)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		n, ok := res.Values[0].(mathexp.Number)
+		require.True(t, ok)
+		require.Equal(t, data.Labels{"owner": "alice"}, n.GetLabels())
+		require.Equal(t, fp(95), n.GetFloat64Value())
+	})
+
+	t.Run("other results are tables", func(t *testing.T) {
+		cmd, err := NewSQLCommand("C", "SELECT time, value, host FROM A ORDER BY time")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), vars)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		table, ok := res.Values[0].(mathexp.TableData)
+		require.True(t, ok)
+		require.Equal(t, 2, table.Frame.Rows())
+		require.Len(t, table.Frame.Fields, 3)
+	})
+}
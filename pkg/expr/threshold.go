@@ -0,0 +1,144 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/classic"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// ThresholdCommand is an expression command that compares each number of the input against
+// a threshold and returns 1 when the condition is met and 0 when it is not.
+//
+// When a recovery evaluator is set the command acts as a hysteresis: dimensions that are
+// present in LoadedDimensions (i.e. currently firing) keep returning 1 until the recovery
+// condition is met, rather than as soon as the threshold condition is no longer met.
+type ThresholdCommand struct {
+	ReferenceVar     string
+	Evaluator        classic.Evaluator
+	Recovery         classic.Evaluator
+	LoadedDimensions []data.Labels
+	refID            string
+}
+
+// ThresholdCommandJSON is the JSON model of the threshold command.
+type ThresholdCommandJSON struct {
+	Expression        string                     `json:"expression"`
+	Evaluator         classic.ConditionEvalJSON  `json:"evaluator"`
+	RecoveryEvaluator *classic.ConditionEvalJSON `json:"recoveryEvaluator,omitempty"`
+	// LoadedDimensions holds the labels of the dimensions that were firing at the previous evaluation.
+	// It is set by the alerting scheduler and is only used when RecoveryEvaluator is set.
+	LoadedDimensions []data.Labels `json:"loadedDimensions,omitempty"`
+}
+
+// NewThresholdCommand creates a new ThresholdCommand. recovery may be nil.
+func NewThresholdCommand(refID, referenceVar string, evaluator classic.ConditionEvalJSON, recovery *classic.ConditionEvalJSON, loaded []data.Labels) (*ThresholdCommand, error) {
+	eval, err := newThresholdEvaluator(evaluator)
+	if err != nil {
+		return nil, fmt.Errorf("invalid threshold evaluator for refId %v: %w", refID, err)
+	}
+	cmd := &ThresholdCommand{
+		ReferenceVar:     referenceVar,
+		Evaluator:        eval,
+		LoadedDimensions: loaded,
+		refID:            refID,
+	}
+	if recovery != nil {
+		cmd.Recovery, err = newThresholdEvaluator(*recovery)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recovery threshold evaluator for refId %v: %w", refID, err)
+		}
+	}
+	return cmd, nil
+}
+
+// UnmarshalThresholdCommand creates a ThresholdCommand from Grafana's frontend query.
+func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
+	jsonFromM, err := json.Marshal(rn.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remarshal threshold command body for refId %v: %w", rn.RefID, err)
+	}
+	var tj ThresholdCommandJSON
+	if err = json.Unmarshal(jsonFromM, &tj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal threshold command body for refId %v: %w", rn.RefID, err)
+	}
+	referenceVar := strings.TrimPrefix(tj.Expression, "$")
+	if referenceVar == "" {
+		return nil, fmt.Errorf("no variable specified to reference for refId %v", rn.RefID)
+	}
+	return NewThresholdCommand(rn.RefID, referenceVar, tj.Evaluator, tj.RecoveryEvaluator, tj.LoadedDimensions)
+}
+
+// newThresholdEvaluator creates a classic evaluator, limited to the types that compare
+// a value against one or two thresholds.
+func newThresholdEvaluator(model classic.ConditionEvalJSON) (classic.Evaluator, error) {
+	switch model.Type {
+	case "gt", "lt", "within_range", "outside_range":
+		return classic.NewAlertEvaluator(model)
+	}
+	return nil, fmt.Errorf("'%v' is not a valid threshold evaluator type", model.Type)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (tc *ThresholdCommand) NeedsVars() []string {
+	return []string{tc.ReferenceVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (tc *ThresholdCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
+	newRes := mathexp.Results{}
+	for _, val := range vars[tc.ReferenceVar].Values {
+		var labels data.Labels
+		var f *float64
+		switch v := val.(type) {
+		case mathexp.Number:
+			labels = v.GetLabels()
+			f = v.GetFloat64Value()
+		case mathexp.Scalar:
+			f = v.GetFloat64Value()
+		default:
+			return newRes, fmt.Errorf("can only apply a threshold to type number, got type %v", val.Type())
+		}
+
+		num := mathexp.NewNumber(tc.refID, labels)
+		if f == nil {
+			num.SetValue(nil)
+			newRes.Values = append(newRes.Values, num)
+			continue
+		}
+
+		evalNum := mathexp.NewNumber("", nil)
+		evalNum.SetValue(f)
+		var firing bool
+		if tc.Recovery != nil && tc.isLoaded(labels) {
+			firing = !tc.Recovery.Eval(evalNum)
+		} else {
+			firing = tc.Evaluator.Eval(evalNum)
+		}
+
+		result := 0.0
+		if firing {
+			result = 1
+		}
+		num.SetValue(&result)
+		newRes.Values = append(newRes.Values, num)
+	}
+	return newRes, nil
+}
+
+// isLoaded returns true if the dimension identified by labels was firing at the previous evaluation.
+func (tc *ThresholdCommand) isLoaded(labels data.Labels) bool {
+	for _, loaded := range tc.LoadedDimensions {
+		if loaded.Contains(labels) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/classic"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+func TestUnmarshalThresholdCommand(t *testing.T) {
+	var tests = []struct {
+		name        string
+		query       string
+		isError     bool
+		hasRecovery bool
+	}{
+		{
+			name:  "threshold without recovery",
+			query: `{"expression": "$A", "type": "threshold", "evaluator": {"type": "gt", "params": [90]}}`,
+		},
+		{
+			name: "threshold with recovery",
+			query: `{"expression": "$A", "type": "threshold", "evaluator": {"type": "gt", "params": [90]},
+				"recoveryEvaluator": {"type": "lt", "params": [80]}}`,
+			hasRecovery: true,
+		},
+		{
+			name:    "error when expression is missing",
+			query:   `{"type": "threshold", "evaluator": {"type": "gt", "params": [90]}}`,
+			isError: true,
+		},
+		{
+			name:    "error when evaluator type is not a threshold",
+			query:   `{"expression": "$A", "type": "threshold", "evaluator": {"type": "no_value", "params": []}}`,
+			isError: true,
+		},
+		{
+			name:    "error when evaluator is missing a param",
+			query:   `{"expression": "$A", "type": "threshold", "evaluator": {"type": "within_range", "params": [1]}}`,
+			isError: true,
+		},
+		{
+			name: "error when recovery evaluator is invalid",
+			query: `{"expression": "$A", "type": "threshold", "evaluator": {"type": "gt", "params": [90]},
+				"recoveryEvaluator": {"type": "lt", "params": []}}`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalThresholdCommand(&rawNode{
+				RefID: "B",
+				Query: qmap,
+			})
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+			require.Equal(t, test.hasRecovery, cmd.Recovery != nil)
+		})
+	}
+}
+
+func TestThresholdCommandExecute(t *testing.T) {
+	number := func(labels data.Labels, f *float64) mathexp.Number {
+		n := mathexp.NewNumber("", labels)
+		n.SetValue(f)
+		return n
+	}
+	fp := func(f float64) *float64 { return &f }
+
+	vars := mathexp.Vars{
+		"A": mathexp.Results{
+			Values: []mathexp.Value{
+				number(data.Labels{"host": "a"}, fp(95)),
+				number(data.Labels{"host": "b"}, fp(85)),
+				number(data.Labels{"host": "c"}, fp(75)),
+				number(data.Labels{"host": "d"}, nil),
+			},
+		},
+	}
+
+	var tests = []struct {
+		name     string
+		query    string
+		expected []*float64
+	}{
+		{
+			name:     "greater than",
+			query:    `{"expression": "$A", "evaluator": {"type": "gt", "params": [80]}}`,
+			expected: []*float64{fp(1), fp(1), fp(0), nil},
+		},
+		{
+			name:     "outside range",
+			query:    `{"expression": "$A", "evaluator": {"type": "outside_range", "params": [80, 90]}}`,
+			expected: []*float64{fp(1), fp(0), fp(1), nil},
+		},
+		{
+			name: "recovery is not used for dimensions that are not loaded",
+			query: `{"expression": "$A", "evaluator": {"type": "gt", "params": [90]},
+				"recoveryEvaluator": {"type": "lt", "params": [80]}}`,
+			expected: []*float64{fp(1), fp(0), fp(0), nil},
+		},
+		{
+			name: "loaded dimensions keep firing until recovered",
+			query: `{"expression": "$A", "evaluator": {"type": "gt", "params": [90]},
+				"recoveryEvaluator": {"type": "lt", "params": [80]},
+				"loadedDimensions": [{"host": "b", "alertname": "test"}, {"host": "c"}]}`,
+			expected: []*float64{fp(1), fp(1), fp(0), nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalThresholdCommand(&rawNode{
+				RefID: "B",
+				Query: qmap,
+			})
+			require.NoError(t, err)
+
+			res, err := cmd.Execute(context.Background(), vars)
+			require.NoError(t, err)
+			require.Len(t, res.Values, len(test.expected))
+			for i, v := range res.Values {
+				n, ok := v.(mathexp.Number)
+				require.True(t, ok)
+				require.Equal(t, vars["A"].Values[i].GetLabels(), n.GetLabels())
+				require.Equal(t, test.expected[i], n.GetFloat64Value())
+			}
+		})
+	}
+
+	t.Run("error on series input", func(t *testing.T) {
+		cmd, err := NewThresholdCommand("B", "A", classic.ConditionEvalJSON{Type: "gt", Params: []float64{80}}, nil, nil)
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), mathexp.Vars{
+			"A": mathexp.Results{Values: []mathexp.Value{mathexp.NewSeries("A", nil, 0)}},
+		})
+		require.Error(t, err)
+	})
+}
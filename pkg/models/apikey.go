@@ -10,6 +10,7 @@ var (
 	ErrInvalidApiKey           = errors.New("invalid API key")
 	ErrInvalidApiKeyExpiration = errors.New("negative value for SecondsToLive")
 	ErrDuplicateApiKey         = errors.New("API key, organization ID and name must be unique")
+	ErrApiKeyRevoked           = errors.New("API key has been revoked")
 )
 
 type ApiKey struct {
@@ -22,6 +23,9 @@ type ApiKey struct {
 	Updated          time.Time
 	Expires          *int64
 	ServiceAccountId *int64
+	LastUsedAt       *time.Time `xorm:"last_used_at"`
+	LastUsedIP       string     `xorm:"last_used_ip"`
+	IsRevoked        *bool      `xorm:"is_revoked"`
 }
 
 // ---------------------
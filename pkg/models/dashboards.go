@@ -436,7 +436,10 @@ type GetDashboardTagsQuery struct {
 
 type GetDashboardsQuery struct {
 	DashboardIds []int64
-	Result       []*Dashboard
+	// DashboardUIds are the UIDs of the dashboards of the organization OrgId, if DashboardIds is empty.
+	DashboardUIds []string
+	OrgId         int64
+	Result        []*Dashboard
 }
 
 type GetDashboardPermissionsForUserQuery struct {
@@ -0,0 +1,47 @@
+package models
+
+// Kinds of the identities that can be provisioned from config files.
+const (
+	IdentityKindServiceAccount = "service_account"
+	IdentityKindTeam           = "team"
+)
+
+// IdentityProvisioning marks a service account or a team as provisioned from a config file.
+// Provisioned identities cannot be changed in the API.
+type IdentityProvisioning struct {
+	Id         int64
+	OrgId      int64
+	Kind       string
+	IdentityId int64
+	ExternalId string
+	Updated    int64
+}
+
+// ---------------------
+// COMMANDS
+
+type SaveIdentityProvisioningCommand struct {
+	OrgId      int64
+	Kind       string
+	IdentityId int64
+	ExternalId string
+}
+
+type DeleteIdentityProvisioningCommand struct {
+	Kind       string
+	IdentityId int64
+}
+
+// ---------------------
+// QUERIES
+
+type GetProvisionedIdentitiesQuery struct {
+	Kind   string
+	Result []*IdentityProvisioning
+}
+
+type IsIdentityProvisionedQuery struct {
+	Kind       string
+	IdentityId int64
+	Result     bool
+}
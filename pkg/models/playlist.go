@@ -6,22 +6,38 @@ import (
 
 // Typed errors
 var (
-	ErrPlaylistNotFound = errors.New("Playlist not found")
+	ErrPlaylistNotFound                = errors.New("Playlist not found")
+	ErrPlaylistWithSameUIDExists       = errors.New("a playlist with the same uid already exists")
+	ErrPlaylistInvalidUID              = errors.New("uid contains illegal characters")
+	ErrPlaylistUIDTooLong              = errors.New("uid too long, max 40 characters")
+	ErrPlaylistFailedGenerateUniqueUID = errors.New("failed to generate unique playlist uid")
+)
+
+// Types of the playlist items
+const (
+	PlaylistItemTypeDashboardByID  = "dashboard_by_id"
+	PlaylistItemTypeDashboardByUID = "dashboard_by_uid"
+	PlaylistItemTypeDashboardByTag = "dashboard_by_tag"
 )
 
 // Playlist model
 type Playlist struct {
 	Id       int64  `json:"id"`
+	UID      string `json:"uid" xorm:"uid"`
 	Name     string `json:"name"`
 	Interval string `json:"interval"`
 	OrgId    int64  `json:"-"`
+	// ReadOnly is true if the playlist is provisioned, so it cannot be changed in the API.
+	ReadOnly bool `json:"readOnly"`
 }
 
 type PlaylistDTO struct {
 	Id       int64             `json:"id"`
+	UID      string            `json:"uid"`
 	Name     string            `json:"name"`
 	Interval string            `json:"interval"`
 	OrgId    int64             `json:"-"`
+	ReadOnly bool              `json:"readOnly"`
 	Items    []PlaylistItemDTO `json:"items"`
 }
 
@@ -55,14 +71,18 @@ type UpdatePlaylistCommand struct {
 	Name     string            `json:"name" binding:"Required"`
 	Interval string            `json:"interval"`
 	Items    []PlaylistItemDTO `json:"items"`
+	ReadOnly bool              `json:"-"`
 
 	Result *PlaylistDTO
 }
 
 type CreatePlaylistCommand struct {
+	// UID is generated if it is empty.
+	UID      string            `json:"uid"`
 	Name     string            `json:"name" binding:"Required"`
 	Interval string            `json:"interval"`
 	Items    []PlaylistItemDTO `json:"items"`
+	ReadOnly bool              `json:"-"`
 
 	OrgId  int64 `json:"-"`
 	Result *Playlist
@@ -85,11 +105,22 @@ type GetPlaylistsQuery struct {
 	Result Playlists
 }
 
+// GetProvisionedPlaylistsQuery returns the read-only playlists of all organizations.
+type GetProvisionedPlaylistsQuery struct {
+	Result Playlists
+}
+
 type GetPlaylistByIdQuery struct {
 	Id     int64
 	Result *Playlist
 }
 
+type GetPlaylistByUidQuery struct {
+	UID    string
+	OrgId  int64
+	Result *Playlist
+}
+
 type GetPlaylistItemsByIdQuery struct {
 	PlaylistId int64
 	Result     *[]PlaylistItem
@@ -88,6 +88,7 @@ type TeamDTO struct {
 	AvatarUrl     string          `json:"avatarUrl"`
 	MemberCount   int64           `json:"memberCount"`
 	Permission    PermissionType  `json:"permission"`
+	IsProvisioned bool            `json:"isProvisioned"`
 	AccessControl map[string]bool `json:"accessControl"`
 }
 
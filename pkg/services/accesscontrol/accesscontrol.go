@@ -36,6 +36,8 @@ type AccessControl interface {
 
 type PermissionsProvider interface {
 	GetUserPermissions(ctx context.Context, query GetUserPermissionsQuery) ([]*Permission, error)
+	// GetUserFixedRoles returns the names of the fixed roles assigned to the user, directly or through their teams.
+	GetUserFixedRoles(ctx context.Context, orgID, userID int64) ([]string, error)
 }
 
 type PermissionsServices interface {
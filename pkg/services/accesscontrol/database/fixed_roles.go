@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// GetUserFixedRoles returns the names of the fixed roles assigned to the user in the organization,
+// directly or through the teams of the user.
+func (s *AccessControlStore) GetUserFixedRoles(ctx context.Context, orgID, userID int64) ([]string, error) {
+	result := make([]string, 0)
+	err := s.sql.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		filter, params := userRolesFilter(orgID, userID, nil)
+		q := `SELECT DISTINCT role.name FROM role ` + filter + ` AND role.name LIKE ?`
+		params = append(params, accesscontrol.FixedRolePrefix+"%")
+		return sess.SQL(q, params...).Find(&result)
+	})
+	return result, err
+}
+
+// SetUserFixedRoles replaces the fixed roles assigned to the user in the organization. The other roles of the user,
+// such as the managed roles of resource permissions, are left unchanged.
+func (s *AccessControlStore) SetUserFixedRoles(ctx context.Context, orgID, userID int64, roleNames []string) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Exec("DELETE FROM user_role WHERE org_id = ? AND user_id = ? AND role_id IN (SELECT id FROM role WHERE name LIKE ?)",
+			orgID, userID, accesscontrol.FixedRolePrefix+"%"); err != nil {
+			return err
+		}
+
+		for _, name := range roleNames {
+			role, err := getOrCreateFixedRole(sess, name)
+			if err != nil {
+				return err
+			}
+			if _, err := sess.Insert(&accesscontrol.UserRole{OrgID: orgID, UserID: userID, RoleID: role.ID, Created: time.Now()}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetTeamFixedRoles replaces the fixed roles assigned to the team. The other roles of the team are left unchanged.
+func (s *AccessControlStore) SetTeamFixedRoles(ctx context.Context, orgID, teamID int64, roleNames []string) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Exec("DELETE FROM team_role WHERE org_id = ? AND team_id = ? AND role_id IN (SELECT id FROM role WHERE name LIKE ?)",
+			orgID, teamID, accesscontrol.FixedRolePrefix+"%"); err != nil {
+			return err
+		}
+
+		for _, name := range roleNames {
+			role, err := getOrCreateFixedRole(sess, name)
+			if err != nil {
+				return err
+			}
+			if _, err := sess.Insert(&accesscontrol.TeamRole{OrgID: orgID, TeamID: teamID, RoleID: role.ID, Created: time.Now()}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// getOrCreateFixedRole returns the global role of a fixed role, the permissions of fixed roles are not stored
+// in the database.
+func getOrCreateFixedRole(sess *sqlstore.DBSession, name string) (*accesscontrol.Role, error) {
+	role := accesscontrol.Role{}
+	has, err := sess.Where("org_id = ? AND name = ?", globalOrgID, name).Get(&role)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		return &role, nil
+	}
+
+	uid, err := generateNewRoleUID(sess, globalOrgID)
+	if err != nil {
+		return nil, err
+	}
+	role = accesscontrol.Role{
+		OrgID:   globalOrgID,
+		Name:    name,
+		UID:     uid,
+		Created: time.Now(),
+		Updated: time.Now(),
+	}
+	if _, err := sess.Insert(&role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
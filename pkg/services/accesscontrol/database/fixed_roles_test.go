@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/resourcepermissions/types"
+)
+
+func TestAccessControlStore_FixedRoles(t *testing.T) {
+	ctx := context.Background()
+	store, sql := setupTestEnv(t)
+	user, team := createUserAndTeam(t, sql, 1)
+
+	// a managed role of the user must not be removed when the fixed roles are replaced
+	_, err := store.SetUserResourcePermission(ctx, 1, accesscontrol.User{ID: user.Id}, types.SetResourcePermissionCommand{
+		Actions:    []string{"dashboards:write"},
+		Resource:   "dashboards",
+		ResourceID: "1",
+	}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SetUserFixedRoles(ctx, 1, user.Id, []string{"fixed:users:reader", "fixed:teams:writer"}))
+	require.NoError(t, store.SetTeamFixedRoles(ctx, 1, team.Id, []string{"fixed:dashboards:reader", "fixed:users:reader"}))
+
+	roles, err := store.GetUserFixedRoles(ctx, 1, user.Id)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"fixed:users:reader", "fixed:teams:writer", "fixed:dashboards:reader"}, roles)
+
+	roles, err = store.GetUserFixedRoles(ctx, 2, user.Id)
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+
+	require.NoError(t, store.SetUserFixedRoles(ctx, 1, user.Id, []string{"fixed:teams:writer"}))
+	require.NoError(t, store.SetTeamFixedRoles(ctx, 1, team.Id, nil))
+
+	roles, err = store.GetUserFixedRoles(ctx, 1, user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fixed:teams:writer"}, roles)
+
+	permissions, err := store.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{OrgID: 1, UserID: user.Id})
+	require.NoError(t, err)
+	assert.Len(t, permissions, 1)
+}
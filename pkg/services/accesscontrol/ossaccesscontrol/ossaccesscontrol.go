@@ -41,6 +41,7 @@ func ProvideOSSAccessControl(features featuremgmt.FeatureToggles, provider acces
 		log:           log.New("accesscontrol"),
 		scopeResolver: accesscontrol.NewScopeResolver(),
 		roles:         accesscontrol.BuildMacroRoleDefinitions(),
+		fixedRoles:    map[string]accesscontrol.RoleDTO{},
 	}
 
 	return s
@@ -54,6 +55,7 @@ type OSSAccessControlService struct {
 	provider      accesscontrol.PermissionsProvider
 	registrations accesscontrol.RegistrationList
 	roles         map[string]*accesscontrol.RoleDTO
+	fixedRoles    map[string]accesscontrol.RoleDTO
 }
 
 func (ac *OSSAccessControlService) IsDisabled() bool {
@@ -117,6 +119,12 @@ func (ac *OSSAccessControlService) GetUserPermissions(ctx context.Context, user
 
 	permissions := ac.getFixedPermissions(ctx, user)
 
+	assignedPermissions, err := ac.getAssignedPermissions(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	permissions = append(permissions, assignedPermissions...)
+
 	dbPermissions, err := ac.provider.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{
 		OrgID:   user.OrgId,
 		UserID:  user.UserId,
@@ -156,6 +164,33 @@ func (ac *OSSAccessControlService) getFixedPermissions(ctx context.Context, user
 	return permissions
 }
 
+// getAssignedPermissions returns the permissions of the fixed roles that are assigned to the user, e.g. by provisioning.
+func (ac *OSSAccessControlService) getAssignedPermissions(ctx context.Context, user *models.SignedInUser) ([]*accesscontrol.Permission, error) {
+	permissions := make([]*accesscontrol.Permission, 0)
+	if user.UserId == 0 {
+		return permissions, nil
+	}
+
+	names, err := ac.provider.GetUserFixedRoles(ctx, user.OrgId, user.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		role, ok := ac.fixedRoles[name]
+		if !ok {
+			ac.log.Debug("Ignoring unknown fixed role assignment", "role", name, "userId", user.UserId)
+			continue
+		}
+		for i := range role.Permissions {
+			p := role.Permissions[i]
+			permissions = append(permissions, &p)
+		}
+	}
+
+	return permissions, nil
+}
+
 func (ac *OSSAccessControlService) GetUserBuiltInRoles(user *models.SignedInUser) []string {
 	builtInRoles := []string{string(user.OrgRole)}
 
@@ -181,6 +216,7 @@ func (ac *OSSAccessControlService) RegisterFixedRoles(ctx context.Context) error
 	}
 	ac.registrations.Range(func(registration accesscontrol.RoleRegistration) bool {
 		ac.registerFixedRole(registration.Role, registration.Grants)
+		ac.fixedRoles[registration.Role.Name] = registration.Role
 		return true
 	})
 	return nil
@@ -27,6 +27,7 @@ func setupTestEnv(t testing.TB) *OSSAccessControlService {
 		scopeResolver: accesscontrol.NewScopeResolver(),
 		provider:      database.ProvideService(sqlstore.InitTestDB(t)),
 		roles:         accesscontrol.BuildMacroRoleDefinitions(),
+		fixedRoles:    map[string]accesscontrol.RoleDTO{},
 	}
 	require.NoError(t, ac.RegisterFixedRoles(context.Background()))
 	return ac
@@ -426,6 +427,35 @@ func TestOSSAccessControlService_GetUserPermissions(t *testing.T) {
 	}
 }
 
+func TestOSSAccessControlService_GetUserPermissions_AssignedFixedRoles(t *testing.T) {
+	testUser := models.SignedInUser{UserId: 2, OrgId: 3, OrgRole: models.ROLE_VIEWER}
+	ac := setupTestEnv(t)
+
+	err := ac.DeclareFixedRoles(accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Version:     1,
+			Name:        "fixed:test:writer",
+			Permissions: []accesscontrol.Permission{{Action: "test:write", Scope: "test:*"}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ac.RegisterFixedRoles(context.Background()))
+
+	store := ac.provider.(*database.AccessControlStore)
+	err = store.SetUserFixedRoles(context.Background(), testUser.OrgId, testUser.UserId, []string{"fixed:test:writer", "fixed:unknown:role"})
+	require.NoError(t, err)
+
+	userPerms, err := ac.GetUserPermissions(context.Background(), &testUser, accesscontrol.Options{})
+	require.NoError(t, err)
+	assert.Contains(t, extractRawPermissionsHelper(userPerms), &accesscontrol.Permission{Action: "test:write", Scope: "test:*"})
+
+	otherOrgUser := testUser
+	otherOrgUser.OrgId = 4
+	userPerms, err = ac.GetUserPermissions(context.Background(), &otherOrgUser, accesscontrol.Options{})
+	require.NoError(t, err)
+	assert.NotContains(t, extractRawPermissionsHelper(userPerms), &accesscontrol.Permission{Action: "test:write", Scope: "test:*"})
+}
+
 func TestOSSAccessControlService_Evaluate(t *testing.T) {
 	testUser := models.SignedInUser{
 		UserId:  2,
@@ -101,6 +101,19 @@ func ProvideTeamPermissions(
 
 			return nil
 		},
+		// the members of provisioned teams can only be changed by provisioning
+		ReadOnlyResolver: func(ctx context.Context, orgID int64, resourceID string) (bool, error) {
+			id, err := strconv.ParseInt(resourceID, 10, 64)
+			if err != nil {
+				return false, err
+			}
+
+			query := &models.IsIdentityProvisionedQuery{Kind: models.IdentityKindTeam, IdentityId: id}
+			if err := sql.IsIdentityProvisioned(ctx, query); err != nil {
+				return false, err
+			}
+			return query.Result, nil
+		},
 		Assignments: resourcepermissions.Assignments{
 			Users:        true,
 			Teams:        false,
@@ -165,6 +165,10 @@ func (a *api) setUserPermission(c *models.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if resp := a.checkNotReadOnly(c, resourceID); resp != nil {
+		return resp
+	}
+
 	_, err = a.service.SetUserPermission(c.Req.Context(), c.OrgId, accesscontrol.User{ID: userID}, resourceID, cmd.Permission)
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "failed to set user permission", err)
@@ -185,6 +189,10 @@ func (a *api) setTeamPermission(c *models.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if resp := a.checkNotReadOnly(c, resourceID); resp != nil {
+		return resp
+	}
+
 	_, err = a.service.SetTeamPermission(c.Req.Context(), c.OrgId, teamID, resourceID, cmd.Permission)
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "failed to set team permission", err)
@@ -202,6 +210,10 @@ func (a *api) setBuiltinRolePermission(c *models.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if resp := a.checkNotReadOnly(c, resourceID); resp != nil {
+		return resp
+	}
+
 	_, err := a.service.SetBuiltInRolePermission(c.Req.Context(), c.OrgId, builtInRole, resourceID, cmd.Permission)
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "failed to set role permission", err)
@@ -210,6 +222,21 @@ func (a *api) setBuiltinRolePermission(c *models.ReqContext) response.Response {
 	return permissionSetResponse(cmd)
 }
 
+func (a *api) checkNotReadOnly(c *models.ReqContext, resourceID string) response.Response {
+	if a.service.options.ReadOnlyResolver == nil {
+		return nil
+	}
+
+	readOnly, err := a.service.options.ReadOnlyResolver(c.Req.Context(), c.OrgId, resourceID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "failed to check resource", err)
+	}
+	if readOnly {
+		return response.Error(http.StatusForbidden, "cannot change the permissions of a read-only resource", nil)
+	}
+	return nil
+}
+
 func permissionSetResponse(cmd setPermissionCommand) response.Response {
 	message := "Permission updated"
 	if cmd.Permission == "" {
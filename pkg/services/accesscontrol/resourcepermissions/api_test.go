@@ -417,6 +417,37 @@ func TestApi_setUserPermission(t *testing.T) {
 	}
 }
 
+func TestApi_ReadOnlyResolver(t *testing.T) {
+	permissions := []*accesscontrol.Permission{
+		{Action: "dashboards.permissions:read", Scope: "dashboards:id:1"},
+		{Action: "dashboards.permissions:write", Scope: "dashboards:id:1"},
+		{Action: "dashboards.permissions:read", Scope: "dashboards:id:2"},
+		{Action: "dashboards.permissions:write", Scope: "dashboards:id:2"},
+		{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+		{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+	}
+	options := testOptions
+	options.ReadOnlyResolver = func(ctx context.Context, orgID int64, resourceID string) (bool, error) {
+		return resourceID == "2", nil
+	}
+	service, sql := setupTestEnvironment(t, permissions, options)
+	server := setupTestServer(t, &models.SignedInUser{OrgId: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(permissions)}}, service)
+
+	_, err := sql.CreateUser(context.Background(), models.CreateUserCommand{Login: "test", OrgId: 1})
+	require.NoError(t, err)
+
+	recorder := setPermission(t, server, options.Resource, "1", "View", "users", "1")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = setPermission(t, server, options.Resource, "2", "View", "users", "1")
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	recorder = setPermission(t, server, options.Resource, "2", "View", "builtInRoles", "Viewer")
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+
+	// the service can still set the permissions of read-only resources
+	_, err = service.SetUserPermission(context.Background(), 1, accesscontrol.User{ID: 1}, "2", "View")
+	require.NoError(t, err)
+}
+
 type uidSolverTestCase struct {
 	desc           string
 	uid            string
@@ -10,6 +10,7 @@ import (
 type UidSolver func(ctx context.Context, orgID int64, uid string) (int64, error)
 type ResourceValidator func(ctx context.Context, orgID int64, resourceID string) error
 type InheritedScopesSolver func(ctx context.Context, orgID int64, resourceID string) ([]string, error)
+type ReadOnlyResolver func(ctx context.Context, orgID int64, resourceID string) (bool, error)
 
 type Options struct {
 	// Resource is the action and scope prefix that is generated
@@ -21,6 +22,9 @@ type Options struct {
 	// ResourceValidator is a validator function that will be called before each assignment.
 	// If set to nil the validator will be skipped
 	ResourceValidator ResourceValidator
+	// ReadOnlyResolver if configured will be called by the api before each assignment, the permissions of
+	// read-only resources can only be set with the service, e.g. by provisioning
+	ReadOnlyResolver ReadOnlyResolver
 	// Assignments decides what we can assign permissions to (users/teams/builtInRoles)
 	Assignments Assignments
 	// PermissionsToAction is a map of friendly named permissions and what access control actions they should generate.
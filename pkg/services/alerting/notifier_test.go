@@ -365,6 +365,10 @@ func (s *testRenderService) RenderCSV(ctx context.Context, opts rendering.CSVOpt
 	return nil, nil
 }
 
+func (s *testRenderService) RenderPDF(ctx context.Context, opts rendering.PDFOpts, session rendering.Session) (*rendering.RenderPDFResult, error) {
+	return nil, nil
+}
+
 func (s *testRenderService) RenderErrorImage(theme models.Theme, err error) (*rendering.RenderResult, error) {
 	if s.renderErrorImageProvider != nil {
 		return s.renderErrorImageProvider(err)
@@ -11,14 +11,20 @@ import (
 
 var (
 	ErrTimerangeMissing = errors.New("missing timerange")
+	ErrInvalidInterval  = errors.New("interval must be positive and the time range must be divided into at most 10000 buckets")
+	ErrNotSupported     = errors.New("operation is not supported by the annotation store")
 )
 
+// MaxBuckets is the maximum number of buckets of a CountQuery.
+const MaxBuckets = 10000
+
 type Repository interface {
 	Save(item *Item) error
 	Update(ctx context.Context, item *Item) error
 	Find(ctx context.Context, query *ItemQuery) ([]*ItemDTO, error)
 	Delete(ctx context.Context, params *DeleteParams) error
 	FindTags(ctx context.Context, query *TagsQuery) (FindTagsResult, error)
+	Count(ctx context.Context, query *CountQuery) ([]*Bucket, error)
 }
 
 // AnnotationCleaner is responsible for cleaning up old annotations
@@ -40,9 +46,58 @@ type ItemQuery struct {
 	MatchAny     bool     `json:"matchAny"`
 	SignedInUser *models.SignedInUser
 
+	// TagMatchers must all match the tags of an annotation. Unlike Tags, they can match
+	// the values of the tags with regular expressions and can exclude annotations.
+	TagMatchers []*TagMatcher `json:"tagMatchers"`
+	// Text is a search in the text of the annotations. Every word of the search must be in the text.
+	Text string `json:"text"`
+
 	Limit int64 `json:"limit"`
 }
 
+// CountQuery is the query for the number of annotations in each interval of the time range,
+// e.g. to show the density of the annotations. The filters of ItemQuery are applied but its limit is ignored.
+type CountQuery struct {
+	ItemQuery
+	// Interval is the length of a bucket in milliseconds.
+	Interval int64 `json:"interval"`
+}
+
+// Bucket is the number of annotations that overlap the interval that starts at Time.
+// A region is counted in every bucket it overlaps.
+type Bucket struct {
+	Time  int64 `json:"time"`
+	Count int64 `json:"count"`
+}
+
+// NewBuckets returns the empty buckets of the time range of the query.
+func NewBuckets(query *CountQuery) ([]*Bucket, error) {
+	if query.From <= 0 || query.To <= query.From {
+		return nil, ErrTimerangeMissing
+	}
+	if query.Interval <= 0 || (query.To-query.From+query.Interval-1)/query.Interval > MaxBuckets {
+		return nil, ErrInvalidInterval
+	}
+	buckets := make([]*Bucket, 0, (query.To-query.From+query.Interval-1)/query.Interval)
+	for t := query.From; t < query.To; t += query.Interval {
+		buckets = append(buckets, &Bucket{Time: t})
+	}
+	return buckets, nil
+}
+
+// AddToBuckets counts the annotation from epoch to epochEnd in every bucket of the query that it overlaps.
+func AddToBuckets(buckets []*Bucket, query *CountQuery, epoch, epochEnd int64) {
+	if epoch < query.From {
+		epoch = query.From
+	}
+	if epochEnd >= query.To {
+		epochEnd = query.To - 1
+	}
+	for i := (epoch - query.From) / query.Interval; i <= (epochEnd-query.From)/query.Interval; i++ {
+		buckets[i].Count++
+	}
+}
+
 // TagsQuery is the query for a tags search.
 type TagsQuery struct {
 	OrgID int64  `json:"orgId"`
@@ -0,0 +1,132 @@
+package annotations
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// CompositeRepository saves the annotations to one repository and reads them from several repositories,
+// so that the annotations of a previous store stay visible after the annotations are saved to a new one.
+type CompositeRepository struct {
+	writer       Repository
+	repositories []Repository
+}
+
+// NewCompositeRepository returns a repository that saves the annotations to writer and reads them
+// from writer and readers.
+func NewCompositeRepository(writer Repository, readers ...Repository) *CompositeRepository {
+	return &CompositeRepository{
+		writer:       writer,
+		repositories: append([]Repository{writer}, readers...),
+	}
+}
+
+func (c *CompositeRepository) Save(item *Item) error {
+	return c.writer.Save(item)
+}
+
+// Update updates the annotation in the first repository that supports updates.
+func (c *CompositeRepository) Update(ctx context.Context, item *Item) error {
+	for _, r := range c.repositories {
+		err := r.Update(ctx, item)
+		if errors.Is(err, ErrNotSupported) {
+			continue
+		}
+		return err
+	}
+	return ErrNotSupported
+}
+
+// Delete deletes the annotations from every repository that supports deletes.
+func (c *CompositeRepository) Delete(ctx context.Context, params *DeleteParams) error {
+	supported := false
+	for _, r := range c.repositories {
+		err := r.Delete(ctx, params)
+		if errors.Is(err, ErrNotSupported) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		supported = true
+	}
+	if !supported {
+		return ErrNotSupported
+	}
+	return nil
+}
+
+// Find returns the most recent annotations of all the repositories, in the order of the SQL repository.
+func (c *CompositeRepository) Find(ctx context.Context, query *ItemQuery) ([]*ItemDTO, error) {
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+	items := make([]*ItemDTO, 0)
+	for _, r := range c.repositories {
+		q := *query
+		found, err := r.Find(ctx, &q)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, found...)
+	}
+	// sort by the same key as the SQL repository, epoch*1000+epoch_end DESC
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Time*1000+items[i].TimeEnd > items[j].Time*1000+items[j].TimeEnd
+	})
+	if int64(len(items)) > query.Limit {
+		items = items[:query.Limit]
+	}
+	return items, nil
+}
+
+// FindTags adds up the counts of the tags of all the repositories.
+func (c *CompositeRepository) FindTags(ctx context.Context, query *TagsQuery) (FindTagsResult, error) {
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+	counts := map[string]*TagsDTO{}
+	for _, r := range c.repositories {
+		q := *query
+		result, err := r.FindTags(ctx, &q)
+		if err != nil {
+			return FindTagsResult{Tags: []*TagsDTO{}}, err
+		}
+		for _, tag := range result.Tags {
+			if existing, ok := counts[tag.Tag]; ok {
+				existing.Count += tag.Count
+				continue
+			}
+			counts[tag.Tag] = &TagsDTO{Tag: tag.Tag, Count: tag.Count}
+		}
+	}
+	tags := make([]*TagsDTO, 0, len(counts))
+	for _, tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Tag < tags[j].Tag })
+	if int64(len(tags)) > query.Limit {
+		tags = tags[:query.Limit]
+	}
+	return FindTagsResult{Tags: tags}, nil
+}
+
+// Count adds up the buckets of all the repositories.
+func (c *CompositeRepository) Count(ctx context.Context, query *CountQuery) ([]*Bucket, error) {
+	buckets, err := NewBuckets(query)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range c.repositories {
+		q := *query
+		counted, err := r.Count(ctx, &q)
+		if err != nil {
+			return nil, err
+		}
+		for i := range counted {
+			buckets[i].Count += counted[i].Count
+		}
+	}
+	return buckets, nil
+}
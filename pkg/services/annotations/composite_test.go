@@ -0,0 +1,147 @@
+package annotations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository keeps the annotations in memory. It only filters them by the time range.
+type memoryRepository struct {
+	items     []*Item
+	immutable bool
+}
+
+func (m *memoryRepository) Save(item *Item) error {
+	item.Id = int64(len(m.items) + 1)
+	m.items = append(m.items, item)
+	return nil
+}
+
+func (m *memoryRepository) Update(ctx context.Context, item *Item) error {
+	if m.immutable {
+		return ErrNotSupported
+	}
+	for _, existing := range m.items {
+		if existing.Id == item.Id {
+			existing.Text = item.Text
+			return nil
+		}
+	}
+	return errors.New("annotation not found")
+}
+
+func (m *memoryRepository) Delete(ctx context.Context, params *DeleteParams) error {
+	if m.immutable {
+		return ErrNotSupported
+	}
+	for i, existing := range m.items {
+		if existing.Id == params.Id {
+			m.items = append(m.items[:i], m.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memoryRepository) Find(ctx context.Context, query *ItemQuery) ([]*ItemDTO, error) {
+	var items []*ItemDTO
+	for _, item := range m.items {
+		if query.From > 0 && (item.Epoch > query.To || item.EpochEnd < query.From) {
+			continue
+		}
+		items = append(items, &ItemDTO{Id: item.Id, Text: item.Text, Time: item.Epoch, TimeEnd: item.EpochEnd, Tags: item.Tags})
+	}
+	return items, nil
+}
+
+func (m *memoryRepository) FindTags(ctx context.Context, query *TagsQuery) (FindTagsResult, error) {
+	counts := map[string]int64{}
+	for _, item := range m.items {
+		for _, tag := range item.Tags {
+			counts[tag]++
+		}
+	}
+	var result FindTagsResult
+	for tag, count := range counts {
+		result.Tags = append(result.Tags, &TagsDTO{Tag: tag, Count: count})
+	}
+	return result, nil
+}
+
+func (m *memoryRepository) Count(ctx context.Context, query *CountQuery) ([]*Bucket, error) {
+	buckets, err := NewBuckets(query)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range m.items {
+		if item.Epoch < query.To && item.EpochEnd >= query.From {
+			AddToBuckets(buckets, query, item.Epoch, item.EpochEnd)
+		}
+	}
+	return buckets, nil
+}
+
+func TestCompositeRepository(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*CompositeRepository, *memoryRepository, *memoryRepository) {
+		t.Helper()
+		writer := &memoryRepository{immutable: true}
+		reader := &memoryRepository{}
+		require.NoError(t, reader.Save(&Item{Text: "old", Epoch: 1000, EpochEnd: 1000, Tags: []string{"deploy"}}))
+		require.NoError(t, reader.Save(&Item{Text: "old region", Epoch: 1500, EpochEnd: 3500, Tags: []string{"deploy", "env:prod"}}))
+		return NewCompositeRepository(writer, reader), writer, reader
+	}
+
+	t.Run("annotations are saved to the writer", func(t *testing.T) {
+		repo, writer, reader := setup(t)
+		require.NoError(t, repo.Save(&Item{Text: "new", Epoch: 3000, EpochEnd: 3000}))
+		require.Len(t, writer.items, 1)
+		require.Len(t, reader.items, 2)
+	})
+
+	t.Run("annotations of all the repositories are found", func(t *testing.T) {
+		repo, _, _ := setup(t)
+		require.NoError(t, repo.Save(&Item{Text: "new", Epoch: 3000, EpochEnd: 3000, Tags: []string{"env:prod"}}))
+
+		items, err := repo.Find(ctx, &ItemQuery{})
+		require.NoError(t, err)
+		texts := make([]string, 0, len(items))
+		for _, item := range items {
+			texts = append(texts, item.Text)
+		}
+		// the annotations that start last come first, even if an earlier one ends later
+		require.Equal(t, []string{"new", "old region", "old"}, texts)
+
+		items, err = repo.Find(ctx, &ItemQuery{Limit: 1, From: 2000, To: 4000})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		require.Equal(t, "new", items[0].Text)
+
+		tags, err := repo.FindTags(ctx, &TagsQuery{})
+		require.NoError(t, err)
+		require.Equal(t, []*TagsDTO{{Tag: "deploy", Count: 2}, {Tag: "env:prod", Count: 2}}, tags.Tags)
+
+		buckets, err := repo.Count(ctx, &CountQuery{ItemQuery: ItemQuery{From: 1000, To: 4000}, Interval: 1000})
+		require.NoError(t, err)
+		require.Equal(t, []*Bucket{{Time: 1000, Count: 2}, {Time: 2000, Count: 1}, {Time: 3000, Count: 2}}, buckets)
+	})
+
+	t.Run("annotations are updated and deleted in the repositories that support it", func(t *testing.T) {
+		repo, _, reader := setup(t)
+		require.NoError(t, repo.Update(ctx, &Item{Id: 1, Text: "updated"}))
+		require.Equal(t, "updated", reader.items[0].Text)
+		require.EqualError(t, repo.Update(ctx, &Item{Id: 5, Text: "updated"}), "annotation not found")
+
+		require.NoError(t, repo.Delete(ctx, &DeleteParams{Id: 1}))
+		require.Len(t, reader.items, 1)
+	})
+
+	t.Run("changes are not supported if no repository supports them", func(t *testing.T) {
+		repo := NewCompositeRepository(&memoryRepository{immutable: true})
+		require.ErrorIs(t, repo.Update(ctx, &Item{Id: 1}), ErrNotSupported)
+		require.ErrorIs(t, repo.Delete(ctx, &DeleteParams{Id: 1}), ErrNotSupported)
+	})
+}
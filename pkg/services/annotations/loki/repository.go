@@ -0,0 +1,454 @@
+package loki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// defaultLookback is the time range of the queries without a time range. It is within the default maximum
+// query length of Loki.
+const defaultLookback = 30 * 24 * time.Hour
+
+// ReadFilter returns a function that tells whether the user can read the annotations of a dashboard,
+// where the dashboard ID 0 stands for the annotations of the organization.
+type ReadFilter func(ctx context.Context, user *models.SignedInUser) (func(dashboardID int64) bool, error)
+
+// Repository stores the annotations as log lines in a Loki compatible log store. Each annotation is a JSON line
+// of the stream {source="grafana", org_id="<org>"} at the time the annotation starts.
+//
+// The log lines cannot be changed, so the annotations cannot be updated or deleted. Loki cannot filter by the tags
+// and the access control of the dashboards, so the annotations are fetched with the other filters of the query
+// and the rest is applied here, up to the configured maximum number of lines per query.
+type Repository struct {
+	cfg        setting.AnnotationStorageSettings
+	client     *http.Client
+	readFilter ReadFilter
+	log        log.Logger
+
+	// seq makes the IDs of the annotations saved in the same millisecond unique.
+	seq uint32
+}
+
+// NewRepository returns a repository that stores the annotations in Loki. The access control of the annotations
+// is checked with readFilter, if it is not nil.
+func NewRepository(cfg setting.AnnotationStorageSettings, readFilter ReadFilter) *Repository {
+	return &Repository{
+		cfg:        cfg,
+		client:     &http.Client{Timeout: cfg.LokiTimeout},
+		readFilter: readFilter,
+		log:        log.New("annotations.loki"),
+	}
+}
+
+// entry is the log line of an annotation.
+type entry struct {
+	ID          int64            `json:"id"`
+	DashboardID int64            `json:"dashboard_id"`
+	PanelID     int64            `json:"panel_id"`
+	AlertID     int64            `json:"alert_id"`
+	UserID      int64            `json:"user_id"`
+	Text        string           `json:"text"`
+	PrevState   string           `json:"prev_state,omitempty"`
+	NewState    string           `json:"new_state,omitempty"`
+	Epoch       int64            `json:"epoch"`
+	EpochEnd    int64            `json:"epoch_end"`
+	Created     int64            `json:"created"`
+	Tags        []string         `json:"tags,omitempty"`
+	Data        *simplejson.Json `json:"data,omitempty"`
+}
+
+func (r *Repository) Save(item *annotations.Item) error {
+	item.Tags = models.JoinTagPairs(models.ParseTagPairs(item.Tags))
+	item.Created = time.Now().UnixNano() / int64(time.Millisecond)
+	item.Updated = item.Created
+	if item.Epoch == 0 {
+		item.Epoch = item.Created
+	}
+	if item.EpochEnd == 0 {
+		item.EpochEnd = item.Epoch
+	}
+	if item.EpochEnd < item.Epoch {
+		item.Epoch, item.EpochEnd = item.EpochEnd, item.Epoch
+	}
+	// the IDs stay below 2^53, so that they are not rounded by JavaScript, and above the IDs of the database.
+	item.Id = item.Created*1000 + int64(atomic.AddUint32(&r.seq, 1)%1000)
+
+	line, err := json.Marshal(entry{
+		ID:          item.Id,
+		DashboardID: item.DashboardId,
+		PanelID:     item.PanelId,
+		AlertID:     item.AlertId,
+		UserID:      item.UserId,
+		Text:        item.Text,
+		PrevState:   item.PrevState,
+		NewState:    item.NewState,
+		Epoch:       item.Epoch,
+		EpochEnd:    item.EpochEnd,
+		Created:     item.Created,
+		Tags:        item.Tags,
+		Data:        item.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(pushRequest{Streams: []pushStream{{
+		Stream: streamLabels(item.OrgId),
+		Values: [][2]string{{strconv.FormatInt(item.Epoch*int64(time.Millisecond), 10), string(line)}},
+	}}})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.LokiTimeout)
+	defer cancel()
+	req, err := r.newRequest(ctx, http.MethodPost, "/loki/api/v1/push", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = r.do(req)
+	return err
+}
+
+func (r *Repository) Update(ctx context.Context, item *annotations.Item) error {
+	return annotations.ErrNotSupported
+}
+
+func (r *Repository) Delete(ctx context.Context, params *annotations.DeleteParams) error {
+	return annotations.ErrNotSupported
+}
+
+func (r *Repository) Find(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+	entries, err := r.find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].EpochEnd != entries[j].EpochEnd {
+			return entries[i].EpochEnd > entries[j].EpochEnd
+		}
+		return entries[i].Epoch > entries[j].Epoch
+	})
+	if int64(len(entries)) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+
+	items := make([]*annotations.ItemDTO, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, &annotations.ItemDTO{
+			Id:          e.ID,
+			AlertId:     e.AlertID,
+			DashboardId: e.DashboardID,
+			PanelId:     e.PanelID,
+			UserId:      e.UserID,
+			NewState:    e.NewState,
+			PrevState:   e.PrevState,
+			Created:     e.Created,
+			Updated:     e.Created,
+			Time:        e.Epoch,
+			TimeEnd:     e.EpochEnd,
+			Text:        e.Text,
+			Tags:        e.Tags,
+			Data:        e.Data,
+		})
+	}
+	return items, nil
+}
+
+func (r *Repository) Count(ctx context.Context, query *annotations.CountQuery) ([]*annotations.Bucket, error) {
+	buckets, err := annotations.NewBuckets(query)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := r.find(ctx, &query.ItemQuery)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		annotations.AddToBuckets(buckets, query, e.Epoch, e.EpochEnd)
+	}
+	return buckets, nil
+}
+
+// FindTags counts the tags of the annotations of the last 30 days.
+func (r *Repository) FindTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+	now := time.Now()
+	entries, err := r.query(ctx, streamSelector(query.OrgID), now.Add(-defaultLookback), now)
+	if err != nil {
+		return annotations.FindTagsResult{Tags: []*annotations.TagsDTO{}}, err
+	}
+
+	counts := map[string]int64{}
+	for _, e := range entries {
+		for _, tag := range models.ParseTagPairs(e.Tags) {
+			if !strings.Contains(tag.Key, query.Tag) && !strings.Contains(tag.Value, query.Tag) {
+				continue
+			}
+			counts[models.JoinTagPairs([]*models.Tag{tag})[0]]++
+		}
+	}
+	tags := make([]*annotations.TagsDTO, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, &annotations.TagsDTO{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Tag < tags[j].Tag })
+	if int64(len(tags)) > query.Limit {
+		tags = tags[:query.Limit]
+	}
+	return annotations.FindTagsResult{Tags: tags}, nil
+}
+
+// find returns the annotations that match the query and that the user of the query can read.
+// A region is found only if it starts within the time range of the query, because the log line
+// of the region is at the time it starts.
+func (r *Repository) find(ctx context.Context, query *annotations.ItemQuery) ([]*entry, error) {
+	canRead := func(int64) bool { return true }
+	if r.readFilter != nil {
+		var err error
+		if canRead, err = r.readFilter(ctx, query.SignedInUser); err != nil {
+			return nil, err
+		}
+	}
+
+	from, to := time.Now().Add(-defaultLookback), time.Now()
+	if query.From > 0 && query.To > 0 {
+		from, to = time.UnixMilli(query.From), time.UnixMilli(query.To)
+	}
+	entries, err := r.query(ctx, logQuery(query), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entry, 0, len(entries))
+	for _, e := range entries {
+		if canRead(e.DashboardID) && matches(e, query) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// logQuery returns the LogQL query of the filters of the query that Loki can apply.
+func logQuery(query *annotations.ItemQuery) string {
+	var b strings.Builder
+	b.WriteString(streamSelector(query.OrgId))
+	for _, word := range strings.Fields(query.Text) {
+		// the line is JSON, so words that are escaped in JSON are only matched by matches.
+		if quoted, _ := json.Marshal(word); string(quoted) == `"`+word+`"` {
+			fmt.Fprintf(&b, " |~ %s", strconv.Quote("(?i)"+regexp.QuoteMeta(word)))
+		}
+	}
+	b.WriteString(" | json")
+	for _, f := range []struct {
+		label string
+		value int64
+	}{
+		{"id", query.AnnotationId},
+		{"alert_id", query.AlertId},
+		{"dashboard_id", query.DashboardId},
+		{"panel_id", query.PanelId},
+		{"user_id", query.UserId},
+	} {
+		if f.value != 0 {
+			fmt.Fprintf(&b, ` | %s="%d"`, f.label, f.value)
+		}
+	}
+	return b.String()
+}
+
+// matches applies the filters of the query to the annotation, like the filters of the database.
+func matches(e *entry, query *annotations.ItemQuery) bool {
+	if (query.AnnotationId != 0 && e.ID != query.AnnotationId) ||
+		(query.AlertId != 0 && e.AlertID != query.AlertId) ||
+		(query.DashboardId != 0 && e.DashboardID != query.DashboardId) ||
+		(query.PanelId != 0 && e.PanelID != query.PanelId) ||
+		(query.UserId != 0 && e.UserID != query.UserId) {
+		return false
+	}
+	if query.From > 0 && query.To > 0 && (e.Epoch > query.To || e.EpochEnd < query.From) {
+		return false
+	}
+
+	switch query.Type {
+	case "alert":
+		if e.AlertID == 0 {
+			return false
+		}
+	case "annotation":
+		if e.AlertID != 0 {
+			return false
+		}
+	case "region":
+		if e.EpochEnd <= e.Epoch {
+			return false
+		}
+	}
+
+	text := strings.ToLower(e.Text)
+	for _, word := range strings.Fields(query.Text) {
+		if !strings.Contains(text, strings.ToLower(word)) {
+			return false
+		}
+	}
+
+	tags := models.ParseTagPairs(e.Tags)
+	if wanted := models.ParseTagPairs(query.Tags); len(wanted) > 0 {
+		found := 0
+		for _, w := range wanted {
+			for _, t := range tags {
+				if t.Key == w.Key && (w.Value == "" || t.Value == w.Value) {
+					found++
+					break
+				}
+			}
+		}
+		if (query.MatchAny && found == 0) || (!query.MatchAny && found < len(wanted)) {
+			return false
+		}
+	}
+
+	for _, matcher := range query.TagMatchers {
+		matched := false
+		for _, t := range tags {
+			if t.Key == matcher.Key && matcher.MatchesValue(t.Value) {
+				matched = true
+				break
+			}
+		}
+		if matched == matcher.Negative() {
+			return false
+		}
+	}
+	return true
+}
+
+func streamLabels(orgID int64) map[string]string {
+	return map[string]string{"source": "grafana", "org_id": strconv.FormatInt(orgID, 10)}
+}
+
+func streamSelector(orgID int64) string {
+	return fmt.Sprintf(`{source="grafana",org_id="%d"}`, orgID)
+}
+
+type pushRequest struct {
+	Streams []pushStream `json:"streams"`
+}
+
+type pushStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// query returns the annotations of the log lines of the LogQL query, the most recent first.
+func (r *Repository) query(ctx context.Context, logQL string, from, to time.Time) ([]*entry, error) {
+	params := url.Values{}
+	params.Set("query", logQL)
+	params.Set("start", strconv.FormatInt(from.UnixNano(), 10))
+	// the end of a Loki query is exclusive.
+	params.Set("end", strconv.FormatInt(to.UnixNano()+1, 10))
+	params.Set("limit", strconv.Itoa(r.cfg.LokiMaxLines))
+	params.Set("direction", "backward")
+
+	req, err := r.newRequest(ctx, http.MethodGet, "/loki/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := r.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var res queryResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("failed to parse the response of Loki: %w", err)
+	}
+	if res.Status != "success" {
+		return nil, fmt.Errorf("query to Loki failed with status %q", res.Status)
+	}
+
+	var entries []*entry
+	for _, stream := range res.Data.Result {
+		for _, value := range stream.Values {
+			var e entry
+			if err := json.Unmarshal([]byte(value[1]), &e); err != nil {
+				r.log.Warn("skipping log line that is not an annotation", "line", value[1], "err", err)
+				continue
+			}
+			entries = append(entries, &e)
+		}
+	}
+	if len(entries) >= r.cfg.LokiMaxLines {
+		r.log.Warn("query reached the maximum number of lines, some annotations may be missing", "query", logQL, "maxLines", r.cfg.LokiMaxLines)
+	}
+	return entries, nil
+}
+
+func (r *Repository) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, r.cfg.LokiURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if r.cfg.LokiTenantID != "" {
+		req.Header.Set("X-Scope-OrgID", r.cfg.LokiTenantID)
+	}
+	if r.cfg.LokiBasicAuthUser != "" {
+		req.SetBasicAuth(r.cfg.LokiBasicAuthUser, r.cfg.LokiBasicAuthPass)
+	}
+	return req, nil
+}
+
+func (r *Repository) do(req *http.Request) ([]byte, error) {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to Loki failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			r.log.Warn("failed to close response body", "err", err)
+		}
+	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of Loki: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("request to Loki failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
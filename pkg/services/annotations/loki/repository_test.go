@@ -0,0 +1,233 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeLoki keeps the pushed lines in memory. Its queries only select the stream and the time range.
+type fakeLoki struct {
+	mtx     sync.Mutex
+	streams map[string][][2]string
+	queries []string
+	headers http.Header
+}
+
+var orgIDLabel = regexp.MustCompile(`org_id="(\d+)"`)
+
+func newFakeLoki(t *testing.T) (*fakeLoki, *httptest.Server) {
+	t.Helper()
+	f := &fakeLoki{streams: map[string][][2]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeLoki) handle(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.headers = r.Header.Clone()
+
+	switch r.URL.Path {
+	case "/loki/api/v1/push":
+		var req pushRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, s := range req.Streams {
+			f.streams[s.Stream["org_id"]] = append(f.streams[s.Stream["org_id"]], s.Values...)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case "/loki/api/v1/query_range":
+		query := r.URL.Query().Get("query")
+		f.queries = append(f.queries, query)
+		start, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		end, _ := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+
+		var res queryResponse
+		res.Status = "success"
+		res.Data.ResultType = "streams"
+		orgID := orgIDLabel.FindStringSubmatch(query)[1]
+		var values [][2]string
+		for _, v := range f.streams[orgID] {
+			ts, _ := strconv.ParseInt(v[0], 10, 64)
+			if ts >= start && ts < end {
+				values = append(values, v)
+			}
+		}
+		res.Data.Result = append(res.Data.Result, struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		}{Stream: streamLabels(1), Values: values})
+		_ = json.NewEncoder(w).Encode(res)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T, readFilter ReadFilter) (*Repository, *fakeLoki) {
+		t.Helper()
+		f, srv := newFakeLoki(t)
+		repo := NewRepository(setting.AnnotationStorageSettings{
+			Backend:           setting.AnnotationBackendLoki,
+			LokiURL:           srv.URL,
+			LokiTenantID:      "tenant",
+			LokiBasicAuthUser: "user",
+			LokiBasicAuthPass: "pass",
+			LokiTimeout:       time.Second,
+			LokiMaxLines:      100,
+		}, readFilter)
+		return repo, f
+	}
+
+	t.Run("saved annotations are found", func(t *testing.T) {
+		repo, f := setup(t, nil)
+		deploy := &annotations.Item{OrgId: 1, DashboardId: 1, PanelId: 2, Text: "Deployed version 1.2", Epoch: 1000, EpochEnd: 1500, Tags: []string{"deploy", "env:prod"}}
+		alert := &annotations.Item{OrgId: 1, AlertId: 3, Text: "CPU alert", Epoch: 2000, NewState: "alerting", PrevState: "ok"}
+		other := &annotations.Item{OrgId: 2, Text: "other org", Epoch: 1000}
+		for _, item := range []*annotations.Item{deploy, alert, other} {
+			require.NoError(t, repo.Save(item))
+			require.NotZero(t, item.Id)
+		}
+		require.NotEqual(t, deploy.Id, alert.Id)
+		require.Equal(t, "tenant", f.headers.Get("X-Scope-OrgID"))
+		user, pass, ok := (&http.Request{Header: f.headers}).BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", user)
+		require.Equal(t, "pass", pass)
+
+		items, err := repo.Find(ctx, &annotations.ItemQuery{OrgId: 1, From: 500, To: 3000})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		require.Equal(t, alert.Id, items[0].Id)
+		require.Equal(t, int64(2000), items[0].TimeEnd)
+		require.Equal(t, "alerting", items[0].NewState)
+		require.Equal(t, deploy.Id, items[1].Id)
+		require.Equal(t, int64(1000), items[1].Time)
+		require.Equal(t, int64(1500), items[1].TimeEnd)
+		require.Equal(t, []string{"deploy", "env:prod"}, items[1].Tags)
+
+		// without a time range, the annotations of the last days are found.
+		recent := &annotations.Item{OrgId: 1, Text: "recent"}
+		require.NoError(t, repo.Save(recent))
+		items, err = repo.Find(ctx, &annotations.ItemQuery{OrgId: 1, AnnotationId: recent.Id})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		require.Equal(t, "recent", items[0].Text)
+	})
+
+	t.Run("filters of the query are applied", func(t *testing.T) {
+		repo, f := setup(t, nil)
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, DashboardId: 1, Text: "Deployed version 1.2", Epoch: 1000, EpochEnd: 1500, Tags: []string{"deploy", "env:prod"}}))
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, DashboardId: 1, Text: "Deployed version 1.3", Epoch: 1200, Tags: []string{"deploy", "env:dev"}}))
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, AlertId: 3, Text: "CPU alert", Epoch: 2000}))
+
+		texts := func(query *annotations.ItemQuery) []string {
+			t.Helper()
+			query.OrgId = 1
+			if query.From == 0 {
+				query.From, query.To = 500, 3000
+			}
+			items, err := repo.Find(ctx, query)
+			require.NoError(t, err)
+			result := make([]string, 0, len(items))
+			for _, item := range items {
+				result = append(result, item.Text)
+			}
+			return result
+		}
+
+		require.Equal(t, []string{"Deployed version 1.2", "Deployed version 1.3"}, texts(&annotations.ItemQuery{DashboardId: 1}))
+		require.Equal(t, []string{"CPU alert"}, texts(&annotations.ItemQuery{Type: "alert"}))
+		require.Equal(t, []string{"Deployed version 1.2"}, texts(&annotations.ItemQuery{Type: "region"}))
+		require.Equal(t, []string{"Deployed version 1.3"}, texts(&annotations.ItemQuery{Text: "deployed 1.3"}))
+		require.Equal(t, []string{"Deployed version 1.3"}, texts(&annotations.ItemQuery{Tags: []string{"deploy", "env:dev"}}))
+		require.Equal(t, []string{"Deployed version 1.2", "Deployed version 1.3"}, texts(&annotations.ItemQuery{Tags: []string{"env:prod", "env:dev"}, MatchAny: true}))
+		// the region overlaps the time range but it is not found, because its log line is at the time it starts.
+		require.Equal(t, []string{"Deployed version 1.3"}, texts(&annotations.ItemQuery{From: 1100, To: 1300}))
+		require.Equal(t, []string{"Deployed version 1.2"}, texts(&annotations.ItemQuery{Limit: 1, Type: "annotation"}))
+
+		notProd, err := annotations.ParseTagMatcher("env!~prod|staging")
+		require.NoError(t, err)
+		require.Equal(t, []string{"CPU alert", "Deployed version 1.3"}, texts(&annotations.ItemQuery{TagMatchers: []*annotations.TagMatcher{notProd}}))
+
+		require.Contains(t, f.queries[len(f.queries)-1], `{source="grafana",org_id="1"} | json`)
+		texts(&annotations.ItemQuery{DashboardId: 1, Text: "deployed"})
+		require.Equal(t, `{source="grafana",org_id="1"} |~ "(?i)deployed" | json | dashboard_id="1"`, f.queries[len(f.queries)-1])
+	})
+
+	t.Run("annotations the user cannot read are filtered", func(t *testing.T) {
+		repo, _ := setup(t, func(ctx context.Context, user *models.SignedInUser) (func(int64) bool, error) {
+			return func(dashboardID int64) bool { return dashboardID == 1 }, nil
+		})
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, DashboardId: 1, Text: "visible", Epoch: 1000}))
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, DashboardId: 2, Text: "hidden", Epoch: 1000}))
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, Text: "organization", Epoch: 1000}))
+
+		items, err := repo.Find(ctx, &annotations.ItemQuery{OrgId: 1, From: 500, To: 3000, SignedInUser: &models.SignedInUser{OrgId: 1}})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		require.Equal(t, "visible", items[0].Text)
+	})
+
+	t.Run("annotations are counted", func(t *testing.T) {
+		repo, _ := setup(t, nil)
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, Text: "a", Epoch: 1000}))
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, Text: "region", Epoch: 1500, EpochEnd: 2500}))
+
+		buckets, err := repo.Count(ctx, &annotations.CountQuery{ItemQuery: annotations.ItemQuery{OrgId: 1, From: 1000, To: 4000}, Interval: 1000})
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.Bucket{{Time: 1000, Count: 2}, {Time: 2000, Count: 1}, {Time: 3000, Count: 0}}, buckets)
+	})
+
+	t.Run("tags are counted", func(t *testing.T) {
+		repo, _ := setup(t, nil)
+		now := time.Now().UnixNano() / int64(time.Millisecond)
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, Text: "a", Tags: []string{"deploy", "env:prod"}, Epoch: now}))
+		require.NoError(t, repo.Save(&annotations.Item{OrgId: 1, Text: "b", Tags: []string{"deploy", "env:dev"}, Epoch: now}))
+
+		result, err := repo.FindTags(ctx, &annotations.TagsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.TagsDTO{{Tag: "deploy", Count: 2}, {Tag: "env:dev", Count: 1}, {Tag: "env:prod", Count: 1}}, result.Tags)
+
+		result, err = repo.FindTags(ctx, &annotations.TagsQuery{OrgID: 1, Tag: "pro"})
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.TagsDTO{{Tag: "env:prod", Count: 1}}, result.Tags)
+	})
+
+	t.Run("annotations cannot be changed", func(t *testing.T) {
+		repo, _ := setup(t, nil)
+		require.ErrorIs(t, repo.Update(ctx, &annotations.Item{Id: 1}), annotations.ErrNotSupported)
+		require.ErrorIs(t, repo.Delete(ctx, &annotations.DeleteParams{Id: 1}), annotations.ErrNotSupported)
+	})
+
+	t.Run("errors of Loki are returned", func(t *testing.T) {
+		repo := NewRepository(setting.AnnotationStorageSettings{LokiURL: "http://127.0.0.1:1", LokiTimeout: time.Second, LokiMaxLines: 10}, nil)
+		require.Error(t, repo.Save(&annotations.Item{OrgId: 1, Text: "a"}))
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "entry too far behind", http.StatusBadRequest)
+		}))
+		t.Cleanup(srv.Close)
+		repo = NewRepository(setting.AnnotationStorageSettings{LokiURL: srv.URL, LokiTimeout: time.Second, LokiMaxLines: 10}, nil)
+		require.ErrorContains(t, repo.Save(&annotations.Item{OrgId: 1, Text: "a"}), "entry too far behind")
+		_, err := repo.Find(ctx, &annotations.ItemQuery{OrgId: 1})
+		require.ErrorContains(t, err, "status 400")
+	})
+}
@@ -0,0 +1,87 @@
+package annotations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TagMatchType is the type of a TagMatcher.
+type TagMatchType string
+
+const (
+	TagMatchEqual     TagMatchType = "="
+	TagMatchNotEqual  TagMatchType = "!="
+	TagMatchRegexp    TagMatchType = "=~"
+	TagMatchNotRegexp TagMatchType = "!~"
+)
+
+// TagMatcher matches the annotations by the values of their tags with the key of the matcher.
+// A key without a value, e.g. the tag "deploy", has an empty value.
+type TagMatcher struct {
+	Key   string       `json:"key"`
+	Type  TagMatchType `json:"type"`
+	Value string       `json:"value"`
+
+	re *regexp.Regexp
+}
+
+// NewTagMatcher returns a matcher of the tags with the key. The regular expression of a regexp matcher must match
+// the whole value of the tag.
+func NewTagMatcher(t TagMatchType, key, value string) (*TagMatcher, error) {
+	if key == "" {
+		return nil, fmt.Errorf("tag matcher must have a key")
+	}
+	m := &TagMatcher{Key: key, Type: t, Value: value}
+	switch t {
+	case TagMatchEqual, TagMatchNotEqual:
+	case TagMatchRegexp, TagMatchNotRegexp:
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression of tag matcher %s: %w", key, err)
+		}
+		m.re = re
+	default:
+		return nil, fmt.Errorf("unknown type of tag matcher %q", t)
+	}
+	return m, nil
+}
+
+// ParseTagMatcher parses a matcher of the form key=value, key!=value, key=~regexp or key!~regexp.
+func ParseTagMatcher(s string) (*TagMatcher, error) {
+	// the longer operators go first so that "!=" is not read as "=" with the key ending with "!".
+	for _, t := range []TagMatchType{TagMatchNotRegexp, TagMatchRegexp, TagMatchNotEqual, TagMatchEqual} {
+		if i := strings.Index(s, string(t)); i > 0 && !strings.Contains(s[:i], "=") {
+			return NewTagMatcher(t, strings.TrimSpace(s[:i]), s[i+len(t):])
+		}
+	}
+	return nil, fmt.Errorf("invalid tag matcher %q", s)
+}
+
+// Negative returns true if the matcher excludes the annotations that have a tag whose value matches.
+func (m *TagMatcher) Negative() bool {
+	return m.Type == TagMatchNotEqual || m.Type == TagMatchNotRegexp
+}
+
+// MatchesValue returns true if the value of a tag with the key of the matcher is equal to the value of the matcher
+// or matches its regular expression, regardless of whether the matcher is negative.
+func (m *TagMatcher) MatchesValue(value string) bool {
+	switch m.Type {
+	case TagMatchRegexp, TagMatchNotRegexp:
+		re := m.re
+		if re == nil {
+			// the matcher was not created by NewTagMatcher, e.g. it was unmarshalled.
+			var err error
+			if re, err = regexp.Compile("^(?:" + m.Value + ")$"); err != nil {
+				return false
+			}
+		}
+		return re.MatchString(value)
+	default:
+		return m.Value == value
+	}
+}
+
+func (m *TagMatcher) String() string {
+	return m.Key + string(m.Type) + m.Value
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/remotecache"
@@ -24,11 +25,33 @@ func ProvideService(cfg *setting.Cfg, remoteCache *remotecache.RemoteCache) (*Au
 	return s, nil
 }
 
+// NewVerifier returns a service that verifies JSON Web Tokens with the keys and the claim expectations of settings,
+// independently of the [auth.jwt] configuration.
+func NewVerifier(settings VerifierSettings, remoteCache *remotecache.RemoteCache, logger log.Logger) (*AuthService, error) {
+	s := &AuthService{
+		RemoteCache: remoteCache,
+		settings:    settings,
+		log:         logger,
+	}
+	if err := s.initVerifier(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
 func newService(cfg *setting.Cfg, remoteCache *remotecache.RemoteCache) *AuthService {
 	return &AuthService{
 		Cfg:         cfg,
 		RemoteCache: remoteCache,
-		log:         log.New("auth.jwt"),
+		settings: VerifierSettings{
+			KeyFile:      cfg.JWTAuthKeyFile,
+			JWKSetFile:   cfg.JWTAuthJWKSetFile,
+			JWKSetURL:    cfg.JWTAuthJWKSetURL,
+			CacheTTL:     cfg.JWTAuthCacheTTL,
+			ExpectClaims: cfg.JWTAuthExpectClaims,
+		},
+		log: log.New("auth.jwt"),
 	}
 }
 
@@ -37,6 +60,10 @@ func (s *AuthService) init() error {
 		return nil
 	}
 
+	return s.initVerifier()
+}
+
+func (s *AuthService) initVerifier() error {
 	if err := s.initClaimExpectations(); err != nil {
 		return err
 	}
@@ -47,10 +74,21 @@ func (s *AuthService) init() error {
 	return nil
 }
 
+// VerifierSettings configures where the keys that sign the tokens are loaded from,
+// and the claims that the tokens are expected to have.
+type VerifierSettings struct {
+	KeyFile      string
+	JWKSetFile   string
+	JWKSetURL    string
+	CacheTTL     time.Duration
+	ExpectClaims string
+}
+
 type AuthService struct {
 	Cfg         *setting.Cfg
 	RemoteCache *remotecache.RemoteCache
 
+	settings         VerifierSettings
 	keySet           keySet
 	log              log.Logger
 	expect           map[string]interface{}
@@ -8,6 +8,7 @@ import (
 	"path"
 	"time"
 
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 
@@ -19,11 +20,12 @@ import (
 )
 
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
-	shortURLService shorturls.Service, store sqlstore.Store) *CleanUpService {
+	shortURLService shorturls.Service, store sqlstore.Store, serviceAccounts serviceaccounts.Service) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:               cfg,
 		ServerLockService: serverLockService,
 		ShortURLService:   shortURLService,
+		ServiceAccounts:   serviceAccounts,
 		store:             store,
 		log:               log.New("cleanup"),
 	}
@@ -36,6 +38,7 @@ type CleanUpService struct {
 	Cfg               *setting.Cfg
 	ServerLockService *serverlock.ServerLockService
 	ShortURLService   shorturls.Service
+	ServiceAccounts   serviceaccounts.Service
 }
 
 func (srv *CleanUpService) Run(ctx context.Context) error {
@@ -61,6 +64,13 @@ func (srv *CleanUpService) Run(ctx context.Context) error {
 			if err != nil {
 				srv.log.Error("failed to lock and execute cleanup of old login attempts", "error", err)
 			}
+			err = srv.ServerLockService.LockAndExecute(ctx, "handle stale service account tokens",
+				time.Hour, func(context.Context) {
+					srv.handleStaleServiceAccountTokens(ctx)
+				})
+			if err != nil {
+				srv.log.Error("failed to lock and execute handling of stale service account tokens", "error", err)
+			}
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -81,6 +91,7 @@ func (srv *CleanUpService) cleanUpTmpFiles() {
 	folders := []string{
 		srv.Cfg.ImagesDir,
 		srv.Cfg.CSVsDir,
+		srv.Cfg.PDFsDir,
 	}
 
 	for _, f := range folders {
@@ -183,3 +194,9 @@ func (srv *CleanUpService) deleteStaleShortURLs(ctx context.Context) {
 		srv.log.Debug("Deleted short urls", "rows affected", cmd.NumDeleted)
 	}
 }
+
+func (srv *CleanUpService) handleStaleServiceAccountTokens(ctx context.Context) {
+	if err := srv.ServiceAccounts.HandleStaleTokens(ctx); err != nil {
+		srv.log.Error("Problem handling stale service account tokens", "error", err)
+	}
+}
@@ -36,6 +36,9 @@ const (
 
 const ServiceName = "ContextHandler"
 
+// apiKeyLastUsedUpdateInterval is how often the last use of an API key is recorded.
+const apiKeyLastUsedUpdateInterval = time.Minute
+
 func ProvideService(cfg *setting.Cfg, tokenService models.UserTokenService, jwtService models.JWTService,
 	remoteCache *remotecache.RemoteCache, renderService rendering.Service, sqlStore sqlstore.Store,
 	tracer tracing.Tracer, authProxy *authproxy.AuthProxy, loginService login.Service, authenticator loginpkg.Authenticator) *ContextHandler {
@@ -243,6 +246,18 @@ func (h *ContextHandler) initContextWithAPIKey(reqContext *models.ReqContext) bo
 		return true
 	}
 
+	if apikey.IsRevoked != nil && *apikey.IsRevoked {
+		reqContext.JsonApiErr(401, "Revoked API key", models.ErrApiKeyRevoked)
+		return true
+	}
+
+	// the last use is only recorded once in a while to avoid a database write on every request
+	if apikey.LastUsedAt == nil || getTime().Sub(*apikey.LastUsedAt) >= apiKeyLastUsedUpdateInterval {
+		if err := h.SQLStore.UpdateAPIKeyLastUsed(reqContext.Req.Context(), apikey.Id, getTime(), reqContext.RemoteAddr()); err != nil {
+			reqContext.Logger.Warn("Failed to record last use of API key", "id", apikey.Id, "error", err)
+		}
+	}
+
 	if apikey.ServiceAccountId == nil || *apikey.ServiceAccountId < 1 { //There is no service account attached to the apikey
 		//Use the old APIkey method.  This provides backwards compatibility.
 		reqContext.SignedInUser = &models.SignedInUser{}
@@ -0,0 +1,253 @@
+package hashicorpvault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+// Provider encrypts and decrypts the data encryption keys with a named key
+// of the transit secrets engine of HashiCorp Vault.
+type Provider struct {
+	id     secrets.ProviderID
+	cfg    Settings
+	client *http.Client
+	log    log.Logger
+
+	mtx         sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func New(id secrets.ProviderID, cfg Settings) *Provider {
+	p := &Provider{
+		id:     id,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		log:    log.New("secrets.hashicorpvault"),
+	}
+	if cfg.AuthMethod == AuthMethodToken {
+		p.token = cfg.Token
+	}
+	return p
+}
+
+type transitResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+}
+
+type authResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int64  `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+func (p *Provider) Encrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	var resp transitResponse
+	body := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(blob)}
+	if err := p.transit(ctx, "encrypt", body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("%s: vault returned an empty ciphertext", p.id)
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+func (p *Provider) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	var resp transitResponse
+	body := map[string]string{"ciphertext": string(blob)}
+	if err := p.transit(ctx, "decrypt", body, &resp); err != nil {
+		return nil, err
+	}
+	decrypted, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to decode the plaintext returned by vault: %w", p.id, err)
+	}
+	return decrypted, nil
+}
+
+// Run renews the token of the provider periodically so that it doesn't expire.
+// Tokens issued by the AppRole auth method are replaced by a new one when they can't be renewed.
+func (p *Provider) Run(ctx context.Context) error {
+	if p.cfg.TokenRenewalInterval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(p.cfg.TokenRenewalInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.renewToken(ctx); err != nil {
+				p.log.Error("Failed to renew vault token", "provider", p.id, "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *Provider) renewToken(ctx context.Context) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.cfg.AuthMethod == AuthMethodAppRole && (p.token == "" || p.expiresWithin(2*p.cfg.TokenRenewalInterval)) {
+		return p.login(ctx)
+	}
+
+	var resp authResponse
+	if err := p.do(ctx, "auth/token/renew-self", p.token, struct{}{}, &resp); err != nil {
+		if p.cfg.AuthMethod == AuthMethodAppRole {
+			return p.login(ctx)
+		}
+		return err
+	}
+	p.setTokenExpiry(resp.Auth.LeaseDuration)
+	p.log.Debug("Renewed vault token", "provider", p.id)
+	return nil
+}
+
+func (p *Provider) transit(ctx context.Context, operation string, body interface{}, out interface{}) error {
+	path := fmt.Sprintf("%s/%s/%s", strings.Trim(p.cfg.TransitEnginePath, "/"), operation, url.PathEscape(p.cfg.KeyRing))
+
+	token, err := p.currentToken(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	err = p.do(ctx, path, token, body, out)
+	var statusErr *statusError
+	if p.cfg.AuthMethod == AuthMethodAppRole && errors.As(err, &statusErr) && statusErr.code == http.StatusForbidden {
+		// the token might have expired or been revoked, try again once with a new one
+		if token, err = p.currentToken(ctx, true); err != nil {
+			return err
+		}
+		err = p.do(ctx, path, token, body, out)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to %s with vault: %w", p.id, operation, err)
+	}
+	return nil
+}
+
+func (p *Provider) currentToken(ctx context.Context, forceLogin bool) (string, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.cfg.AuthMethod == AuthMethodAppRole && (forceLogin || p.token == "" || p.expiresWithin(10*time.Second)) {
+		if err := p.login(ctx); err != nil {
+			return "", err
+		}
+	}
+	return p.token, nil
+}
+
+// login gets a new token from the AppRole auth method. It must be called with the lock held.
+func (p *Provider) login(ctx context.Context) error {
+	var resp authResponse
+	body := map[string]string{"role_id": p.cfg.RoleID, "secret_id": p.cfg.SecretID}
+	path := fmt.Sprintf("auth/%s/login", strings.Trim(p.cfg.AppRolePath, "/"))
+	if err := p.do(ctx, path, "", body, &resp); err != nil {
+		return fmt.Errorf("%s: failed to log in to vault with approle: %w", p.id, err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return fmt.Errorf("%s: vault returned no token on approle login", p.id)
+	}
+	p.token = resp.Auth.ClientToken
+	p.setTokenExpiry(resp.Auth.LeaseDuration)
+	return nil
+}
+
+func (p *Provider) expiresWithin(d time.Duration) bool {
+	return !p.tokenExpiry.IsZero() && time.Now().Add(d).After(p.tokenExpiry)
+}
+
+// setTokenExpiry sets the expiry of the token. A lease of zero seconds means that the token doesn't expire.
+func (p *Provider) setTokenExpiry(leaseSeconds int64) {
+	if leaseSeconds <= 0 {
+		p.tokenExpiry = time.Time{}
+		return
+	}
+	p.tokenExpiry = time.Now().Add(time.Duration(leaseSeconds) * time.Second)
+}
+
+type statusError struct {
+	code   int
+	errors []string
+}
+
+func (e *statusError) Error() string {
+	if len(e.errors) == 0 {
+		return fmt.Sprintf("vault responded with status %d", e.code)
+	}
+	return fmt.Sprintf("vault responded with status %d: %s", e.code, strings.Join(e.errors, ", "))
+}
+
+func (p *Provider) do(ctx context.Context, path, token string, body interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	u := strings.TrimSuffix(p.cfg.URL, "/") + "/v1/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			p.log.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	respBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode/100 != 2 {
+		var errResp errorResponse
+		_ = json.Unmarshal(respBody, &errResp)
+		return &statusError{code: resp.StatusCode, errors: errResp.Errors}
+	}
+
+	if len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,194 @@
+package hashicorpvault
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeVault implements the endpoints of the transit secrets engine, the AppRole login and the token renewal.
+// The ciphertexts are the plaintexts prefixed with the key version.
+type fakeVault struct {
+	mtx      sync.Mutex
+	tokens   map[string]bool
+	logins   int
+	renewals int
+	requests []*http.Request
+}
+
+func newFakeVault(t *testing.T, tokens ...string) (*fakeVault, *httptest.Server) {
+	t.Helper()
+	v := &fakeVault{tokens: map[string]bool{}}
+	for _, token := range tokens {
+		v.tokens[token] = true
+	}
+	server := httptest.NewServer(http.HandlerFunc(v.handle))
+	t.Cleanup(server.Close)
+	return v, server
+}
+
+func (v *fakeVault) revoke(token string) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	delete(v.tokens, token)
+}
+
+func (v *fakeVault) handle(w http.ResponseWriter, r *http.Request) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	v.requests = append(v.requests, r)
+
+	var body map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": []string{err.Error()}})
+		return
+	}
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": []string{"invalid role or secret ID"}})
+			return
+		}
+		v.logins++
+		token := "approle-token-" + string(rune('0'+v.logins))
+		v.tokens[token] = true
+		writeJSON(w, http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{"client_token": token, "lease_duration": 3600}})
+		return
+	}
+
+	if !v.tokens[r.Header.Get("X-Vault-Token")] {
+		writeJSON(w, http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/auth/token/renew-self":
+		v.renewals++
+		writeJSON(w, http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{"client_token": r.Header.Get("X-Vault-Token"), "lease_duration": 3600}})
+	case "/v1/transit/encrypt/grafana":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"ciphertext": "vault:v1:" + body["plaintext"]}})
+	case "/v1/transit/decrypt/grafana":
+		if !strings.HasPrefix(body["ciphertext"], "vault:v1:") {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": []string{"invalid ciphertext: no prefix"}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"plaintext": strings.TrimPrefix(body["ciphertext"], "vault:v1:")}})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"errors": []string{}})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("data is encrypted and decrypted with the transit engine", func(t *testing.T) {
+		vault, server := newFakeVault(t, "root-token")
+		p := New("hashicorpvault.test", Settings{URL: server.URL, Namespace: "team", TransitEnginePath: "transit", KeyRing: "grafana", AuthMethod: AuthMethodToken, Token: "root-token"})
+
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		require.Equal(t, "vault:v1:"+base64.StdEncoding.EncodeToString([]byte("data key")), string(encrypted))
+
+		decrypted, err := p.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, []byte("data key"), decrypted)
+
+		require.Equal(t, "team", vault.requests[0].Header.Get("X-Vault-Namespace"))
+	})
+
+	t.Run("errors of vault are returned", func(t *testing.T) {
+		_, server := newFakeVault(t, "root-token")
+		p := New("hashicorpvault.test", Settings{URL: server.URL, TransitEnginePath: "transit", KeyRing: "grafana", AuthMethod: AuthMethodToken, Token: "root-token"})
+
+		_, err := p.Decrypt(ctx, []byte("not encrypted"))
+		require.EqualError(t, err, "hashicorpvault.test: failed to decrypt with vault: vault responded with status 400: invalid ciphertext: no prefix")
+
+		p = New("hashicorpvault.test", Settings{URL: server.URL, TransitEnginePath: "transit", KeyRing: "grafana", AuthMethod: AuthMethodToken, Token: "invalid"})
+		_, err = p.Encrypt(ctx, []byte("data key"))
+		require.EqualError(t, err, "hashicorpvault.test: failed to encrypt with vault: vault responded with status 403: permission denied")
+	})
+
+	t.Run("approle logs in again when the token is revoked", func(t *testing.T) {
+		vault, server := newFakeVault(t)
+		p := New("hashicorpvault.test", Settings{URL: server.URL, TransitEnginePath: "transit", KeyRing: "grafana", AuthMethod: AuthMethodAppRole, AppRolePath: "approle", RoleID: "role", SecretID: "secret"})
+
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		require.Equal(t, 1, vault.logins)
+
+		vault.revoke("approle-token-1")
+		decrypted, err := p.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, []byte("data key"), decrypted)
+		require.Equal(t, 2, vault.logins)
+	})
+
+	t.Run("token is renewed in the background", func(t *testing.T) {
+		vault, server := newFakeVault(t, "root-token")
+		p := New("hashicorpvault.test", Settings{URL: server.URL, TransitEnginePath: "transit", KeyRing: "grafana", AuthMethod: AuthMethodToken, Token: "root-token", TokenRenewalInterval: 10 * time.Millisecond})
+
+		ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, p.Run(ctx), context.DeadlineExceeded)
+
+		vault.mtx.Lock()
+		defer vault.mtx.Unlock()
+		require.Greater(t, vault.renewals, 0)
+	})
+}
+
+func TestReadSettings(t *testing.T) {
+	read := func(t *testing.T, section string) (Settings, error) {
+		t.Helper()
+		raw, err := ini.Load([]byte("[security.encryption.hashicorpvault.test]\n" + section))
+		require.NoError(t, err)
+		settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw}}
+		return ReadSettings(settings.Section("security.encryption.hashicorpvault.test"))
+	}
+
+	t.Run("defaults are applied", func(t *testing.T) {
+		s, err := read(t, "url = http://localhost:8200\nkey_ring = grafana\ntoken = root-token")
+		require.NoError(t, err)
+		require.Equal(t, Settings{
+			URL:                  "http://localhost:8200",
+			TransitEnginePath:    "transit",
+			KeyRing:              "grafana",
+			AuthMethod:           AuthMethodToken,
+			Token:                "root-token",
+			AppRolePath:          "approle",
+			TokenRenewalInterval: 5 * time.Minute,
+			Timeout:              10 * time.Second,
+		}, s)
+	})
+
+	t.Run("invalid settings return an error", func(t *testing.T) {
+		_, err := read(t, "key_ring = grafana\ntoken = root-token")
+		require.EqualError(t, err, "url is required")
+
+		_, err = read(t, "url = http://localhost:8200\nkey_ring = grafana")
+		require.EqualError(t, err, "token is required with the token auth method")
+
+		_, err = read(t, "url = http://localhost:8200\nkey_ring = grafana\nauth_method = approle\nrole_id = role")
+		require.EqualError(t, err, "role_id and secret_id are required with the approle auth method")
+
+		_, err = read(t, "url = http://localhost:8200\nkey_ring = grafana\nauth_method = userpass")
+		require.EqualError(t, err, `unsupported auth_method "userpass", expected "token" or "approle"`)
+	})
+}
@@ -0,0 +1,70 @@
+package hashicorpvault
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// Kind is the kind of the provider identifiers, e.g. hashicorpvault.my-key.
+	Kind = "hashicorpvault"
+
+	AuthMethodToken   = "token"
+	AuthMethodAppRole = "approle"
+)
+
+// Settings are read from the [security.encryption.hashicorpvault.<KEY-NAME>] section of the provider.
+type Settings struct {
+	URL                  string
+	Namespace            string
+	TransitEnginePath    string
+	KeyRing              string
+	AuthMethod           string
+	Token                string
+	AppRolePath          string
+	RoleID               string
+	SecretID             string
+	TokenRenewalInterval time.Duration
+	Timeout              time.Duration
+}
+
+func ReadSettings(section setting.Section) (Settings, error) {
+	s := Settings{
+		URL:                  section.KeyValue("url").MustString(""),
+		Namespace:            section.KeyValue("namespace").MustString(""),
+		TransitEnginePath:    section.KeyValue("transit_engine_path").MustString("transit"),
+		KeyRing:              section.KeyValue("key_ring").MustString(""),
+		AuthMethod:           section.KeyValue("auth_method").MustString(AuthMethodToken),
+		Token:                section.KeyValue("token").MustString(""),
+		AppRolePath:          section.KeyValue("approle_path").MustString("approle"),
+		RoleID:               section.KeyValue("role_id").MustString(""),
+		SecretID:             section.KeyValue("secret_id").MustString(""),
+		TokenRenewalInterval: section.KeyValue("token_renewal_interval").MustDuration(5 * time.Minute),
+		Timeout:              section.KeyValue("timeout").MustDuration(10 * time.Second),
+	}
+
+	if s.URL == "" {
+		return s, errors.New("url is required")
+	}
+	if s.KeyRing == "" {
+		return s, errors.New("key_ring is required")
+	}
+
+	switch s.AuthMethod {
+	case AuthMethodToken:
+		if s.Token == "" {
+			return s, errors.New("token is required with the token auth method")
+		}
+	case AuthMethodAppRole:
+		if s.RoleID == "" || s.SecretID == "" {
+			return s, errors.New("role_id and secret_id are required with the approle auth method")
+		}
+	default:
+		return s, fmt.Errorf("unsupported auth_method %q, expected %q or %q", s.AuthMethod, AuthMethodToken, AuthMethodAppRole)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,211 @@
+// Package keyfile implements a key encryption key provider that loads versioned keys from a local keyring file.
+//
+// Each line of the keyring file holds the version of a key and the base64 encoded key, separated by whitespace:
+//
+//	# version key
+//	1 kTUgZ3l1G0mkCRsj+HEnGl1nEdNvgzq0R6vJXk3rzA8=
+//	2 XSb2Rg1X0qjvnwD0cXmtuI0LFYrM1/HH8/eOj12Uf3c=
+//
+// The keys must be 16, 24 or 32 bytes long for AES-128, AES-192 or AES-256 in GCM mode. The data encryption keys are
+// encrypted with the key of the highest version, and decrypted with the key of the version they were encrypted with,
+// so a key is rotated by appending a new version to the file.
+package keyfile
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Kind is the kind of the provider identifiers, e.g. keyfile.v1.
+const Kind = "keyfile"
+
+type keyring struct {
+	keys    map[int]cipher.AEAD
+	current int
+}
+
+// Provider encrypts and decrypts the data encryption keys with the keys of a keyring file.
+type Provider struct {
+	id             secrets.ProviderID
+	path           string
+	reloadInterval time.Duration
+	log            log.Logger
+
+	mtx     sync.RWMutex
+	keyring keyring
+}
+
+// New loads the keyring file of the [security.encryption.keyfile.<KEY-NAME>] section of the provider.
+func New(id secrets.ProviderID, section setting.Section) (*Provider, error) {
+	p := &Provider{
+		id:             id,
+		path:           section.KeyValue("path").MustString(""),
+		reloadInterval: section.KeyValue("reload_interval").MustDuration(time.Minute),
+		log:            log.New("secrets.keyfile"),
+	}
+	if p.path == "" {
+		return nil, errors.New("path is required")
+	}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) Encrypt(_ context.Context, blob []byte) ([]byte, error) {
+	p.mtx.RLock()
+	version := p.keyring.current
+	aead := p.keyring.keys[version]
+	p.mtx.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	encrypted := []byte(fmt.Sprintf("v%d:", version))
+	encrypted = append(encrypted, nonce...)
+	return aead.Seal(encrypted, nonce, blob, nil), nil
+}
+
+func (p *Provider) Decrypt(_ context.Context, blob []byte) ([]byte, error) {
+	end := bytes.IndexByte(blob, ':')
+	if len(blob) == 0 || blob[0] != 'v' || end == -1 {
+		return nil, fmt.Errorf("%s: key version not found in the encrypted data", p.id)
+	}
+	version, err := strconv.Atoi(string(blob[1:end]))
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid key version in the encrypted data: %w", p.id, err)
+	}
+
+	p.mtx.RLock()
+	aead, ok := p.keyring.keys[version]
+	p.mtx.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%s: key version %d not found in keyring file %s", p.id, version, p.path)
+	}
+
+	blob = blob[end+1:]
+	if len(blob) < aead.NonceSize() {
+		return nil, fmt.Errorf("%s: encrypted data is too short", p.id)
+	}
+	return aead.Open(nil, blob[:aead.NonceSize()], blob[aead.NonceSize():], nil)
+}
+
+// Run reloads the keyring file periodically, so that new key versions are used without restarting Grafana.
+func (p *Provider) Run(ctx context.Context) error {
+	if p.reloadInterval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(p.reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.reload(); err != nil {
+				p.log.Error("Failed to reload keyring file, the previous keys are still used", "provider", p.id, "path", p.path, "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *Provider) reload() error {
+	if info, err := os.Stat(p.path); err == nil && info.Mode().Perm()&0077 != 0 {
+		p.log.Warn("Keyring file is accessible by other users", "provider", p.id, "path", p.path, "mode", info.Mode().Perm())
+	}
+
+	kr, err := readKeyring(p.path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p.id, err)
+	}
+
+	p.mtx.Lock()
+	previous := p.keyring.current
+	p.keyring = kr
+	p.mtx.Unlock()
+
+	if previous != 0 && previous != kr.current {
+		p.log.Info("Using new key version of keyring file", "provider", p.id, "version", kr.current)
+	}
+	return nil
+}
+
+func readKeyring(path string) (keyring, error) {
+	kr := keyring{keys: make(map[int]cipher.AEAD)}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the configuration
+	f, err := os.Open(path)
+	if err != nil {
+		return kr, fmt.Errorf("failed to read keyring file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return kr, fmt.Errorf("line %d of keyring file: expected a version and a key", n)
+		}
+		version, err := strconv.Atoi(fields[0])
+		if err != nil || version < 1 {
+			return kr, fmt.Errorf("line %d of keyring file: version must be a positive integer", n)
+		}
+		if _, ok := kr.keys[version]; ok {
+			return kr, fmt.Errorf("line %d of keyring file: duplicate version %d", n, version)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return kr, fmt.Errorf("line %d of keyring file: invalid base64 key: %w", n, err)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return kr, fmt.Errorf("line %d of keyring file: %w", n, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return kr, fmt.Errorf("line %d of keyring file: %w", n, err)
+		}
+
+		kr.keys[version] = aead
+		if version > kr.current {
+			kr.current = version
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return kr, fmt.Errorf("failed to read keyring file: %w", err)
+	}
+	if len(kr.keys) == 0 {
+		return kr, errors.New("keyring file has no keys")
+	}
+
+	return kr, nil
+}
@@ -0,0 +1,127 @@
+package keyfile
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func newKey(b byte, size int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune(b)), size)))
+}
+
+func writeKeyring(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600))
+}
+
+func newProvider(t *testing.T, path string) (*Provider, error) {
+	t.Helper()
+	raw, err := ini.Load([]byte("[security.encryption.keyfile.v1]\npath = " + path))
+	require.NoError(t, err)
+	settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw}}
+	return New("keyfile.v1", settings.Section("security.encryption.keyfile.v1"))
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("data is encrypted with the latest key version", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring")
+		writeKeyring(t, path, "# version key", "1 "+newKey('a', 32), "", "2 "+newKey('b', 16))
+		p, err := newProvider(t, path)
+		require.NoError(t, err)
+
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(encrypted), "v2:"))
+
+		decrypted, err := p.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, []byte("data key"), decrypted)
+	})
+
+	t.Run("new key versions are used after reloading the keyring", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring")
+		writeKeyring(t, path, "1 "+newKey('a', 32))
+		p, err := newProvider(t, path)
+		require.NoError(t, err)
+
+		encryptedV1, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+
+		writeKeyring(t, path, "1 "+newKey('a', 32), "2 "+newKey('b', 32))
+		require.NoError(t, p.reload())
+
+		encryptedV2, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(encryptedV2), "v2:"))
+
+		for _, encrypted := range [][]byte{encryptedV1, encryptedV2} {
+			decrypted, err := p.Decrypt(ctx, encrypted)
+			require.NoError(t, err)
+			require.Equal(t, []byte("data key"), decrypted)
+		}
+
+		// the previous keys are kept if the keyring becomes invalid
+		writeKeyring(t, path, "3")
+		require.Error(t, p.reload())
+		_, err = p.Decrypt(ctx, encryptedV2)
+		require.NoError(t, err)
+
+		// data encrypted with a removed version can't be decrypted anymore
+		writeKeyring(t, path, "2 "+newKey('b', 32))
+		require.NoError(t, p.reload())
+		_, err = p.Decrypt(ctx, encryptedV1)
+		require.EqualError(t, err, "keyfile.v1: key version 1 not found in keyring file "+path)
+	})
+
+	t.Run("tampered data is not decrypted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring")
+		writeKeyring(t, path, "1 "+newKey('a', 32))
+		p, err := newProvider(t, path)
+		require.NoError(t, err)
+
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		encrypted[len(encrypted)-1] ^= 1
+		_, err = p.Decrypt(ctx, encrypted)
+		require.Error(t, err)
+
+		_, err = p.Decrypt(ctx, []byte("data key"))
+		require.EqualError(t, err, "keyfile.v1: key version not found in the encrypted data")
+	})
+
+	t.Run("invalid keyrings are rejected", func(t *testing.T) {
+		testCases := []struct {
+			desc  string
+			lines []string
+			err   string
+		}{
+			{desc: "no keys", lines: []string{"# no keys yet"}, err: "keyfile.v1: keyring file has no keys"},
+			{desc: "missing key", lines: []string{"1"}, err: "keyfile.v1: line 1 of keyring file: expected a version and a key"},
+			{desc: "invalid version", lines: []string{"0 " + newKey('a', 32)}, err: "keyfile.v1: line 1 of keyring file: version must be a positive integer"},
+			{desc: "duplicate version", lines: []string{"1 " + newKey('a', 32), "1 " + newKey('b', 32)}, err: "keyfile.v1: line 2 of keyring file: duplicate version 1"},
+			{desc: "invalid key size", lines: []string{"1 " + newKey('a', 10)}, err: "keyfile.v1: line 1 of keyring file: crypto/aes: invalid key size 10"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "keyring")
+				writeKeyring(t, path, tc.lines...)
+				_, err := newProvider(t, path)
+				require.EqualError(t, err, tc.err)
+			})
+		}
+
+		_, err := newProvider(t, "")
+		require.EqualError(t, err, "path is required")
+	})
+}
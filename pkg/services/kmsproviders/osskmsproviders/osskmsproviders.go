@@ -1,10 +1,15 @@
 package osskmsproviders
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/kmsproviders"
 	grafana "github.com/grafana/grafana/pkg/services/kmsproviders/defaultprovider"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/hashicorpvault"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/keyfile"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -28,7 +33,54 @@ func (s Service) Provide() (map[secrets.ProviderID]secrets.Provider, error) {
 		return nil, nil
 	}
 
-	return map[secrets.ProviderID]secrets.Provider{
+	providers := map[secrets.ProviderID]secrets.Provider{
 		kmsproviders.Default: grafana.New(s.settings, s.enc),
-	}, nil
+	}
+
+	for _, id := range s.configuredProviders() {
+		kind, err := id.Kind()
+		if err != nil {
+			return nil, err
+		}
+
+		section := s.settings.Section(fmt.Sprintf("security.encryption.%s", id))
+		switch kind {
+		case hashicorpvault.Kind:
+			cfg, err := hashicorpvault.ReadSettings(section)
+			if err != nil {
+				return nil, fmt.Errorf("invalid configuration for encryption provider %s: %w", id, err)
+			}
+			providers[id] = hashicorpvault.New(id, cfg)
+		case keyfile.Kind:
+			provider, err := keyfile.New(id, section)
+			if err != nil {
+				return nil, fmt.Errorf("invalid configuration for encryption provider %s: %w", id, err)
+			}
+			providers[id] = provider
+		default:
+			// Providers of other kinds are not available in OSS, the secrets service
+			// fails to start if the current provider is one of them.
+		}
+	}
+
+	return providers, nil
+}
+
+// configuredProviders returns the providers listed in available_encryption_providers
+// and the current encryption provider.
+func (s Service) configuredProviders() []secrets.ProviderID {
+	ids := strings.Fields(s.settings.KeyValue("security", "available_encryption_providers").MustString(""))
+	ids = append(ids, s.settings.KeyValue("security", "encryption_provider").MustString(kmsproviders.Default))
+
+	seen := make(map[secrets.ProviderID]struct{}, len(ids))
+	providers := make([]secrets.ProviderID, 0, len(ids))
+	for _, id := range ids {
+		providerID := kmsproviders.NormalizeProviderID(secrets.ProviderID(id))
+		if _, ok := seen[providerID]; ok || providerID == kmsproviders.Default {
+			continue
+		}
+		seen[providerID] = struct{}{}
+		providers = append(providers, providerID)
+	}
+	return providers
 }
@@ -5,12 +5,24 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/otlp"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/prometheus"
 	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
 )
 
+const (
+	InputFormatInflux     = "influx"
+	InputFormatPrometheus = "prometheus"
+	InputFormatOTLP       = "otlp"
+)
+
 type Converter struct {
-	telegrafConverterWide         *telegraf.Converter
-	telegrafConverterLabelsColumn *telegraf.Converter
+	telegrafConverterWide           *telegraf.Converter
+	telegrafConverterLabelsColumn   *telegraf.Converter
+	prometheusConverterWide         *prometheus.Converter
+	prometheusConverterLabelsColumn *prometheus.Converter
+	otlpConverterWide               *otlp.Converter
+	otlpConverterLabelsColumn       *otlp.Converter
 }
 
 func NewConverter() *Converter {
@@ -22,18 +34,41 @@ func NewConverter() *Converter {
 			telegraf.WithUseLabelsColumn(true),
 			telegraf.WithFloat64Numbers(true),
 		),
+		prometheusConverterWide: prometheus.NewConverter(),
+		prometheusConverterLabelsColumn: prometheus.NewConverter(
+			prometheus.WithUseLabelsColumn(true),
+		),
+		otlpConverterWide: otlp.NewConverter(),
+		otlpConverterLabelsColumn: otlp.NewConverter(
+			otlp.WithUseLabelsColumn(true),
+		),
 	}
 }
 
-var ErrUnsupportedFrameFormat = errors.New("unsupported frame format")
+var (
+	ErrUnsupportedFrameFormat = errors.New("unsupported frame format")
+	ErrUnsupportedInputFormat = errors.New("unsupported input format")
+)
+
+func (c *Converter) Convert(data []byte, inputFormat string, frameFormat string) ([]telemetry.FrameWrapper, error) {
+	var wide, labelsColumn telemetry.Converter
+	switch inputFormat {
+	case InputFormatInflux:
+		wide, labelsColumn = c.telegrafConverterWide, c.telegrafConverterLabelsColumn
+	case InputFormatPrometheus:
+		wide, labelsColumn = c.prometheusConverterWide, c.prometheusConverterLabelsColumn
+	case InputFormatOTLP:
+		wide, labelsColumn = c.otlpConverterWide, c.otlpConverterLabelsColumn
+	default:
+		return nil, ErrUnsupportedInputFormat
+	}
 
-func (c *Converter) Convert(data []byte, frameFormat string) ([]telemetry.FrameWrapper, error) {
 	var converter telemetry.Converter
 	switch frameFormat {
 	case "wide":
-		converter = c.telegrafConverterWide
+		converter = wide
 	case "labels_column":
-		converter = c.telegrafConverterLabelsColumn
+		converter = labelsColumn
 	default:
 		return nil, ErrUnsupportedFrameFormat
 	}
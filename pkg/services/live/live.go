@@ -157,6 +157,10 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	channelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, nil)
 
 	var managedStreamRunner *managedstream.Runner
+	historyConfig := managedstream.HistoryConfig{
+		MaxAge:    g.Cfg.LiveHistoryMaxAge,
+		MaxPoints: g.Cfg.LiveHistoryMaxPoints,
+	}
 	if g.IsHA() {
 		redisClient := redis.NewClient(&redis.Options{
 			Addr: g.Cfg.LiveHAEngineAddress,
@@ -168,19 +172,20 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
-			managedstream.NewRedisFrameCache(redisClient),
+			managedstream.NewRedisFrameCache(redisClient, historyConfig),
 		)
 	} else {
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
-			managedstream.NewMemoryFrameCache(),
+			managedstream.NewMemoryFrameCache(historyConfig),
 		)
 	}
 
 	g.ManagedStreamRunner = managedStreamRunner
 	if g.Features.IsEnabled(featuremgmt.FlagLivePipeline) {
 		var builder pipeline.RuleBuilder
+		var sqlStorage *pipeline.SQLStorage
 		if os.Getenv("GF_LIVE_DEV_BUILDER") != "" {
 			builder = &pipeline.DevRuleBuilder{
 				Node:                 node,
@@ -189,15 +194,26 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 				ChannelHandlerGetter: g,
 			}
 		} else {
-			storage := &pipeline.FileStorage{
-				DataPath:       cfg.DataPath,
-				SecretsService: g.SecretsService,
+			var storage pipeline.Storage
+			if cfg.LivePipelineStorage == "database" {
+				sqlStorage = &pipeline.SQLStorage{
+					SQLStore:       sqlStore,
+					SecretsService: g.SecretsService,
+				}
+				storage = sqlStorage
+			} else {
+				storage = &pipeline.FileStorage{
+					DataPath:       cfg.DataPath,
+					SecretsService: g.SecretsService,
+				}
 			}
 			g.pipelineStorage = storage
+			g.aggregateStorage = pipeline.NewAggregateStorage()
 			builder = &pipeline.StorageRuleBuilder{
 				Node:                 node,
 				ManagedStream:        g.ManagedStreamRunner,
 				FrameStorage:         pipeline.NewFrameStorage(),
+				AggregateStorage:     g.aggregateStorage,
 				Storage:              storage,
 				ChannelHandlerGetter: g,
 				SecretsService:       g.SecretsService,
@@ -205,6 +221,13 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		}
 		channelRuleGetter := pipeline.NewCacheSegmentedTree(builder)
 
+		if sqlStorage != nil {
+			// Rebuild channel rules on all Grafana instances as soon as they are changed.
+			changeNotifier := pipeline.NewNodeChangeNotifier(node, channelRuleGetter)
+			changeNotifier.SetupHandlers()
+			sqlStorage.ChangeNotifier = changeNotifier
+		}
+
 		// Pre-build/validate channel rules for all organizations on start.
 		// This can be unreasonable to have in production scenario with many
 		// organizations.
@@ -426,6 +449,7 @@ type GrafanaLive struct {
 	ManagedStreamRunner *managedstream.Runner
 	Pipeline            *pipeline.Pipeline
 	pipelineStorage     pipeline.Storage
+	aggregateStorage    *pipeline.AggregateStorage
 
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
@@ -470,6 +494,12 @@ func (g *GrafanaLive) Run(ctx context.Context) error {
 		})
 	}
 
+	if g.Pipeline != nil && g.aggregateStorage != nil {
+		eGroup.Go(func() error {
+			return g.Pipeline.RunAggregateFlush(eCtx, g.aggregateStorage)
+		})
+	}
+
 	return eGroup.Wait()
 }
 
@@ -1200,6 +1230,9 @@ func (g *GrafanaLive) HandleChannelRulesPutHTTP(c *models.ReqContext) response.R
 	}
 	rule, err := g.pipelineStorage.UpdateChannelRule(c.Req.Context(), c.OrgId, cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrVersionConflict) {
+			return response.Error(http.StatusConflict, "Channel rule was changed by someone else", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to update channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
@@ -1311,6 +1344,9 @@ func (g *GrafanaLive) HandleWriteConfigsPutHTTP(c *models.ReqContext) response.R
 	}
 	result, err := g.pipelineStorage.UpdateWriteConfig(c.Req.Context(), c.OrgId, cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrVersionConflict) {
+			return response.Error(http.StatusConflict, "Write config was changed by someone else", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to update write config", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
@@ -4,20 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
 // MemoryFrameCache ...
 type MemoryFrameCache struct {
-	mu     sync.RWMutex
-	frames map[int64]map[string]data.FrameJSONCache
+	mu      sync.RWMutex
+	frames  map[int64]map[string]data.FrameJSONCache
+	history map[int64]map[string]*historyRing
+	config  HistoryConfig
 }
 
 // NewMemoryFrameCache ...
-func NewMemoryFrameCache() *MemoryFrameCache {
+func NewMemoryFrameCache(config HistoryConfig) *MemoryFrameCache {
 	return &MemoryFrameCache{
-		frames: map[int64]map[string]data.FrameJSONCache{},
+		frames:  map[int64]map[string]data.FrameJSONCache{},
+		history: map[int64]map[string]*historyRing{},
+		config:  config,
 	}
 }
 
@@ -38,6 +43,9 @@ func (c *MemoryFrameCache) GetActiveChannels(orgID int64) (map[string]json.RawMe
 func (c *MemoryFrameCache) GetFrame(ctx context.Context, orgID int64, channel string) (json.RawMessage, bool, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	if ring, ok := c.history[orgID][channel]; ok {
+		return mergeHistory(ring.items(), c.config, time.Now())
+	}
 	cachedFrame, ok := c.frames[orgID][channel]
 	return cachedFrame.Bytes(data.IncludeAll), ok, nil
 }
@@ -51,5 +59,23 @@ func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel stri
 	cachedJsonFrame, exists := c.frames[orgID][channel]
 	schemaUpdated := !exists || !cachedJsonFrame.SameSchema(&jsonFrame)
 	c.frames[orgID][channel] = jsonFrame
+	if c.config.enabled() {
+		c.updateHistory(orgID, channel, jsonFrame, schemaUpdated)
+	}
 	return schemaUpdated, nil
 }
+
+func (c *MemoryFrameCache) updateHistory(orgID int64, channel string, jsonFrame data.FrameJSONCache, schemaUpdated bool) {
+	if _, ok := c.history[orgID]; !ok {
+		c.history[orgID] = map[string]*historyRing{}
+	}
+	ring, ok := c.history[orgID][channel]
+	if !ok {
+		ring = &historyRing{}
+		c.history[orgID][channel] = ring
+	}
+	if schemaUpdated {
+		ring.reset()
+	}
+	ring.push(historyEntry{Time: time.Now(), Frame: jsonFrame.Bytes(data.IncludeAll)}, c.config.MaxPoints)
+}
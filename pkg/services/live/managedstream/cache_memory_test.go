@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -63,8 +64,57 @@ func testFrameCache(t *testing.T, c FrameCache) {
 	require.NotEqual(t, string(channels["test"]), string(schema))
 }
 
+func testFrameCacheHistory(t *testing.T, c FrameCache) {
+	update := func(values ...float64) {
+		frame := data.NewFrame("hello", data.NewField("value", nil, values))
+		frameJsonCache, err := data.FrameToJSONCache(frame)
+		require.NoError(t, err)
+		_, err = c.Update(context.Background(), 1, "history", frameJsonCache)
+		require.NoError(t, err)
+	}
+	getValues := func() []float64 {
+		frameJSON, ok, err := c.GetFrame(context.Background(), 1, "history")
+		require.NoError(t, err)
+		require.True(t, ok)
+		var f data.Frame
+		require.NoError(t, json.Unmarshal(frameJSON, &f))
+		values := make([]float64, f.Rows())
+		for i := range values {
+			values[i] = f.Fields[0].At(i).(float64)
+		}
+		return values
+	}
+
+	// Frames are merged up to the configured number of points.
+	update(1, 2)
+	update(3)
+	update(4, 5)
+	require.Equal(t, []float64{2, 3, 4, 5}, getValues())
+
+	// A schema change starts a new history.
+	frame := data.NewFrame("hello", data.NewField("value", nil, []int64{6}))
+	frameJsonCache, err := data.FrameToJSONCache(frame)
+	require.NoError(t, err)
+	updated, err := c.Update(context.Background(), 1, "history", frameJsonCache)
+	require.NoError(t, err)
+	require.True(t, updated)
+	frameJSON, ok, err := c.GetFrame(context.Background(), 1, "history")
+	require.NoError(t, err)
+	require.True(t, ok)
+	var f data.Frame
+	require.NoError(t, json.Unmarshal(frameJSON, &f))
+	require.Equal(t, 1, f.Rows())
+	require.Equal(t, int64(6), f.Fields[0].At(0))
+}
+
 func TestMemoryFrameCache(t *testing.T) {
-	c := NewMemoryFrameCache()
+	c := NewMemoryFrameCache(HistoryConfig{})
 	require.NotNil(t, c)
 	testFrameCache(t, c)
 }
+
+func TestMemoryFrameCache_History(t *testing.T) {
+	c := NewMemoryFrameCache(HistoryConfig{MaxAge: time.Minute, MaxPoints: 4})
+	testFrameCache(t, c)
+	testFrameCacheHistory(t, c)
+}
@@ -18,13 +18,15 @@ type RedisFrameCache struct {
 	mu          sync.RWMutex
 	redisClient *redis.Client
 	frames      map[int64]map[string]data.FrameJSONCache
+	config      HistoryConfig
 }
 
 // NewRedisFrameCache ...
-func NewRedisFrameCache(redisClient *redis.Client) *RedisFrameCache {
+func NewRedisFrameCache(redisClient *redis.Client, config HistoryConfig) *RedisFrameCache {
 	return &RedisFrameCache{
 		frames:      map[int64]map[string]data.FrameJSONCache{},
 		redisClient: redisClient,
+		config:      config,
 	}
 }
 
@@ -43,6 +45,10 @@ func (c *RedisFrameCache) GetActiveChannels(orgID int64) (map[string]json.RawMes
 }
 
 func (c *RedisFrameCache) GetFrame(ctx context.Context, orgID int64, channel string) (json.RawMessage, bool, error) {
+	if c.config.enabled() {
+		return c.getHistory(ctx, orgID, channel)
+	}
+
 	key := getCacheKey(orgchannel.PrependOrgID(orgID, channel))
 	cmd := c.redisClient.HGetAll(ctx, key)
 	result, err := cmd.Result()
@@ -55,6 +61,26 @@ func (c *RedisFrameCache) GetFrame(ctx context.Context, orgID int64, channel str
 	return json.RawMessage(result["frame"]), true, nil
 }
 
+// getHistory returns the frames of the history list of the channel merged into a single frame. The list may
+// contain frames with a previous schema, they are skipped by mergeHistory.
+func (c *RedisFrameCache) getHistory(ctx context.Context, orgID int64, channel string) (json.RawMessage, bool, error) {
+	key := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+	result, err := c.redisClient.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	entries := make([]historyEntry, 0, len(result))
+	for _, item := range result {
+		var entry historyEntry
+		if err := json.Unmarshal([]byte(item), &entry); err != nil {
+			return nil, false, err
+		}
+		entries = append(entries, entry)
+	}
+	return mergeHistory(entries, c.config, time.Now())
+}
+
 const (
 	frameCacheTTL = 7 * 24 * time.Hour
 )
@@ -81,6 +107,17 @@ func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel strin
 	})
 	pipe.Expire(ctx, key, frameCacheTTL)
 
+	if c.config.enabled() {
+		entry, err := json.Marshal(historyEntry{Time: time.Now(), Frame: jsonFrame.Bytes(data.IncludeAll)})
+		if err != nil {
+			return false, err
+		}
+		historyKey := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+		pipe.RPush(ctx, historyKey, entry)
+		pipe.LTrim(ctx, historyKey, int64(-c.config.MaxPoints), -1)
+		pipe.Expire(ctx, historyKey, frameCacheTTL)
+	}
+
 	replies, err := pipe.Exec(ctx)
 	if err != nil {
 		return false, err
@@ -110,3 +147,7 @@ func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel strin
 func getCacheKey(channelID string) string {
 	return "gf_live.managed_stream." + channelID
 }
+
+func getHistoryKey(channelID string) string {
+	return "gf_live.managed_stream_history." + channelID
+}
@@ -4,7 +4,9 @@
 package managedstream
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/require"
@@ -14,7 +16,16 @@ func TestRedisCacheStorage(t *testing.T) {
 	redisClient := redis.NewClient(&redis.Options{
 		Addr: "localhost:6379",
 	})
-	c := NewRedisFrameCache(redisClient)
+	c := NewRedisFrameCache(redisClient, HistoryConfig{})
 	require.NotNil(t, c)
 	testFrameCache(t, c)
 }
+
+func TestRedisCacheStorage_History(t *testing.T) {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	require.NoError(t, redisClient.Del(context.Background(), getHistoryKey("1/history")).Err())
+	c := NewRedisFrameCache(redisClient, HistoryConfig{MaxAge: time.Minute, MaxPoints: 4})
+	testFrameCacheHistory(t, c)
+}
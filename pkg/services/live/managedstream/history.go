@@ -0,0 +1,123 @@
+package managedstream
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// HistoryConfig bounds the frames a FrameCache keeps for each channel. New subscribers
+// receive the frames pushed during the last MaxAge, up to MaxPoints rows in total, merged
+// into a single frame, or the last frame if none was pushed during the last MaxAge. When
+// MaxPoints is 0 only the last frame is kept.
+type HistoryConfig struct {
+	MaxAge    time.Duration
+	MaxPoints int
+}
+
+func (h HistoryConfig) enabled() bool {
+	return h.MaxPoints > 0
+}
+
+type historyEntry struct {
+	Time  time.Time       `json:"t"`
+	Frame json.RawMessage `json:"f"`
+}
+
+// historyRing is a ring buffer of the last frames of a channel, the oldest frames are
+// overwritten once the buffer is full.
+type historyRing struct {
+	entries []historyEntry
+	head    int
+}
+
+func (r *historyRing) push(entry historyEntry, capacity int) {
+	if len(r.entries) < capacity {
+		r.entries = append(r.entries, entry)
+		return
+	}
+	r.entries[r.head] = entry
+	r.head = (r.head + 1) % len(r.entries)
+}
+
+func (r *historyRing) reset() {
+	r.entries = r.entries[:0]
+	r.head = 0
+}
+
+// items returns the entries from the oldest to the newest.
+func (r *historyRing) items() []historyEntry {
+	items := make([]historyEntry, 0, len(r.entries))
+	items = append(items, r.entries[r.head:]...)
+	return append(items, r.entries[:r.head]...)
+}
+
+// mergeHistory merges the entries, ordered from the oldest to the newest, into a single frame
+// that contains the rows within the bounds of the config. Entries with a schema different from
+// the newest entry are skipped along with the older ones. If no entry is within the bounds, the newest
+// entry is returned as is, like the last frame is when the history is disabled. It returns false if
+// there are no entries.
+func mergeHistory(entries []historyEntry, config HistoryConfig, now time.Time) (json.RawMessage, bool, error) {
+	var frames []*data.Frame
+	var startRow int
+	points := 0
+	for i := len(entries) - 1; i >= 0 && points < config.MaxPoints; i-- {
+		if config.MaxAge > 0 && now.Sub(entries[i].Time) > config.MaxAge {
+			break
+		}
+
+		var frame data.Frame
+		if err := json.Unmarshal(entries[i].Frame, &frame); err != nil {
+			return nil, false, err
+		}
+		if len(frames) > 0 && !sameSchema(frames[0], &frame) {
+			break
+		}
+
+		frames = append(frames, &frame)
+		startRow = 0
+		if rows := frame.Rows(); points+rows > config.MaxPoints {
+			startRow = rows - (config.MaxPoints - points)
+		}
+		points += frame.Rows() - startRow
+	}
+	if len(frames) == 0 {
+		if len(entries) == 0 {
+			return nil, false, nil
+		}
+		return entries[len(entries)-1].Frame, true, nil
+	}
+
+	// The frames are ordered from the newest to the oldest, only the last rows of the oldest one are kept.
+	merged := frames[0].EmptyCopy()
+	for i := len(frames) - 1; i >= 0; i-- {
+		from := 0
+		if i == len(frames)-1 {
+			from = startRow
+		}
+		for fieldIdx, field := range frames[i].Fields {
+			for row := from; row < field.Len(); row++ {
+				merged.Fields[fieldIdx].Append(field.At(row))
+			}
+		}
+	}
+
+	frameJSON, err := data.FrameToJSON(merged, data.IncludeAll)
+	if err != nil {
+		return nil, false, err
+	}
+	return frameJSON, true, nil
+}
+
+func sameSchema(a, b *data.Frame) bool {
+	if a.Name != b.Name || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package managedstream
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryRing(t *testing.T) {
+	var r historyRing
+	for i := 0; i < 5; i++ {
+		r.push(historyEntry{Frame: json.RawMessage{byte('0' + i)}}, 3)
+	}
+	var frames []string
+	for _, entry := range r.items() {
+		frames = append(frames, string(entry.Frame))
+	}
+	require.Equal(t, []string{"2", "3", "4"}, frames)
+
+	r.reset()
+	require.Empty(t, r.items())
+	r.push(historyEntry{Frame: json.RawMessage("5")}, 3)
+	require.Len(t, r.items(), 1)
+}
+
+func TestMergeHistory(t *testing.T) {
+	now := time.Now()
+	entry := func(t *testing.T, age time.Duration, field *data.Field) historyEntry {
+		frameJSON, err := data.FrameToJSON(data.NewFrame("test", field), data.IncludeAll)
+		require.NoError(t, err)
+		return historyEntry{Time: now.Add(-age), Frame: frameJSON}
+	}
+	merge := func(t *testing.T, entries []historyEntry, config HistoryConfig) []interface{} {
+		frameJSON, ok, err := mergeHistory(entries, config, now)
+		require.NoError(t, err)
+		if !ok {
+			return nil
+		}
+		var f data.Frame
+		require.NoError(t, json.Unmarshal(frameJSON, &f))
+		values := make([]interface{}, f.Rows())
+		for i := range values {
+			values[i] = f.Fields[0].At(i)
+		}
+		return values
+	}
+
+	t.Run("frames are merged in order", func(t *testing.T) {
+		entries := []historyEntry{
+			entry(t, 3*time.Second, data.NewField("value", nil, []float64{1, 2})),
+			entry(t, 2*time.Second, data.NewField("value", nil, []float64{3})),
+			entry(t, time.Second, data.NewField("value", nil, []float64{4, 5})),
+		}
+		values := merge(t, entries, HistoryConfig{MaxPoints: 10})
+		require.Equal(t, []interface{}{1.0, 2.0, 3.0, 4.0, 5.0}, values)
+	})
+
+	t.Run("only the last points of the oldest frame are kept", func(t *testing.T) {
+		entries := []historyEntry{
+			entry(t, 2*time.Second, data.NewField("value", nil, []float64{1, 2, 3})),
+			entry(t, time.Second, data.NewField("value", nil, []float64{4, 5})),
+		}
+		values := merge(t, entries, HistoryConfig{MaxPoints: 3})
+		require.Equal(t, []interface{}{3.0, 4.0, 5.0}, values)
+	})
+
+	t.Run("frames older than max age are skipped", func(t *testing.T) {
+		entries := []historyEntry{
+			entry(t, 2*time.Minute, data.NewField("value", nil, []float64{1})),
+			entry(t, time.Second, data.NewField("value", nil, []float64{2})),
+		}
+		values := merge(t, entries, HistoryConfig{MaxAge: time.Minute, MaxPoints: 10})
+		require.Equal(t, []interface{}{2.0}, values)
+
+	})
+
+	t.Run("the last frame is returned when all frames are older than max age", func(t *testing.T) {
+		entries := []historyEntry{
+			entry(t, 3*time.Minute, data.NewField("value", nil, []float64{1})),
+			entry(t, 2*time.Minute, data.NewField("value", nil, []float64{2, 3})),
+		}
+		values := merge(t, entries, HistoryConfig{MaxAge: time.Minute, MaxPoints: 10})
+		require.Equal(t, []interface{}{2.0, 3.0}, values)
+
+		require.Nil(t, merge(t, nil, HistoryConfig{MaxAge: time.Minute, MaxPoints: 10}))
+	})
+
+	t.Run("frames with a previous schema are skipped", func(t *testing.T) {
+		entries := []historyEntry{
+			entry(t, 3*time.Second, data.NewField("value", nil, []int64{1})),
+			entry(t, 2*time.Second, data.NewField("value", nil, []float64{2})),
+			entry(t, time.Second, data.NewField("value", nil, []float64{3})),
+		}
+		values := merge(t, entries, HistoryConfig{MaxPoints: 10})
+		require.Equal(t, []interface{}{2.0, 3.0}, values)
+	})
+}
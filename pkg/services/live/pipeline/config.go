@@ -30,15 +30,18 @@ type ChannelRuleSettings struct {
 type ChannelRule struct {
 	OrgId    int64               `json:"-"`
 	Pattern  string              `json:"pattern"`
+	Version  int64               `json:"version,omitempty"`
 	Settings ChannelRuleSettings `json:"settings"`
 }
 
 type ConverterConfig struct {
-	Type                      string                     `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
-	AutoJsonConverterConfig   *AutoJsonConverterConfig   `json:"jsonAuto,omitempty"`
-	ExactJsonConverterConfig  *ExactJsonConverterConfig  `json:"jsonExact,omitempty"`
-	AutoInfluxConverterConfig *AutoInfluxConverterConfig `json:"influxAuto,omitempty"`
-	JsonFrameConverterConfig  *JsonFrameConverterConfig  `json:"jsonFrame,omitempty"`
+	Type                          string                         `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
+	AutoJsonConverterConfig       *AutoJsonConverterConfig       `json:"jsonAuto,omitempty"`
+	ExactJsonConverterConfig      *ExactJsonConverterConfig      `json:"jsonExact,omitempty"`
+	AutoInfluxConverterConfig     *AutoInfluxConverterConfig     `json:"influxAuto,omitempty"`
+	AutoPrometheusConverterConfig *AutoPrometheusConverterConfig `json:"prometheusAuto,omitempty"`
+	AutoOTLPConverterConfig       *AutoOTLPConverterConfig       `json:"otlpAuto,omitempty"`
+	JsonFrameConverterConfig      *JsonFrameConverterConfig      `json:"jsonFrame,omitempty"`
 }
 
 type DropFieldsFrameProcessorConfig struct {
@@ -49,11 +52,42 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+type RenameFieldsFrameProcessorConfig struct {
+	// Renames maps current field names to new field names.
+	Renames map[string]string `json:"renames"`
+}
+
+type ComputeFieldFrameProcessorConfig struct {
+	// FieldName is a name of the computed field. An existing field with the
+	// same name is replaced.
+	FieldName string `json:"fieldName"`
+	// Expression is a JavaScript expression evaluated for every row. Row values
+	// are available as properties of x object, i.e. x.value * 100.
+	Expression string `json:"expression"`
+}
+
+type LabelsFrameProcessorConfig struct {
+	Labels map[string]string `json:"labels"`
+	// Override existing labels with the same names.
+	Override bool `json:"override,omitempty"`
+}
+
+type AggregateFrameProcessorConfig struct {
+	// IntervalMilliseconds is a size of the tumbling window rows are aggregated over.
+	IntervalMilliseconds int64 `json:"intervalMilliseconds"`
+	// Function to aggregate numeric fields with: mean, min, max, sum, count, first or last.
+	Function string `json:"function"`
+}
+
 type FrameProcessorConfig struct {
-	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
-	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
-	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
-	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	Type                        string                            `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
+	DropFieldsProcessorConfig   *DropFieldsFrameProcessorConfig   `json:"dropFields,omitempty"`
+	KeepFieldsProcessorConfig   *KeepFieldsFrameProcessorConfig   `json:"keepFields,omitempty"`
+	RenameFieldsProcessorConfig *RenameFieldsFrameProcessorConfig `json:"renameFields,omitempty"`
+	ComputeFieldProcessorConfig *ComputeFieldFrameProcessorConfig `json:"computeField,omitempty"`
+	LabelsProcessorConfig       *LabelsFrameProcessorConfig       `json:"labels,omitempty"`
+	AggregateProcessorConfig    *AggregateFrameProcessorConfig    `json:"aggregate,omitempty"`
+	MultipleProcessorConfig     *MultipleFrameProcessorConfig     `json:"multiple,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -149,6 +183,14 @@ type AutoInfluxConverterConfig struct {
 	FrameFormat string `json:"frameFormat"`
 }
 
+type AutoPrometheusConverterConfig struct {
+	FrameFormat string `json:"frameFormat"`
+}
+
+type AutoOTLPConverterConfig struct {
+	FrameFormat string `json:"frameFormat"`
+}
+
 type JsonFrameConverterConfig struct{}
 
 type ManagedStreamOutputConfig struct{}
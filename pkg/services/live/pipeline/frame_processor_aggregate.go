@@ -0,0 +1,299 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+const (
+	AggregateFunctionMean  = "mean"
+	AggregateFunctionMin   = "min"
+	AggregateFunctionMax   = "max"
+	AggregateFunctionSum   = "sum"
+	AggregateFunctionCount = "count"
+	AggregateFunctionFirst = "first"
+	AggregateFunctionLast  = "last"
+)
+
+// AggregateStorage keeps windows of AggregateFrameProcessor in memory, so that they
+// survive rebuilding of channel rules. Not usable in HA setup.
+// Windows which don't receive data are removed by Flush.
+type AggregateStorage struct {
+	mu      sync.Mutex
+	windows map[string]*aggregateWindow
+}
+
+func NewAggregateStorage() *AggregateStorage {
+	return &AggregateStorage{
+		windows: map[string]*aggregateWindow{},
+	}
+}
+
+// AggregateFrameProcessor downsamples a data.Frame by aggregating rows over tumbling
+// windows of fixed size. Frames are held back until a row of the next window arrives,
+// then the processor returns a frame with a row for each completed window. If no data
+// arrives for the channel during an interval, the window is completed by Flush instead.
+// Numeric fields are aggregated with the configured function, other fields keep the last value.
+type AggregateFrameProcessor struct {
+	config   AggregateFrameProcessorConfig
+	interval time.Duration
+	storage  *AggregateStorage
+}
+
+func NewAggregateFrameProcessor(config AggregateFrameProcessorConfig, storage *AggregateStorage) (*AggregateFrameProcessor, error) {
+	if config.IntervalMilliseconds <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+	switch config.Function {
+	case AggregateFunctionMean, AggregateFunctionMin, AggregateFunctionMax, AggregateFunctionSum,
+		AggregateFunctionCount, AggregateFunctionFirst, AggregateFunctionLast:
+	default:
+		return nil, fmt.Errorf("unknown aggregate function: %s", config.Function)
+	}
+	if storage == nil {
+		storage = NewAggregateStorage()
+	}
+	return &AggregateFrameProcessor{
+		config:   config,
+		interval: time.Duration(config.IntervalMilliseconds) * time.Millisecond,
+		storage:  storage,
+	}, nil
+}
+
+const FrameProcessorTypeAggregate = "aggregate"
+
+func (p *AggregateFrameProcessor) Type() string {
+	return FrameProcessorTypeAggregate
+}
+
+type aggregateWindow struct {
+	orgID     int64
+	channel   string
+	config    AggregateFrameProcessorConfig
+	schema    string
+	start     time.Time
+	fields    []*fieldAggregate
+	completed *data.Frame
+	// frame is an empty copy of a frame of the schema, used to complete the window on flush.
+	frame     *data.Frame
+	timeIndex int
+	updated   time.Time
+}
+
+type fieldAggregate struct {
+	count     int
+	sum       float64
+	min       float64
+	max       float64
+	first     float64
+	last      float64
+	lastValue interface{}
+}
+
+func (p *AggregateFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	timeIndices := frame.TypeIndices(data.FieldTypeTime, data.FieldTypeNullableTime)
+	if len(timeIndices) == 0 {
+		return nil, errors.New("frame has no time field")
+	}
+	timeIndex := timeIndices[0]
+	rowLen, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s/%d/%s", orgchannel.PrependOrgID(vars.OrgID, vars.Channel), p.config.IntervalMilliseconds, p.config.Function)
+	schema := frameSchemaKey(frame)
+
+	p.storage.mu.Lock()
+	defer p.storage.mu.Unlock()
+	window, ok := p.storage.windows[key]
+	if !ok || window.schema != schema {
+		// Rows of a previous schema can't be merged into the frame, so they are dropped.
+		window = &aggregateWindow{
+			orgID:     vars.OrgID,
+			channel:   vars.Channel,
+			config:    p.config,
+			schema:    schema,
+			frame:     frame.EmptyCopy(),
+			timeIndex: timeIndex,
+		}
+		p.storage.windows[key] = window
+	}
+	window.updated = time.Now()
+
+	for row := 0; row < rowLen; row++ {
+		t, ok := frame.Fields[timeIndex].ConcreteAt(row)
+		if !ok {
+			continue
+		}
+		start := t.(time.Time).Truncate(p.interval)
+		if window.fields != nil && start.After(window.start) {
+			window.complete(frame, timeIndex)
+		}
+		if window.fields == nil {
+			window.start = start
+			window.fields = make([]*fieldAggregate, len(frame.Fields))
+			for i := range window.fields {
+				window.fields[i] = &fieldAggregate{}
+			}
+		}
+		for i, field := range frame.Fields {
+			if i == timeIndex {
+				continue
+			}
+			if err := window.fields[i].add(field, row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	completed := window.completed
+	window.completed = nil
+	return completed, nil
+}
+
+// AggregateFlush is a frame with the windows of an AggregateFrameProcessor completed by
+// AggregateStorage.Flush.
+type AggregateFlush struct {
+	OrgID   int64
+	Channel string
+	Config  AggregateFrameProcessorConfig
+	Frame   *data.Frame
+}
+
+// Flush removes the windows which did not receive data for an interval before now, so
+// that windows of channels which are gone are not kept forever. It returns a frame for
+// each removed window which has aggregated rows, to be passed to the rest of the rule.
+// Rows of a removed window which arrive later start a new window.
+func (s *AggregateStorage) Flush(now time.Time) []AggregateFlush {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var flushes []AggregateFlush
+	for key, window := range s.windows {
+		interval := time.Duration(window.config.IntervalMilliseconds) * time.Millisecond
+		if now.Sub(window.updated) < interval {
+			continue
+		}
+		delete(s.windows, key)
+		if window.fields == nil {
+			continue
+		}
+		window.complete(window.frame, window.timeIndex)
+		flushes = append(flushes, AggregateFlush{
+			OrgID:   window.orgID,
+			Channel: window.channel,
+			Config:  window.config,
+			Frame:   window.completed,
+		})
+	}
+	return flushes
+}
+
+// complete appends a row with the aggregated values of the current window to the
+// completed frame and resets the window.
+func (window *aggregateWindow) complete(frame *data.Frame, timeIndex int) {
+	if window.completed == nil {
+		fields := make([]*data.Field, len(frame.Fields))
+		for i, field := range frame.Fields {
+			var f *data.Field
+			switch {
+			case i == timeIndex:
+				f = data.NewField(field.Name, field.Labels, []time.Time{})
+			case field.Type().Numeric():
+				f = data.NewField(field.Name, field.Labels, []*float64{})
+			default:
+				f = data.NewFieldFromFieldType(field.Type(), 0)
+				f.Name = field.Name
+				f.Labels = field.Labels
+			}
+			f.Config = field.Config
+			fields[i] = f
+		}
+		window.completed = data.NewFrame(frame.Name, fields...)
+		window.completed.Meta = frame.Meta
+	}
+
+	for i, field := range window.completed.Fields {
+		switch {
+		case i == timeIndex:
+			field.Append(window.start)
+		case frame.Fields[i].Type().Numeric():
+			field.Append(window.fields[i].value(window.config.Function))
+		default:
+			field.Append(window.fields[i].lastValue)
+		}
+	}
+	window.fields = nil
+}
+
+func (a *fieldAggregate) add(field *data.Field, row int) error {
+	if !field.Type().Numeric() {
+		a.lastValue = field.CopyAt(row)
+		return nil
+	}
+	v, err := field.NullableFloatAt(row)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	if a.count == 0 {
+		a.first, a.min, a.max = *v, *v, *v
+	}
+	a.count++
+	a.sum += *v
+	a.min = math.Min(a.min, *v)
+	a.max = math.Max(a.max, *v)
+	a.last = *v
+	return nil
+}
+
+func (a *fieldAggregate) value(function string) *float64 {
+	var v float64
+	switch function {
+	case AggregateFunctionCount:
+		v = float64(a.count)
+		return &v
+	case AggregateFunctionSum:
+		v = a.sum
+		return &v
+	}
+	if a.count == 0 {
+		return nil
+	}
+	switch function {
+	case AggregateFunctionMean:
+		v = a.sum / float64(a.count)
+	case AggregateFunctionMin:
+		v = a.min
+	case AggregateFunctionMax:
+		v = a.max
+	case AggregateFunctionFirst:
+		v = a.first
+	case AggregateFunctionLast:
+		v = a.last
+	}
+	return &v
+}
+
+func frameSchemaKey(frame *data.Frame) string {
+	var sb strings.Builder
+	sb.WriteString(frame.Name)
+	for _, field := range frame.Fields {
+		sb.WriteString("|")
+		sb.WriteString(field.Name)
+		sb.WriteString(":")
+		sb.WriteString(field.Type().ItemTypeString())
+	}
+	return sb.String()
+}
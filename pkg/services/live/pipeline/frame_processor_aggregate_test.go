@@ -0,0 +1,168 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateFrameProcessor(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	newFrame := func(offsets []time.Duration, values []float64) *data.Frame {
+		times := make([]time.Time, len(offsets))
+		states := make([]string, len(offsets))
+		for i, offset := range offsets {
+			times[i] = start.Add(offset)
+			states[i] = offset.String()
+		}
+		return data.NewFrame("test",
+			data.NewField("time", nil, times),
+			data.NewField("value", data.Labels{"host": "a"}, values),
+			data.NewField("state", nil, states),
+		)
+	}
+	vars := Vars{OrgID: 1, Channel: "stream/test/aggregate"}
+
+	p, err := NewAggregateFrameProcessor(AggregateFrameProcessorConfig{
+		IntervalMilliseconds: 1000,
+		Function:             AggregateFunctionMean,
+	}, nil)
+	require.NoError(t, err)
+
+	// Frames are held back until the window is completed.
+	frame, err := p.ProcessFrame(context.Background(), vars, newFrame(
+		[]time.Duration{0, 250 * time.Millisecond, 500 * time.Millisecond}, []float64{1, 2, 3}))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+
+	frame, err = p.ProcessFrame(context.Background(), vars, newFrame(
+		[]time.Duration{750 * time.Millisecond, 1100 * time.Millisecond, 2100 * time.Millisecond}, []float64{6, 10, 20}))
+	require.NoError(t, err)
+	require.NotNil(t, frame)
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, start, frame.Fields[0].At(0))
+	require.Equal(t, 3.0, *frame.Fields[1].At(0).(*float64))
+	require.Equal(t, data.Labels{"host": "a"}, frame.Fields[1].Labels)
+	require.Equal(t, "750ms", frame.Fields[2].At(0))
+	require.Equal(t, start.Add(time.Second), frame.Fields[0].At(1))
+	require.Equal(t, 10.0, *frame.Fields[1].At(1).(*float64))
+
+	// The window of a previous schema is dropped.
+	frame, err = p.ProcessFrame(context.Background(), vars, data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{start.Add(2500 * time.Millisecond), start.Add(3 * time.Second)}),
+		data.NewField("value", nil, []int64{1, 2}),
+	))
+	require.NoError(t, err)
+	require.Equal(t, 1, frame.Rows())
+	require.Equal(t, start.Add(2*time.Second), frame.Fields[0].At(0))
+	require.Equal(t, 1.0, *frame.Fields[1].At(0).(*float64))
+}
+
+func TestAggregateFrameProcessor_Functions(t *testing.T) {
+	testCases := []struct {
+		function string
+		expected float64
+	}{
+		{function: AggregateFunctionMean, expected: 2},
+		{function: AggregateFunctionMin, expected: 1},
+		{function: AggregateFunctionMax, expected: 3},
+		{function: AggregateFunctionSum, expected: 6},
+		{function: AggregateFunctionCount, expected: 3},
+		{function: AggregateFunctionFirst, expected: 3},
+		{function: AggregateFunctionLast, expected: 2},
+	}
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range testCases {
+		t.Run(tc.function, func(t *testing.T) {
+			p, err := NewAggregateFrameProcessor(AggregateFrameProcessorConfig{
+				IntervalMilliseconds: 1000,
+				Function:             tc.function,
+			}, nil)
+			require.NoError(t, err)
+			three, one, two := 3.0, 1.0, 2.0
+			frame, err := p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "test"}, data.NewFrame("test",
+				data.NewField("time", nil, []time.Time{
+					start, start.Add(100 * time.Millisecond), start.Add(200 * time.Millisecond),
+					start.Add(300 * time.Millisecond), start.Add(time.Second),
+				}),
+				data.NewField("value", nil, []*float64{&three, nil, &one, &two, &one}),
+			))
+			require.NoError(t, err)
+			require.Equal(t, 1, frame.Rows())
+			require.Equal(t, tc.expected, *frame.Fields[1].At(0).(*float64))
+		})
+	}
+}
+
+func TestNewAggregateFrameProcessor_Invalid(t *testing.T) {
+	_, err := NewAggregateFrameProcessor(AggregateFrameProcessorConfig{Function: AggregateFunctionMean}, nil)
+	require.Error(t, err)
+	_, err = NewAggregateFrameProcessor(AggregateFrameProcessorConfig{IntervalMilliseconds: 1000, Function: "median"}, nil)
+	require.Error(t, err)
+}
+
+func TestAggregateFrameProcessor_Multiple(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	aggregate, err := NewAggregateFrameProcessor(AggregateFrameProcessorConfig{
+		IntervalMilliseconds: 1000,
+		Function:             AggregateFunctionSum,
+	}, nil)
+	require.NoError(t, err)
+	p := NewMultipleFrameProcessor(aggregate, NewDropFieldsFrameProcessor(DropFieldsFrameProcessorConfig{
+		FieldNames: []string{"state"},
+	}))
+	newFrame := func(offset time.Duration) *data.Frame {
+		return data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{start.Add(offset)}),
+			data.NewField("value", nil, []float64{1}),
+			data.NewField("state", nil, []string{"ok"}),
+		)
+	}
+	vars := Vars{OrgID: 1, Channel: "stream/test/aggregate"}
+
+	// The chain stops while the window is open.
+	frame, err := p.ProcessFrame(context.Background(), vars, newFrame(0))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+
+	frame, err = p.ProcessFrame(context.Background(), vars, newFrame(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, 1, frame.Rows())
+	require.Len(t, frame.Fields, 2)
+}
+
+func TestAggregateStorage_Flush(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := NewAggregateStorage()
+	p, err := NewAggregateFrameProcessor(AggregateFrameProcessorConfig{
+		IntervalMilliseconds: 1000,
+		Function:             AggregateFunctionSum,
+	}, storage)
+	require.NoError(t, err)
+	vars := Vars{OrgID: 1, Channel: "stream/test/aggregate"}
+
+	frame, err := p.ProcessFrame(context.Background(), vars, data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{start, start.Add(500 * time.Millisecond)}),
+		data.NewField("value", nil, []float64{1, 2}),
+	))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+
+	// The window still receives data.
+	require.Empty(t, storage.Flush(time.Now()))
+
+	flushes := storage.Flush(time.Now().Add(2 * time.Second))
+	require.Len(t, flushes, 1)
+	require.Equal(t, int64(1), flushes[0].OrgID)
+	require.Equal(t, "stream/test/aggregate", flushes[0].Channel)
+	require.Equal(t, 1, flushes[0].Frame.Rows())
+	require.Equal(t, start, flushes[0].Frame.Fields[0].At(0))
+	require.Equal(t, 3.0, *flushes[0].Frame.Fields[1].At(0).(*float64))
+
+	// Flushed windows are removed.
+	require.Empty(t, storage.windows)
+	require.Empty(t, storage.Flush(time.Now().Add(4*time.Second)))
+}
@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ComputeFieldFrameProcessor can add a field to a data.Frame with values computed
+// by a JavaScript expression for every row.
+type ComputeFieldFrameProcessor struct {
+	config  ComputeFieldFrameProcessorConfig
+	program *goja.Program
+}
+
+func NewComputeFieldFrameProcessor(config ComputeFieldFrameProcessorConfig) (*ComputeFieldFrameProcessor, error) {
+	if config.FieldName == "" {
+		return nil, fmt.Errorf("field name required")
+	}
+	program, err := goja.Compile("", config.Expression, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return &ComputeFieldFrameProcessor{config: config, program: program}, nil
+}
+
+const FrameProcessorTypeComputeField = "computeField"
+
+func (p *ComputeFieldFrameProcessor) Type() string {
+	return FrameProcessorTypeComputeField
+}
+
+func (p *ComputeFieldFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	rowLen, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+	r := newRuntime()
+	values := make([]*float64, rowLen)
+	row := make(map[string]interface{}, len(frame.Fields))
+	for i := 0; i < rowLen; i++ {
+		for _, field := range frame.Fields {
+			row[field.Name] = exportValue(field.At(i))
+		}
+		if err := r.vm.Set("x", row); err != nil {
+			return nil, err
+		}
+		v, err := r.runProgram(p.program)
+		if err != nil {
+			return nil, fmt.Errorf("error computing field %s: %w", p.config.FieldName, err)
+		}
+		values[i], err = toNullableFloat64(v.Export())
+		if err != nil {
+			return nil, fmt.Errorf("error computing field %s: %w", p.config.FieldName, err)
+		}
+	}
+
+	computed := data.NewField(p.config.FieldName, nil, values)
+	for i, field := range frame.Fields {
+		if field.Name == p.config.FieldName {
+			frame.Fields[i] = computed
+			return frame, nil
+		}
+	}
+	frame.Fields = append(frame.Fields, computed)
+	return frame, nil
+}
+
+// exportValue converts a field value to a value that can be used in expressions. Time values
+// are converted to Unix milliseconds.
+func exportValue(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.UnixMilli()
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		return exportValue(rv.Elem().Interface())
+	}
+	return v
+}
+
+func toNullableFloat64(v interface{}) (*float64, error) {
+	var f float64
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		f = val
+	case int64:
+		f = float64(val)
+	case bool:
+		if val {
+			f = 1
+		}
+	default:
+		return nil, fmt.Errorf("unexpected return value: %v (%T)", v, v)
+	}
+	if math.IsNaN(f) {
+		return nil, nil
+	}
+	return &f, nil
+}
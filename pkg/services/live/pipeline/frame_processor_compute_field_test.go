@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeFieldFrameProcessor(t *testing.T) {
+	p, err := NewComputeFieldFrameProcessor(ComputeFieldFrameProcessorConfig{
+		FieldName:  "fahrenheit",
+		Expression: "x.celsius === null ? null : x.celsius * 9 / 5 + 32",
+	})
+	require.NoError(t, err)
+
+	celsius := 100.0
+	frame, err := p.ProcessFrame(context.Background(), Vars{}, data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{time.Unix(0, 0), time.Unix(1, 0)}),
+		data.NewField("celsius", nil, []*float64{&celsius, nil}),
+	))
+	require.NoError(t, err)
+	require.Len(t, frame.Fields, 3)
+	require.Equal(t, "fahrenheit", frame.Fields[2].Name)
+	require.Equal(t, 212.0, *frame.Fields[2].At(0).(*float64))
+	require.Nil(t, frame.Fields[2].At(1))
+
+	// Existing field with the same name is replaced, time values are Unix milliseconds.
+	p, err = NewComputeFieldFrameProcessor(ComputeFieldFrameProcessorConfig{
+		FieldName:  "celsius",
+		Expression: "x.time",
+	})
+	require.NoError(t, err)
+	frame, err = p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Len(t, frame.Fields, 3)
+	require.Equal(t, 1000.0, *frame.Fields[1].At(1).(*float64))
+
+	p, err = NewComputeFieldFrameProcessor(ComputeFieldFrameProcessorConfig{
+		FieldName:  "invalid",
+		Expression: "'string'",
+	})
+	require.NoError(t, err)
+	_, err = p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.ErrorContains(t, err, "unexpected return value")
+
+	_, err = NewComputeFieldFrameProcessor(ComputeFieldFrameProcessorConfig{
+		FieldName:  "invalid",
+		Expression: "x.value *",
+	})
+	require.Error(t, err)
+}
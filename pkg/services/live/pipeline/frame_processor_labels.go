@@ -0,0 +1,40 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// LabelsFrameProcessor can add labels to all non-time fields of a data.Frame.
+type LabelsFrameProcessor struct {
+	config LabelsFrameProcessorConfig
+}
+
+func NewLabelsFrameProcessor(config LabelsFrameProcessorConfig) *LabelsFrameProcessor {
+	return &LabelsFrameProcessor{config: config}
+}
+
+const FrameProcessorTypeLabels = "labels"
+
+func (p *LabelsFrameProcessor) Type() string {
+	return FrameProcessorTypeLabels
+}
+
+func (p *LabelsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	for _, field := range frame.Fields {
+		if fieldType := field.Type(); fieldType == data.FieldTypeTime || fieldType == data.FieldTypeNullableTime {
+			continue
+		}
+		if field.Labels == nil {
+			field.Labels = data.Labels{}
+		}
+		for name, value := range p.config.Labels {
+			if _, ok := field.Labels[name]; ok && !p.config.Override {
+				continue
+			}
+			field.Labels[name] = value
+		}
+	}
+	return frame, nil
+}
@@ -0,0 +1,31 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelsFrameProcessor(t *testing.T) {
+	newFrame := func() *data.Frame {
+		return data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(0, 0)}),
+			data.NewField("value", data.Labels{"host": "a"}, []float64{1}),
+		)
+	}
+	labels := map[string]string{"host": "b", "location": "office"}
+
+	p := NewLabelsFrameProcessor(LabelsFrameProcessorConfig{Labels: labels})
+	frame, err := p.ProcessFrame(context.Background(), Vars{}, newFrame())
+	require.NoError(t, err)
+	require.Nil(t, frame.Fields[0].Labels)
+	require.Equal(t, data.Labels{"host": "a", "location": "office"}, frame.Fields[1].Labels)
+
+	p = NewLabelsFrameProcessor(LabelsFrameProcessorConfig{Labels: labels, Override: true})
+	frame, err = p.ProcessFrame(context.Background(), Vars{}, newFrame())
+	require.NoError(t, err)
+	require.Equal(t, data.Labels{"host": "b", "location": "office"}, frame.Fields[1].Labels)
+}
@@ -26,6 +26,10 @@ func (p *MultipleFrameProcessor) ProcessFrame(ctx context.Context, vars Vars, fr
 			logger.Error("Error processing frame", "error", err)
 			return nil, err
 		}
+		if frame == nil {
+			// A processor can hold back a frame, e.g. until an aggregate window is completed.
+			return nil, nil
+		}
 	}
 	return frame, nil
 }
@@ -57,6 +57,7 @@ func WriteConfigToDto(b WriteConfig) WriteConfigDto {
 	}
 	return WriteConfigDto{
 		UID:          b.UID,
+		Version:      b.Version,
 		Settings:     b.Settings,
 		SecureFields: secureFields,
 	}
@@ -64,6 +65,7 @@ func WriteConfigToDto(b WriteConfig) WriteConfigDto {
 
 type WriteConfigDto struct {
 	UID          string          `json:"uid"`
+	Version      int64           `json:"version,omitempty"`
 	Settings     WriteSettings   `json:"settings"`
 	SecureFields map[string]bool `json:"secureFields"`
 }
@@ -78,11 +80,13 @@ type WriteConfigCreateCmd struct {
 	SecureSettings map[string]string `json:"secureSettings"`
 }
 
-// TODO: add version field later.
 type WriteConfigUpdateCmd struct {
 	UID            string            `json:"uid"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string]string `json:"secureSettings"`
+	// Version is an optional version of the write config the update is based on.
+	// Storages that keep versions reject the update if the write config has changed.
+	Version int64 `json:"version,omitempty"`
 }
 
 type WriteConfigDeleteCmd struct {
@@ -92,6 +96,7 @@ type WriteConfigDeleteCmd struct {
 type WriteConfig struct {
 	OrgId          int64             `json:"-"`
 	UID            string            `json:"uid"`
+	Version        int64             `json:"version,omitempty"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string][]byte `json:"secureSettings,omitempty"`
 }
@@ -154,6 +159,9 @@ type ChannelRuleCreateCmd struct {
 type ChannelRuleUpdateCmd struct {
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	// Version is an optional version of the channel rule the update is based on.
+	// Storages that keep versions reject the update if the channel rule has changed.
+	Version int64 `json:"version,omitempty"`
 }
 
 type ChannelRuleDeleteCmd struct {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/grafana/grafana/pkg/models"
 
@@ -365,6 +366,74 @@ func (p *Pipeline) processChannelFrames(ctx context.Context, orgID int64, channe
 	return nil
 }
 
+// aggregateFlushInterval is how often windows of aggregate frame processors
+// which no longer receive data are flushed.
+const aggregateFlushInterval = time.Second
+
+// RunAggregateFlush periodically flushes windows of aggregate frame processors which no
+// longer receive data, and passes the completed frames to the rest of the channel rules.
+func (p *Pipeline) RunAggregateFlush(ctx context.Context, storage *AggregateStorage) error {
+	ticker := time.NewTicker(aggregateFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, flush := range storage.Flush(now) {
+				if err := p.processAggregateFlush(ctx, flush); err != nil {
+					logger.Error("Error processing flushed aggregate frame", "error", err, "channel", flush.Channel)
+				}
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *Pipeline) processAggregateFlush(ctx context.Context, flush AggregateFlush) error {
+	rule, ok, err := p.ruleGetter.Get(flush.OrgID, flush.Channel)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	processors, ok := processorsAfterAggregate(rule.FrameProcessors, flush.Config)
+	if !ok {
+		// The rule has changed and does not aggregate the channel this way anymore.
+		return nil
+	}
+	vars, err := channelVars(flush.OrgID, flush.Channel)
+	if err != nil {
+		return err
+	}
+	frames, err := p.processRuleFrame(ctx, rule, processors, vars, flush.Frame)
+	if err != nil {
+		return err
+	}
+	if len(frames) > 0 {
+		return p.processChannelFrames(ctx, flush.OrgID, flush.Channel, frames, map[string]struct{}{flush.Channel: {}})
+	}
+	return nil
+}
+
+// processorsAfterAggregate returns the processors which follow the aggregate frame
+// processor with the config, including the ones after a multiple processor it belongs to.
+func processorsAfterAggregate(processors []FrameProcessor, config AggregateFrameProcessorConfig) ([]FrameProcessor, bool) {
+	for i, proc := range processors {
+		switch proc := proc.(type) {
+		case *AggregateFrameProcessor:
+			if proc.config == config {
+				return processors[i+1:], true
+			}
+		case *MultipleFrameProcessor:
+			if rest, ok := processorsAfterAggregate(proc.Processors, config); ok {
+				return append(append([]FrameProcessor{}, rest...), processors[i+1:]...), true
+			}
+		}
+	}
+	return nil, false
+}
+
 func (p *Pipeline) processFrame(ctx context.Context, orgID int64, channelID string, frame *data.Frame) ([]*ChannelFrame, error) {
 	var span trace.Span
 	if p.tracer != nil {
@@ -390,30 +459,40 @@ func (p *Pipeline) processFrame(ctx context.Context, orgID int64, channelID stri
 		return nil, err
 	}
 
-	ch, err := live.ParseChannel(channelID)
+	vars, err := channelVars(orgID, channelID)
 	if err != nil {
 		logger.Error("Error parsing channel", "error", err, "channel", channelID)
 		return nil, err
 	}
 
-	vars := Vars{
+	return p.processRuleFrame(ctx, rule, rule.FrameProcessors, vars, frame)
+}
+
+func channelVars(orgID int64, channelID string) (Vars, error) {
+	ch, err := live.ParseChannel(channelID)
+	if err != nil {
+		return Vars{}, err
+	}
+	return Vars{
 		OrgID:     orgID,
 		Channel:   channelID,
 		Scope:     ch.Scope,
 		Namespace: ch.Namespace,
 		Path:      ch.Path,
-	}
+	}, nil
+}
 
-	if len(rule.FrameProcessors) > 0 {
-		for _, proc := range rule.FrameProcessors {
-			frame, err = p.execProcessor(ctx, proc, vars, frame)
-			if err != nil {
-				logger.Error("Error processing frame", "error", err)
-				return nil, err
-			}
-			if frame == nil {
-				return nil, nil
-			}
+// processRuleFrame applies processors and then frame outputters of the rule to a frame.
+func (p *Pipeline) processRuleFrame(ctx context.Context, rule *LiveChannelRule, processors []FrameProcessor, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	var err error
+	for _, proc := range processors {
+		frame, err = p.execProcessor(ctx, proc, vars, frame)
+		if err != nil {
+			logger.Error("Error processing frame", "error", err)
+			return nil, err
+		}
+		if frame == nil {
+			return nil, nil
 		}
 	}
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -101,6 +102,42 @@ func TestPipeline(t *testing.T) {
 	require.NotNil(t, outputter.frame)
 }
 
+func TestPipeline_AggregateFlush(t *testing.T) {
+	storage := NewAggregateStorage()
+	config := AggregateFrameProcessorConfig{IntervalMilliseconds: 1000, Function: AggregateFunctionSum}
+	aggregate, err := NewAggregateFrameProcessor(config, storage)
+	require.NoError(t, err)
+	outputter := &testOutputter{}
+	p, err := New(&testRuleGetter{
+		rules: map[string]*LiveChannelRule{
+			"stream/test/xxx": {
+				Converter: &testConverter{"", data.NewFrame("test",
+					data.NewField("time", nil, []time.Time{time.Now()}),
+					data.NewField("value", nil, []float64{1}),
+					data.NewField("state", nil, []string{"ok"}),
+				)},
+				FrameProcessors: []FrameProcessor{
+					NewMultipleFrameProcessor(aggregate, NewDropFieldsFrameProcessor(DropFieldsFrameProcessorConfig{
+						FieldNames: []string{"state"},
+					})),
+				},
+				FrameOutputters: []FrameOutputter{outputter},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = p.ProcessInput(context.Background(), 1, "stream/test/xxx", []byte(`{}`))
+	require.NoError(t, err)
+	require.Nil(t, outputter.frame)
+
+	flushes := storage.Flush(time.Now().Add(2 * time.Second))
+	require.Len(t, flushes, 1)
+	require.NoError(t, p.processAggregateFlush(context.Background(), flushes[0]))
+	require.NotNil(t, outputter.frame)
+	require.Equal(t, 1, outputter.frame.Rows())
+	require.Len(t, outputter.frame.Fields, 2)
+}
+
 func TestPipeline_OutputError(t *testing.T) {
 	boomErr := errors.New("boom")
 	outputter := &testOutputter{err: boomErr}
@@ -66,6 +66,20 @@ var ConvertersRegistry = []EntityInfo{
 			FrameFormat: "labels_column",
 		},
 	},
+	{
+		Type:        ConverterTypePrometheusAuto,
+		Description: "accept Prometheus text exposition format",
+		Example: AutoPrometheusConverterConfig{
+			FrameFormat: "labels_column",
+		},
+	},
+	{
+		Type:        ConverterTypeOTLPAuto,
+		Description: "accept OTLP protobuf metrics",
+		Example: AutoOTLPConverterConfig{
+			FrameFormat: "labels_column",
+		},
+	},
 	{
 		Type:        ConverterTypeJsonFrame,
 		Description: "JSON-encoded Grafana data frame",
@@ -83,6 +97,36 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeRenameFields,
+		Description: "rename fields",
+		Example: RenameFieldsFrameProcessorConfig{
+			Renames: map[string]string{"value": "temperature"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeComputeField,
+		Description: "add a field computed by a JavaScript expression for every row",
+		Example: ComputeFieldFrameProcessorConfig{
+			FieldName:  "fahrenheit",
+			Expression: "x.celsius * 9 / 5 + 32",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeLabels,
+		Description: "add labels to all non-time fields",
+		Example: LabelsFrameProcessorConfig{
+			Labels: map[string]string{"location": "office"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeAggregate,
+		Description: "downsample frames by aggregating rows over tumbling time windows",
+		Example: AggregateFrameProcessorConfig{
+			IntervalMilliseconds: 1000,
+			Function:             AggregateFunctionMean,
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
@@ -13,6 +13,7 @@ type StorageRuleBuilder struct {
 	Node                 *centrifuge.Node
 	ManagedStream        *managedstream.Runner
 	FrameStorage         *FrameStorage
+	AggregateStorage     *AggregateStorage
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
@@ -73,6 +74,16 @@ func (f *StorageRuleBuilder) extractConverter(config *ConverterConfig) (Converte
 			return nil, missingConfiguration
 		}
 		return NewAutoInfluxConverter(*config.AutoInfluxConverterConfig), nil
+	case ConverterTypePrometheusAuto:
+		if config.AutoPrometheusConverterConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewAutoPrometheusConverter(*config.AutoPrometheusConverterConfig), nil
+	case ConverterTypeOTLPAuto:
+		if config.AutoOTLPConverterConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewAutoOTLPConverter(*config.AutoOTLPConverterConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown converter type: %s", config.Type)
 	}
@@ -94,6 +105,26 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			return nil, missingConfiguration
 		}
 		return NewKeepFieldsFrameProcessor(*config.KeepFieldsProcessorConfig), nil
+	case FrameProcessorTypeRenameFields:
+		if config.RenameFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewRenameFieldsFrameProcessor(*config.RenameFieldsProcessorConfig), nil
+	case FrameProcessorTypeComputeField:
+		if config.ComputeFieldProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewComputeFieldFrameProcessor(*config.ComputeFieldProcessorConfig)
+	case FrameProcessorTypeLabels:
+		if config.LabelsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewLabelsFrameProcessor(*config.LabelsProcessorConfig), nil
+	case FrameProcessorTypeAggregate:
+		if config.AggregateProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewAggregateFrameProcessor(*config.AggregateProcessorConfig, f.AggregateStorage)
 	case FrameProcessorTypeMultiple:
 		if config.MultipleProcessorConfig == nil {
 			return nil, missingConfiguration
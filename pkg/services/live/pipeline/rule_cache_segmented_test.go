@@ -53,6 +53,39 @@ func TestStorage_Get(t *testing.T) {
 	require.Equal(t, "stream/boom:er", rule.Pattern)
 }
 
+type countingBuilder struct {
+	pattern string
+	calls   int
+}
+
+func (b *countingBuilder) BuildRules(_ context.Context, orgID int64) ([]*LiveChannelRule, error) {
+	b.calls++
+	return []*LiveChannelRule{{OrgId: orgID, Pattern: b.pattern}}, nil
+}
+
+func TestStorage_Refresh(t *testing.T) {
+	builder := &countingBuilder{pattern: "stream/a"}
+	s := NewCacheSegmentedTree(builder)
+
+	// Organizations that are not cached yet are built on first access.
+	require.NoError(t, s.Refresh(1))
+	require.Equal(t, 0, builder.calls)
+
+	_, ok, err := s.Get(1, "stream/a")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	builder.pattern = "stream/b"
+	require.NoError(t, s.Refresh(1))
+	_, ok, err = s.Get(1, "stream/a")
+	require.NoError(t, err)
+	require.False(t, ok)
+	rule, ok, err := s.Get(1, "stream/b")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "stream/b", rule.Pattern)
+}
+
 func BenchmarkRuleGet(b *testing.B) {
 	s := NewCacheSegmentedTree(&testBuilder{})
 	for i := 0; i < b.N; i++ {
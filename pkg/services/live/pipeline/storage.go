@@ -1,6 +1,13 @@
 package pipeline
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrVersionConflict is returned when an update is based on an outdated version of
+// a channel rule or a write config.
+var ErrVersionConflict = errors.New("entity was changed since the provided version")
 
 // Storage describes all methods to manage Live pipeline persistent data.
 type Storage interface {
@@ -14,3 +21,8 @@ type Storage interface {
 	UpdateChannelRule(_ context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error)
 	DeleteChannelRule(_ context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error
 }
+
+// ChangeNotifier is notified when pipeline persistent data of an organization changes.
+type ChangeNotifier interface {
+	NotifyChange(orgID int64) error
+}
@@ -115,7 +115,11 @@ func (f *FileStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd Wr
 	if index > -1 {
 		writeConfigs.Configs[index] = backend
 	} else {
-		return f.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd(cmd))
+		return f.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd{
+			UID:            cmd.UID,
+			Settings:       cmd.Settings,
+			SecureSettings: cmd.SecureSettings,
+		})
 	}
 
 	err = f.saveWriteConfigs(orgID, writeConfigs)
@@ -221,7 +225,10 @@ func (f *FileStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd Ch
 	if index > -1 {
 		channelRules.Rules[index] = rule
 	} else {
-		return f.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd(cmd))
+		return f.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd{
+			Pattern:  cmd.Pattern,
+			Settings: cmd.Settings,
+		})
 	}
 
 	err = f.saveChannelRules(orgID, channelRules)
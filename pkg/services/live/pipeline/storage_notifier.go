@@ -0,0 +1,55 @@
+package pipeline
+
+import (
+	"encoding/json"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+const storageChangeNotificationOp = "pipeline_storage_change"
+
+type storageChangeNotification struct {
+	OrgID int64 `json:"orgId"`
+}
+
+// NodeChangeNotifier notifies all Grafana instances about changes of pipeline
+// persistent data over Centrifuge node control channel, so that each instance
+// rebuilds channel rules of the changed organization without waiting for the
+// periodic update. Without HA engine only the current instance is notified.
+type NodeChangeNotifier struct {
+	node  *centrifuge.Node
+	cache *CacheSegmentedTree
+}
+
+func NewNodeChangeNotifier(node *centrifuge.Node, cache *CacheSegmentedTree) *NodeChangeNotifier {
+	return &NodeChangeNotifier{node: node, cache: cache}
+}
+
+func (n *NodeChangeNotifier) SetupHandlers() {
+	n.node.OnNotification(n.handleNotification)
+}
+
+func (n *NodeChangeNotifier) NotifyChange(orgID int64) error {
+	data, err := json.Marshal(storageChangeNotification{OrgID: orgID})
+	if err != nil {
+		return err
+	}
+	return n.node.Notify(storageChangeNotificationOp, data, "")
+}
+
+func (n *NodeChangeNotifier) handleNotification(e centrifuge.NotificationEvent) {
+	if e.Op != storageChangeNotificationOp {
+		return
+	}
+	var notification storageChangeNotification
+	if err := json.Unmarshal(e.Data, &notification); err != nil {
+		logger.Error("Error decoding pipeline storage change notification", "error", err)
+		return
+	}
+	// Rebuild rules asynchronously to not block processing of control messages.
+	go func() {
+		if err := n.cache.Refresh(notification.OrgID); err != nil {
+			logger.Error("Error refreshing channel rules", "error", err, "orgId", notification.OrgID)
+		}
+	}()
+}
@@ -0,0 +1,384 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SQLStorage keeps channel rules and write configs in the Grafana database, so
+// all Grafana instances share the same pipeline configuration.
+type SQLStorage struct {
+	SQLStore       *sqlstore.SQLStore
+	SecretsService secrets.Service
+	// ChangeNotifier is notified after the pipeline configuration of an
+	// organization is changed. Optional.
+	ChangeNotifier ChangeNotifier
+}
+
+type liveChannelRule struct {
+	Id       int64
+	OrgId    int64
+	Pattern  string
+	Settings string
+	Version  int64
+	Created  time.Time
+	Updated  time.Time
+}
+
+func (liveChannelRule) TableName() string {
+	return "live_channel_rule"
+}
+
+func (r liveChannelRule) toChannelRule() (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:   r.OrgId,
+		Pattern: r.Pattern,
+		Version: r.Version,
+	}
+	if err := json.Unmarshal([]byte(r.Settings), &rule.Settings); err != nil {
+		return ChannelRule{}, fmt.Errorf("can't unmarshal settings of channel rule %s: %w", r.Pattern, err)
+	}
+	return rule, nil
+}
+
+type liveWriteConfig struct {
+	Id             int64
+	OrgId          int64
+	Uid            string
+	Settings       string
+	SecureSettings string
+	Version        int64
+	Created        time.Time
+	Updated        time.Time
+}
+
+func (liveWriteConfig) TableName() string {
+	return "live_write_config"
+}
+
+func (c liveWriteConfig) toWriteConfig() (WriteConfig, error) {
+	writeConfig := WriteConfig{
+		OrgId:   c.OrgId,
+		UID:     c.Uid,
+		Version: c.Version,
+	}
+	if err := json.Unmarshal([]byte(c.Settings), &writeConfig.Settings); err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal settings of write config %s: %w", c.Uid, err)
+	}
+	if c.SecureSettings != "" {
+		if err := json.Unmarshal([]byte(c.SecureSettings), &writeConfig.SecureSettings); err != nil {
+			return WriteConfig{}, fmt.Errorf("can't unmarshal secure settings of write config %s: %w", c.Uid, err)
+		}
+	}
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]WriteConfig, error) {
+	var rows []liveWriteConfig
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read write configs: %w", err)
+	}
+	writeConfigs := make([]WriteConfig, 0, len(rows))
+	for _, row := range rows {
+		writeConfig, err := row.toWriteConfig()
+		if err != nil {
+			return nil, err
+		}
+		writeConfigs = append(writeConfigs, writeConfig)
+	}
+	return writeConfigs, nil
+}
+
+func (s *SQLStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigGetCmd) (WriteConfig, bool, error) {
+	var row liveWriteConfig
+	var exists bool
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		exists, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&row)
+		return err
+	})
+	if err != nil || !exists {
+		return WriteConfig{}, false, err
+	}
+	writeConfig, err := row.toWriteConfig()
+	return writeConfig, err == nil, err
+}
+
+func (s *SQLStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigCreateCmd) (WriteConfig, error) {
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	row, writeConfig, err := s.newWriteConfigRow(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Exist(&liveWriteConfig{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("backend already exists in org: %s", cmd.UID)
+		}
+		row.Version = 1
+		_, err = sess.Insert(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	s.notifyChange(orgID)
+	writeConfig.Version = row.Version
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigUpdateCmd) (WriteConfig, error) {
+	row, writeConfig, err := s.newWriteConfigRow(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var existing liveWriteConfig
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			row.Version = 1
+			_, err = sess.Insert(&row)
+			return err
+		}
+		if cmd.Version != 0 && cmd.Version != existing.Version {
+			return ErrVersionConflict
+		}
+		row.Version = existing.Version + 1
+		_, err = sess.ID(existing.Id).Cols("settings", "secure_settings", "version", "updated").Update(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	s.notifyChange(orgID)
+	writeConfig.Version = row.Version
+	return writeConfig, nil
+}
+
+// newWriteConfigRow validates the write config and encrypts its secure settings. The encryption must
+// happen outside of database transactions.
+func (s *SQLStorage) newWriteConfigRow(ctx context.Context, orgID int64, uid string, settings WriteSettings, secureSettings map[string]string) (liveWriteConfig, WriteConfig, error) {
+	encryptedSettings, err := s.SecretsService.EncryptJsonData(ctx, secureSettings, secrets.WithoutScope())
+	if err != nil {
+		return liveWriteConfig{}, WriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+
+	writeConfig := WriteConfig{
+		OrgId:          orgID,
+		UID:            uid,
+		Settings:       settings,
+		SecureSettings: encryptedSettings,
+	}
+	ok, reason := writeConfig.Valid()
+	if !ok {
+		return liveWriteConfig{}, WriteConfig{}, fmt.Errorf("invalid write config: %s", reason)
+	}
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return liveWriteConfig{}, WriteConfig{}, err
+	}
+	secureSettingsJSON, err := json.Marshal(encryptedSettings)
+	if err != nil {
+		return liveWriteConfig{}, WriteConfig{}, err
+	}
+	now := time.Now()
+	return liveWriteConfig{
+		OrgId:          orgID,
+		Uid:            uid,
+		Settings:       string(settingsJSON),
+		SecureSettings: string(secureSettingsJSON),
+		Created:        now,
+		Updated:        now,
+	}, writeConfig, nil
+}
+
+func (s *SQLStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigDeleteCmd) error {
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		affected, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Delete(&liveWriteConfig{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return errors.New("write config not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.notifyChange(orgID)
+	return nil
+}
+
+func (s *SQLStorage) ListChannelRules(ctx context.Context, orgID int64) ([]ChannelRule, error) {
+	var rules []ChannelRule
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		rules, err = listChannelRules(sess, orgID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read channel rules: %w", err)
+	}
+	return rules, nil
+}
+
+func listChannelRules(sess *sqlstore.DBSession, orgID int64) ([]ChannelRule, error) {
+	var rows []liveChannelRule
+	if err := sess.Where("org_id = ?", orgID).Asc("pattern").Find(&rows); err != nil {
+		return nil, err
+	}
+	rules := make([]ChannelRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := row.toChannelRule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (s *SQLStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: cmd.Settings,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return s.saveChannelRule(sess, &rule, 0, false)
+	})
+	if err != nil {
+		return rule, err
+	}
+	s.notifyChange(orgID)
+	return rule, nil
+}
+
+func (s *SQLStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: cmd.Settings,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return s.saveChannelRule(sess, &rule, cmd.Version, true)
+	})
+	if err != nil {
+		return rule, err
+	}
+	s.notifyChange(orgID)
+	return rule, nil
+}
+
+// saveChannelRule inserts the rule, or updates it if allowUpdate is set and a rule with the same
+// pattern exists. The rule is only saved if it does not conflict with other rules of the org.
+func (s *SQLStorage) saveChannelRule(sess *sqlstore.DBSession, rule *ChannelRule, version int64, allowUpdate bool) error {
+	rules, err := listChannelRules(sess, rule.OrgId)
+	if err != nil {
+		return fmt.Errorf("can't read channel rules: %w", err)
+	}
+
+	index := -1
+	for i, existingRule := range rules {
+		if existingRule.Pattern == rule.Pattern {
+			index = i
+			break
+		}
+	}
+	if index > -1 && !allowUpdate {
+		return fmt.Errorf("pattern already exists in org: %s", rule.Pattern)
+	}
+	if index > -1 {
+		if version != 0 && version != rules[index].Version {
+			return ErrVersionConflict
+		}
+		rule.Version = rules[index].Version + 1
+		rules[index] = *rule
+	} else {
+		rule.Version = 1
+		rules = append(rules, *rule)
+	}
+	ok, reason := checkRulesValid(rule.OrgId, rules)
+	if !ok {
+		return errors.New(reason)
+	}
+
+	settingsJSON, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return err
+	}
+	row := liveChannelRule{
+		OrgId:    rule.OrgId,
+		Pattern:  rule.Pattern,
+		Settings: string(settingsJSON),
+		Version:  rule.Version,
+		Updated:  time.Now(),
+	}
+	if index > -1 {
+		_, err = sess.Where("org_id = ? AND pattern = ?", rule.OrgId, rule.Pattern).
+			Cols("settings", "version", "updated").Update(&row)
+		return err
+	}
+	row.Created = row.Updated
+	_, err = sess.Insert(&row)
+	return err
+}
+
+func (s *SQLStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		affected, err := sess.Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Delete(&liveChannelRule{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return errors.New("rule not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.notifyChange(orgID)
+	return nil
+}
+
+func (s *SQLStorage) notifyChange(orgID int64) {
+	if s.ChangeNotifier == nil {
+		return
+	}
+	if err := s.ChangeNotifier.NotifyChange(orgID); err != nil {
+		logger.Error("Error notifying about pipeline storage change", "error", err, "orgId", orgID)
+	}
+}
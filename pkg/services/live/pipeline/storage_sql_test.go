@@ -0,0 +1,148 @@
+//go:build integration
+// +build integration
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+type testChangeNotifier struct {
+	orgIDs []int64
+}
+
+func (n *testChangeNotifier) NotifyChange(orgID int64) error {
+	n.orgIDs = append(n.orgIDs, orgID)
+	return nil
+}
+
+func setupSQLStorage(t *testing.T) (*SQLStorage, *testChangeNotifier) {
+	t.Helper()
+	sqlStore := sqlstore.InitTestDB(t, sqlstore.InitTestDBOpt{FeatureFlags: []string{featuremgmt.FlagLivePipeline}})
+	notifier := &testChangeNotifier{}
+	return &SQLStorage{
+		SQLStore:       sqlStore,
+		SecretsService: secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore)),
+		ChangeNotifier: notifier,
+	}, notifier
+}
+
+func TestSQLStorage_ChannelRules(t *testing.T) {
+	ctx := context.Background()
+	storage, notifier := setupSQLStorage(t)
+
+	rule, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{
+		Pattern:  "stream/telegraf/:metric",
+		Settings: ChannelRuleSettings{Converter: &ConverterConfig{Type: ConverterTypeInfluxAuto}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rule.Version)
+
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/telegraf/:metric"})
+	require.ErrorContains(t, err, "pattern already exists in org")
+
+	// Patterns that conflict with existing rules are rejected.
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/telegraf/:other"})
+	require.Error(t, err)
+
+	// Rules are scoped by organization.
+	_, err = storage.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/telegraf/:metric"})
+	require.NoError(t, err)
+
+	rules, err := storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, ConverterTypeInfluxAuto, rules[0].Settings.Converter.Type)
+
+	rule, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/telegraf/:metric", Version: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), rule.Version)
+
+	_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/telegraf/:metric", Version: 1})
+	require.ErrorIs(t, err, ErrVersionConflict)
+
+	rules, err = storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Nil(t, rules[0].Settings.Converter)
+	require.Equal(t, int64(2), rules[0].Version)
+
+	require.NoError(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/telegraf/:metric"}))
+	require.ErrorContains(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/telegraf/:metric"}), "rule not found")
+
+	rules, err = storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, rules)
+	rules, err = storage.ListChannelRules(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	require.Equal(t, []int64{1, 2, 1, 1}, notifier.orgIDs)
+}
+
+func TestSQLStorage_WriteConfigs(t *testing.T) {
+	ctx := context.Background()
+	storage, notifier := setupSQLStorage(t)
+
+	writeConfig, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+		Settings:       WriteSettings{Endpoint: "http://localhost:9090/api/v1/write"},
+		SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, writeConfig.UID)
+	require.Equal(t, int64(1), writeConfig.Version)
+
+	_, err = storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: "invalid"})
+	require.ErrorContains(t, err, "endpoint required")
+
+	stored, ok, err := storage.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: writeConfig.UID})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "http://localhost:9090/api/v1/write", stored.Settings.Endpoint)
+	require.NotEqual(t, []byte("secret"), stored.SecureSettings["basicAuthPassword"])
+	secureSettings, err := storage.SecretsService.DecryptJsonData(ctx, stored.SecureSettings)
+	require.NoError(t, err)
+	require.Equal(t, "secret", secureSettings["basicAuthPassword"])
+
+	// Write configs are scoped by organization.
+	_, ok, err = storage.GetWriteConfig(ctx, 2, WriteConfigGetCmd{UID: writeConfig.UID})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	updated, err := storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{
+		UID:      writeConfig.UID,
+		Settings: WriteSettings{Endpoint: "http://localhost:9091/api/v1/write"},
+		Version:  1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), updated.Version)
+	require.Empty(t, updated.SecureSettings)
+
+	_, err = storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{
+		UID:      writeConfig.UID,
+		Settings: WriteSettings{Endpoint: "http://localhost:9092/api/v1/write"},
+		Version:  1,
+	})
+	require.ErrorIs(t, err, ErrVersionConflict)
+
+	writeConfigs, err := storage.ListWriteConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, writeConfigs, 1)
+	require.Equal(t, "http://localhost:9091/api/v1/write", writeConfigs[0].Settings.Endpoint)
+
+	require.NoError(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: writeConfig.UID}))
+	require.ErrorContains(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: writeConfig.UID}), "write config not found")
+	writeConfigs, err = storage.ListWriteConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, writeConfigs)
+
+	require.Equal(t, []int64{1, 1, 1}, notifier.orgIDs)
+}
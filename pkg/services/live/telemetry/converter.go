@@ -1,12 +1,24 @@
 package telemetry
 
-import "github.com/grafana/grafana-plugin-sdk-go/data"
+import (
+	"regexp"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
 
 // Converter can convert input to Grafana Data Frames.
 type Converter interface {
 	Convert(data []byte) ([]FrameWrapper, error)
 }
 
+var invalidPathChars = regexp.MustCompile(`[^A-Za-z0-9_\-=.]`)
+
+// SanitizePath replaces characters which are not allowed in Live channel path,
+// so that a metric name can be used as a frame key.
+func SanitizePath(s string) string {
+	return invalidPathChars.ReplaceAllString(s, "_")
+}
+
 // FrameWrapper is a wrapper over data.Frame.
 type FrameWrapper interface {
 	// Key returns a key which describes Frame metrics.
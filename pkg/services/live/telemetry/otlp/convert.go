@@ -0,0 +1,200 @@
+package otlp
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// serviceNameAttribute is a resource attribute with a name of a service
+// which produced the metrics.
+const serviceNameAttribute = "service.name"
+
+// Converter converts OTLP/HTTP metrics protobuf payloads to Grafana frames.
+type Converter struct {
+	unmarshaler     pdata.MetricsUnmarshaler
+	useLabelsColumn bool
+}
+
+// ConverterOption ...
+type ConverterOption func(*Converter)
+
+// WithUseLabelsColumn ...
+func WithUseLabelsColumn(enabled bool) ConverterOption {
+	return func(c *Converter) {
+		c.useLabelsColumn = enabled
+	}
+}
+
+// NewConverter creates new Converter from OTLP metrics to Grafana Data Frames. This
+// converter generates frames for each metric of each service, the frame key is
+// <service.name>/<metric name>. Resource attributes and data point labels become
+// frame labels. Histograms and summaries are expanded to the _bucket, _sum and _count
+// series like in Prometheus.
+func NewConverter(opts ...ConverterOption) *Converter {
+	c := &Converter{
+		unmarshaler: otlp.NewProtobufMetricsUnmarshaler(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Convert metrics.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	metrics, err := c.unmarshaler.UnmarshalMetrics(body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	b := &sampleBuilder{now: time.Now()}
+	resourceMetrics := metrics.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+		resourceLabels := attributesToLabels(rm.Resource().Attributes())
+		keyPrefix := ""
+		if serviceName, ok := resourceLabels[serviceNameAttribute]; ok && serviceName != "" {
+			keyPrefix = telemetry.SanitizePath(serviceName) + "/"
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ms := ilms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := ms.At(k)
+				b.key = keyPrefix + telemetry.SanitizePath(m.Name())
+				b.name = m.Name()
+				b.resourceLabels = resourceLabels
+				b.appendMetric(m)
+			}
+		}
+	}
+	return telemetry.SamplesToFrames(b.samples, c.useLabelsColumn), nil
+}
+
+// sampleBuilder collects samples of metrics, key, name and resourceLabels describe
+// the currently processed metric.
+type sampleBuilder struct {
+	key            string
+	name           string
+	resourceLabels data.Labels
+	now            time.Time
+	samples        []telemetry.Sample
+}
+
+func (b *sampleBuilder) appendMetric(m pdata.Metric) {
+	switch m.DataType() {
+	case pdata.MetricDataTypeGauge:
+		b.appendNumberDataPoints(m.Gauge().DataPoints())
+	case pdata.MetricDataTypeSum:
+		b.appendNumberDataPoints(m.Sum().DataPoints())
+	case pdata.MetricDataTypeIntGauge:
+		b.appendIntDataPoints(m.IntGauge().DataPoints())
+	case pdata.MetricDataTypeIntSum:
+		b.appendIntDataPoints(m.IntSum().DataPoints())
+	case pdata.MetricDataTypeHistogram:
+		points := m.Histogram().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			dp := points.At(i)
+			labels, t := b.labels(dp.LabelsMap()), b.time(dp.Timestamp())
+			// OTLP bucket counts are not cumulative, bucket i counts values in
+			// (bounds[i-1], bounds[i]] and the last bucket counts values above the
+			// last bound.
+			var cumulative uint64
+			bounds := dp.ExplicitBounds()
+			for j, count := range dp.BucketCounts() {
+				cumulative += count
+				le := "+Inf"
+				if j < len(bounds) {
+					le = strconv.FormatFloat(bounds[j], 'f', -1, 64)
+				}
+				b.append(b.name+"_bucket", withLabel(labels, "le", le), t, float64(cumulative))
+			}
+			b.append(b.name+"_sum", labels, t, dp.Sum())
+			b.append(b.name+"_count", labels, t, float64(dp.Count()))
+		}
+	case pdata.MetricDataTypeSummary:
+		points := m.Summary().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			dp := points.At(i)
+			labels, t := b.labels(dp.LabelsMap()), b.time(dp.Timestamp())
+			quantiles := dp.QuantileValues()
+			for j := 0; j < quantiles.Len(); j++ {
+				q := quantiles.At(j)
+				b.append(b.name, withLabel(labels, "quantile", strconv.FormatFloat(q.Quantile(), 'f', -1, 64)), t, q.Value())
+			}
+			b.append(b.name+"_sum", labels, t, dp.Sum())
+			b.append(b.name+"_count", labels, t, float64(dp.Count()))
+		}
+	}
+}
+
+func (b *sampleBuilder) appendNumberDataPoints(points pdata.NumberDataPointSlice) {
+	for i := 0; i < points.Len(); i++ {
+		dp := points.At(i)
+		value := dp.DoubleVal()
+		if dp.Type() == pdata.MetricValueTypeInt {
+			value = float64(dp.IntVal())
+		}
+		b.append(b.name, b.labels(dp.LabelsMap()), b.time(dp.Timestamp()), value)
+	}
+}
+
+func (b *sampleBuilder) appendIntDataPoints(points pdata.IntDataPointSlice) {
+	for i := 0; i < points.Len(); i++ {
+		dp := points.At(i)
+		b.append(b.name, b.labels(dp.LabelsMap()), b.time(dp.Timestamp()), float64(dp.Value()))
+	}
+}
+
+func (b *sampleBuilder) append(name string, labels data.Labels, t time.Time, value float64) {
+	b.samples = append(b.samples, telemetry.Sample{Key: b.key, Name: name, Labels: labels, Time: t, Value: value})
+}
+
+func (b *sampleBuilder) labels(labelsMap pdata.StringMap) data.Labels {
+	labels := b.resourceLabels.Copy()
+	labelsMap.Range(func(k string, v string) bool {
+		labels[k] = v
+		return true
+	})
+	return labels
+}
+
+func (b *sampleBuilder) time(ts pdata.Timestamp) time.Time {
+	if ts == 0 {
+		return b.now
+	}
+	return ts.AsTime()
+}
+
+func withLabel(labels data.Labels, name, value string) data.Labels {
+	l := labels.Copy()
+	l[name] = value
+	return l
+}
+
+func attributesToLabels(attributes pdata.AttributeMap) data.Labels {
+	labels := data.Labels{}
+	attributes.Range(func(k string, v pdata.AttributeValue) bool {
+		switch v.Type() {
+		case pdata.AttributeValueTypeString:
+			labels[k] = v.StringVal()
+		case pdata.AttributeValueTypeInt:
+			labels[k] = strconv.FormatInt(v.IntVal(), 10)
+		case pdata.AttributeValueTypeDouble:
+			labels[k] = strconv.FormatFloat(v.DoubleVal(), 'f', -1, 64)
+		case pdata.AttributeValueTypeBool:
+			labels[k] = strconv.FormatBool(v.BoolVal())
+		}
+		return true
+	})
+	return labels
+}
@@ -0,0 +1,93 @@
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+var testTime = time.Unix(1395066363, 0).UTC()
+
+func testMetrics(t *testing.T) []byte {
+	t.Helper()
+	metrics := pdata.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString("service.name", "checkout service")
+	ms := rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+
+	m := ms.AppendEmpty()
+	m.SetName("requests")
+	m.SetDataType(pdata.MetricDataTypeSum)
+	for _, code := range []string{"200", "400"} {
+		dp := m.Sum().DataPoints().AppendEmpty()
+		dp.LabelsMap().Insert("code", code)
+		dp.SetTimestamp(pdata.TimestampFromTime(testTime))
+		dp.SetIntVal(3)
+	}
+
+	m = ms.AppendEmpty()
+	m.SetName("duration")
+	m.SetDataType(pdata.MetricDataTypeHistogram)
+	dp := m.Histogram().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pdata.TimestampFromTime(testTime))
+	dp.SetExplicitBounds([]float64{0.1, 1})
+	dp.SetBucketCounts([]uint64{2, 3, 1})
+	dp.SetSum(4.5)
+	dp.SetCount(6)
+
+	body, err := otlp.NewProtobufMetricsMarshaler().MarshalMetrics(metrics)
+	require.NoError(t, err)
+	return body
+}
+
+func TestNewConverter(t *testing.T) {
+	c := NewConverter(WithUseLabelsColumn(true))
+	require.True(t, c.useLabelsColumn)
+}
+
+func TestConverter_Convert(t *testing.T) {
+	frameWrappers, err := NewConverter().Convert(testMetrics(t))
+	require.NoError(t, err)
+	require.Len(t, frameWrappers, 2)
+	require.Equal(t, "checkout_service/requests", frameWrappers[0].Key())
+	require.Equal(t, "checkout_service/duration", frameWrappers[1].Key())
+
+	frame := frameWrappers[0].Frame()
+	require.Len(t, frame.Fields, 3)
+	require.Equal(t, testTime, frame.Fields[0].At(0).(time.Time).UTC())
+	require.Equal(t, data.Labels{"service.name": "checkout service", "code": "400"}, frame.Fields[2].Labels)
+	require.Equal(t, 3.0, *frame.Fields[2].At(0).(*float64))
+
+	frame = frameWrappers[1].Frame()
+	require.Len(t, frame.Fields, 6)
+	var buckets []float64
+	for _, f := range frame.Fields[1:4] {
+		require.Equal(t, "duration_bucket", f.Name)
+		buckets = append(buckets, *f.At(0).(*float64))
+	}
+	require.Equal(t, []float64{2, 5, 6}, buckets)
+	require.Equal(t, "+Inf", frame.Fields[3].Labels["le"])
+	require.Equal(t, "duration_sum", frame.Fields[4].Name)
+	require.Equal(t, "duration_count", frame.Fields[5].Name)
+}
+
+func TestConverter_Convert_LabelsColumn(t *testing.T) {
+	frameWrappers, err := NewConverter(WithUseLabelsColumn(true)).Convert(testMetrics(t))
+	require.NoError(t, err)
+	require.Len(t, frameWrappers, 2)
+
+	frame := frameWrappers[0].Frame()
+	require.Equal(t, 2, frame.Rows())
+	require.Len(t, frame.Fields, 3)
+	require.Equal(t, "code=200, service.name=checkout service", frame.Fields[0].At(0))
+	require.Equal(t, "requests", frame.Fields[2].Name)
+}
+
+func TestConverter_Convert_Invalid(t *testing.T) {
+	_, err := NewConverter().Convert([]byte("not a protobuf"))
+	require.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// Converter converts metrics in Prometheus text exposition format to Grafana frames.
+type Converter struct {
+	useLabelsColumn bool
+}
+
+// ConverterOption ...
+type ConverterOption func(*Converter)
+
+// WithUseLabelsColumn ...
+func WithUseLabelsColumn(enabled bool) ConverterOption {
+	return func(c *Converter) {
+		c.useLabelsColumn = enabled
+	}
+}
+
+// NewConverter creates new Converter from Prometheus text exposition format to Grafana
+// Data Frames. This converter generates frames for each metric family, histograms and
+// summaries are expanded to the _bucket, _sum and _count series like Prometheus does.
+// Samples without a timestamp get the time of conversion.
+func NewConverter(opts ...ConverterOption) *Converter {
+	c := &Converter{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Convert metrics.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	var samples []telemetry.Sample
+	for _, name := range names {
+		samples = appendFamilySamples(samples, families[name], now)
+	}
+	return telemetry.SamplesToFrames(samples, c.useLabelsColumn), nil
+}
+
+func appendFamilySamples(samples []telemetry.Sample, family *dto.MetricFamily, now time.Time) []telemetry.Sample {
+	name := family.GetName()
+	// Recording rule names such as job:http_requests:rate5m contain characters which
+	// are not allowed in channel paths.
+	key := telemetry.SanitizePath(name)
+	for _, m := range family.GetMetric() {
+		labels := data.Labels{}
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		t := now
+		if m.TimestampMs != nil {
+			t = time.UnixMilli(m.GetTimestampMs())
+		}
+		sample := func(sampleName string, value float64, extraLabel ...string) telemetry.Sample {
+			sampleLabels := labels
+			if len(extraLabel) == 2 {
+				sampleLabels = labels.Copy()
+				sampleLabels[extraLabel[0]] = extraLabel[1]
+			}
+			return telemetry.Sample{Key: key, Name: sampleName, Labels: sampleLabels, Time: t, Value: value}
+		}
+
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			samples = append(samples, sample(name, m.GetCounter().GetValue()))
+		case dto.MetricType_GAUGE:
+			samples = append(samples, sample(name, m.GetGauge().GetValue()))
+		case dto.MetricType_UNTYPED:
+			samples = append(samples, sample(name, m.GetUntyped().GetValue()))
+		case dto.MetricType_SUMMARY:
+			summary := m.GetSummary()
+			for _, q := range summary.GetQuantile() {
+				samples = append(samples, sample(name, q.GetValue(), "quantile", formatFloat(q.GetQuantile())))
+			}
+			samples = append(samples,
+				sample(name+"_sum", summary.GetSampleSum()),
+				sample(name+"_count", float64(summary.GetSampleCount())),
+			)
+		case dto.MetricType_HISTOGRAM:
+			histogram := m.GetHistogram()
+			for _, b := range histogram.GetBucket() {
+				samples = append(samples, sample(name+"_bucket", float64(b.GetCumulativeCount()), "le", formatFloat(b.GetUpperBound())))
+			}
+			samples = append(samples,
+				sample(name+"_sum", histogram.GetSampleSum()),
+				sample(name+"_count", float64(histogram.GetSampleCount())),
+			)
+		}
+	}
+	return samples
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
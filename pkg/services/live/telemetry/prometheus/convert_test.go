@@ -0,0 +1,101 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+const testMetrics = `# HELP http_requests_total The total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="post",code="200"} 1027 1395066363000
+http_requests_total{method="post",code="400"} 3 1395066363000
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} 5 1395066363000
+request_duration_seconds_bucket{le="+Inf"} 7 1395066363000
+request_duration_seconds_sum 1.5 1395066363000
+request_duration_seconds_count 7 1395066363000
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.2 1395066363000
+rpc_duration_seconds{quantile="0.9"} NaN 1395066363000
+rpc_duration_seconds_sum 17 1395066363000
+rpc_duration_seconds_count 20 1395066363000
+`
+
+func TestNewConverter(t *testing.T) {
+	c := NewConverter(WithUseLabelsColumn(true))
+	require.True(t, c.useLabelsColumn)
+}
+
+func TestConverter_Convert(t *testing.T) {
+	frameWrappers, err := NewConverter().Convert([]byte(testMetrics))
+	require.NoError(t, err)
+	require.Len(t, frameWrappers, 3)
+
+	keys := make([]string, 0, len(frameWrappers))
+	for _, fw := range frameWrappers {
+		keys = append(keys, fw.Key())
+		_, err := data.FrameToJSON(fw.Frame(), data.IncludeAll)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"http_requests_total", "request_duration_seconds", "rpc_duration_seconds"}, keys)
+
+	frame := frameWrappers[0].Frame()
+	require.Len(t, frame.Fields, 3)
+	require.Equal(t, time.UnixMilli(1395066363000), frame.Fields[0].At(0))
+	require.Equal(t, data.Labels{"method": "post", "code": "400"}, frame.Fields[2].Labels)
+	require.Equal(t, 3.0, *frame.Fields[2].At(0).(*float64))
+
+	frame = frameWrappers[1].Frame()
+	require.Len(t, frame.Fields, 5)
+	require.Equal(t, "request_duration_seconds_bucket", frame.Fields[1].Name)
+	require.Equal(t, data.Labels{"le": "0.1"}, frame.Fields[1].Labels)
+	require.Equal(t, data.Labels{"le": "+Inf"}, frame.Fields[2].Labels)
+	require.Equal(t, "request_duration_seconds_count", frame.Fields[4].Name)
+
+	frame = frameWrappers[2].Frame()
+	require.Nil(t, frame.Fields[2].At(0))
+}
+
+func TestConverter_Convert_LabelsColumn(t *testing.T) {
+	frameWrappers, err := NewConverter(WithUseLabelsColumn(true)).Convert([]byte(testMetrics))
+	require.NoError(t, err)
+	require.Len(t, frameWrappers, 3)
+
+	frame := frameWrappers[0].Frame()
+	require.Len(t, frame.Fields, 3)
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, "labels", frame.Fields[0].Name)
+	require.Equal(t, "code=200, method=post", frame.Fields[0].At(0))
+	require.Equal(t, "http_requests_total", frame.Fields[2].Name)
+
+	frame = frameWrappers[1].Frame()
+	require.Equal(t, 3, frame.Rows())
+	require.Equal(t, []string{"labels", "time", "request_duration_seconds_bucket", "request_duration_seconds_sum", "request_duration_seconds_count"}, fieldNames(frame))
+	require.Nil(t, frame.Fields[3].At(0))
+	require.Equal(t, 1.5, *frame.Fields[3].At(2).(*float64))
+}
+
+func TestConverter_Convert_Invalid(t *testing.T) {
+	_, err := NewConverter().Convert([]byte("metric{ 1"))
+	require.Error(t, err)
+}
+
+func fieldNames(frame *data.Frame) []string {
+	names := make([]string, 0, len(frame.Fields))
+	for _, f := range frame.Fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestConverter_Convert_RecordingRuleName(t *testing.T) {
+	frameWrappers, err := NewConverter().Convert([]byte(`job:http_requests:rate5m{job="api"} 1.5 1395066363000
+`))
+	require.NoError(t, err)
+	require.Len(t, frameWrappers, 1)
+	require.Equal(t, "job_http_requests_rate5m", frameWrappers[0].Key())
+	require.Equal(t, "job:http_requests:rate5m", frameWrappers[0].Frame().Fields[1].Name)
+}
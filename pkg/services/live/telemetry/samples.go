@@ -0,0 +1,138 @@
+package telemetry
+
+import (
+	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Sample is a single value of a metric series.
+type Sample struct {
+	// Key of a frame the sample belongs to.
+	Key    string
+	Name   string
+	Labels data.Labels
+	Time   time.Time
+	Value  float64
+}
+
+type sampleFrame struct {
+	key   string
+	frame *data.Frame
+}
+
+// Key returns a key which describes Frame metrics.
+func (f *sampleFrame) Key() string {
+	return f.key
+}
+
+// Frame allows getting data.Frame.
+func (f *sampleFrame) Frame() *data.Frame {
+	return f.frame
+}
+
+// SamplesToFrames groups samples to frames by key, maintaining the order of samples in
+// input. With labelsColumn each frame has labels and time fields and a field for each
+// sample name. Otherwise there is a frame for each key and time combination with a field
+// for each series.
+func SamplesToFrames(samples []Sample, labelsColumn bool) []FrameWrapper {
+	if labelsColumn {
+		return samplesToLabelsColumnFrames(samples)
+	}
+	return samplesToWideFrames(samples)
+}
+
+func nullableValue(v float64) *float64 {
+	if math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
+
+func samplesToWideFrames(samples []Sample) []FrameWrapper {
+	type frameKey struct {
+		key  string
+		time time.Time
+	}
+	var frameKeyOrder []frameKey
+	frames := map[frameKey]*data.Frame{}
+
+	for _, s := range samples {
+		k := frameKey{key: s.Key, time: s.Time}
+		frame, ok := frames[k]
+		if !ok {
+			frameKeyOrder = append(frameKeyOrder, k)
+			frame = data.NewFrame(s.Key, data.NewField("time", nil, []time.Time{s.Time}))
+			frames[k] = frame
+		}
+		frame.Fields = append(frame.Fields, data.NewField(s.Name, s.Labels, []*float64{nullableValue(s.Value)}))
+	}
+
+	frameWrappers := make([]FrameWrapper, 0, len(frameKeyOrder))
+	for _, k := range frameKeyOrder {
+		frameWrappers = append(frameWrappers, &sampleFrame{key: k.key, frame: frames[k]})
+	}
+	return frameWrappers
+}
+
+type labelsColumnFrame struct {
+	labels     []string
+	times      []time.Time
+	rows       map[string]int
+	fieldOrder []string
+	fields     map[string]map[int]*float64
+}
+
+func samplesToLabelsColumnFrames(samples []Sample) []FrameWrapper {
+	var keyOrder []string
+	frames := map[string]*labelsColumnFrame{}
+
+	for _, s := range samples {
+		frame, ok := frames[s.Key]
+		if !ok {
+			keyOrder = append(keyOrder, s.Key)
+			frame = &labelsColumnFrame{
+				rows:   map[string]int{},
+				fields: map[string]map[int]*float64{},
+			}
+			frames[s.Key] = frame
+		}
+
+		labels := s.Labels.String()
+		rowKey := labels + "_" + s.Time.String()
+		row, ok := frame.rows[rowKey]
+		if !ok {
+			row = len(frame.labels)
+			frame.rows[rowKey] = row
+			frame.labels = append(frame.labels, labels)
+			frame.times = append(frame.times, s.Time)
+		}
+		values, ok := frame.fields[s.Name]
+		if !ok {
+			frame.fieldOrder = append(frame.fieldOrder, s.Name)
+			values = map[int]*float64{}
+			frame.fields[s.Name] = values
+		}
+		values[row] = nullableValue(s.Value)
+	}
+
+	frameWrappers := make([]FrameWrapper, 0, len(keyOrder))
+	for _, key := range keyOrder {
+		frame := frames[key]
+		fields := []*data.Field{
+			data.NewField("labels", nil, frame.labels),
+			data.NewField("time", nil, frame.times),
+		}
+		for _, name := range frame.fieldOrder {
+			// Rows without a value of the field are filled with nulls.
+			values := make([]*float64, len(frame.labels))
+			for row, v := range frame.fields[name] {
+				values[row] = v
+			}
+			fields = append(fields, data.NewField(name, nil, values))
+		}
+		frameWrappers = append(frameWrappers, &sampleFrame{key: key, frame: data.NewFrame(key, fields...)})
+	}
+	return frameWrappers
+}
@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplesToFrames(t *testing.T) {
+	t1 := time.Unix(1, 0)
+	t2 := time.Unix(2, 0)
+	samples := []Sample{
+		{Key: "cpu", Name: "usage", Labels: data.Labels{"host": "a"}, Time: t1, Value: 1},
+		{Key: "cpu", Name: "usage", Labels: data.Labels{"host": "b"}, Time: t1, Value: 2},
+		{Key: "mem", Name: "used", Labels: data.Labels{"host": "a"}, Time: t1, Value: math.NaN()},
+		{Key: "cpu", Name: "idle", Labels: data.Labels{"host": "a"}, Time: t2, Value: 3},
+	}
+
+	t.Run("wide", func(t *testing.T) {
+		frameWrappers := SamplesToFrames(samples, false)
+		require.Len(t, frameWrappers, 3)
+		require.Equal(t, "cpu", frameWrappers[0].Key())
+		require.Equal(t, "mem", frameWrappers[1].Key())
+		require.Equal(t, "cpu", frameWrappers[2].Key())
+
+		frame := frameWrappers[0].Frame()
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, t1, frame.Fields[0].At(0))
+		require.Equal(t, data.Labels{"host": "b"}, frame.Fields[2].Labels)
+		require.Equal(t, 2.0, *frame.Fields[2].At(0).(*float64))
+
+		require.Nil(t, frameWrappers[1].Frame().Fields[1].At(0))
+	})
+
+	t.Run("labels column", func(t *testing.T) {
+		frameWrappers := SamplesToFrames(samples, true)
+		require.Len(t, frameWrappers, 2)
+
+		frame := frameWrappers[0].Frame()
+		require.Equal(t, "cpu", frameWrappers[0].Key())
+		require.Equal(t, 3, frame.Rows())
+		require.Len(t, frame.Fields, 4)
+		require.Equal(t, "host=b", frame.Fields[0].At(1))
+		require.Equal(t, "idle", frame.Fields[3].Name)
+		require.Nil(t, frame.Fields[3].At(0))
+		require.Equal(t, 3.0, *frame.Fields[3].At(2).(*float64))
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -69,6 +70,7 @@ type API struct {
 	TransactionManager   provisioning.TransactionManager
 	RuleStore            store.RuleStore
 	InstanceStore        store.InstanceStore
+	StateHistoryStore    store.StateHistoryStore
 	AlertingStore        AlertingStore
 	AdminConfigStore     store.AdminConfigurationStore
 	DataProxy            *datasourceproxy.DataSourceProxyService
@@ -78,6 +80,10 @@ type API struct {
 	AccessControl        accesscontrol.AccessControl
 	Policies             *provisioning.NotificationPolicyService
 	ContactPointService  *provisioning.ContactPointService
+	Templates            *provisioning.TemplateService
+	MuteTimings          *provisioning.MuteTimingService
+	AlertRules           *provisioning.AlertRuleService
+	ProvenanceStore      provisioning.ProvisioningStore
 }
 
 // RegisterAPIEndpoints registers API handlers
@@ -91,7 +97,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterAlertmanagerApiEndpoints(NewForkedAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
-		&AlertmanagerSrv{store: api.AlertingStore, mam: api.MultiOrgAlertmanager, secrets: api.SecretsService, log: logger, ac: api.AccessControl},
+		&AlertmanagerSrv{store: api.AlertingStore, provenanceStore: api.ProvenanceStore, mam: api.MultiOrgAlertmanager, secrets: api.SecretsService, log: logger, ac: api.AccessControl},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkedProm(
@@ -108,12 +114,15 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			QuotaService:    api.QuotaService,
 			scheduleService: api.Schedule,
 			store:           api.RuleStore,
+			historyStore:    api.StateHistoryStore,
+			provenanceStore: api.ProvenanceStore,
 			xactManager:     api.TransactionManager,
 			log:             logger,
 			cfg:             &api.Cfg.UnifiedAlerting,
 			ac:              api.AccessControl,
 		},
 	), m)
+	evaluator := eval.NewEvaluator(api.Cfg, log.New("ngalert.eval"), api.DatasourceCache, api.SecretsService)
 	api.RegisterTestingApiEndpoints(NewForkedTestingApi(
 		&TestingApiSrv{
 			AlertingProxy:     proxy,
@@ -121,7 +130,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			DatasourceCache:   api.DatasourceCache,
 			log:               logger,
 			accessControl:     api.AccessControl,
-			evaluator:         eval.NewEvaluator(api.Cfg, log.New("ngalert.eval"), api.DatasourceCache, api.SecretsService),
+			evaluator:         evaluator,
+			backtesting:       backtesting.NewEngine(evaluator, api.ExpressionService, backtesting.DefaultMaxEvaluations, log.New("ngalert.backtesting")),
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewForkedConfiguration(
 		&AdminSrv{
@@ -136,6 +146,13 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:                 logger,
 			policies:            api.Policies,
 			contactPointService: api.ContactPointService,
+			templates:           api.Templates,
+			muteTimings:         api.MuteTimings,
+			alertRules:          api.AlertRules,
+			ruleStore:           api.RuleStore,
+			datasourceCache:     api.DatasourceCache,
+			cfg:                 &api.Cfg.UnifiedAlerting,
+			ac:                  api.AccessControl,
 		}), m)
 	}
 }
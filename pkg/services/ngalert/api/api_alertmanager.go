@@ -1,8 +1,10 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -17,6 +19,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/util"
@@ -29,11 +32,12 @@ const (
 )
 
 type AlertmanagerSrv struct {
-	mam     *notifier.MultiOrgAlertmanager
-	secrets secrets.Service
-	store   AlertingStore
-	log     log.Logger
-	ac      accesscontrol.AccessControl
+	mam             *notifier.MultiOrgAlertmanager
+	secrets         secrets.Service
+	store           AlertingStore
+	provenanceStore provisioning.ProvisioningStore
+	log             log.Logger
+	ac              accesscontrol.AccessControl
 }
 
 type UnknownReceiverError struct {
@@ -343,6 +347,18 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 		}
 	}
 
+	if query.Result != nil {
+		currentConfig, err := notifier.Load([]byte(query.Result.AlertmanagerConfiguration))
+		if err != nil {
+			srv.log.Warn("Last known alertmanager configuration was invalid. Overwriting...")
+		} else if err := verifyProvisionedConfigNotAffected(c.Req.Context(), srv.provenanceStore, c.OrgId, currentConfig, &body); err != nil {
+			if errors.Is(err, errProvisionedResource) {
+				return ErrResp(http.StatusConflict, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, "failed to check provisioned resources")
+		}
+	}
+
 	if err := srv.loadSecureSettings(c.Req.Context(), c.OrgId, body.AlertmanagerConfig.Receivers); err != nil {
 		var unknownReceiverError UnknownReceiverError
 		if errors.As(err, &unknownReceiverError) {
@@ -371,6 +387,88 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "configuration created"})
 }
 
+// verifyProvisionedConfigNotAffected returns errProvisionedResource if the new configuration changes or removes the notification
+// policies, contact points, templates or mute timings that were created by provisioning.
+func verifyProvisionedConfigNotAffected(ctx context.Context, provenanceStore provisioning.ProvisioningStore, orgID int64, current, updated *apimodels.PostableUserConfig) error {
+	provenances, err := provenanceStore.GetProvenances(ctx, orgID, (&apimodels.Route{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	if provenance := provenances[""]; provenance != ngmodels.ProvenanceNone && !equalAsJSON(current.AlertmanagerConfig.Route, updated.AlertmanagerConfig.Route) {
+		return fmt.Errorf("%w: notification policies are provisioned with provenance '%s'", errProvisionedResource, provenance)
+	}
+
+	provenances, err = provenanceStore.GetProvenances(ctx, orgID, (&apimodels.EmbeddedContactPoint{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	currentReceivers := current.GetGrafanaReceiverMap()
+	updatedReceivers := updated.GetGrafanaReceiverMap()
+	for uid, provenance := range provenances {
+		if provenance == ngmodels.ProvenanceNone {
+			continue
+		}
+		cur, ok := currentReceivers[uid]
+		if !ok {
+			continue
+		}
+		upd, ok := updatedReceivers[uid]
+		if !ok || cur.Name != upd.Name || cur.Type != upd.Type || cur.DisableResolveMessage != upd.DisableResolveMessage || !equalAsJSON(cur.Settings, upd.Settings) {
+			return fmt.Errorf("%w: contact point %s is provisioned with provenance '%s'", errProvisionedResource, uid, provenance)
+		}
+	}
+
+	provenances, err = provenanceStore.GetProvenances(ctx, orgID, (&apimodels.MessageTemplate{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	for name, provenance := range provenances {
+		if provenance == ngmodels.ProvenanceNone {
+			continue
+		}
+		cur, ok := current.TemplateFiles[name]
+		if !ok {
+			continue
+		}
+		if upd, ok := updated.TemplateFiles[name]; !ok || cur != upd {
+			return fmt.Errorf("%w: template %s is provisioned with provenance '%s'", errProvisionedResource, name, provenance)
+		}
+	}
+
+	provenances, err = provenanceStore.GetProvenances(ctx, orgID, (&apimodels.MuteTimeInterval{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	currentIntervals := make(map[string]interface{}, len(current.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range current.AlertmanagerConfig.MuteTimeIntervals {
+		currentIntervals[mt.Name] = mt
+	}
+	updatedIntervals := make(map[string]interface{}, len(updated.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range updated.AlertmanagerConfig.MuteTimeIntervals {
+		updatedIntervals[mt.Name] = mt
+	}
+	for name, provenance := range provenances {
+		if provenance == ngmodels.ProvenanceNone {
+			continue
+		}
+		cur, ok := currentIntervals[name]
+		if !ok {
+			continue
+		}
+		if upd, ok := updatedIntervals[name]; !ok || !equalAsJSON(cur, upd) {
+			return fmt.Errorf("%w: mute timing %s is provisioned with provenance '%s'", errProvisionedResource, name, provenance)
+		}
+	}
+	return nil
+}
+
+// equalAsJSON reports whether both values have the same JSON representation.
+func equalAsJSON(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
 func (srv AlertmanagerSrv) RoutePostAMAlerts(_ *models.ReqContext, _ apimodels.PostableAlerts) response.Response {
 	return NotImplementedResp
 }
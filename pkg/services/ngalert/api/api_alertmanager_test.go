@@ -208,6 +208,42 @@ func TestAlertmanagerConfig(t *testing.T) {
 
 		require.Equal(t, 202, response.Status())
 	})
+
+	t.Run("when notification policies are provisioned", func(t *testing.T) {
+		sut := createSut(t, nil)
+		provenanceStore := newFakeProvenanceStore()
+		provenanceStore.records[1] = map[string]map[string]ngmodels.Provenance{
+			(&apimodels.Route{}).ResourceType(): {"": ngmodels.ProvenanceAPI},
+		}
+		sut.provenanceStore = provenanceStore
+		rc := models.ReqContext{
+			Context: &web.Context{
+				Req: &http.Request{},
+			},
+			SignedInUser: &models.SignedInUser{
+				OrgId: 1,
+			},
+		}
+
+		t.Run("assert 202 when config does not change them", func(t *testing.T) {
+			request := createAmConfigRequest(t)
+
+			response := sut.RoutePostAlertingConfig(&rc, request)
+
+			require.Equal(t, 202, response.Status())
+		})
+
+		t.Run("assert 409 Conflict when config changes them", func(t *testing.T) {
+			request := createAmConfigRequest(t)
+			request.AlertmanagerConfig.Route.Routes = append(request.AlertmanagerConfig.Route.Routes, &apimodels.Route{
+				Receiver: request.AlertmanagerConfig.Route.Receiver,
+			})
+
+			response := sut.RoutePostAlertingConfig(&rc, request)
+
+			require.Equal(t, 409, response.Status())
+		})
+	})
 }
 
 func TestRouteCreateSilence(t *testing.T) {
@@ -357,7 +393,7 @@ func createSut(t *testing.T, accessControl accesscontrol.AccessControl) Alertman
 		accessControl = acMock.New().WithDisabled()
 	}
 	log := log.NewNopLogger()
-	return AlertmanagerSrv{mam: mam, store: &configStore, secrets: secrets, ac: accessControl, log: log}
+	return AlertmanagerSrv{mam: mam, store: &configStore, provenanceStore: newFakeProvenanceStore(), secrets: secrets, ac: accessControl, log: log}
 }
 
 func createAmConfigRequest(t *testing.T) apimodels.PostableUserConfig {
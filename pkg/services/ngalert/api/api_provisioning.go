@@ -3,15 +3,21 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -20,6 +26,13 @@ type ProvisioningSrv struct {
 	log                 log.Logger
 	policies            NotificationPolicyService
 	contactPointService ContactPointService
+	templates           TemplateService
+	muteTimings         MuteTimingService
+	alertRules          AlertRuleService
+	ruleStore           store.RuleStore
+	datasourceCache     datasources.CacheService
+	cfg                 *setting.UnifiedAlertingSettings
+	ac                  accesscontrol.AccessControl
 }
 
 type ContactPointService interface {
@@ -34,6 +47,30 @@ type NotificationPolicyService interface {
 	UpdatePolicyTree(ctx context.Context, orgID int64, tree apimodels.Route, p alerting_models.Provenance) error
 }
 
+type TemplateService interface {
+	GetTemplates(ctx context.Context, orgID int64) ([]apimodels.MessageTemplate, error)
+	GetTemplate(ctx context.Context, orgID int64, name string) (apimodels.MessageTemplate, error)
+	SetTemplate(ctx context.Context, orgID int64, tmpl apimodels.MessageTemplate, p alerting_models.Provenance) (apimodels.MessageTemplate, error)
+	DeleteTemplate(ctx context.Context, orgID int64, name string, p alerting_models.Provenance) error
+}
+
+type MuteTimingService interface {
+	GetMuteTimings(ctx context.Context, orgID int64) ([]apimodels.MuteTimeInterval, error)
+	GetMuteTiming(ctx context.Context, orgID int64, name string) (apimodels.MuteTimeInterval, error)
+	CreateMuteTiming(ctx context.Context, orgID int64, mt apimodels.MuteTimeInterval, p alerting_models.Provenance) (apimodels.MuteTimeInterval, error)
+	UpdateMuteTiming(ctx context.Context, orgID int64, mt apimodels.MuteTimeInterval, p alerting_models.Provenance) (apimodels.MuteTimeInterval, error)
+	DeleteMuteTiming(ctx context.Context, orgID int64, name string, p alerting_models.Provenance) error
+}
+
+type AlertRuleService interface {
+	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
+	CreateAlertRule(ctx context.Context, rule alerting_models.AlertRule, p alerting_models.Provenance) (alerting_models.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule alerting_models.AlertRule, p alerting_models.Provenance) (alerting_models.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, p alerting_models.Provenance) error
+	GetRuleGroup(ctx context.Context, orgID int64, folderUID, group string) (apimodels.AlertRuleGroup, error)
+	UpdateRuleGroup(ctx context.Context, orgID int64, folderUID, group string, intervalSeconds int64, p alerting_models.Provenance) error
+}
+
 func (srv *ProvisioningSrv) RouteGetPolicyTree(c *models.ReqContext) response.Response {
 	policies, err := srv.policies.GetPolicyTree(c.Req.Context(), c.OrgId)
 	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
@@ -92,3 +129,246 @@ func (srv *ProvisioningSrv) RouteDeleteContactPoint(c *models.ReqContext) respon
 	}
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint deleted"})
 }
+
+func (srv *ProvisioningSrv) RouteGetTemplates(c *models.ReqContext) response.Response {
+	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return provisioningErrResp(err, "failed to get templates")
+	}
+	return response.JSON(http.StatusOK, templates)
+}
+
+func (srv *ProvisioningSrv) RouteGetTemplate(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	template, err := srv.templates.GetTemplate(c.Req.Context(), c.OrgId, name)
+	if err != nil {
+		return provisioningErrResp(err, "failed to get template")
+	}
+	return response.JSON(http.StatusOK, template)
+}
+
+func (srv *ProvisioningSrv) RoutePutTemplate(c *models.ReqContext, body apimodels.MessageTemplateContent) response.Response {
+	tmpl := apimodels.MessageTemplate{
+		Name:     web.Params(c.Req)[":name"],
+		Template: body.Template,
+	}
+	modified, err := srv.templates.SetTemplate(c.Req.Context(), c.OrgId, tmpl, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err, "failed to save template")
+	}
+	return response.JSON(http.StatusAccepted, modified)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteTemplate(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	err := srv.templates.DeleteTemplate(c.Req.Context(), c.OrgId, name, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err, "failed to delete template")
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTimings(c *models.ReqContext) response.Response {
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return provisioningErrResp(err, "failed to get mute timings")
+	}
+	return response.JSON(http.StatusOK, timings)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTiming(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	timing, err := srv.muteTimings.GetMuteTiming(c.Req.Context(), c.OrgId, name)
+	if err != nil {
+		return provisioningErrResp(err, "failed to get mute timing")
+	}
+	return response.JSON(http.StatusOK, timing)
+}
+
+func (srv *ProvisioningSrv) RoutePostMuteTiming(c *models.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
+	created, err := srv.muteTimings.CreateMuteTiming(c.Req.Context(), c.OrgId, mt, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err, "failed to create mute timing")
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutMuteTiming(c *models.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
+	mt.Name = web.Params(c.Req)[":name"]
+	updated, err := srv.muteTimings.UpdateMuteTiming(c.Req.Context(), c.OrgId, mt, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err, "failed to update mute timing")
+	}
+	return response.JSON(http.StatusOK, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	err := srv.muteTimings.DeleteMuteTiming(c.Req.Context(), c.OrgId, name, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err, "failed to delete mute timing")
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRule(c *models.ReqContext) response.Response {
+	rule, provenance, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, web.Params(c.Req)[":UID"])
+	if err != nil {
+		return provisioningErrResp(err, "failed to get alert rule")
+	}
+	if errResp := srv.authorizeAlertRule(c, &rule, accesscontrol.ActionAlertingRuleRead); errResp != nil {
+		return errResp
+	}
+	return response.JSON(http.StatusOK, apimodels.NewAlertRule(rule, provenance))
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRule(c *models.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
+	upstreamModel := ar.UpstreamModel()
+	upstreamModel.OrgID = c.OrgId
+	if errResp := srv.validateAlertRule(c, ar, &upstreamModel, accesscontrol.ActionAlertingRuleCreate); errResp != nil {
+		return errResp
+	}
+	created, err := srv.alertRules.CreateAlertRule(c.Req.Context(), upstreamModel, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err, "failed to create alert rule")
+	}
+	return response.JSON(http.StatusCreated, apimodels.NewAlertRule(created, alerting_models.ProvenanceAPI))
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRule(c *models.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
+	upstreamModel := ar.UpstreamModel()
+	upstreamModel.OrgID = c.OrgId
+	upstreamModel.UID = web.Params(c.Req)[":UID"]
+	existing, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, upstreamModel.UID)
+	if err != nil {
+		return provisioningErrResp(err, "failed to update alert rule")
+	}
+	// moving the rule to another folder requires the permissions to delete rules from the current folder and to create rules in the other one.
+	existingAction, action := accesscontrol.ActionAlertingRuleUpdate, accesscontrol.ActionAlertingRuleUpdate
+	if upstreamModel.NamespaceUID != existing.NamespaceUID {
+		existingAction, action = accesscontrol.ActionAlertingRuleDelete, accesscontrol.ActionAlertingRuleCreate
+	}
+	if errResp := srv.authorizeAlertRule(c, &existing, existingAction); errResp != nil {
+		return errResp
+	}
+	ar.UID = upstreamModel.UID
+	if errResp := srv.validateAlertRule(c, ar, &upstreamModel, action); errResp != nil {
+		return errResp
+	}
+	updated, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), upstreamModel, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err, "failed to update alert rule")
+	}
+	return response.JSON(http.StatusOK, apimodels.NewAlertRule(updated, alerting_models.ProvenanceAPI))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":UID"]
+	existing, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, uid)
+	if err != nil {
+		return provisioningErrResp(err, "failed to delete alert rule")
+	}
+	if errResp := srv.authorizeAlertRule(c, &existing, accesscontrol.ActionAlertingRuleDelete); errResp != nil {
+		return errResp
+	}
+	err = srv.alertRules.DeleteAlertRule(c.Req.Context(), c.OrgId, uid, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err, "failed to delete alert rule")
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleGroup(c *models.ReqContext) response.Response {
+	folderUID := web.Params(c.Req)[":FolderUID"]
+	group := web.Params(c.Req)[":Group"]
+	if _, errResp := srv.getAuthorizedNamespace(c, folderUID, accesscontrol.ActionAlertingRuleRead); errResp != nil {
+		return errResp
+	}
+	ruleGroup, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.OrgId, folderUID, group)
+	if err != nil {
+		return provisioningErrResp(err, "failed to get rule group")
+	}
+	return response.JSON(http.StatusOK, ruleGroup)
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *models.ReqContext, ag apimodels.AlertRuleGroupMetadata) response.Response {
+	folderUID := web.Params(c.Req)[":FolderUID"]
+	group := web.Params(c.Req)[":Group"]
+	if _, errResp := srv.getAuthorizedNamespace(c, folderUID, accesscontrol.ActionAlertingRuleUpdate); errResp != nil {
+		return errResp
+	}
+	err := srv.alertRules.UpdateRuleGroup(c.Req.Context(), c.OrgId, folderUID, group, ag.Interval, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err, "failed to update rule group")
+	}
+	ruleGroup, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.OrgId, folderUID, group)
+	if err != nil {
+		return provisioningErrResp(err, "failed to get rule group")
+	}
+	return response.JSON(http.StatusOK, ruleGroup)
+}
+
+// validateAlertRule validates the submitted alert rule the same way as the ruler API does, and checks that the user
+// is allowed to perform the action on alert rules in the folder of the rule and to query the data sources the rule uses.
+func (srv *ProvisioningSrv) validateAlertRule(c *models.ReqContext, ar apimodels.ProvisionedAlertRule, rule *alerting_models.AlertRule, action string) response.Response {
+	namespace, errResp := srv.getAuthorizedNamespace(c, rule.NamespaceUID, action)
+	if errResp != nil {
+		return errResp
+	}
+	if err := validateProvisionedAlertRule(ar, c.OrgId, namespace, conditionValidator(c, srv.datasourceCache), srv.cfg); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid alert rule")
+	}
+	return srv.authorizeDatasourceAccess(c, rule)
+}
+
+// authorizeAlertRule checks that the user is allowed to perform the action on alert rules in the folder of the rule
+// and to query the data sources the rule uses.
+func (srv *ProvisioningSrv) authorizeAlertRule(c *models.ReqContext, rule *alerting_models.AlertRule, action string) response.Response {
+	if _, errResp := srv.getAuthorizedNamespace(c, rule.NamespaceUID, action); errResp != nil {
+		return errResp
+	}
+	return srv.authorizeDatasourceAccess(c, rule)
+}
+
+// getAuthorizedNamespace returns the folder with the given UID if the user is allowed to perform the action on alert rules in it.
+// Any action but reading requires the user to be able to save in the folder.
+func (srv *ProvisioningSrv) getAuthorizedNamespace(c *models.ReqContext, folderUID string, action string) (*models.Folder, response.Response) {
+	canSave := action != accesscontrol.ActionAlertingRuleRead
+	namespace, err := srv.ruleStore.GetNamespaceByUID(c.Req.Context(), folderUID, c.OrgId, c.SignedInUser, canSave)
+	if err != nil {
+		return nil, toNamespaceErrorResponse(err)
+	}
+	fallback := accesscontrol.ReqSignedIn
+	if canSave {
+		fallback = accesscontrol.ReqOrgAdminOrEditor
+	}
+	scope := dashboards.ScopeFoldersProvider.GetResourceScope(strconv.FormatInt(namespace.Id, 10))
+	if !accesscontrol.HasAccess(srv.ac, c)(fallback, accesscontrol.EvalPermission(action, scope)) {
+		return nil, ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to access alert rules in the folder %s", ErrAuthorization, namespace.Title), "")
+	}
+	return namespace, nil
+}
+
+// authorizeDatasourceAccess checks that the user is allowed to query all the data sources the rule uses.
+func (srv *ProvisioningSrv) authorizeDatasourceAccess(c *models.ReqContext, rule *alerting_models.AlertRule) response.Response {
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqSignedIn, evaluator)
+	}
+	if !authorizeDatasourceAccessForRule(rule, hasAccess) {
+		return ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to access the alert rule '%s' because the user does not have read permissions for one or many datasources the rule uses", ErrAuthorization, rule.Title), "")
+	}
+	return nil
+}
+
+// provisioningErrResp maps the errors of the provisioning services to responses with the matching status code.
+func provisioningErrResp(err error, msg string) response.Response {
+	switch {
+	case errors.Is(err, provisioning.ErrValidation), errors.Is(err, alerting_models.ErrAlertRuleFailedValidation):
+		return ErrResp(http.StatusBadRequest, err, msg)
+	case errors.Is(err, provisioning.ErrNotFound), errors.Is(err, alerting_models.ErrAlertRuleNotFound), errors.Is(err, store.ErrNoAlertmanagerConfiguration):
+		return ErrResp(http.StatusNotFound, err, msg)
+	case errors.Is(err, provisioning.ErrProvenanceMismatch), errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation):
+		return ErrResp(http.StatusConflict, err, msg)
+	}
+	return ErrResp(http.StatusInternalServerError, err, msg)
+}
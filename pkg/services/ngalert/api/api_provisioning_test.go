@@ -2,16 +2,23 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	domain "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/require"
 )
@@ -86,26 +93,286 @@ func TestProvisioningApi(t *testing.T) {
 			require.Contains(t, string(response.Body()), "something went wrong")
 		})
 	})
+
+	t.Run("message templates", func(t *testing.T) {
+		t.Run("successful PUT returns 202 with provenance", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":name": "a"})
+
+			response := sut.RoutePutTemplate(&rc, apimodels.MessageTemplateContent{Template: "content"})
+
+			require.Equal(t, 202, response.Status())
+			require.Contains(t, string(response.Body()), `"provenance":"api"`)
+		})
+
+		t.Run("successful DELETE returns 204", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+
+			response := sut.RouteDeleteTemplate(&rc)
+
+			require.Equal(t, 204, response.Status())
+		})
+
+		t.Run("GET of unknown template returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetTemplate(&rc)
+
+			require.Equal(t, 404, response.Status())
+		})
+	})
+
+	t.Run("mute timings", func(t *testing.T) {
+		t.Run("successful POST returns 201", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+			mt := apimodels.MuteTimeInterval{}
+			mt.Name = "weekends"
+
+			response := sut.RoutePostMuteTiming(&rc, mt)
+
+			require.Equal(t, 201, response.Status())
+		})
+
+		t.Run("PUT takes the name from the path", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":name": "weekends"})
+
+			response := sut.RoutePutMuteTiming(&rc, apimodels.MuteTimeInterval{})
+
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), `"name":"weekends"`)
+		})
+
+		t.Run("DELETE of a mute timing in use returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			sut.muteTimings = &fakeMuteTimingService{err: fmt.Errorf("%w: in use", provisioning.ErrValidation)}
+			rc := createTestRequestCtx()
+
+			response := sut.RouteDeleteMuteTiming(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
+	})
+
+	t.Run("alert rules", func(t *testing.T) {
+		t.Run("successful POST returns 201 with provenance", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRule(&rc, createTestProvisionedAlertRule())
+
+			require.Equal(t, 201, response.Status())
+			require.Contains(t, string(response.Body()), `"provenance":"api"`)
+		})
+
+		t.Run("POST to a missing folder returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+			rule := createTestProvisionedAlertRule()
+			rule.FolderUID = "missing"
+
+			response := sut.RoutePostAlertRule(&rc, rule)
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("POST with an invalid condition returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+			rule := createTestProvisionedAlertRule()
+			rule.Condition = "C"
+
+			response := sut.RoutePostAlertRule(&rc, rule)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("POST with an unknown data source returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			sut.datasourceCache = &datasources.FakeCacheService{}
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRule(&rc, createTestProvisionedAlertRule())
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("POST by a viewer returns 401", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+			rc.OrgRole = models.ROLE_VIEWER
+
+			response := sut.RoutePostAlertRule(&rc, createTestProvisionedAlertRule())
+
+			require.Equal(t, 401, response.Status())
+		})
+
+		t.Run("with access control enabled", func(t *testing.T) {
+			folderScope := dashboards.ScopeFoldersProvider.GetResourceScope("1")
+			datasourceScope := datasources.ScopeProvider.GetResourceScopeUID("datasource-uid")
+
+			t.Run("POST without permission to create rules in the folder returns 401", func(t *testing.T) {
+				sut := createProvisioningSrvSut()
+				sut.ac = acMock.New().WithPermissions([]*accesscontrol.Permission{
+					{Action: datasources.ActionQuery, Scope: datasourceScope},
+				})
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostAlertRule(&rc, createTestProvisionedAlertRule())
+
+				require.Equal(t, 401, response.Status())
+			})
+
+			t.Run("POST without permission to query the data source returns 401", func(t *testing.T) {
+				sut := createProvisioningSrvSut()
+				sut.ac = acMock.New().WithPermissions([]*accesscontrol.Permission{
+					{Action: accesscontrol.ActionAlertingRuleCreate, Scope: folderScope},
+				})
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostAlertRule(&rc, createTestProvisionedAlertRule())
+
+				require.Equal(t, 401, response.Status())
+			})
+
+			t.Run("GET without permission to read rules in the folder returns 401", func(t *testing.T) {
+				sut := createProvisioningSrvSut()
+				sut.ac = acMock.New()
+				rc := createTestRequestCtx()
+
+				require.Equal(t, 401, sut.RouteGetAlertRule(&rc).Status())
+				require.Equal(t, 401, sut.RouteGetAlertRuleGroup(&rc).Status())
+			})
+
+			t.Run("DELETE with permission to delete rules in the folder returns 204", func(t *testing.T) {
+				sut := createProvisioningSrvSut()
+				sut.ac = acMock.New().WithPermissions([]*accesscontrol.Permission{
+					{Action: accesscontrol.ActionAlertingRuleDelete, Scope: folderScope},
+				})
+				rc := createTestRequestCtx()
+
+				require.Equal(t, 204, sut.RouteDeleteAlertRule(&rc).Status())
+			})
+		})
+
+		t.Run("PUT takes the UID from the path", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":UID": "rule-uid"})
+			rule := createTestProvisionedAlertRule()
+			rule.UID = "other-uid"
+
+			response := sut.RoutePutAlertRule(&rc, rule)
+
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), `"uid":"rule-uid"`)
+		})
+
+		t.Run("successful DELETE returns 204", func(t *testing.T) {
+			sut := createProvisioningSrvSut()
+			rc := createTestRequestCtx()
+
+			response := sut.RouteDeleteAlertRule(&rc)
+
+			require.Equal(t, 204, response.Status())
+		})
+
+		testCases := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "validation error", err: fmt.Errorf("%w: bad rule", provisioning.ErrValidation), expectedStatus: 400},
+			{name: "rule not found", err: domain.ErrAlertRuleNotFound, expectedStatus: 404},
+			{name: "group not found", err: fmt.Errorf("%w: group", provisioning.ErrNotFound), expectedStatus: 404},
+			{name: "provenance mismatch", err: fmt.Errorf("%w: file", provisioning.ErrProvenanceMismatch), expectedStatus: 409},
+			{name: "unexpected error", err: fmt.Errorf("something went wrong"), expectedStatus: 500},
+		}
+		for _, tc := range testCases {
+			t.Run(fmt.Sprintf("%s returns %d", tc.name, tc.expectedStatus), func(t *testing.T) {
+				sut := createProvisioningSrvSut()
+				sut.alertRules = &fakeAlertRuleService{err: tc.err}
+				rc := createTestRequestCtx()
+
+				require.Equal(t, tc.expectedStatus, sut.RouteGetAlertRule(&rc).Status())
+				require.Equal(t, tc.expectedStatus, sut.RoutePostAlertRule(&rc, createTestProvisionedAlertRule()).Status())
+				require.Equal(t, tc.expectedStatus, sut.RoutePutAlertRule(&rc, createTestProvisionedAlertRule()).Status())
+				require.Equal(t, tc.expectedStatus, sut.RouteDeleteAlertRule(&rc).Status())
+				require.Equal(t, tc.expectedStatus, sut.RouteGetAlertRuleGroup(&rc).Status())
+				require.Equal(t, tc.expectedStatus, sut.RoutePutAlertRuleGroup(&rc, apimodels.AlertRuleGroupMetadata{Interval: 60}).Status())
+			})
+		}
+	})
 }
 
+const testFolderUID = "folder-uid"
+
 func createProvisioningSrvSut() ProvisioningSrv {
+	ruleStore := store.NewFakeRuleStore(nil)
+	ruleStore.Folders[1] = append(ruleStore.Folders[1], &models.Folder{Id: 1, Uid: testFolderUID, Title: "folder"})
 	return ProvisioningSrv{
-		log:      log.NewNopLogger(),
-		policies: newFakeNotificationPolicyService(),
+		log:         log.NewNopLogger(),
+		policies:    newFakeNotificationPolicyService(),
+		templates:   &fakeTemplateService{templates: map[string]string{}},
+		muteTimings: &fakeMuteTimingService{},
+		alertRules:  &fakeAlertRuleService{},
+		ruleStore:   ruleStore,
+		datasourceCache: &datasources.FakeCacheService{
+			DataSources: []*models.DataSource{{Uid: "datasource-uid"}},
+		},
+		cfg: &setting.UnifiedAlertingSettings{
+			BaseInterval:                  setting.SchedulerBaseInterval,
+			DefaultRuleEvaluationInterval: setting.DefaultRuleEvaluationInterval,
+		},
+		ac: acMock.New().WithDisabled(),
 	}
 }
 
 func createTestRequestCtx() models.ReqContext {
+	ctx := &web.Context{
+		Req: &http.Request{},
+	}
+	ctx.Req = web.SetURLParams(ctx.Req, map[string]string{":UID": "rule-uid", ":FolderUID": testFolderUID, ":Group": "group"})
 	return models.ReqContext{
-		Context: &web.Context{
-			Req: &http.Request{},
-		},
+		Context:    ctx,
+		IsSignedIn: true,
 		SignedInUser: &models.SignedInUser{
-			OrgId: 1,
+			OrgId:   1,
+			OrgRole: models.ROLE_EDITOR,
 		},
 	}
 }
 
+// createTestProvisionedAlertRule returns a valid rule that queries a data source and has an expression as condition.
+func createTestProvisionedAlertRule() apimodels.ProvisionedAlertRule {
+	return apimodels.ProvisionedAlertRule{
+		FolderUID: testFolderUID,
+		RuleGroup: "group",
+		Title:     "rule",
+		Condition: "B",
+		Data: []domain.AlertQuery{
+			{
+				RefID:             "A",
+				DatasourceUID:     "datasource-uid",
+				RelativeTimeRange: domain.RelativeTimeRange{From: domain.Duration(time.Hour)},
+				Model:             json.RawMessage(`{"refId":"A"}`),
+			},
+			{
+				RefID:         "B",
+				DatasourceUID: "-100",
+				Model:         json.RawMessage(`{"refId":"B","type":"math","expression":"$A > 0"}`),
+			},
+		},
+		NoDataState:  domain.NoData,
+		ExecErrState: domain.AlertingErrState,
+	}
+}
+
 type fakeNotificationPolicyService struct {
 	tree apimodels.Route
 	prov domain.Provenance
@@ -148,3 +415,88 @@ func (f *fakeFailingNotificationPolicyService) GetPolicyTree(ctx context.Context
 func (f *fakeFailingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree apimodels.Route, p domain.Provenance) error {
 	return fmt.Errorf("something went wrong")
 }
+
+type fakeTemplateService struct {
+	templates map[string]string
+}
+
+func (f *fakeTemplateService) GetTemplates(ctx context.Context, orgID int64) ([]apimodels.MessageTemplate, error) {
+	result := make([]apimodels.MessageTemplate, 0, len(f.templates))
+	for name, content := range f.templates {
+		result = append(result, apimodels.MessageTemplate{Name: name, Template: content})
+	}
+	return result, nil
+}
+
+func (f *fakeTemplateService) GetTemplate(ctx context.Context, orgID int64, name string) (apimodels.MessageTemplate, error) {
+	content, ok := f.templates[name]
+	if !ok {
+		return apimodels.MessageTemplate{}, provisioning.ErrNotFound
+	}
+	return apimodels.MessageTemplate{Name: name, Template: content}, nil
+}
+
+func (f *fakeTemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl apimodels.MessageTemplate, p domain.Provenance) (apimodels.MessageTemplate, error) {
+	f.templates[tmpl.Name] = tmpl.Template
+	tmpl.Provenance = p
+	return tmpl, nil
+}
+
+func (f *fakeTemplateService) DeleteTemplate(ctx context.Context, orgID int64, name string, p domain.Provenance) error {
+	delete(f.templates, name)
+	return nil
+}
+
+type fakeMuteTimingService struct {
+	err error
+}
+
+func (f *fakeMuteTimingService) GetMuteTimings(ctx context.Context, orgID int64) ([]apimodels.MuteTimeInterval, error) {
+	return nil, f.err
+}
+
+func (f *fakeMuteTimingService) GetMuteTiming(ctx context.Context, orgID int64, name string) (apimodels.MuteTimeInterval, error) {
+	return apimodels.MuteTimeInterval{}, f.err
+}
+
+func (f *fakeMuteTimingService) CreateMuteTiming(ctx context.Context, orgID int64, mt apimodels.MuteTimeInterval, p domain.Provenance) (apimodels.MuteTimeInterval, error) {
+	mt.Provenance = p
+	return mt, f.err
+}
+
+func (f *fakeMuteTimingService) UpdateMuteTiming(ctx context.Context, orgID int64, mt apimodels.MuteTimeInterval, p domain.Provenance) (apimodels.MuteTimeInterval, error) {
+	mt.Provenance = p
+	return mt, f.err
+}
+
+func (f *fakeMuteTimingService) DeleteMuteTiming(ctx context.Context, orgID int64, name string, p domain.Provenance) error {
+	return f.err
+}
+
+type fakeAlertRuleService struct {
+	err error
+}
+
+func (f *fakeAlertRuleService) GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (domain.AlertRule, domain.Provenance, error) {
+	return domain.AlertRule{OrgID: orgID, UID: ruleUID, NamespaceUID: testFolderUID}, domain.ProvenanceNone, f.err
+}
+
+func (f *fakeAlertRuleService) CreateAlertRule(ctx context.Context, rule domain.AlertRule, p domain.Provenance) (domain.AlertRule, error) {
+	return rule, f.err
+}
+
+func (f *fakeAlertRuleService) UpdateAlertRule(ctx context.Context, rule domain.AlertRule, p domain.Provenance) (domain.AlertRule, error) {
+	return rule, f.err
+}
+
+func (f *fakeAlertRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, p domain.Provenance) error {
+	return f.err
+}
+
+func (f *fakeAlertRuleService) GetRuleGroup(ctx context.Context, orgID int64, folderUID, group string) (apimodels.AlertRuleGroup, error) {
+	return apimodels.AlertRuleGroup{Title: group, FolderUID: folderUID}, f.err
+}
+
+func (f *fakeAlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, folderUID, group string, intervalSeconds int64, p domain.Provenance) error {
+	return f.err
+}
@@ -31,6 +31,8 @@ import (
 type RulerSrv struct {
 	xactManager     provisioning.TransactionManager
 	store           store.RuleStore
+	historyStore    store.StateHistoryStore
+	provenanceStore provisioning.ProvisioningStore
 	DatasourceCache datasources.CacheService
 	QuotaService    *quota.QuotaService
 	scheduleService schedule.ScheduleService
@@ -40,7 +42,8 @@ type RulerSrv struct {
 }
 
 var (
-	errQuotaReached = errors.New("quota has been exceeded")
+	errQuotaReached        = errors.New("quota has been exceeded")
+	errProvisionedResource = errors.New("request affects resources created via provisioning API")
 )
 
 // RouteDeleteAlertRules deletes all alert rules user is authorized to access in the namespace (request parameter :Namespace)
@@ -99,6 +102,10 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *models.ReqContext) response.Respons
 			logger.Info("user cannot delete one or many alert rules because it does not have access to data sources. Those rules will be skipped", "expected", len(q.Result), "authorized", len(canDelete), "unauthorized", cannotDelete)
 		}
 
+		if err := verifyProvisionedRulesNotAffected(ctx, srv.provenanceStore, c.SignedInUser.OrgId, canDelete...); err != nil {
+			return err
+		}
+
 		return srv.store.DeleteAlertRulesByUID(ctx, c.SignedInUser.OrgId, canDelete...)
 	})
 
@@ -106,6 +113,9 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *models.ReqContext) response.Respons
 		if errors.Is(err, ErrAuthorization) {
 			return ErrResp(http.StatusUnauthorized, err, "")
 		}
+		if errors.Is(err, errProvisionedResource) {
+			return ErrResp(http.StatusConflict, err, "failed to delete rule group")
+		}
 		return ErrResp(http.StatusInternalServerError, err, "failed to delete rule group")
 	}
 
@@ -352,6 +362,17 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, namespace *mod
 			logger.Info("user is not authorized to delete one or many rules in the group. those rules will be skipped", "expected", len(groupChanges.Delete), "authorized", len(authorizedChanges.Delete))
 		}
 
+		affected := make([]string, 0, len(authorizedChanges.Update)+len(authorizedChanges.Delete))
+		for _, update := range authorizedChanges.Update {
+			affected = append(affected, update.Existing.UID)
+		}
+		for _, rule := range authorizedChanges.Delete {
+			affected = append(affected, rule.UID)
+		}
+		if err := verifyProvisionedRulesNotAffected(tranCtx, srv.provenanceStore, c.SignedInUser.OrgId, affected...); err != nil {
+			return err
+		}
+
 		logger.Debug("updating database with the authorized changes", "add", len(authorizedChanges.New), "update", len(authorizedChanges.New), "delete", len(authorizedChanges.Delete))
 
 		if len(authorizedChanges.Update) > 0 || len(authorizedChanges.New) > 0 {
@@ -410,6 +431,8 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, namespace *mod
 			return ErrResp(http.StatusForbidden, err, "")
 		} else if errors.Is(err, ErrAuthorization) {
 			return ErrResp(http.StatusUnauthorized, err, "")
+		} else if errors.Is(err, errProvisionedResource) {
+			return ErrResp(http.StatusConflict, err, "failed to update rule group")
 		}
 		return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 	}
@@ -435,6 +458,24 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, namespace *mod
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
 }
 
+// verifyProvisionedRulesNotAffected returns errProvisionedResource if any of the rules was created by provisioning.
+// Provisioned rules can only be changed by the provisioning mechanism they were created with.
+func verifyProvisionedRulesNotAffected(ctx context.Context, provenanceStore provisioning.ProvisioningStore, orgID int64, ruleUIDs ...string) error {
+	if len(ruleUIDs) == 0 {
+		return nil
+	}
+	provenances, err := provenanceStore.GetProvenances(ctx, orgID, (&ngmodels.AlertRule{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	for _, uid := range ruleUIDs {
+		if provenance, ok := provenances[uid]; ok && provenance != ngmodels.ProvenanceNone {
+			return fmt.Errorf("%w: alert rule %s is provisioned with provenance '%s'", errProvisionedResource, uid, provenance)
+		}
+	}
+	return nil
+}
+
 func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64) apimodels.GettableExtendedRuleNode {
 	gettableExtendedRuleNode := apimodels.GettableExtendedRuleNode{
 		GrafanaManagedAlert: &apimodels.GettableGrafanaRule{
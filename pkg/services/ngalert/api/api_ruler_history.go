@@ -0,0 +1,145 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// defaultStateHistoryLimit is the maximum number of state changes returned when the request has no limit.
+const defaultStateHistoryLimit = 1000
+
+// RouteGetRuleStateHistory returns the state changes of the alert instances of the rule (request parameter :RuleUID) as data frames, one frame per alert instance.
+// The history can be limited by the query parameters "from" and "to" (epoch milliseconds), "filter" (label matchers that the alert instances must match) and "limit",
+// which defaults to defaultStateHistoryLimit.
+func (srv RulerSrv) RouteGetRuleStateHistory(c *models.ReqContext) response.Response {
+	rule, _, errResp := srv.getAuthorizedRule(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	q := ngmodels.ListAlertStateHistoryQuery{
+		OrgID:   c.SignedInUser.OrgId,
+		RuleUID: rule.UID,
+		Limit:   defaultStateHistoryLimit,
+	}
+
+	var err error
+	if q.From, err = getTimeFromQuery(c, "from"); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if q.To, err = getTimeFromQuery(c, "to"); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.From.After(q.To) {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid time range: from %s is after to %s", q.From, q.To), "")
+	}
+
+	for _, filter := range c.QueryStrings("filter") {
+		matcher, err := labels.ParseMatcher(filter)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid filter %q: %w", filter, err), "")
+		}
+		q.Matchers = append(q.Matchers, matcher)
+	}
+
+	if s := c.Query("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid limit %q", s), "")
+		}
+		q.Limit = limit
+	}
+
+	if err := srv.historyStore.ListAlertStateHistory(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert state history")
+	}
+
+	return response.JSON(http.StatusOK, toStateHistoryFrames(q.Result))
+}
+
+// getTimeFromQuery returns the time in the query parameter given in epoch milliseconds or zero time if the parameter is not set.
+func getTimeFromQuery(c *models.ReqContext, param string) (time.Time, error) {
+	s := c.Query(param)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || ms < 0 {
+		return time.Time{}, fmt.Errorf("invalid %s time %q, it should be epoch milliseconds", param, s)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// toStateHistoryFrames converts the history, ordered by time, into one frame per alert instance.
+// Each frame has the fields Time, State, Previous state and Error, and a field per RefID with the values at the time of the evaluation.
+// All fields but Time have the labels of the alert instance.
+func toStateHistoryFrames(entries []*ngmodels.AlertStateHistoryEntry) apimodels.RuleStateHistoryResponse {
+	type instance struct {
+		labels  data.Labels
+		entries []*ngmodels.AlertStateHistoryEntry
+	}
+	var keys []string
+	instances := make(map[string]*instance)
+	for _, entry := range entries {
+		key := data.Labels(entry.Labels).String()
+		i, ok := instances[key]
+		if !ok {
+			i = &instance{labels: data.Labels(entry.Labels)}
+			instances[key] = i
+			keys = append(keys, key)
+		}
+		i.entries = append(i.entries, entry)
+	}
+	sort.Strings(keys)
+
+	frames := make(apimodels.RuleStateHistoryResponse, 0, len(keys))
+	for _, key := range keys {
+		i := instances[key]
+
+		var refIDs []string
+		seen := make(map[string]struct{})
+		for _, entry := range i.entries {
+			for refID := range entry.Values {
+				if _, ok := seen[refID]; !ok {
+					seen[refID] = struct{}{}
+					refIDs = append(refIDs, refID)
+				}
+			}
+		}
+		sort.Strings(refIDs)
+
+		timeField := data.NewField("Time", nil, make([]time.Time, 0, len(i.entries)))
+		stateField := data.NewField("State", i.labels, make([]string, 0, len(i.entries)))
+		previousStateField := data.NewField("Previous state", i.labels, make([]string, 0, len(i.entries)))
+		errorField := data.NewField("Error", i.labels, make([]string, 0, len(i.entries)))
+		valueFields := make([]*data.Field, 0, len(refIDs))
+		for _, refID := range refIDs {
+			valueFields = append(valueFields, data.NewField(refID, i.labels, make([]*float64, 0, len(i.entries))))
+		}
+
+		for _, entry := range i.entries {
+			timeField.Append(entry.EvaluatedAt)
+			stateField.Append(string(entry.CurrentState))
+			previousStateField.Append(string(entry.PreviousState))
+			errorField.Append(entry.Error)
+			for idx, refID := range refIDs {
+				valueFields[idx].Append(entry.Values[refID])
+			}
+		}
+
+		fields := append([]*data.Field{timeField, stateField, previousStateField, errorField}, valueFields...)
+		frames = append(frames, data.NewFrame(key, fields...))
+	}
+	return frames
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	models2 "github.com/grafana/grafana/pkg/models"
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestRouteGetRuleStateHistory(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := store.NewFakeRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+	ruleStore.PutRule(context.Background(), rule)
+
+	value := 42.0
+	start := time.Unix(1000, 0)
+	historyStore := &store.FakeStateHistoryStore{}
+	require.NoError(t, historyStore.SaveAlertStateHistory(context.Background(),
+		&models.AlertStateHistoryEntry{
+			RuleOrgID:     orgID,
+			RuleUID:       rule.UID,
+			Labels:        models.InstanceLabels{"severity": "critical"},
+			PreviousState: models.InstanceStateNormal,
+			CurrentState:  models.InstanceStatePending,
+			Values:        map[string]*float64{"B": &value},
+			EvaluatedAt:   start,
+		},
+		&models.AlertStateHistoryEntry{
+			RuleOrgID:     orgID,
+			RuleUID:       rule.UID,
+			Labels:        models.InstanceLabels{"severity": "critical"},
+			PreviousState: models.InstanceStatePending,
+			CurrentState:  models.InstanceStateFiring,
+			Values:        map[string]*float64{"B": &value},
+			EvaluatedAt:   start.Add(time.Minute),
+		},
+		&models.AlertStateHistoryEntry{
+			RuleOrgID:     orgID,
+			RuleUID:       rule.UID,
+			Labels:        models.InstanceLabels{"severity": "warning"},
+			PreviousState: models.InstanceStateNormal,
+			CurrentState:  models.InstanceStateError,
+			Error:         "failed to execute query",
+			EvaluatedAt:   start.Add(2 * time.Minute),
+		},
+	))
+
+	request := func(query string) *models2.ReqContext {
+		c := createRequestContext(orgID, models2.ROLE_VIEWER, map[string]string{
+			":RuleUID": rule.UID,
+		})
+		c.Req.URL = &url.URL{RawQuery: query}
+		return c
+	}
+
+	getFrames := func(t *testing.T, query string) data.Frames {
+		t.Helper()
+		srv := createService(acMock.New().WithDisabled(), ruleStore, nil)
+		srv.historyStore = historyStore
+		response := srv.RouteGetRuleStateHistory(request(query))
+		require.Equalf(t, http.StatusOK, response.Status(), "unexpected response: %s", string(response.Body()))
+		var frames data.Frames
+		require.NoError(t, json.Unmarshal(response.Body(), &frames))
+		return frames
+	}
+
+	t.Run("should return a frame per alert instance", func(t *testing.T) {
+		frames := getFrames(t, "")
+		require.Len(t, frames, 2)
+
+		critical := frames[0]
+		require.Equal(t, 2, critical.Rows())
+		require.Equal(t, data.Labels{"severity": "critical"}, critical.Fields[1].Labels)
+		require.Equal(t, "Pending", critical.Fields[1].At(0))
+		require.Equal(t, "Alerting", critical.Fields[1].At(1))
+		require.Equal(t, "Normal", critical.Fields[2].At(0))
+		require.Equal(t, "B", critical.Fields[4].Name)
+		require.Equal(t, value, *critical.Fields[4].At(0).(*float64))
+
+		warning := frames[1]
+		require.Equal(t, 1, warning.Rows())
+		require.Equal(t, "Error", warning.Fields[1].At(0))
+		require.Equal(t, "failed to execute query", warning.Fields[3].At(0))
+	})
+
+	t.Run("should filter by labels", func(t *testing.T) {
+		frames := getFrames(t, url.Values{"filter": []string{`severity="warning"`}}.Encode())
+		require.Len(t, frames, 1)
+		require.Equal(t, data.Labels{"severity": "warning"}, frames[0].Fields[1].Labels)
+	})
+
+	t.Run("should filter by time range", func(t *testing.T) {
+		frames := getFrames(t, url.Values{
+			"from": []string{"1030000"},
+			"to":   []string{"1090000"},
+		}.Encode())
+		require.Len(t, frames, 1)
+		require.Equal(t, 1, frames[0].Rows())
+		require.Equal(t, "Alerting", frames[0].Fields[1].At(0))
+	})
+
+	t.Run("should return the most recent changes if limited", func(t *testing.T) {
+		frames := getFrames(t, "limit=1")
+		require.Len(t, frames, 1)
+		require.Equal(t, data.Labels{"severity": "warning"}, frames[0].Fields[1].Labels)
+	})
+
+	t.Run("should return 400 if parameters are invalid", func(t *testing.T) {
+		srv := createService(acMock.New().WithDisabled(), ruleStore, nil)
+		srv.historyStore = historyStore
+		for _, query := range []string{"from=yesterday", "to=-1", "from=2000&to=1000", "filter=severity", "limit=all", "limit=0"} {
+			response := srv.RouteGetRuleStateHistory(request(query))
+			require.Equalf(t, http.StatusBadRequest, response.Status(), "query %s", query)
+		}
+	})
+}
@@ -474,6 +474,32 @@ func TestRouteDeleteAlertRules(t *testing.T) {
 			})
 		})
 	})
+	t.Run("when rules in folder are provisioned", func(t *testing.T) {
+		t.Run("should not delete any rule", func(t *testing.T) {
+			ruleStore := store.NewFakeRuleStore(t)
+			orgID := rand.Int63()
+			folder := randFolder()
+			ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+			rulesInFolder := models.GenerateAlertRules(rand.Intn(4)+2, models.AlertRuleGen(withOrgID(orgID), withNamespace(folder)))
+			ruleStore.PutRule(context.Background(), rulesInFolder...)
+
+			scheduler := &schedule.FakeScheduleService{}
+			scheduler.On("DeleteAlertRule", mock.Anything).Panic("should not be called")
+
+			ac := acMock.New().WithDisabled()
+			request := createRequestContext(orgID, models2.ROLE_EDITOR, map[string]string{
+				":Namespace": folder.Title,
+			})
+			svc := createService(ac, ruleStore, scheduler)
+			require.NoError(t, svc.provenanceStore.SetProvenance(context.Background(), rulesInFolder[0], models.ProvenanceAPI))
+
+			response := svc.RouteDeleteAlertRules(request)
+			require.Equalf(t, 409, response.Status(), "Expected 409 but got %d: %v", response.Status(), string(response.Body()))
+
+			scheduler.AssertNotCalled(t, "DeleteAlertRule")
+			require.Empty(t, getRecordedCommand(ruleStore))
+		})
+	})
 }
 
 func TestRouteGetNamespaceRulesConfig(t *testing.T) {
@@ -556,6 +582,7 @@ func createService(ac *acMock.Mock, store *store.FakeRuleStore, scheduler schedu
 	return &RulerSrv{
 		xactManager:     store,
 		store:           store,
+		provenanceStore: newFakeProvenanceStore(),
 		DatasourceCache: nil,
 		QuotaService:    nil,
 		scheduleService: scheduler,
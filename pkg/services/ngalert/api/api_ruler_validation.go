@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/prometheus/common/model"
+
 	"github.com/grafana/grafana/pkg/models"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -181,3 +183,61 @@ func validateRuleGroup(
 	}
 	return result, nil
 }
+
+// validateRuleVersion converts a version of the rule to API model (definitions.PostableExtendedRuleNode) and validates it the same way as a submitted rule.
+// The version is restored in the current folder and group of the rule, and uses the current evaluation interval of the group.
+func validateRuleVersion(
+	version *ngmodels.AlertRuleVersion,
+	current *ngmodels.AlertRule,
+	namespace *models.Folder,
+	conditionValidator func(ngmodels.Condition) error,
+	cfg *setting.UnifiedAlertingSettings) (*ngmodels.AlertRule, error) {
+	ruleNode := apimodels.PostableExtendedRuleNode{
+		ApiRuleNode: &apimodels.ApiRuleNode{
+			For:         model.Duration(version.For),
+			Annotations: version.Annotations,
+			Labels:      version.Labels,
+		},
+		GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+			Title:        version.Title,
+			Condition:    version.Condition,
+			Data:         version.Data,
+			UID:          current.UID,
+			NoDataState:  apimodels.NoDataState(version.NoDataState),
+			ExecErrState: apimodels.ExecutionErrorState(version.ExecErrState),
+		},
+	}
+	interval := time.Duration(current.IntervalSeconds) * time.Second
+	rule, err := validateRuleNode(&ruleNode, current.RuleGroup, interval, current.OrgID, namespace, conditionValidator, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("version %d of the rule is not valid: %w", version.Version, err)
+	}
+	return rule, nil
+}
+
+// validateProvisionedAlertRule converts the provisioned alert rule to API model (definitions.PostableExtendedRuleNode) and validates it the same way as a submitted rule.
+// The rule is validated with the default evaluation interval because the provisioning service sets the interval of the rule group.
+func validateProvisionedAlertRule(
+	ar apimodels.ProvisionedAlertRule,
+	orgID int64,
+	namespace *models.Folder,
+	conditionValidator func(ngmodels.Condition) error,
+	cfg *setting.UnifiedAlertingSettings) error {
+	ruleNode := apimodels.PostableExtendedRuleNode{
+		ApiRuleNode: &apimodels.ApiRuleNode{
+			For:         ar.For,
+			Annotations: ar.Annotations,
+			Labels:      ar.Labels,
+		},
+		GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+			Title:        ar.Title,
+			Condition:    ar.Condition,
+			Data:         ar.Data,
+			UID:          ar.UID,
+			NoDataState:  apimodels.NoDataState(ar.NoDataState),
+			ExecErrState: apimodels.ExecutionErrorState(ar.ExecErrState),
+		},
+	}
+	_, err := validateRuleNode(&ruleNode, ar.RuleGroup, cfg.DefaultRuleEvaluationInterval, orgID, namespace, conditionValidator, cfg)
+	return err
+}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/cmputil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// RouteGetRuleVersions returns the versions of the rule (request parameter :RuleUID), newest first.
+// Versions that use data sources the user cannot query are skipped.
+func (srv RulerSrv) RouteGetRuleVersions(c *models.ReqContext) response.Response {
+	rule, namespace, errResp := srv.getAuthorizedRule(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	q := ngmodels.ListAlertRuleVersionsQuery{
+		OrgID:   c.SignedInUser.OrgId,
+		RuleUID: rule.UID,
+	}
+	if err := srv.store.ListAlertRuleVersions(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule versions")
+	}
+
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqSignedIn, evaluator)
+	}
+
+	result := make(apimodels.RuleVersionsResponse, 0, len(q.Result))
+	for _, v := range q.Result {
+		r := v.ToAlertRule()
+		if !authorizeDatasourceAccessForRule(r, hasAccess) {
+			continue
+		}
+		r.ID = rule.ID
+		result = append(result, apimodels.GettableRuleVersion{
+			Version:       v.Version,
+			ParentVersion: v.ParentVersion,
+			RestoredFrom:  v.RestoredFrom,
+			Created:       v.Created,
+			Rule:          toGettableExtendedRuleNode(*r, namespace.Id),
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteGetRuleVersionsDiff returns the fields that are different between two versions of the rule (request parameter :RuleUID).
+// The versions are given by the query parameters "from" and "to". If "to" is not set, the current version of the rule is used.
+// If "from" is not set, the version that "to" was created from is used.
+func (srv RulerSrv) RouteGetRuleVersionsDiff(c *models.ReqContext) response.Response {
+	rule, _, errResp := srv.getAuthorizedRule(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	toVersion, err := getVersionFromQuery(c, "to", rule.Version)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	to, errResp := srv.getAuthorizedRuleVersion(c, rule.UID, toVersion)
+	if errResp != nil {
+		return errResp
+	}
+
+	fromVersion, err := getVersionFromQuery(c, "from", to.ParentVersion)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if fromVersion == 0 {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("version %d is the first version of the rule, there is nothing to compare it with", toVersion), "")
+	}
+	from, errResp := srv.getAuthorizedRuleVersion(c, rule.UID, fromVersion)
+	if errResp != nil {
+		return errResp
+	}
+
+	diff := from.ToAlertRule().Diff(to.ToAlertRule(), alertRuleFieldsToIgnoreInDiff...)
+	return response.JSON(http.StatusOK, toRuleVersionDiff(fromVersion, toVersion, diff))
+}
+
+// RoutePostRuleVersionRestore restores the rule (request parameter :RuleUID) to the version given by the request parameter :Version.
+// The restored rule is validated like a rule submitted to the ruler API and saved as a new version of the rule.
+// Only the definition of the rule is restored: it stays in its current folder and group, and keeps the evaluation interval of the group.
+func (srv RulerSrv) RoutePostRuleVersionRestore(c *models.ReqContext) response.Response {
+	version, err := strconv.ParseInt(web.Params(c.Req)[":Version"], 10, 64)
+	if err != nil || version <= 0 {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid version %q", web.Params(c.Req)[":Version"]), "")
+	}
+
+	rule, namespace, errResp := srv.getAuthorizedRule(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	// if access control is disabled, this checks that the user is allowed to save in the folder
+	namespace, err = srv.store.GetNamespaceByTitle(c.Req.Context(), namespace.Title, c.SignedInUser.OrgId, c.SignedInUser, true)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	v, errResp := srv.getAuthorizedRuleVersion(c, rule.UID, version)
+	if errResp != nil {
+		return errResp
+	}
+
+	restored, err := validateRuleVersion(v, rule, namespace, conditionValidator(c, srv.DatasourceCache), srv.cfg)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	return srv.restoreAlertRule(c, namespace, rule, restored, version)
+}
+
+// restoreAlertRule verifies that the user is authorized to replace the existing rule by the restored one and updates the database.
+func (srv RulerSrv) restoreAlertRule(c *models.ReqContext, namespace *models.Folder, existing *ngmodels.AlertRule, restored *ngmodels.AlertRule, restoredFrom int64) response.Response {
+	logger := srv.log.New("namespace_uid", namespace.Uid, "rule_uid", existing.UID, "org_id", c.OrgId, "user_id", c.UserId, "restored_from", restoredFrom)
+
+	diff := existing.Diff(restored, alertRuleFieldsToIgnoreInDiff...)
+	if len(diff) == 0 {
+		logger.Info("restored version is the same as the current version of the rule. Do nothing")
+		return response.JSON(http.StatusAccepted, util.DynMap{"message": "no changes detected in the rule"})
+	}
+
+	hasAccess := accesscontrol.HasAccess(srv.ac, c)
+	ruleChanges := &changes{
+		Update: []ruleUpdate{{Existing: existing, New: restored, Diff: diff}},
+	}
+	if _, err := authorizeRuleChanges(namespace, ruleChanges, func(evaluator accesscontrol.Evaluator) bool {
+		return hasAccess(accesscontrol.ReqOrgAdminOrEditor, evaluator)
+	}); err != nil {
+		return ErrResp(http.StatusUnauthorized, err, "")
+	}
+
+	logger.Debug("restoring rule", "diff", diff.String())
+	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		if err := verifyProvisionedRulesNotAffected(tranCtx, srv.provenanceStore, c.SignedInUser.OrgId, existing.UID); err != nil {
+			return err
+		}
+		return srv.store.UpsertAlertRules(tranCtx, []store.UpsertRule{{
+			Existing:     existing,
+			New:          *restored,
+			RestoredFrom: restoredFrom,
+		}})
+	})
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, ngmodels.ErrAlertRuleUniqueConstraintViolation) {
+			return ErrResp(http.StatusBadRequest, err, "failed to restore rule")
+		}
+		if errors.Is(err, errProvisionedResource) {
+			return ErrResp(http.StatusConflict, err, "failed to restore rule")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to restore rule")
+	}
+
+	srv.scheduleService.UpdateAlertRule(existing.GetKey())
+
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule restored successfully"})
+}
+
+// getAuthorizedRule returns the rule (request parameter :RuleUID) and its folder if the user can see the folder and query the data sources of the rule.
+// Otherwise, it returns the error response.
+func (srv RulerSrv) getAuthorizedRule(c *models.ReqContext) (*ngmodels.AlertRule, *models.Folder, response.Response) {
+	q := ngmodels.GetAlertRuleByUIDQuery{
+		OrgID: c.SignedInUser.OrgId,
+		UID:   web.Params(c.Req)[":RuleUID"],
+	}
+	if err := srv.store.GetAlertRuleByUID(c.Req.Context(), &q); err != nil || q.Result == nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) || err == nil {
+			return nil, nil, ErrResp(http.StatusNotFound, ngmodels.ErrAlertRuleNotFound, "")
+		}
+		return nil, nil, ErrResp(http.StatusInternalServerError, err, "failed to get alert rule")
+	}
+
+	namespaces, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.SignedInUser.OrgId, c.SignedInUser)
+	if err != nil {
+		return nil, nil, ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	namespace, ok := namespaces[q.Result.NamespaceUID]
+	if !ok {
+		// do not reveal that the rule exists
+		return nil, nil, ErrResp(http.StatusNotFound, ngmodels.ErrAlertRuleNotFound, "")
+	}
+
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqSignedIn, evaluator)
+	}
+	if !authorizeDatasourceAccessForRule(q.Result, hasAccess) {
+		return nil, nil, ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to access rule %s because the user does not have read permissions for one or many datasources the rule uses", ErrAuthorization, q.Result.UID), "")
+	}
+	return q.Result, namespace, nil
+}
+
+// getAuthorizedRuleVersion returns the version of the rule if the user can query the data sources it uses.
+// Otherwise, it returns the error response.
+func (srv RulerSrv) getAuthorizedRuleVersion(c *models.ReqContext, ruleUID string, version int64) (*ngmodels.AlertRuleVersion, response.Response) {
+	q := ngmodels.GetAlertRuleVersionQuery{
+		OrgID:   c.SignedInUser.OrgId,
+		RuleUID: ruleUID,
+		Version: version,
+	}
+	if err := srv.store.GetAlertRuleVersion(c.Req.Context(), &q); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleVersionNotFound) {
+			return nil, ErrResp(http.StatusNotFound, fmt.Errorf("%w: %d", err, version), "")
+		}
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to get rule version")
+	}
+
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqSignedIn, evaluator)
+	}
+	if !authorizeDatasourceAccessForRule(q.Result.ToAlertRule(), hasAccess) {
+		return nil, ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to access version %d of the rule because the user does not have read permissions for one or many datasources the version uses", ErrAuthorization, version), "")
+	}
+	return q.Result, nil
+}
+
+// getVersionFromQuery returns the version in the query parameter or defaultVersion if the parameter is not set.
+func getVersionFromQuery(c *models.ReqContext, param string, defaultVersion int64) (int64, error) {
+	s := c.Query(param)
+	if s == "" {
+		return defaultVersion, nil
+	}
+	version, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid %s version %q", param, s)
+	}
+	return version, nil
+}
+
+func toRuleVersionDiff(from, to int64, diff cmputil.DiffReport) apimodels.RuleVersionDiff {
+	result := apimodels.RuleVersionDiff{
+		From:    from,
+		To:      to,
+		Changes: make([]apimodels.RuleFieldChange, 0, len(diff)),
+	}
+	for _, d := range diff {
+		result.Changes = append(result.Changes, apimodels.RuleFieldChange{
+			Path: d.Path,
+			From: diffValue(d.Left),
+			To:   diffValue(d.Right),
+		})
+	}
+	return result
+}
+
+// diffValue returns the value of the field in the diff. It is nil if the field was added or removed.
+func diffValue(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
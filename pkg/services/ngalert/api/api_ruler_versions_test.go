@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	models2 "github.com/grafana/grafana/pkg/models"
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestRouteGetRuleVersions(t *testing.T) {
+	t.Run("should return versions newest first", func(t *testing.T) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := store.NewFakeRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+		ruleStore.PutRule(context.Background(), rule)
+		ruleStore.PutRuleVersion(ruleVersions(rule, 3)...)
+
+		response := createService(acMock.New().WithDisabled(), ruleStore, nil).RouteGetRuleVersions(createRequestContext(orgID, models2.ROLE_VIEWER, map[string]string{
+			":RuleUID": rule.UID,
+		}))
+
+		require.Equalf(t, http.StatusOK, response.Status(), "unexpected response: %s", string(response.Body()))
+		var result apimodels.RuleVersionsResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 3)
+		for i, v := range result {
+			require.Equal(t, int64(3-i), v.Version)
+			require.Equal(t, rule.UID, v.Rule.GrafanaManagedAlert.UID)
+			require.Equal(t, folder.Id, v.Rule.GrafanaManagedAlert.NamespaceID)
+		}
+	})
+
+	t.Run("should return 404 if rule is not in a folder visible to the user", func(t *testing.T) {
+		orgID := rand.Int63()
+		ruleStore := store.NewFakeRuleStore(t)
+		rule := models.AlertRuleGen(withOrgID(orgID))()
+		ruleStore.PutRule(context.Background(), rule)
+		ruleStore.Folders[orgID] = nil
+
+		response := createService(acMock.New().WithDisabled(), ruleStore, nil).RouteGetRuleVersions(createRequestContext(orgID, models2.ROLE_VIEWER, map[string]string{
+			":RuleUID": rule.UID,
+		}))
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 401 if user cannot query data sources of the rule", func(t *testing.T) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := store.NewFakeRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+		ruleStore.PutRule(context.Background(), rule)
+
+		response := createService(acMock.New(), ruleStore, nil).RouteGetRuleVersions(createRequestContext(orgID, "None", map[string]string{
+			":RuleUID": rule.UID,
+		}))
+
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+	})
+}
+
+func TestRouteGetRuleVersionsDiff(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := store.NewFakeRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+	versions := ruleVersions(rule, 3)
+	versions[1].Title = "changed title"
+	versions[2].Title = "changed title"
+	versions[2].Labels = map[string]string{"severity": "critical"}
+	rule.Version = 3
+	ruleStore.PutRule(context.Background(), rule)
+	ruleStore.PutRuleVersion(versions...)
+
+	request := func(query string) *models2.ReqContext {
+		c := createRequestContext(orgID, models2.ROLE_VIEWER, map[string]string{
+			":RuleUID": rule.UID,
+		})
+		c.Req.URL = &url.URL{RawQuery: query}
+		return c
+	}
+
+	t.Run("should compare given versions", func(t *testing.T) {
+		response := createService(acMock.New().WithDisabled(), ruleStore, nil).RouteGetRuleVersionsDiff(request("from=1&to=2"))
+
+		require.Equalf(t, http.StatusOK, response.Status(), "unexpected response: %s", string(response.Body()))
+		var result apimodels.RuleVersionDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, int64(1), result.From)
+		require.Equal(t, int64(2), result.To)
+		require.Equal(t, []apimodels.RuleFieldChange{{Path: "Title", From: rule.Title, To: "changed title"}}, result.Changes)
+	})
+
+	t.Run("should compare current version with its parent by default", func(t *testing.T) {
+		response := createService(acMock.New().WithDisabled(), ruleStore, nil).RouteGetRuleVersionsDiff(request(""))
+
+		require.Equalf(t, http.StatusOK, response.Status(), "unexpected response: %s", string(response.Body()))
+		var result apimodels.RuleVersionDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, int64(2), result.From)
+		require.Equal(t, int64(3), result.To)
+		require.NotEmpty(t, result.Changes)
+		for _, change := range result.Changes {
+			require.Contains(t, change.Path, "Labels")
+		}
+	})
+
+	t.Run("should return 404 if version does not exist", func(t *testing.T) {
+		response := createService(acMock.New().WithDisabled(), ruleStore, nil).RouteGetRuleVersionsDiff(request("from=1&to=10"))
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 400 if version is invalid", func(t *testing.T) {
+		response := createService(acMock.New().WithDisabled(), ruleStore, nil).RouteGetRuleVersionsDiff(request("from=first"))
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
+func TestRestoreAlertRule(t *testing.T) {
+	getUpserts := func(ruleStore *store.FakeRuleStore) [][]store.UpsertRule {
+		var result [][]store.UpsertRule
+		for _, cmd := range ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]store.UpsertRule)
+			return c, ok
+		}) {
+			result = append(result, cmd.([]store.UpsertRule))
+		}
+		return result
+	}
+
+	t.Run("should save restored rule and update the scheduler", func(t *testing.T) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := store.NewFakeRuleStore(t)
+		rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+		restored := models.CopyRule(rule)
+		restored.Title = "restored title"
+
+		scheduler := &schedule.FakeScheduleService{}
+		scheduler.On("UpdateAlertRule", rule.GetKey())
+
+		request := createRequestContext(orgID, models2.ROLE_EDITOR, nil)
+		response := createService(acMock.New().WithDisabled(), ruleStore, scheduler).restoreAlertRule(request, folder, rule, restored, 1)
+
+		require.Equalf(t, http.StatusAccepted, response.Status(), "unexpected response: %s", string(response.Body()))
+		upserts := getUpserts(ruleStore)
+		require.Len(t, upserts, 1)
+		require.Len(t, upserts[0], 1)
+		require.Equal(t, rule, upserts[0][0].Existing)
+		require.Equal(t, *restored, upserts[0][0].New)
+		require.Equal(t, int64(1), upserts[0][0].RestoredFrom)
+		scheduler.AssertExpectations(t)
+	})
+
+	t.Run("should do nothing if there are no changes", func(t *testing.T) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := store.NewFakeRuleStore(t)
+		rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+
+		scheduler := &schedule.FakeScheduleService{}
+		scheduler.On("UpdateAlertRule", mock.Anything).Panic("should not be called")
+
+		request := createRequestContext(orgID, models2.ROLE_EDITOR, nil)
+		response := createService(acMock.New().WithDisabled(), ruleStore, scheduler).restoreAlertRule(request, folder, rule, models.CopyRule(rule), 1)
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.Empty(t, getUpserts(ruleStore))
+	})
+
+	t.Run("should return 401 if user is not authorized to update the rule", func(t *testing.T) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := store.NewFakeRuleStore(t)
+		rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+		restored := models.CopyRule(rule)
+		restored.Title = "restored title"
+
+		scheduler := &schedule.FakeScheduleService{}
+		scheduler.On("UpdateAlertRule", mock.Anything).Panic("should not be called")
+
+		request := createRequestContext(orgID, "None", nil)
+		response := createService(acMock.New().WithPermissions(createPermissionsForRules([]*models.AlertRule{rule})), ruleStore, scheduler).restoreAlertRule(request, folder, rule, restored, 1)
+
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+		require.Empty(t, getUpserts(ruleStore))
+	})
+}
+
+// ruleVersions returns count versions of the rule that are the same as the rule.
+func ruleVersions(rule *models.AlertRule, count int) []*models.AlertRuleVersion {
+	result := make([]*models.AlertRuleVersion, 0, count)
+	for i := 1; i <= count; i++ {
+		result = append(result, &models.AlertRuleVersion{
+			RuleOrgID:        rule.OrgID,
+			RuleUID:          rule.UID,
+			RuleNamespaceUID: rule.NamespaceUID,
+			RuleGroup:        rule.RuleGroup,
+			ParentVersion:    int64(i - 1),
+			Version:          int64(i),
+			Created:          time.Now(),
+			Title:            rule.Title,
+			Condition:        rule.Condition,
+			Data:             rule.Data,
+			IntervalSeconds:  rule.IntervalSeconds,
+			NoDataState:      rule.NoDataState,
+			ExecErrState:     rule.ExecErrState,
+			For:              rule.For,
+			Annotations:      rule.Annotations,
+			Labels:           rule.Labels,
+		})
+	}
+	return result
+}
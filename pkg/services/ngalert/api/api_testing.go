@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util"
@@ -30,6 +32,7 @@ type TestingApiSrv struct {
 	log               log.Logger
 	accessControl     accesscontrol.AccessControl
 	evaluator         eval.Evaluator
+	backtesting       *backtesting.Engine
 }
 
 func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *models.ReqContext, body apimodels.TestRulePayload) response.Response {
@@ -134,3 +137,56 @@ func (srv TestingApiSrv) RouteEvalQueries(c *models.ReqContext, cmd apimodels.Ev
 
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
+
+func (srv TestingApiSrv) RouteBacktestConfig(c *models.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	if !authorizeDatasourceAccessForRule(&ngmodels.AlertRule{Data: cmd.Data}, func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.accessControl, c)(accesscontrol.ReqSignedIn, evaluator)
+	}) {
+		return ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization), "")
+	}
+
+	// the same defaults as for the rules that are created via the ruler API
+	noDataState := ngmodels.NoData
+	if cmd.NoDataState != "" {
+		var err error
+		if noDataState, err = ngmodels.NoDataStateFromString(string(cmd.NoDataState)); err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+	}
+	execErrState := ngmodels.AlertingErrState
+	if cmd.ExecErrState != "" {
+		var err error
+		if execErrState, err = ngmodels.ErrStateFromString(string(cmd.ExecErrState)); err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+	}
+	interval := time.Duration(cmd.Interval)
+	if interval < time.Second || interval%time.Second != 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("evaluation interval must be a positive duration in whole seconds"), "")
+	}
+
+	rule := &ngmodels.AlertRule{
+		OrgID:           c.SignedInUser.OrgId,
+		Title:           cmd.Title,
+		Condition:       cmd.Condition,
+		Data:            cmd.Data,
+		IntervalSeconds: int64(interval.Seconds()),
+		For:             time.Duration(cmd.For),
+		NoDataState:     noDataState,
+		ExecErrState:    execErrState,
+		Labels:          cmd.Labels,
+		Annotations:     cmd.Annotations,
+	}
+	if err := validateCondition(c.Req.Context(), ngmodels.Condition{Condition: rule.Condition, OrgID: rule.OrgID, Data: rule.Data}, c.SignedInUser, c.SkipCache, srv.DatasourceCache); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid condition")
+	}
+
+	frame, err := srv.backtesting.Test(c.Req.Context(), rule, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to backtest the rule")
+	}
+	return response.JSONStreaming(http.StatusOK, frame)
+}
@@ -1,19 +1,26 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
 	models2 "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/web"
@@ -265,6 +272,124 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestRouteBacktestConfig(t *testing.T) {
+	rc := &models2.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &models2.SignedInUser{
+			OrgId: 1,
+		},
+	}
+	from := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("should return 401 if user cannot query a data source", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		data2 := models.GenerateAlertQuery()
+
+		ac := acMock.New().WithPermissions([]*accesscontrol.Permission{
+			{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+		})
+
+		srv := createTestingApiSrv(nil, ac, &eval.FakeEvaluator{})
+
+		response := srv.RouteBacktestConfig(rc, definitions.BacktestConfig{
+			From:      from,
+			To:        from.Add(time.Hour),
+			Interval:  model.Duration(10 * time.Minute),
+			Condition: data1.RefID,
+			Data:      []models.AlertQuery{data1, data2},
+		})
+
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+	})
+
+	t.Run("should return the states of the alert instances at each evaluation", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		ds := &datasources.FakeCacheService{DataSources: []*models2.DataSource{
+			{Uid: data1.DatasourceUID},
+		}}
+		evaluator := &eval.FakeEvaluator{}
+		evaluator.On("ConditionEval", mock.Anything, mock.Anything, mock.Anything).Return(
+			func(_ *models.Condition, now time.Time, _ *expr.Service) eval.Results {
+				return eval.Results{{State: eval.Alerting, EvaluatedAt: now}}
+			}, nil)
+
+		srv := createTestingApiSrv(ds, canQuery(data1), evaluator)
+
+		response := srv.RouteBacktestConfig(rc, definitions.BacktestConfig{
+			From:      from,
+			To:        from.Add(time.Hour),
+			Interval:  model.Duration(10 * time.Minute),
+			Condition: data1.RefID,
+			Data:      []models.AlertQuery{data1},
+			For:       model.Duration(20 * time.Minute),
+		})
+
+		require.Equal(t, http.StatusOK, response.Status())
+		evaluator.AssertNumberOfCalls(t, "ConditionEval", 7)
+
+		recorder := httptest.NewRecorder()
+		response.WriteTo(&models2.ReqContext{Context: &web.Context{Resp: web.NewResponseWriter(http.MethodPost, recorder)}})
+		frame := data.Frame{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &frame))
+		require.Len(t, frame.Fields, 2)
+		states := make([]string, 0, frame.Fields[1].Len())
+		for i := 0; i < frame.Fields[1].Len(); i++ {
+			states = append(states, *frame.Fields[1].At(i).(*string))
+		}
+		require.Equal(t, []string{"Pending", "Pending", "Alerting", "Alerting", "Alerting", "Alerting", "Alerting"}, states)
+	})
+
+	t.Run("should return 400 if the time range requires too many evaluations", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		ds := &datasources.FakeCacheService{DataSources: []*models2.DataSource{
+			{Uid: data1.DatasourceUID},
+		}}
+		evaluator := &eval.FakeEvaluator{}
+
+		srv := createTestingApiSrv(ds, canQuery(data1), evaluator)
+
+		response := srv.RouteBacktestConfig(rc, definitions.BacktestConfig{
+			From:      from,
+			To:        from.Add(time.Hour),
+			Interval:  model.Duration(time.Minute),
+			Condition: data1.RefID,
+			Data:      []models.AlertQuery{data1},
+		})
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		evaluator.AssertNotCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 if the interval is not whole seconds", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		ds := &datasources.FakeCacheService{DataSources: []*models2.DataSource{
+			{Uid: data1.DatasourceUID},
+		}}
+
+		srv := createTestingApiSrv(ds, canQuery(data1), &eval.FakeEvaluator{})
+
+		response := srv.RouteBacktestConfig(rc, definitions.BacktestConfig{
+			From:      from,
+			To:        from.Add(time.Hour),
+			Interval:  model.Duration(1500 * time.Millisecond),
+			Condition: data1.RefID,
+			Data:      []models.AlertQuery{data1},
+		})
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
+func canQuery(queries ...models.AlertQuery) *acMock.Mock {
+	permissions := make([]*accesscontrol.Permission, 0, len(queries))
+	for _, q := range queries {
+		permissions = append(permissions, &accesscontrol.Permission{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(q.DatasourceUID)})
+	}
+	return acMock.New().WithPermissions(permissions)
+}
+
 func createTestingApiSrv(ds *datasources.FakeCacheService, ac *acMock.Mock, evaluator *eval.FakeEvaluator) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New().WithDisabled()
@@ -274,5 +399,6 @@ func createTestingApiSrv(ds *datasources.FakeCacheService, ac *acMock.Mock, eval
 		DatasourceCache: ds,
 		accessControl:   ac,
 		evaluator:       evaluator,
+		backtesting:     backtesting.NewEngine(evaluator, nil, 10, log.NewNopLogger()),
 	}
 }
@@ -59,6 +59,14 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingRuleCreate, scope),
 			ac.EvalPermission(ac.ActionAlertingRuleDelete, scope),
 		)
+	case http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions",
+		http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/diff",
+		http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}/history":
+		// access to the folder of the rule is checked by the handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore":
+		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalPermission(ac.ActionAlertingRuleUpdate)
 
 	// Grafana, Prometheus-compatible Paths
 	case http.MethodGet + "/api/prometheus/grafana/api/v1/rules":
@@ -73,6 +81,10 @@ func (api *API) authorize(method, path string) web.Handler {
 		fallback = middleware.ReqSignedIn
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/backtest":
+		fallback = middleware.ReqSignedIn
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
 	// Lotex Paths
 	case http.MethodDelete + "/api/ruler/{Recipient}/api/v1/rules/{Namespace}":
@@ -182,13 +194,28 @@ func (api *API) authorize(method, path string) web.Handler {
 
 	// Grafana-only Provisioning Read Paths
 	case http.MethodGet + "/api/provisioning/policies",
-		http.MethodGet + "/api/provisioning/contact-points":
+		http.MethodGet + "/api/provisioning/contact-points",
+		http.MethodGet + "/api/provisioning/templates",
+		http.MethodGet + "/api/provisioning/templates/{name}",
+		http.MethodGet + "/api/provisioning/mute-timings",
+		http.MethodGet + "/api/provisioning/mute-timings/{name}",
+		http.MethodGet + "/api/provisioning/alert-rules/{UID}",
+		http.MethodGet + "/api/provisioning/folder/{FolderUID}/rule-groups/{Group}":
 		return middleware.ReqSignedIn
 
 	case http.MethodPost + "/api/provisioning/policies",
 		http.MethodPost + "/api/provisioning/contact-points",
 		http.MethodPut + "/api/provisioning/contact-points",
-		http.MethodDelete + "/api/provisioning/contact-points/{ID}":
+		http.MethodDelete + "/api/provisioning/contact-points/{ID}",
+		http.MethodPut + "/api/provisioning/templates/{name}",
+		http.MethodDelete + "/api/provisioning/templates/{name}",
+		http.MethodPost + "/api/provisioning/mute-timings",
+		http.MethodPut + "/api/provisioning/mute-timings/{name}",
+		http.MethodDelete + "/api/provisioning/mute-timings/{name}",
+		http.MethodPost + "/api/provisioning/alert-rules",
+		http.MethodPut + "/api/provisioning/alert-rules/{UID}",
+		http.MethodDelete + "/api/provisioning/alert-rules/{UID}",
+		http.MethodPut + "/api/provisioning/folder/{FolderUID}/rule-groups/{Group}":
 		return middleware.ReqEditorRole
 	}
 
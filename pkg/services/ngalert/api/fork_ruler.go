@@ -125,6 +125,22 @@ func (f *ForkedRulerApi) forkRouteGetGrafanaRuleGroupConfig(ctx *models.ReqConte
 	return f.GrafanaRuler.RouteGetRulegGroupConfig(ctx)
 }
 
+func (f *ForkedRulerApi) forkRouteGetGrafanaRuleStateHistory(ctx *models.ReqContext) response.Response {
+	return f.GrafanaRuler.RouteGetRuleStateHistory(ctx)
+}
+
+func (f *ForkedRulerApi) forkRouteGetGrafanaRuleVersions(ctx *models.ReqContext) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersions(ctx)
+}
+
+func (f *ForkedRulerApi) forkRouteGetGrafanaRuleVersionsDiff(ctx *models.ReqContext) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersionsDiff(ctx)
+}
+
+func (f *ForkedRulerApi) forkRoutePostGrafanaRuleVersionRestore(ctx *models.ReqContext) response.Response {
+	return f.GrafanaRuler.RoutePostRuleVersionRestore(ctx)
+}
+
 func (f *ForkedRulerApi) forkRouteGetGrafanaRulesConfig(ctx *models.ReqContext) response.Response {
 	return f.GrafanaRuler.RouteGetRulesConfig(ctx)
 }
@@ -25,11 +25,15 @@ type RulerApiForkingService interface {
 	RouteDeleteNamespaceRulesConfig(*models.ReqContext) response.Response
 	RouteDeleteRuleGroupConfig(*models.ReqContext) response.Response
 	RouteGetGrafanaRuleGroupConfig(*models.ReqContext) response.Response
+	RouteGetGrafanaRuleStateHistory(*models.ReqContext) response.Response
+	RouteGetGrafanaRuleVersions(*models.ReqContext) response.Response
+	RouteGetGrafanaRuleVersionsDiff(*models.ReqContext) response.Response
 	RouteGetGrafanaRulesConfig(*models.ReqContext) response.Response
 	RouteGetNamespaceGrafanaRulesConfig(*models.ReqContext) response.Response
 	RouteGetNamespaceRulesConfig(*models.ReqContext) response.Response
 	RouteGetRulegGroupConfig(*models.ReqContext) response.Response
 	RouteGetRulesConfig(*models.ReqContext) response.Response
+	RoutePostGrafanaRuleVersionRestore(*models.ReqContext) response.Response
 	RoutePostNameGrafanaRulesConfig(*models.ReqContext) response.Response
 	RoutePostNameRulesConfig(*models.ReqContext) response.Response
 }
@@ -54,6 +58,18 @@ func (f *ForkedRulerApi) RouteGetGrafanaRuleGroupConfig(ctx *models.ReqContext)
 	return f.forkRouteGetGrafanaRuleGroupConfig(ctx)
 }
 
+func (f *ForkedRulerApi) RouteGetGrafanaRuleStateHistory(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetGrafanaRuleStateHistory(ctx)
+}
+
+func (f *ForkedRulerApi) RouteGetGrafanaRuleVersions(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetGrafanaRuleVersions(ctx)
+}
+
+func (f *ForkedRulerApi) RouteGetGrafanaRuleVersionsDiff(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetGrafanaRuleVersionsDiff(ctx)
+}
+
 func (f *ForkedRulerApi) RouteGetGrafanaRulesConfig(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetGrafanaRulesConfig(ctx)
 }
@@ -74,6 +90,10 @@ func (f *ForkedRulerApi) RouteGetRulesConfig(ctx *models.ReqContext) response.Re
 	return f.forkRouteGetRulesConfig(ctx)
 }
 
+func (f *ForkedRulerApi) RoutePostGrafanaRuleVersionRestore(ctx *models.ReqContext) response.Response {
+	return f.forkRoutePostGrafanaRuleVersionRestore(ctx)
+}
+
 func (f *ForkedRulerApi) RoutePostNameGrafanaRulesConfig(ctx *models.ReqContext) response.Response {
 	conf := apimodels.PostableRuleGroupConfig{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -142,6 +162,36 @@ func (api *API) RegisterRulerApiEndpoints(srv RulerApiForkingService, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/history"),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rule/{RuleUID}/history"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/history",
+				srv.RouteGetGrafanaRuleStateHistory,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions"),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions",
+				srv.RouteGetGrafanaRuleVersions,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/diff"),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/diff"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/diff",
+				srv.RouteGetGrafanaRuleVersionsDiff,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/ruler/grafana/api/v1/rules"),
 			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rules"),
@@ -192,6 +242,16 @@ func (api *API) RegisterRulerApiEndpoints(srv RulerApiForkingService, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore"),
+			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore",
+				srv.RoutePostGrafanaRuleVersionRestore,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/ruler/grafana/api/v1/rules/{Namespace}"),
 			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rules/{Namespace}"),
@@ -1,12 +1,14 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -103,3 +105,51 @@ func (f *fakeAlertInstanceManager) GenerateAlertInstances(orgID int64, alertRule
 		f.states[orgID][alertRuleUID] = append(f.states[orgID][alertRuleUID], newState)
 	}
 }
+
+// fakeProvenanceStore keeps the provenances of the provisioned resources in memory.
+type fakeProvenanceStore struct {
+	mtx     sync.Mutex
+	records map[int64]map[string]map[string]models.Provenance
+}
+
+func newFakeProvenanceStore() *fakeProvenanceStore {
+	return &fakeProvenanceStore{
+		records: map[int64]map[string]map[string]models.Provenance{},
+	}
+}
+
+func (f *fakeProvenanceStore) GetProvenance(_ context.Context, o models.Provisionable) (models.Provenance, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.records[o.ResourceOrgID()][o.ResourceType()][o.ResourceID()], nil
+}
+
+func (f *fakeProvenanceStore) GetProvenances(_ context.Context, orgID int64, resourceType string) (map[string]models.Provenance, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make(map[string]models.Provenance, len(f.records[orgID][resourceType]))
+	for id, p := range f.records[orgID][resourceType] {
+		result[id] = p
+	}
+	return result, nil
+}
+
+func (f *fakeProvenanceStore) SetProvenance(_ context.Context, o models.Provisionable, p models.Provenance) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if _, ok := f.records[o.ResourceOrgID()]; !ok {
+		f.records[o.ResourceOrgID()] = map[string]map[string]models.Provenance{}
+	}
+	if _, ok := f.records[o.ResourceOrgID()][o.ResourceType()]; !ok {
+		f.records[o.ResourceOrgID()][o.ResourceType()] = map[string]models.Provenance{}
+	}
+	f.records[o.ResourceOrgID()][o.ResourceType()][o.ResourceID()] = p
+	return nil
+}
+
+func (f *fakeProvenanceStore) DeleteProvenance(_ context.Context, o models.Provisionable) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.records[o.ResourceOrgID()][o.ResourceType()], o.ResourceID())
+	return nil
+}
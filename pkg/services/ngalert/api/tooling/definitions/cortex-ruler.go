@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -122,6 +123,43 @@ import (
 //     Responses:
 //       202: Ack
 
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/versions ruler RouteGetGrafanaRuleVersions
+//
+// List the versions of a rule, newest first
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionsResponse
+
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/versions/diff ruler RouteGetGrafanaRuleVersionsDiff
+//
+// Compare two versions of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionDiff
+
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/history ruler RouteGetGrafanaRuleStateHistory
+//
+// Get the state changes of the alert instances of a rule as data frames
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleStateHistoryResponse
+
+// swagger:route POST /api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore ruler RoutePostGrafanaRuleVersionRestore
+//
+// Restore a rule to an earlier version
+//
+//     Responses:
+//       202: Ack
+
 // swagger:parameters RoutePostNameRulesConfig RoutePostNameGrafanaRulesConfig
 type NamespaceConfig struct {
 	// in:path
@@ -152,6 +190,83 @@ type PathGetRulesParams struct {
 	PanelID int64
 }
 
+// swagger:parameters RouteGetGrafanaRuleVersions
+type PathRuleVersionsParams struct {
+	// in: path
+	RuleUID string
+}
+
+// swagger:parameters RouteGetGrafanaRuleVersionsDiff
+type PathRuleVersionsDiffParams struct {
+	// in: path
+	RuleUID string
+	// The older version to compare
+	// in: query
+	From int64 `json:"from"`
+	// The newer version to compare
+	// in: query
+	To int64 `json:"to"`
+}
+
+// swagger:parameters RoutePostGrafanaRuleVersionRestore
+type PathRuleVersionRestoreParams struct {
+	// in: path
+	RuleUID string
+	// in: path
+	Version int64
+}
+
+// swagger:parameters RouteGetGrafanaRuleStateHistory
+type PathRuleStateHistoryParams struct {
+	// in: path
+	RuleUID string
+	// Start of the time range in epoch milliseconds
+	// in: query
+	From int64 `json:"from"`
+	// End of the time range in epoch milliseconds
+	// in: query
+	To int64 `json:"to"`
+	// Label matchers that the alert instances must match, for example severity="critical"
+	// in: query
+	Filter []string `json:"filter"`
+	// The maximum number of the most recent state changes to return, 1000 by default
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type RuleStateHistoryResponse = data.Frames
+
+// swagger:model
+type RuleVersionsResponse []GettableRuleVersion
+
+// GettableRuleVersion is a version of a rule as it was saved.
+type GettableRuleVersion struct {
+	Version       int64 `json:"version" yaml:"version"`
+	ParentVersion int64 `json:"parentVersion" yaml:"parentVersion"`
+	// RestoredFrom is the version that this version restored, if any.
+	RestoredFrom int64                    `json:"restoredFrom,omitempty" yaml:"restoredFrom,omitempty"`
+	Created      time.Time                `json:"created" yaml:"created"`
+	Rule         GettableExtendedRuleNode `json:"rule" yaml:"rule"`
+}
+
+// swagger:model
+type RuleVersionDiff struct {
+	From    int64             `json:"from"`
+	To      int64             `json:"to"`
+	Changes []RuleFieldChange `json:"changes"`
+}
+
+// RuleFieldChange is a field of a rule that is different between two versions.
+type RuleFieldChange struct {
+	// Path to the field, for example Labels[severity] or Data[0].Model.
+	Path string `json:"path"`
+	// From is the value in the older version. It is omitted if the value was added.
+	From interface{} `json:"from,omitempty"`
+	// To is the value in the newer version. It is omitted if the value was removed.
+	To interface{} `json:"to,omitempty"`
+}
+
 // swagger:model
 type RuleGroupConfigResponse struct {
 	GettableRuleGroupConfig
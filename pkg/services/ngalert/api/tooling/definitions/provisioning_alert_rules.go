@@ -0,0 +1,197 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/provisioning/alert-rules/{UID} provisioning RouteGetAlertRule
+//
+// Get a specific alert rule by UID.
+//
+//     Responses:
+//       200: ProvisionedAlertRule
+//       404: description: Not found.
+
+// swagger:route POST /api/provisioning/alert-rules provisioning RoutePostAlertRule
+//
+// Create a new alert rule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisionedAlertRule
+//       400: ValidationError
+
+// swagger:route PUT /api/provisioning/alert-rules/{UID} provisioning RoutePutAlertRule
+//
+// Update an existing alert rule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisionedAlertRule
+//       400: ValidationError
+
+// swagger:route DELETE /api/provisioning/alert-rules/{UID} provisioning RouteDeleteAlertRule
+//
+// Delete a specific alert rule by UID.
+//
+//     Responses:
+//       204: description: The alert rule was deleted successfully.
+
+// swagger:parameters RouteGetAlertRule RoutePutAlertRule RouteDeleteAlertRule
+type AlertRuleUIDReference struct {
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostAlertRule RoutePutAlertRule
+type AlertRulePayload struct {
+	// in:body
+	Body ProvisionedAlertRule
+}
+
+// ProvisionedAlertRule is an alert rule as it is created, updated and returned by the provisioning API.
+// swagger:model
+type ProvisionedAlertRule struct {
+	// UID is the unique identifier of the alert rule. It is generated if it is not set when the rule is created.
+	UID string `json:"uid"`
+	// required: true
+	OrgID int64 `json:"orgID"`
+	// required: true
+	// example: project_x
+	FolderUID string `json:"folderUID"`
+	// required: true
+	// minLength: 1
+	// maxLength: 190
+	// example: eval_group_1
+	RuleGroup string `json:"ruleGroup"`
+	// required: true
+	// minLength: 1
+	// maxLength: 190
+	// example: Always firing
+	Title string `json:"title"`
+	// required: true
+	// example: A
+	Condition string `json:"condition"`
+	// required: true
+	// example: [{"refId":"A","queryType":"","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"-100","model":{"conditions":[{"evaluator":{"params":[0,0],"type":"gt"},"operator":{"type":"and"},"query":{"params":[]},"reducer":{"params":[],"type":"avg"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1 == 1","hide":false,"intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}]
+	Data []models.AlertQuery `json:"data"`
+	// readonly: true
+	Updated time.Time `json:"updated,omitempty"`
+	// required: true
+	// enum: Alerting,NoData,OK
+	NoDataState models.NoDataState `json:"noDataState"`
+	// required: true
+	// enum: Alerting,Error,OK
+	ExecErrState models.ExecutionErrorState `json:"execErrState"`
+	// required: true
+	For model.Duration `json:"for"`
+	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// example: {"team": "sre-team-1"}
+	Labels map[string]string `json:"labels,omitempty"`
+	// readonly: true
+	Provenance models.Provenance `json:"provenance,omitempty"`
+}
+
+// UpstreamModel converts the provisioned alert rule to the alert rule model.
+// The interval of the rule is not part of the provisioned rule, it is defined by the rule group.
+func (a *ProvisionedAlertRule) UpstreamModel() models.AlertRule {
+	return models.AlertRule{
+		UID:          a.UID,
+		OrgID:        a.OrgID,
+		NamespaceUID: a.FolderUID,
+		RuleGroup:    a.RuleGroup,
+		Title:        a.Title,
+		Condition:    a.Condition,
+		Data:         a.Data,
+		Updated:      a.Updated,
+		NoDataState:  a.NoDataState,
+		ExecErrState: a.ExecErrState,
+		For:          time.Duration(a.For),
+		Annotations:  a.Annotations,
+		Labels:       a.Labels,
+	}
+}
+
+// NewAlertRule creates the provisioned alert rule from the alert rule model and its provenance.
+func NewAlertRule(rule models.AlertRule, provenance models.Provenance) ProvisionedAlertRule {
+	return ProvisionedAlertRule{
+		UID:          rule.UID,
+		OrgID:        rule.OrgID,
+		FolderUID:    rule.NamespaceUID,
+		RuleGroup:    rule.RuleGroup,
+		Title:        rule.Title,
+		Condition:    rule.Condition,
+		Data:         rule.Data,
+		Updated:      rule.Updated,
+		NoDataState:  rule.NoDataState,
+		ExecErrState: rule.ExecErrState,
+		For:          model.Duration(rule.For),
+		Annotations:  rule.Annotations,
+		Labels:       rule.Labels,
+		Provenance:   provenance,
+	}
+}
+
+// swagger:route GET /api/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning RouteGetAlertRuleGroup
+//
+// Get a rule group.
+//
+//     Responses:
+//       200: AlertRuleGroup
+//       404: description: Not found.
+
+// swagger:route PUT /api/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning RoutePutAlertRuleGroup
+//
+// Update the interval of a rule group.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleGroup
+//       400: ValidationError
+
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup
+type FolderUIDPathParam struct {
+	// in:path
+	FolderUID string `json:"FolderUID"`
+}
+
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup
+type RuleGroupPathParam struct {
+	// in:path
+	Group string `json:"Group"`
+}
+
+// swagger:parameters RoutePutAlertRuleGroup
+type AlertRuleGroupPayload struct {
+	// in:body
+	Body AlertRuleGroupMetadata
+}
+
+// AlertRuleGroupMetadata is the part of a rule group that is shared by all the rules in the group.
+// swagger:model
+type AlertRuleGroupMetadata struct {
+	// Interval is the evaluation interval of the rules in the group in seconds.
+	// required: true
+	// example: 60
+	Interval int64 `json:"interval"`
+}
+
+// AlertRuleGroup is a rule group with its rules.
+// swagger:model
+type AlertRuleGroup struct {
+	Title     string                 `json:"title"`
+	FolderUID string                 `json:"folderUid"`
+	Interval  int64                  `json:"interval"`
+	Rules     []ProvisionedAlertRule `json:"rules"`
+}
@@ -0,0 +1,92 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/prometheus/alertmanager/config"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/provisioning/mute-timings provisioning RouteGetMuteTimings
+//
+// Get all the mute timings.
+//
+//     Responses:
+//       200: MuteTimings
+
+// swagger:route GET /api/provisioning/mute-timings/{name} provisioning RouteGetMuteTiming
+//
+// Get a mute timing.
+//
+//     Responses:
+//       200: MuteTimeInterval
+//       404: description: Not found.
+
+// swagger:route POST /api/provisioning/mute-timings provisioning RoutePostMuteTiming
+//
+// Create a new mute timing.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: MuteTimeInterval
+//       400: ValidationError
+
+// swagger:route PUT /api/provisioning/mute-timings/{name} provisioning RoutePutMuteTiming
+//
+// Replace an existing mute timing.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: MuteTimeInterval
+//       400: ValidationError
+
+// swagger:route DELETE /api/provisioning/mute-timings/{name} provisioning RouteDeleteMuteTiming
+//
+// Delete a mute timing.
+//
+//     Responses:
+//       204: description: The mute timing was deleted successfully.
+
+// swagger:parameters RouteGetMuteTiming RoutePutMuteTiming RouteDeleteMuteTiming
+type MuteTimingNameReference struct {
+	// in:path
+	Name string `json:"name"`
+}
+
+// swagger:parameters RoutePostMuteTiming RoutePutMuteTiming
+type MuteTimingPayload struct {
+	// in:body
+	Body MuteTimeInterval
+}
+
+// swagger:model
+type MuteTimings []MuteTimeInterval
+
+// MuteTimeInterval is a mute time interval of the Alertmanager configuration and the provenance it was created with.
+// swagger:model
+type MuteTimeInterval struct {
+	config.MuteTimeInterval `json:",inline" yaml:",inline"`
+	Provenance              models.Provenance `json:"provenance,omitempty"`
+}
+
+func (mt *MuteTimeInterval) ResourceType() string {
+	return "muteTimeInterval"
+}
+
+func (mt *MuteTimeInterval) ResourceID() string {
+	return mt.MuteTimeInterval.Name
+}
+
+// Validate checks that the mute timing has a name.
+// The time intervals are validated when they are unmarshalled.
+func (mt *MuteTimeInterval) Validate() error {
+	if mt.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	return nil
+}
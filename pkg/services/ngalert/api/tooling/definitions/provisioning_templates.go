@@ -0,0 +1,89 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/provisioning/templates provisioning RouteGetTemplates
+//
+// Get all message templates.
+//
+//     Responses:
+//       200: MessageTemplates
+//       404: description: Not found.
+
+// swagger:route GET /api/provisioning/templates/{name} provisioning RouteGetTemplate
+//
+// Get a message template.
+//
+//     Responses:
+//       200: MessageTemplate
+//       404: description: Not found.
+
+// swagger:route PUT /api/provisioning/templates/{name} provisioning RoutePutTemplate
+//
+// Updates an existing template or creates a new one.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: MessageTemplate
+//       400: ValidationError
+
+// swagger:route DELETE /api/provisioning/templates/{name} provisioning RouteDeleteTemplate
+//
+// Delete a template.
+//
+//     Responses:
+//       204: description: The template was deleted successfully.
+
+// MessageTemplate is a notification template and the provenance it was created with.
+// swagger:model
+type MessageTemplate struct {
+	Name       string
+	Template   string
+	Provenance models.Provenance `json:"provenance,omitempty"`
+}
+
+// swagger:model
+type MessageTemplates []MessageTemplate
+
+// MessageTemplateContent is the content of a template that is created or updated.
+// swagger:model
+type MessageTemplateContent struct {
+	Template string
+}
+
+// swagger:parameters RoutePutTemplate
+type MessageTemplatePayload struct {
+	// in:body
+	Body MessageTemplateContent
+}
+
+// swagger:parameters RouteGetTemplate RoutePutTemplate RouteDeleteTemplate
+type TemplateNameReference struct {
+	// in:path
+	Name string `json:"name"`
+}
+
+func (t *MessageTemplate) ResourceType() string {
+	return "template"
+}
+
+func (t *MessageTemplate) ResourceID() string {
+	return t.Name
+}
+
+// Validate checks that the template has a name and a content.
+func (t *MessageTemplate) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("template must have a name")
+	}
+	if t.Template == "" {
+		return fmt.Errorf("template must have content")
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/promql"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -52,6 +54,20 @@ import (
 //     Responses:
 //       200: EvalQueriesResponse
 
+// swagger:route Post /api/v1/rule/backtest testing RouteBacktestConfig
+//
+// Replay a rule over a past time range at its evaluation interval
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestResult
+//       400: ValidationError
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -84,6 +100,37 @@ type EvalQueriesPayload struct {
 	Now  time.Time           `json:"now"`
 }
 
+// swagger:parameters RouteBacktestConfig
+type BacktestConfigRequest struct {
+	// in:body
+	Body BacktestConfig
+}
+
+// swagger:model
+type BacktestConfig struct {
+	// From is the time of the first evaluation.
+	From time.Time `json:"from"`
+	// To is the end of the time range, the last evaluation happens at or before this time.
+	To time.Time `json:"to"`
+	// Interval is the evaluation interval of the rule.
+	Interval model.Duration `json:"interval"`
+
+	Condition    string              `json:"condition"`
+	Data         []models.AlertQuery `json:"data"`
+	For          model.Duration      `json:"for,omitempty"`
+	Title        string              `json:"title"`
+	Labels       map[string]string   `json:"labels,omitempty"`
+	Annotations  map[string]string   `json:"annotations,omitempty"`
+	NoDataState  NoDataState         `json:"no_data_state"`
+	ExecErrState ExecutionErrorState `json:"exec_err_state"`
+}
+
+// BacktestResult is a data frame. Its first field contains the time of each evaluation and every other field
+// contains the state of one alert instance at that time. The labels of the field are the labels of the instance.
+// The state is null if the instance did not exist at that time.
+// swagger:model
+type BacktestResult data.Frame
+
 func (p *TestRulePayload) UnmarshalJSON(b []byte) error {
 	type plain TestRulePayload
 	if err := json.Unmarshal(b, (*plain)(p)); err != nil {
@@ -0,0 +1,151 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// DefaultMaxEvaluations is the maximum number of evaluations of a single backtest if it is not configured.
+const DefaultMaxEvaluations = 1000
+
+var ErrInvalidInputData = errors.New("invalid input data")
+
+// Engine replays the evaluations of an alert rule over a past time range. The results of the evaluations
+// go through the same state transitions as the results of the scheduled evaluations, so that the pending period,
+// the recovery thresholds and the handling of NoData and Error can be tested before the rule is saved.
+type Engine struct {
+	evaluator         eval.Evaluator
+	expressionService *expr.Service
+	maxEvaluations    int
+	stateMetrics      *metrics.State
+	log               log.Logger
+}
+
+func NewEngine(evaluator eval.Evaluator, expressionService *expr.Service, maxEvaluations int, logger log.Logger) *Engine {
+	if maxEvaluations <= 0 {
+		maxEvaluations = DefaultMaxEvaluations
+	}
+	return &Engine{
+		evaluator:         evaluator,
+		expressionService: expressionService,
+		maxEvaluations:    maxEvaluations,
+		// the states of a backtest are not real alerts, so their metrics are not exposed.
+		stateMetrics: metrics.NewNGAlert(prometheus.NewRegistry()).GetStateMetrics(),
+		log:          logger,
+	}
+}
+
+// Test evaluates the rule at every evaluation interval of the rule from the time "from" to the time "to" inclusive,
+// and returns a data frame that contains the time of each evaluation and a field with the state of each alert
+// instance at that time. The state of the instance is nil if the instance did not exist at that time.
+func (e *Engine) Test(ctx context.Context, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: evaluation interval must be positive", ErrInvalidInputData)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: the beginning of the time range must be before its end", ErrInvalidInputData)
+	}
+	evaluations := int(to.Sub(from)/interval) + 1
+	if evaluations > e.maxEvaluations {
+		return nil, fmt.Errorf("%w: the time range requires %d evaluations but at most %d evaluations are allowed, increase the evaluation interval or shorten the time range", ErrInvalidInputData, evaluations, e.maxEvaluations)
+	}
+
+	logger := e.log.New("ruleUID", rule.UID, "from", from, "to", to, "evaluations", evaluations)
+	logger.Debug("backtesting alert rule")
+
+	clk := clock.NewMock()
+	manager := state.NewInMemoryManager(logger, e.stateMetrics, nil, clk)
+	condition := &models.Condition{
+		Condition: rule.Condition,
+		OrgID:     rule.OrgID,
+	}
+	tl := newTimeline(evaluations)
+	for i := 0; i < evaluations; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		now := from.Add(time.Duration(i) * interval)
+		clk.Set(now)
+		// threshold expressions apply their recovery threshold to the instances that are pending or firing, as in the scheduler.
+		queries, err := state.WithLoadedDimensions(rule.Data, manager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set loaded dimensions on the rule queries: %w", err)
+		}
+		condition.Data = queries
+		results, err := e.evaluator.ConditionEval(condition, now, e.expressionService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate rule at %s: %w", now.Format(time.RFC3339), err)
+		}
+		manager.ProcessEvalResults(ctx, rule, results)
+		tl.add(now, manager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+	}
+	return tl.toFrame(), nil
+}
+
+// timeline collects the states of every alert instance of a rule at each evaluation.
+type timeline struct {
+	size   int
+	times  []time.Time
+	series map[string]*series
+}
+
+type series struct {
+	labels data.Labels
+	states []*string
+}
+
+func newTimeline(size int) *timeline {
+	return &timeline{
+		size:   size,
+		times:  make([]time.Time, 0, size),
+		series: make(map[string]*series),
+	}
+}
+
+func (t *timeline) add(now time.Time, states []*state.State) {
+	idx := len(t.times)
+	t.times = append(t.times, now)
+	for _, s := range states {
+		ser, ok := t.series[s.CacheId]
+		if !ok {
+			ser = &series{
+				labels: state.RemovePrivateLabels(s.Labels),
+				states: make([]*string, t.size),
+			}
+			t.series[s.CacheId] = ser
+		}
+		v := s.State.String()
+		ser.states[idx] = &v
+	}
+}
+
+func (t *timeline) toFrame() *data.Frame {
+	all := make([]*series, 0, len(t.series))
+	for _, s := range t.series {
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].labels.String() < all[j].labels.String()
+	})
+
+	frame := data.NewFrame("backtesting", data.NewField("Time", nil, t.times))
+	for _, s := range all {
+		frame.Fields = append(frame.Fields, data.NewField("State", s.labels, s.states[:len(t.times)]))
+	}
+	return frame
+}
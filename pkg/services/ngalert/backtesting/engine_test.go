@@ -0,0 +1,213 @@
+package backtesting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestEngine_Test(t *testing.T) {
+	from := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Minute)
+
+	// evaluator returns the results for each minute since "from".
+	evaluatorReturning := func(results ...eval.Results) *eval.FakeEvaluator {
+		evaluator := &eval.FakeEvaluator{}
+		evaluator.On("ConditionEval", mock.Anything, mock.Anything, mock.Anything).Return(
+			func(_ *models.Condition, now time.Time, _ *expr.Service) eval.Results {
+				res := results[int(now.Sub(from)/time.Minute)]
+				for i := range res {
+					res[i].EvaluatedAt = now
+				}
+				return res
+			}, nil)
+		return evaluator
+	}
+
+	rule := &models.AlertRule{
+		OrgID:           1,
+		UID:             "test",
+		Title:           "test rule",
+		Condition:       "A",
+		IntervalSeconds: 60,
+		For:             2 * time.Minute,
+		NoDataState:     models.Alerting,
+		ExecErrState:    models.ErrorErrState,
+		Labels:          map[string]string{"team": "ops"},
+	}
+
+	statesOf := func(t *testing.T, frame *data.Frame, labels data.Labels) []*string {
+		t.Helper()
+		for _, field := range frame.Fields[1:] {
+			if field.Labels.String() == labels.String() {
+				states := make([]*string, 0, field.Len())
+				for i := 0; i < field.Len(); i++ {
+					states = append(states, field.At(i).(*string))
+				}
+				return states
+			}
+		}
+		require.Failf(t, "no states of the instance", "labels: %s", labels)
+		return nil
+	}
+
+	timeline := func(states ...string) []*string {
+		result := make([]*string, 0, len(states))
+		for i := range states {
+			if states[i] == "" {
+				result = append(result, nil)
+				continue
+			}
+			result = append(result, &states[i])
+		}
+		return result
+	}
+
+	t.Run("alert is pending before it fires", func(t *testing.T) {
+		instance := data.Labels{"instance": "a"}
+		evaluator := evaluatorReturning(
+			eval.Results{{Instance: instance, State: eval.Normal}},
+			eval.Results{{Instance: instance, State: eval.Alerting}},
+			eval.Results{{Instance: instance, State: eval.Alerting}},
+			eval.Results{{Instance: instance, State: eval.Alerting}},
+		)
+		engine := NewEngine(evaluator, nil, 0, log.New("test"))
+
+		frame, err := engine.Test(context.Background(), rule, from, to)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 4, frame.Fields[0].Len())
+		require.Equal(t, from, frame.Fields[0].At(0))
+		require.Equal(t, to, frame.Fields[0].At(3))
+
+		labels := data.Labels{"instance": "a", "team": "ops", "alertname": "test rule"}
+		require.Equal(t, timeline("Normal", "Pending", "Pending", "Alerting"), statesOf(t, frame, labels))
+	})
+
+	t.Run("no data and errors are handled as configured", func(t *testing.T) {
+		evaluator := evaluatorReturning(
+			eval.Results{{State: eval.NoData}},
+			eval.Results{{State: eval.Normal}},
+			eval.Results{{State: eval.Error, Error: errors.New("failed")}},
+			eval.Results{{State: eval.Normal}},
+		)
+		engine := NewEngine(evaluator, nil, 0, log.New("test"))
+
+		frame, err := engine.Test(context.Background(), rule, from, to)
+		require.NoError(t, err)
+
+		labels := data.Labels{"team": "ops", "alertname": "test rule"}
+		require.Equal(t, timeline("Alerting", "Normal", "Error", "Normal"), statesOf(t, frame, labels))
+	})
+
+	t.Run("instance has no state before its first evaluation", func(t *testing.T) {
+		a, b := data.Labels{"instance": "a"}, data.Labels{"instance": "b"}
+		evaluator := evaluatorReturning(
+			eval.Results{{Instance: a, State: eval.Normal}},
+			eval.Results{{Instance: a, State: eval.Normal}, {Instance: b, State: eval.Normal}},
+			eval.Results{{Instance: b, State: eval.Normal}},
+			eval.Results{{Instance: b, State: eval.Normal}},
+		)
+		engine := NewEngine(evaluator, nil, 0, log.New("test"))
+
+		frame, err := engine.Test(context.Background(), rule, from, to)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 3)
+
+		require.Equal(t, timeline("Normal", "Normal", "Normal", "Normal"), statesOf(t, frame, data.Labels{"instance": "a", "team": "ops", "alertname": "test rule"}))
+		require.Equal(t, timeline("", "Normal", "Normal", "Normal"), statesOf(t, frame, data.Labels{"instance": "b", "team": "ops", "alertname": "test rule"}))
+	})
+
+	t.Run("instance becomes stale after two missed evaluations", func(t *testing.T) {
+		a, b := data.Labels{"instance": "a"}, data.Labels{"instance": "b"}
+		evaluator := evaluatorReturning(
+			eval.Results{{Instance: a, State: eval.Alerting}, {Instance: b, State: eval.Normal}},
+			eval.Results{{Instance: b, State: eval.Normal}},
+			eval.Results{{Instance: b, State: eval.Normal}},
+			eval.Results{{Instance: b, State: eval.Normal}},
+		)
+		engine := NewEngine(evaluator, nil, 0, log.New("test"))
+
+		frame, err := engine.Test(context.Background(), rule, from, to)
+		require.NoError(t, err)
+
+		require.Equal(t, timeline("Pending", "Pending", "Pending", ""), statesOf(t, frame, data.Labels{"instance": "a", "team": "ops", "alertname": "test rule"}))
+	})
+
+	t.Run("threshold expressions are given the instances that are pending or firing", func(t *testing.T) {
+		instance := data.Labels{"instance": "a"}
+		results := []eval.Results{
+			{{Instance: instance, State: eval.Normal}},
+			{{Instance: instance, State: eval.Alerting}},
+			{{Instance: instance, State: eval.Alerting}},
+			{{Instance: instance, State: eval.Normal}},
+		}
+		var loaded []json.RawMessage
+		evaluator := &eval.FakeEvaluator{}
+		evaluator.On("ConditionEval", mock.Anything, mock.Anything, mock.Anything).Return(
+			func(condition *models.Condition, now time.Time, _ *expr.Service) eval.Results {
+				model, err := condition.Data[0].GetModel()
+				require.NoError(t, err)
+				props := map[string]json.RawMessage{}
+				require.NoError(t, json.Unmarshal(model, &props))
+				loaded = append(loaded, props["loadedDimensions"])
+
+				res := results[int(now.Sub(from)/time.Minute)]
+				for i := range res {
+					res[i].EvaluatedAt = now
+				}
+				return res
+			}, nil)
+		engine := NewEngine(evaluator, nil, 0, log.New("test"))
+
+		thresholdRule := *rule
+		thresholdRule.Data = []models.AlertQuery{{
+			RefID:         "A",
+			DatasourceUID: expr.DatasourceUID,
+			Model:         json.RawMessage(`{"type": "threshold", "expression": "$B", "evaluator": {"type": "gt", "params": [90]}, "recoveryEvaluator": {"type": "lt", "params": [80]}}`),
+		}}
+		_, err := engine.Test(context.Background(), &thresholdRule, from, to)
+		require.NoError(t, err)
+
+		require.Len(t, loaded, 4)
+		require.Nil(t, loaded[0])
+		require.Nil(t, loaded[1])
+		expected := `[{"instance": "a", "team": "ops", "alertname": "test rule", "__alert_rule_uid__": "test", "__alert_rule_namespace_uid__": ""}]`
+		require.JSONEq(t, expected, string(loaded[2]))
+		require.JSONEq(t, expected, string(loaded[3]))
+	})
+
+	t.Run("time range that requires too many evaluations is rejected", func(t *testing.T) {
+		engine := NewEngine(&eval.FakeEvaluator{}, nil, 3, log.New("test"))
+
+		_, err := engine.Test(context.Background(), rule, from, to)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("time range must not be empty", func(t *testing.T) {
+		engine := NewEngine(&eval.FakeEvaluator{}, nil, 0, log.New("test"))
+
+		_, err := engine.Test(context.Background(), rule, to, from)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("evaluation error is returned", func(t *testing.T) {
+		evaluator := &eval.FakeEvaluator{}
+		evaluator.EXPECT().ConditionEval(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("invalid condition"))
+		engine := NewEngine(evaluator, nil, 0, log.New("test"))
+
+		_, err := engine.Test(context.Background(), rule, from, to)
+		require.ErrorContains(t, err, "invalid condition")
+	})
+}
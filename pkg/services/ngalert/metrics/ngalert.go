@@ -52,6 +52,7 @@ type Scheduler struct {
 	EvalDuration             *prometheus.SummaryVec
 	GetAlertRulesDuration    prometheus.Histogram
 	SchedulePeriodicDuration prometheus.Histogram
+	ShardingMembers          prometheus.Gauge
 }
 
 type MultiOrgAlertmanager struct {
@@ -179,6 +180,12 @@ func newSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 				Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 5, 10},
 			},
 		),
+		ShardingMembers: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "scheduler_sharding_members",
+			Help:      "The number of schedulers the alert rules are divided between.",
+		}),
 	}
 }
 
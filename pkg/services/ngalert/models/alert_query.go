@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/expr"
 )
 
@@ -95,6 +96,36 @@ func (aq *AlertQuery) IsExpression() (bool, error) {
 	return expr.IsDataSource(aq.DatasourceUID), nil
 }
 
+// SetLoadedDimensions sets the labels of the dimensions that are currently firing
+// on a threshold expression, so that its recovery threshold can be applied to them.
+// It is a no-op for any other query. The model properties are copied so that
+// copies of the query that share them are not affected.
+func (aq *AlertQuery) SetLoadedDimensions(dims []data.Labels) error {
+	if !expr.IsDataSource(aq.DatasourceUID) {
+		return nil
+	}
+	if aq.modelProps == nil {
+		if err := aq.setModelProps(); err != nil {
+			return err
+		}
+	}
+	if t, ok := aq.modelProps["type"].(string); !ok || t != expr.TypeThreshold.String() {
+		return nil
+	}
+
+	props := make(map[string]interface{}, len(aq.modelProps)+1)
+	for k, v := range aq.modelProps {
+		props[k] = v
+	}
+	if len(dims) == 0 {
+		delete(props, "loadedDimensions")
+	} else {
+		props["loadedDimensions"] = dims
+	}
+	aq.modelProps = props
+	return nil
+}
+
 // setMaxDatapoints sets the model maxDataPoints if it's missing or invalid
 func (aq *AlertQuery) setMaxDatapoints() error {
 	if aq.modelProps == nil {
@@ -7,8 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
 )
 
 func TestAlertQuery(t *testing.T) {
@@ -281,3 +284,45 @@ func TestAlertQuery_GetQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestAlertQuery_SetLoadedDimensions(t *testing.T) {
+	dims := []data.Labels{{"host": "a"}}
+
+	t.Run("sets loaded dimensions on threshold expressions", func(t *testing.T) {
+		original := AlertQuery{
+			RefID:         "C",
+			DatasourceUID: expr.DatasourceUID,
+			Model:         json.RawMessage(`{"type": "threshold", "expression": "$B", "evaluator": {"type": "gt", "params": [90]}}`),
+		}
+		require.NoError(t, original.setModelProps())
+		aq := original
+		require.NoError(t, aq.SetLoadedDimensions(dims))
+
+		model, err := aq.GetModel()
+		require.NoError(t, err)
+		require.JSONEq(t, `[{"host": "a"}]`, string(unmarshalModel(t, model)["loadedDimensions"]))
+
+		_, ok := original.modelProps["loadedDimensions"]
+		require.False(t, ok, "the original query must not be modified")
+	})
+
+	t.Run("ignores other queries", func(t *testing.T) {
+		for _, aq := range []AlertQuery{
+			{RefID: "A", DatasourceUID: "abc", Model: json.RawMessage(`{"type": "threshold"}`)},
+			{RefID: "B", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "math", "expression": "$A"}`)},
+		} {
+			require.NoError(t, aq.SetLoadedDimensions(dims))
+			model, err := aq.GetModel()
+			require.NoError(t, err)
+			_, ok := unmarshalModel(t, model)["loadedDimensions"]
+			require.False(t, ok)
+		}
+	})
+}
+
+func unmarshalModel(t *testing.T, b []byte) map[string]json.RawMessage {
+	t.Helper()
+	m := map[string]json.RawMessage{}
+	require.NoError(t, json.Unmarshal(b, &m))
+	return m
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -22,6 +23,8 @@ var (
 	ErrRuleGroupNamespaceNotFound         = errors.New("rule group not found under this namespace")
 	ErrAlertRuleFailedValidation          = errors.New("invalid alert rule")
 	ErrAlertRuleUniqueConstraintViolation = errors.New("a conflicting alert rule is found: rule title under the same organisation and folder should be unique")
+	// ErrAlertRuleVersionNotFound is an error for an unknown version of an alert rule.
+	ErrAlertRuleVersionNotFound = errors.New("could not find alert rule version")
 )
 
 type NoDataState string
@@ -219,6 +222,53 @@ type AlertRuleVersion struct {
 	Labels      map[string]string
 }
 
+// ToAlertRule returns the alert rule as it was at this version.
+// The ID of the rule is not stored in the version and is left empty.
+func (v *AlertRuleVersion) ToAlertRule() *AlertRule {
+	rule := &AlertRule{
+		OrgID:           v.RuleOrgID,
+		Title:           v.Title,
+		Condition:       v.Condition,
+		Data:            v.Data,
+		Updated:         v.Created,
+		IntervalSeconds: v.IntervalSeconds,
+		Version:         v.Version,
+		UID:             v.RuleUID,
+		NamespaceUID:    v.RuleNamespaceUID,
+		RuleGroup:       v.RuleGroup,
+		NoDataState:     v.NoDataState,
+		ExecErrState:    v.ExecErrState,
+		For:             v.For,
+		Annotations:     v.Annotations,
+		Labels:          v.Labels,
+	}
+	if dashUID, ok := v.Annotations[DashboardUIDAnnotation]; ok {
+		if panelID, err := strconv.ParseInt(v.Annotations[PanelIDAnnotation], 10, 64); err == nil {
+			rule.DashboardUID = &dashUID
+			rule.PanelID = &panelID
+		}
+	}
+	return rule
+}
+
+// ListAlertRuleVersionsQuery is the query for listing the versions of an alert rule, newest first.
+type ListAlertRuleVersionsQuery struct {
+	OrgID   int64
+	RuleUID string
+
+	Result []*AlertRuleVersion
+}
+
+// GetAlertRuleVersionQuery is the query for retrieving a version of an alert rule.
+// It returns ErrAlertRuleVersionNotFound if the version does not exist.
+type GetAlertRuleVersionQuery struct {
+	OrgID   int64
+	RuleUID string
+	Version int64
+
+	Result *AlertRuleVersion
+}
+
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
 type GetAlertRuleByUIDQuery struct {
 	UID   string
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+)
+
+// AlertStateHistoryEntry represents a change of the state of an alert instance.
+type AlertStateHistoryEntry struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	RuleOrgID     int64  `xorm:"rule_org_id"`
+	RuleUID       string `xorm:"rule_uid"`
+	Labels        InstanceLabels
+	LabelsHash    string
+	PreviousState InstanceStateType
+	CurrentState  InstanceStateType
+	// Values contains the RefID and value of reduce and math expressions at the time of the evaluation.
+	// The value is nil if it was not a finite number.
+	Values      map[string]*float64 `xorm:"evaluation_values"`
+	Error       string
+	EvaluatedAt time.Time
+}
+
+// ListAlertStateHistoryQuery is the query for listing the state changes of the alert instances of a rule, oldest first.
+type ListAlertStateHistoryQuery struct {
+	OrgID   int64
+	RuleUID string
+	// Matchers filter the alert instances by their labels. All matchers must match.
+	Matchers []*labels.Matcher
+	// From and To limit the time range of the evaluations. The zero value means that the range is not limited.
+	From time.Time
+	To   time.Time
+	// Limit is the maximum number of entries to return. Zero means no limit.
+	// If there are more entries, the most recent ones are returned.
+	Limit int
+
+	Result []*AlertStateHistoryEntry
+}
+
+// Matches returns true if the labels of the entry match all matchers of the query.
+func (q *ListAlertStateHistoryQuery) Matches(entry *AlertStateHistoryEntry) bool {
+	for _, m := range q.Matchers {
+		if !m.Matches(entry.Labels[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
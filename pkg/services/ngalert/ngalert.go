@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
@@ -33,7 +34,7 @@ import (
 func ProvideService(cfg *setting.Cfg, dataSourceCache datasources.CacheService, routeRegister routing.RouteRegister,
 	sqlStore *sqlstore.SQLStore, kvStore kvstore.KVStore, expressionService *expr.Service, dataProxy *datasourceproxy.DataSourceProxyService,
 	quotaService *quota.QuotaService, secretsService secrets.Service, notificationService notifications.Service, m *metrics.NGAlert,
-	folderService dashboards.FolderService, ac accesscontrol.AccessControl) (*AlertNG, error) {
+	folderService dashboards.FolderService, ac accesscontrol.AccessControl, serverLockService *serverlock.ServerLockService) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                 cfg,
 		DataSourceCache:     dataSourceCache,
@@ -49,6 +50,7 @@ func ProvideService(cfg *setting.Cfg, dataSourceCache datasources.CacheService,
 		NotificationService: notificationService,
 		folderService:       folderService,
 		accesscontrol:       ac,
+		ServerLockService:   serverLockService,
 	}
 
 	if ng.IsDisabled() {
@@ -75,6 +77,7 @@ type AlertNG struct {
 	SecretsService      secrets.Service
 	Metrics             *metrics.NGAlert
 	NotificationService notifications.Service
+	ServerLockService   *serverlock.ServerLockService
 	Log                 log.Logger
 	schedule            schedule.ScheduleService
 	stateManager        *state.Manager
@@ -124,6 +127,11 @@ func (ng *AlertNG) init() error {
 		AdminConfigPollInterval: ng.Cfg.UnifiedAlerting.AdminConfigPollInterval,
 		DisabledOrgs:            ng.Cfg.UnifiedAlerting.DisabledOrgs,
 		MinRuleInterval:         ng.Cfg.UnifiedAlerting.MinInterval,
+
+		ShardedEvaluation:         ng.Cfg.UnifiedAlerting.HAShardedEvaluation,
+		SchedulerMemberStore:      store,
+		ShardingHeartbeatInterval: ng.Cfg.UnifiedAlerting.HAShardingHeartbeatInterval,
+		ShardingMemberTimeout:     ng.Cfg.UnifiedAlerting.HAShardingMemberTimeout,
 	}
 
 	appUrl, err := url.Parse(ng.Cfg.AppURL)
@@ -131,7 +139,7 @@ func (ng *AlertNG) init() error {
 		ng.Log.Error("Failed to parse application URL. Continue without it.", "error", err)
 		appUrl = nil
 	}
-	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, ng.SQLStore)
+	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, store, ng.SQLStore)
 	scheduler := schedule.NewScheduler(schedCfg, ng.ExpressionService, appUrl, stateManager)
 
 	ng.stateManager = stateManager
@@ -140,6 +148,11 @@ func (ng *AlertNG) init() error {
 	// Provisioning
 	policyService := provisioning.NewNotificationPolicyService(store, store, store, ng.Log)
 	contactPointService := provisioning.NewContactPointService(store, ng.SecretsService, store, store, ng.Log)
+	templateService := provisioning.NewTemplateService(store, store, store, ng.Log)
+	muteTimingService := provisioning.NewMuteTimingService(store, store, store, ng.Log)
+	alertRuleService := provisioning.NewAlertRuleService(store, store, store,
+		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
+		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log)
 
 	api := api.API{
 		Cfg:                  ng.Cfg,
@@ -152,6 +165,7 @@ func (ng *AlertNG) init() error {
 		SecretsService:       ng.SecretsService,
 		TransactionManager:   store,
 		InstanceStore:        store,
+		StateHistoryStore:    store,
 		RuleStore:            store,
 		AlertingStore:        store,
 		AdminConfigStore:     store,
@@ -160,6 +174,10 @@ func (ng *AlertNG) init() error {
 		AccessControl:        ng.accesscontrol,
 		Policies:             policyService,
 		ContactPointService:  contactPointService,
+		Templates:            templateService,
+		MuteTimings:          muteTimingService,
+		AlertRules:           alertRuleService,
+		ProvenanceStore:      store,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -169,7 +187,10 @@ func (ng *AlertNG) init() error {
 // Run starts the scheduler and Alertmanager.
 func (ng *AlertNG) Run(ctx context.Context) error {
 	ng.Log.Debug("ngalert starting")
-	ng.stateManager.Warm(ctx)
+	// With sharded evaluation, the scheduler loads the states of the rules it evaluates.
+	if !ng.Cfg.UnifiedAlerting.HAShardedEvaluation {
+		ng.stateManager.Warm(ctx)
+	}
 
 	children, subCtx := errgroup.WithContext(ctx)
 
@@ -181,6 +202,11 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.MultiOrgAlertmanager.Run(subCtx)
 	})
+	if ng.Cfg.UnifiedAlerting.StateHistoryRetention > 0 {
+		children.Go(func() error {
+			return ng.stateManager.CleanStateHistory(subCtx, ng.Cfg.UnifiedAlerting.StateHistoryRetention, ng.ServerLockService)
+		})
+	}
 	return children.Wait()
 }
 
@@ -0,0 +1,313 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type AlertRuleService struct {
+	defaultIntervalSeconds int64
+	baseIntervalSeconds    int64
+	ruleStore              RuleStore
+	provenanceStore        ProvisioningStore
+	xact                   TransactionManager
+	log                    log.Logger
+}
+
+func NewAlertRuleService(ruleStore RuleStore, provenanceStore ProvisioningStore, xact TransactionManager,
+	defaultIntervalSeconds int64, baseIntervalSeconds int64, log log.Logger) *AlertRuleService {
+	return &AlertRuleService{
+		defaultIntervalSeconds: defaultIntervalSeconds,
+		baseIntervalSeconds:    baseIntervalSeconds,
+		ruleStore:              ruleStore,
+		provenanceStore:        provenanceStore,
+		xact:                   xact,
+		log:                    log,
+	}
+}
+
+// GetAlertRule returns the alert rule with its provenance. It returns models.ErrAlertRuleNotFound if there is no such rule.
+func (service *AlertRuleService) GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (models.AlertRule, models.Provenance, error) {
+	rule, err := service.getAlertRule(ctx, orgID, ruleUID)
+	if err != nil {
+		return models.AlertRule{}, models.ProvenanceNone, err
+	}
+	provenance, err := service.provenanceStore.GetProvenance(ctx, rule)
+	if err != nil {
+		return models.AlertRule{}, models.ProvenanceNone, err
+	}
+	return *rule, provenance, nil
+}
+
+// CreateAlertRule creates the alert rule in the rule group of the folder. The rule gets the interval of the rule group
+// or the default interval if the group does not exist yet. A UID is generated if the rule does not have one.
+func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
+	if rule.UID == "" {
+		rule.UID = util.GenerateShortUID()
+	} else if !util.IsValidShortUID(rule.UID) || util.IsShortUIDTooLong(rule.UID) {
+		return models.AlertRule{}, fmt.Errorf("%w: invalid UID '%s'", ErrValidation, rule.UID)
+	}
+	if err := validateAlertRule(&rule); err != nil {
+		return models.AlertRule{}, err
+	}
+
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		_, err := service.getAlertRule(ctx, rule.OrgID, rule.UID)
+		if err == nil {
+			return fmt.Errorf("%w: an alert rule with the UID '%s' already exists", ErrValidation, rule.UID)
+		}
+		if !errors.Is(err, models.ErrAlertRuleNotFound) {
+			return err
+		}
+
+		interval, err := service.getRuleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+		if err != nil {
+			return err
+		}
+		rule.IntervalSeconds = interval
+
+		if err := service.ruleStore.UpsertAlertRules(ctx, []store.UpsertRule{{New: rule}}); err != nil {
+			return err
+		}
+		// the store sets the ID and the version on its own copy of the rule, read back the stored rule.
+		stored, err := service.getAlertRule(ctx, rule.OrgID, rule.UID)
+		if err != nil {
+			return err
+		}
+		rule = *stored
+		return service.provenanceStore.SetProvenance(ctx, &rule, provenance)
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return rule, nil
+}
+
+// UpdateAlertRule replaces the alert rule that has the UID of the given rule. It returns models.ErrAlertRuleNotFound if there is no such rule
+// and ErrProvenanceMismatch if the rule was provisioned with another provenance.
+func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
+	if err := validateAlertRule(&rule); err != nil {
+		return models.AlertRule{}, err
+	}
+
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		existing, err := service.getAlertRule(ctx, rule.OrgID, rule.UID)
+		if err != nil {
+			return err
+		}
+
+		storedProvenance, err := service.provenanceStore.GetProvenance(ctx, existing)
+		if err != nil {
+			return err
+		}
+		if err := checkProvenance(storedProvenance, provenance); err != nil {
+			return err
+		}
+
+		rule.ID = existing.ID
+		rule.IntervalSeconds = existing.IntervalSeconds
+		rule.DashboardUID = existing.DashboardUID
+		rule.PanelID = existing.PanelID
+		if rule.NamespaceUID != existing.NamespaceUID || rule.RuleGroup != existing.RuleGroup {
+			interval, err := service.getRuleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+			if err != nil {
+				return err
+			}
+			rule.IntervalSeconds = interval
+		}
+
+		if err := service.ruleStore.UpsertAlertRules(ctx, []store.UpsertRule{{Existing: existing, New: rule}}); err != nil {
+			return err
+		}
+		stored, err := service.getAlertRule(ctx, rule.OrgID, rule.UID)
+		if err != nil {
+			return err
+		}
+		rule = *stored
+		return service.provenanceStore.SetProvenance(ctx, &rule, provenance)
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return rule, nil
+}
+
+// DeleteAlertRule deletes the alert rule. It returns ErrProvenanceMismatch if the rule was provisioned with another provenance.
+func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance models.Provenance) error {
+	rule := &models.AlertRule{
+		OrgID: orgID,
+		UID:   ruleUID,
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		storedProvenance, err := service.provenanceStore.GetProvenance(ctx, rule)
+		if err != nil {
+			return err
+		}
+		if err := checkProvenance(storedProvenance, provenance); err != nil {
+			return err
+		}
+		if err := service.ruleStore.DeleteAlertRulesByUID(ctx, orgID, ruleUID); err != nil {
+			return err
+		}
+		return service.provenanceStore.DeleteProvenance(ctx, rule)
+	})
+}
+
+// GetRuleGroup returns the rule group with its rules. It returns ErrNotFound if the group has no rules.
+func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64, folderUID, group string) (definitions.AlertRuleGroup, error) {
+	rules, err := service.getRuleGroupRules(ctx, orgID, folderUID, group)
+	if err != nil {
+		return definitions.AlertRuleGroup{}, err
+	}
+	if len(rules) == 0 {
+		return definitions.AlertRuleGroup{}, fmt.Errorf("%w: rule group '%s' in folder '%s'", ErrNotFound, group, folderUID)
+	}
+
+	provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return definitions.AlertRuleGroup{}, err
+	}
+
+	result := definitions.AlertRuleGroup{
+		Title:     group,
+		FolderUID: folderUID,
+		Interval:  rules[0].IntervalSeconds,
+		Rules:     make([]definitions.ProvisionedAlertRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		result.Rules = append(result.Rules, definitions.NewAlertRule(*rule, provenances[rule.UID]))
+	}
+	return result, nil
+}
+
+// UpdateRuleGroup sets the evaluation interval of all the rules in the rule group. It returns ErrNotFound if the group has no rules
+// and ErrProvenanceMismatch if any rule of the group was provisioned with another provenance.
+func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, folderUID, group string, intervalSeconds int64, provenance models.Provenance) error {
+	if intervalSeconds <= 0 || intervalSeconds%service.baseIntervalSeconds != 0 {
+		return fmt.Errorf("%w: interval (%ds) should be non-zero and divided exactly by the scheduler interval (%ds)", ErrValidation, intervalSeconds, service.baseIntervalSeconds)
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		rules, err := service.getRuleGroupRules(ctx, orgID, folderUID, group)
+		if err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return fmt.Errorf("%w: rule group '%s' in folder '%s'", ErrNotFound, group, folderUID)
+		}
+
+		provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			if err := checkProvenance(provenances[rule.UID], provenance); err != nil {
+				return fmt.Errorf("alert rule '%s': %w", rule.UID, err)
+			}
+		}
+
+		updates := make([]store.UpsertRule, 0, len(rules))
+		for _, rule := range rules {
+			if rule.IntervalSeconds == intervalSeconds {
+				continue
+			}
+			newRule := *rule
+			newRule.IntervalSeconds = intervalSeconds
+			updates = append(updates, store.UpsertRule{
+				Existing: rule,
+				New:      newRule,
+			})
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return service.ruleStore.UpsertAlertRules(ctx, updates)
+	})
+}
+
+// getAlertRule returns the alert rule or models.ErrAlertRuleNotFound if there is no such rule.
+func (service *AlertRuleService) getAlertRule(ctx context.Context, orgID int64, ruleUID string) (*models.AlertRule, error) {
+	query := &models.GetAlertRuleByUIDQuery{
+		OrgID: orgID,
+		UID:   ruleUID,
+	}
+	if err := service.ruleStore.GetAlertRuleByUID(ctx, query); err != nil {
+		return nil, err
+	}
+	if query.Result == nil {
+		return nil, models.ErrAlertRuleNotFound
+	}
+	return query.Result, nil
+}
+
+func (service *AlertRuleService) getRuleGroupRules(ctx context.Context, orgID int64, folderUID, group string) ([]*models.AlertRule, error) {
+	query := &models.GetAlertRulesQuery{
+		OrgID:        orgID,
+		NamespaceUID: folderUID,
+		RuleGroup:    &group,
+	}
+	if err := service.ruleStore.GetAlertRules(ctx, query); err != nil {
+		return nil, err
+	}
+	return query.Result, nil
+}
+
+// getRuleGroupInterval returns the interval of the rules in the rule group or the default interval if the group has no rules.
+func (service *AlertRuleService) getRuleGroupInterval(ctx context.Context, orgID int64, folderUID, group string) (int64, error) {
+	rules, err := service.getRuleGroupRules(ctx, orgID, folderUID, group)
+	if err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 {
+		return service.defaultIntervalSeconds, nil
+	}
+	return rules[0].IntervalSeconds, nil
+}
+
+// validateAlertRule checks the fields of the rule that the store does not validate and sets the defaults of the optional ones.
+func validateAlertRule(rule *models.AlertRule) error {
+	if rule.Title == "" {
+		return fmt.Errorf("%w: title is empty", ErrValidation)
+	}
+	if rule.NamespaceUID == "" {
+		return fmt.Errorf("%w: folder UID is empty", ErrValidation)
+	}
+	if rule.RuleGroup == "" {
+		return fmt.Errorf("%w: rule group is empty", ErrValidation)
+	}
+	if len(rule.Data) == 0 {
+		return fmt.Errorf("%w: no queries or expressions are found", ErrValidation)
+	}
+
+	conditionFound := false
+	for _, query := range rule.Data {
+		if query.RefID == rule.Condition {
+			conditionFound = true
+			break
+		}
+	}
+	if !conditionFound {
+		return fmt.Errorf("%w: condition '%s' does not match any query or expression", ErrValidation, rule.Condition)
+	}
+
+	if rule.NoDataState == "" {
+		rule.NoDataState = models.NoData
+	} else if _, err := models.NoDataStateFromString(string(rule.NoDataState)); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if rule.ExecErrState == "" {
+		rule.ExecErrState = models.AlertingErrState
+	} else if _, err := models.ErrStateFromString(string(rule.ExecErrState)); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if rule.For < 0 {
+		return fmt.Errorf("%w: for must not be negative", ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package provisioning
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestAlertRuleService(t *testing.T) {
+	orgID := rand.Int63()
+
+	t.Run("service creates rule with generated UID, default interval and provenance", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rule := createTestAlertRule(orgID)
+		rule.UID = ""
+
+		created, err := sut.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, created.UID)
+		require.NotZero(t, created.ID)
+		require.Equal(t, int64(1), created.Version)
+		require.Equal(t, sut.defaultIntervalSeconds, created.IntervalSeconds)
+		upserts := getRecordedUpserts(ruleStore)
+		require.Len(t, upserts, 1)
+		require.Nil(t, upserts[0].Existing)
+		require.Equal(t, created.UID, upserts[0].New.UID)
+		provenance, err := sut.provenanceStore.GetProvenance(context.Background(), &created)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+	})
+
+	t.Run("service creates rule with the interval of the existing rule group", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		existing := createTestAlertRule(orgID)
+		existing.IntervalSeconds = 120
+		ruleStore.PutRule(context.Background(), &existing)
+		rule := createTestAlertRule(orgID)
+		rule.NamespaceUID = existing.NamespaceUID
+		rule.RuleGroup = existing.RuleGroup
+
+		created, err := sut.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Equal(t, rule.UID, created.UID)
+		require.Equal(t, int64(120), created.IntervalSeconds)
+	})
+
+	t.Run("service rejects invalid rules", func(t *testing.T) {
+		sut, _ := createAlertRuleServiceSut(t)
+		testCases := map[string]func(rule *models.AlertRule){
+			"empty title":       func(rule *models.AlertRule) { rule.Title = "" },
+			"empty folder":      func(rule *models.AlertRule) { rule.NamespaceUID = "" },
+			"empty group":       func(rule *models.AlertRule) { rule.RuleGroup = "" },
+			"unknown condition": func(rule *models.AlertRule) { rule.Condition = "unknown" },
+			"invalid no data":   func(rule *models.AlertRule) { rule.NoDataState = "invalid" },
+			"invalid UID":       func(rule *models.AlertRule) { rule.UID = "in/valid" },
+		}
+		for name, mutate := range testCases {
+			t.Run(name, func(t *testing.T) {
+				rule := createTestAlertRule(orgID)
+				mutate(&rule)
+				_, err := sut.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+				require.ErrorIs(t, err, ErrValidation)
+			})
+		}
+	})
+
+	t.Run("service rejects rules with existing UID", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rule := createTestAlertRule(orgID)
+		ruleStore.PutRule(context.Background(), &rule)
+
+		_, err := sut.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("service updates rule and keeps the interval of the group", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		existing := createTestAlertRule(orgID)
+		existing.IntervalSeconds = 120
+		ruleStore.PutRule(context.Background(), &existing)
+		rule := existing
+		rule.Title = "updated"
+		rule.IntervalSeconds = 0
+
+		updated, err := sut.UpdateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Equal(t, "updated", updated.Title)
+		require.Equal(t, int64(120), updated.IntervalSeconds)
+		require.Equal(t, existing.ID, updated.ID)
+		require.Equal(t, existing.Version+1, updated.Version)
+		upserts := getRecordedUpserts(ruleStore)
+		require.Len(t, upserts, 1)
+		require.Equal(t, existing.ID, upserts[0].Existing.ID)
+	})
+
+	t.Run("service returns not found when updating missing rule", func(t *testing.T) {
+		sut, _ := createAlertRuleServiceSut(t)
+
+		_, err := sut.UpdateAlertRule(context.Background(), createTestAlertRule(orgID), models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+
+	t.Run("service does not change rules provisioned from files", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rule := createTestAlertRule(orgID)
+		ruleStore.PutRule(context.Background(), &rule)
+		require.NoError(t, sut.provenanceStore.SetProvenance(context.Background(), &rule, models.ProvenanceFile))
+
+		_, err := sut.UpdateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		err = sut.DeleteAlertRule(context.Background(), orgID, rule.UID, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+	})
+
+	t.Run("service deletes rule and its provenance", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rule := createTestAlertRule(orgID)
+		ruleStore.PutRule(context.Background(), &rule)
+		require.NoError(t, sut.provenanceStore.SetProvenance(context.Background(), &rule, models.ProvenanceAPI))
+
+		err := sut.DeleteAlertRule(context.Background(), orgID, rule.UID, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, _, err = sut.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+		provenance, err := sut.provenanceStore.GetProvenance(context.Background(), &rule)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+	})
+
+	t.Run("service updates the interval of all rules in the group", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rule1 := createTestAlertRule(orgID)
+		rule2 := createTestAlertRule(orgID)
+		rule2.NamespaceUID = rule1.NamespaceUID
+		rule2.RuleGroup = rule1.RuleGroup
+		ruleStore.PutRule(context.Background(), &rule1, &rule2)
+
+		err := sut.UpdateRuleGroup(context.Background(), orgID, rule1.NamespaceUID, rule1.RuleGroup, 180, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		upserts := getRecordedUpserts(ruleStore)
+		require.Len(t, upserts, 2)
+		for _, upsert := range upserts {
+			require.Equal(t, int64(180), upsert.New.IntervalSeconds)
+		}
+	})
+
+	t.Run("service does not change the interval of groups with rules provisioned from files", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rule1 := createTestAlertRule(orgID)
+		rule2 := createTestAlertRule(orgID)
+		rule2.NamespaceUID = rule1.NamespaceUID
+		rule2.RuleGroup = rule1.RuleGroup
+		ruleStore.PutRule(context.Background(), &rule1, &rule2)
+		require.NoError(t, sut.provenanceStore.SetProvenance(context.Background(), &rule2, models.ProvenanceFile))
+
+		err := sut.UpdateRuleGroup(context.Background(), orgID, rule1.NamespaceUID, rule1.RuleGroup, 180, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+		require.Empty(t, getRecordedUpserts(ruleStore))
+
+		err = sut.UpdateRuleGroup(context.Background(), orgID, rule1.NamespaceUID, rule1.RuleGroup, 180, models.ProvenanceFile)
+		require.NoError(t, err)
+		require.Len(t, getRecordedUpserts(ruleStore), 2)
+	})
+
+	t.Run("service rejects invalid rule group intervals", func(t *testing.T) {
+		sut, _ := createAlertRuleServiceSut(t)
+
+		for _, interval := range []int64{0, -10, 15} {
+			err := sut.UpdateRuleGroup(context.Background(), orgID, "folder", "group", interval, models.ProvenanceAPI)
+			require.ErrorIs(t, err, ErrValidation)
+		}
+	})
+
+	t.Run("service returns not found for empty rule groups", func(t *testing.T) {
+		sut, _ := createAlertRuleServiceSut(t)
+
+		_, err := sut.GetRuleGroup(context.Background(), orgID, "folder", "group")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		err = sut.UpdateRuleGroup(context.Background(), orgID, "folder", "group", 60, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func createAlertRuleServiceSut(t *testing.T) (*AlertRuleService, *store.FakeRuleStore) {
+	ruleStore := store.NewFakeRuleStore(t)
+	// store the upserted rules as the database does, so that the service can read them back.
+	ruleStore.Hook = func(cmd interface{}) error {
+		upserts, ok := cmd.([]store.UpsertRule)
+		if !ok {
+			return nil
+		}
+		for _, upsert := range upserts {
+			rule := upsert.New
+			if upsert.Existing == nil {
+				rule.ID = rand.Int63()
+				rule.Version = 1
+			} else {
+				rule.ID = upsert.Existing.ID
+				rule.Version = upsert.Existing.Version + 1
+			}
+			rules := ruleStore.Rules[rule.OrgID]
+			replaced := false
+			for idx, r := range rules {
+				if r.UID == rule.UID {
+					rules[idx] = &rule
+					replaced = true
+				}
+			}
+			if !replaced {
+				ruleStore.Rules[rule.OrgID] = append(rules, &rule)
+			}
+		}
+		return nil
+	}
+	return &AlertRuleService{
+		defaultIntervalSeconds: 60,
+		baseIntervalSeconds:    10,
+		ruleStore:              ruleStore,
+		provenanceStore:        newFakeProvisioningStore(),
+		xact:                   newNopTransactionManager(),
+		log:                    log.NewNopLogger(),
+	}, ruleStore
+}
+
+func createTestAlertRule(orgID int64) models.AlertRule {
+	rule := models.AlertRuleGen(func(rule *models.AlertRule) {
+		rule.OrgID = orgID
+		rule.Condition = rule.Data[0].RefID
+	})()
+	return *rule
+}
+
+func getRecordedUpserts(ruleStore *store.FakeRuleStore) []store.UpsertRule {
+	var result []store.UpsertRule
+	for _, cmd := range ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+		upserts, ok := cmd.([]store.UpsertRule)
+		return upserts, ok
+	}) {
+		result = append(result, cmd.([]store.UpsertRule)...)
+	}
+	return result
+}
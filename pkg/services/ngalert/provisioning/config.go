@@ -0,0 +1,59 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// cfgRevision is an Alertmanager configuration along with what is needed to save it back without overwriting concurrent changes.
+type cfgRevision struct {
+	cfg              *definitions.PostableUserConfig
+	concurrencyToken string
+	version          string
+}
+
+func getLastConfiguration(ctx context.Context, orgID int64, store AMConfigStore) (*cfgRevision, error) {
+	q := models.GetLatestAlertmanagerConfigurationQuery{
+		OrgID: orgID,
+	}
+	if err := store.GetLatestAlertmanagerConfiguration(ctx, &q); err != nil {
+		return nil, err
+	}
+
+	cfg, err := DeserializeAlertmanagerConfig([]byte(q.Result.AlertmanagerConfiguration))
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfgRevision{
+		cfg:              cfg,
+		concurrencyToken: q.Result.ConfigurationHash,
+		version:          q.Result.ConfigurationVersion,
+	}, nil
+}
+
+func saveConfiguration(ctx context.Context, orgID int64, store AMConfigStore, rev *cfgRevision) error {
+	serialized, err := SerializeAlertmanagerConfig(*rev.cfg)
+	if err != nil {
+		return err
+	}
+	return store.UpdateAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		FetchedConfigurationHash:  rev.concurrencyToken,
+		ConfigurationVersion:      rev.version,
+		Default:                   false,
+		OrgID:                     orgID,
+	})
+}
+
+// checkProvenance returns ErrProvenanceMismatch if the object stored with the provenance cannot be changed with the requested one.
+// Objects that were not provisioned can be changed with any provenance.
+func checkProvenance(stored, requested models.Provenance) error {
+	if stored != requested && stored != models.ProvenanceNone {
+		return fmt.Errorf("%w: cannot change provenance from '%s' to '%s'", ErrProvenanceMismatch, stored, requested)
+	}
+	return nil
+}
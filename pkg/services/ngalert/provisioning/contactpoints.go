@@ -132,7 +132,13 @@ func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID in
 		extractedSecrets[k] = encryptedValue
 	}
 
-	contactPoint.UID = util.GenerateShortUID()
+	if contactPoint.UID == "" {
+		contactPoint.UID = util.GenerateShortUID()
+	} else if !util.IsValidShortUID(contactPoint.UID) || util.IsShortUIDTooLong(contactPoint.UID) {
+		return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: invalid UID '%s'", ErrValidation, contactPoint.UID)
+	} else if _, ok := cfg.GetGrafanaReceiverMap()[contactPoint.UID]; ok {
+		return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: a contact point with the UID '%s' already exists", ErrValidation, contactPoint.UID)
+	}
 	grafanaReceiver := &apimodels.PostableGrafanaReceiver{
 		UID:                   contactPoint.UID,
 		Name:                  contactPoint.Name,
@@ -0,0 +1,12 @@
+package provisioning
+
+import "errors"
+
+var (
+	// ErrValidation is returned when the provisioned object is not valid.
+	ErrValidation = errors.New("invalid object specification")
+	// ErrNotFound is returned when the provisioned object does not exist.
+	ErrNotFound = errors.New("object not found")
+	// ErrProvenanceMismatch is returned when an object is changed with a provenance other than the one it was provisioned with.
+	ErrProvenanceMismatch = errors.New("provenance mismatch")
+)
@@ -0,0 +1,204 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type MuteTimingService struct {
+	config AMConfigStore
+	prov   ProvisioningStore
+	xact   TransactionManager
+	log    log.Logger
+}
+
+func NewMuteTimingService(config AMConfigStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *MuteTimingService {
+	return &MuteTimingService{
+		config: config,
+		prov:   prov,
+		xact:   xact,
+		log:    log,
+	}
+}
+
+// GetMuteTimings returns the mute timings of the organization in the order they are defined in the configuration.
+func (m *MuteTimingService) GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error) {
+	revision, err := getLastConfiguration(ctx, orgID, m.config)
+	if err != nil {
+		return nil, err
+	}
+
+	provenances, err := m.prov.GetProvenances(ctx, orgID, (&definitions.MuteTimeInterval{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]definitions.MuteTimeInterval, 0, len(revision.cfg.AlertmanagerConfig.MuteTimeIntervals))
+	for _, interval := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		result = append(result, definitions.MuteTimeInterval{
+			MuteTimeInterval: interval,
+			Provenance:       provenances[interval.Name],
+		})
+	}
+	return result, nil
+}
+
+// GetMuteTiming returns the mute timing with the given name or ErrNotFound if there is no such mute timing.
+func (m *MuteTimingService) GetMuteTiming(ctx context.Context, orgID int64, name string) (definitions.MuteTimeInterval, error) {
+	revision, err := getLastConfiguration(ctx, orgID, m.config)
+	if err != nil {
+		return definitions.MuteTimeInterval{}, err
+	}
+
+	idx := getMuteTiming(revision, name)
+	if idx < 0 {
+		return definitions.MuteTimeInterval{}, fmt.Errorf("%w: mute timing '%s'", ErrNotFound, name)
+	}
+
+	result := definitions.MuteTimeInterval{
+		MuteTimeInterval: revision.cfg.AlertmanagerConfig.MuteTimeIntervals[idx],
+	}
+	result.Provenance, err = m.prov.GetProvenance(ctx, provenanceOrgAdapter{inner: &result, orgID: orgID})
+	if err != nil {
+		return definitions.MuteTimeInterval{}, err
+	}
+	return result, nil
+}
+
+// CreateMuteTiming adds the mute timing to the configuration. It fails with ErrValidation if a mute timing with the same name exists.
+func (m *MuteTimingService) CreateMuteTiming(ctx context.Context, orgID int64, mt definitions.MuteTimeInterval, p models.Provenance) (definitions.MuteTimeInterval, error) {
+	if err := mt.Validate(); err != nil {
+		return definitions.MuteTimeInterval{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, m.config)
+	if err != nil {
+		return definitions.MuteTimeInterval{}, err
+	}
+
+	if getMuteTiming(revision, mt.Name) >= 0 {
+		return definitions.MuteTimeInterval{}, fmt.Errorf("%w: a mute timing with the name '%s' already exists", ErrValidation, mt.Name)
+	}
+	revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(revision.cfg.AlertmanagerConfig.MuteTimeIntervals, mt.MuteTimeInterval)
+
+	err = m.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := saveConfiguration(ctx, orgID, m.config, revision); err != nil {
+			return err
+		}
+		return m.prov.SetProvenance(ctx, provenanceOrgAdapter{inner: &mt, orgID: orgID}, p)
+	})
+	if err != nil {
+		return definitions.MuteTimeInterval{}, err
+	}
+
+	mt.Provenance = p
+	return mt, nil
+}
+
+// UpdateMuteTiming replaces the time intervals of an existing mute timing. It fails with ErrNotFound if there is no such mute timing.
+func (m *MuteTimingService) UpdateMuteTiming(ctx context.Context, orgID int64, mt definitions.MuteTimeInterval, p models.Provenance) (definitions.MuteTimeInterval, error) {
+	if err := mt.Validate(); err != nil {
+		return definitions.MuteTimeInterval{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	adapter := provenanceOrgAdapter{inner: &mt, orgID: orgID}
+	stored, err := m.prov.GetProvenance(ctx, adapter)
+	if err != nil {
+		return definitions.MuteTimeInterval{}, err
+	}
+	if err := checkProvenance(stored, p); err != nil {
+		return definitions.MuteTimeInterval{}, err
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, m.config)
+	if err != nil {
+		return definitions.MuteTimeInterval{}, err
+	}
+
+	idx := getMuteTiming(revision, mt.Name)
+	if idx < 0 {
+		return definitions.MuteTimeInterval{}, fmt.Errorf("%w: mute timing '%s'", ErrNotFound, mt.Name)
+	}
+	revision.cfg.AlertmanagerConfig.MuteTimeIntervals[idx] = mt.MuteTimeInterval
+
+	err = m.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := saveConfiguration(ctx, orgID, m.config, revision); err != nil {
+			return err
+		}
+		return m.prov.SetProvenance(ctx, adapter, p)
+	})
+	if err != nil {
+		return definitions.MuteTimeInterval{}, err
+	}
+
+	mt.Provenance = p
+	return mt, nil
+}
+
+// DeleteMuteTiming deletes the mute timing. It fails with ErrValidation if the mute timing is used by a notification policy.
+// Deleting a mute timing that does not exist is not an error.
+func (m *MuteTimingService) DeleteMuteTiming(ctx context.Context, orgID int64, name string, p models.Provenance) error {
+	target := definitions.MuteTimeInterval{}
+	target.Name = name
+	adapter := provenanceOrgAdapter{inner: &target, orgID: orgID}
+	stored, err := m.prov.GetProvenance(ctx, adapter)
+	if err != nil {
+		return err
+	}
+	if err := checkProvenance(stored, p); err != nil {
+		return err
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, m.config)
+	if err != nil {
+		return err
+	}
+
+	idx := getMuteTiming(revision, name)
+	if idx < 0 {
+		return nil
+	}
+	if isMuteTimingInUse(name, []*definitions.Route{revision.cfg.AlertmanagerConfig.Route}) {
+		return fmt.Errorf("%w: mute timing '%s' is currently used by a notification policy", ErrValidation, name)
+	}
+	intervals := revision.cfg.AlertmanagerConfig.MuteTimeIntervals
+	revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(intervals[:idx], intervals[idx+1:]...)
+
+	return m.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := saveConfiguration(ctx, orgID, m.config, revision); err != nil {
+			return err
+		}
+		return m.prov.DeleteProvenance(ctx, adapter)
+	})
+}
+
+// getMuteTiming returns the index of the mute timing with the given name in the configuration or -1 if there is no such mute timing.
+func getMuteTiming(rev *cfgRevision, name string) int {
+	for i, interval := range rev.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		if interval.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func isMuteTimingInUse(name string, routes []*definitions.Route) bool {
+	for _, route := range routes {
+		if route == nil {
+			continue
+		}
+		for _, mt := range route.MuteTimeIntervals {
+			if mt == name {
+				return true
+			}
+		}
+		if isMuteTimingInUse(name, route.Routes) {
+			return true
+		}
+	}
+	return false
+}
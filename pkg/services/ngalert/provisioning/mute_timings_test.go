@@ -0,0 +1,118 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestMuteTimingService(t *testing.T) {
+	t.Run("service returns no mute timings if config has none", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+
+		timings, err := sut.GetMuteTimings(context.Background(), 1)
+		require.NoError(t, err)
+
+		require.Empty(t, timings)
+	})
+
+	t.Run("service creates, updates and deletes mute timings", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		timing := createTestMuteTiming()
+
+		created, err := sut.CreateMuteTiming(context.Background(), 1, timing, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, created.Provenance)
+
+		timing.TimeIntervals = nil
+		_, err = sut.UpdateMuteTiming(context.Background(), 1, timing, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		stored, err := sut.GetMuteTiming(context.Background(), 1, timing.Name)
+		require.NoError(t, err)
+		require.Empty(t, stored.TimeIntervals)
+		require.Equal(t, models.ProvenanceAPI, stored.Provenance)
+
+		err = sut.DeleteMuteTiming(context.Background(), 1, timing.Name, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.GetMuteTiming(context.Background(), 1, timing.Name)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("service rejects mute timings with duplicate names", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		timing := createTestMuteTiming()
+		_, err := sut.CreateMuteTiming(context.Background(), 1, timing, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.CreateMuteTiming(context.Background(), 1, timing, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("service returns not found when updating missing mute timing", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+
+		_, err := sut.UpdateMuteTiming(context.Background(), 1, createTestMuteTiming(), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("service does not delete mute timings used by a policy", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		timing := createTestMuteTiming()
+		_, err := sut.CreateMuteTiming(context.Background(), 1, timing, models.ProvenanceAPI)
+		require.NoError(t, err)
+		policies := &NotificationPolicyService{amStore: sut.config, provenanceStore: sut.prov, xact: sut.xact, log: sut.log}
+		tree := createTestRoutingTree()
+		tree.Routes = []*definitions.Route{{Receiver: "a new receiver", MuteTimeIntervals: []string{timing.Name}}}
+		require.NoError(t, policies.UpdatePolicyTree(context.Background(), 1, tree, models.ProvenanceAPI))
+
+		err = sut.DeleteMuteTiming(context.Background(), 1, timing.Name, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("service does not change mute timings provisioned from files", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		timing := createTestMuteTiming()
+		_, err := sut.CreateMuteTiming(context.Background(), 1, timing, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = sut.UpdateMuteTiming(context.Background(), 1, timing, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		err = sut.DeleteMuteTiming(context.Background(), 1, timing.Name, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+	})
+}
+
+func createMuteTimingSvcSut() *MuteTimingService {
+	return &MuteTimingService{
+		config: newFakeAMConfigStore(),
+		prov:   newFakeProvisioningStore(),
+		xact:   newNopTransactionManager(),
+		log:    log.NewNopLogger(),
+	}
+}
+
+func createTestMuteTiming() definitions.MuteTimeInterval {
+	return definitions.MuteTimeInterval{
+		MuteTimeInterval: config.MuteTimeInterval{
+			Name: "weekends",
+			TimeIntervals: []timeinterval.TimeInterval{
+				{
+					Weekdays: []timeinterval.WeekdayRange{
+						{InclusiveRange: timeinterval.InclusiveRange{Begin: 0, End: 0}},
+						{InclusiveRange: timeinterval.InclusiveRange{Begin: 6, End: 6}},
+					},
+				},
+			},
+		},
+	}
+}
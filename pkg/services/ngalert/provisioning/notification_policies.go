@@ -7,6 +7,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 type NotificationPolicyService struct {
@@ -120,6 +121,35 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 	return nil
 }
 
+// ResetPolicyTree restores the notification policy tree of the default Alertmanager configuration and removes its provenance.
+func (nps *NotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
+	defaultCfg, err := DeserializeAlertmanagerConfig([]byte(setting.GetAlertmanagerDefaultConfiguration()))
+	if err != nil {
+		return definitions.Route{}, fmt.Errorf("failed to parse default alertmanager config: %w", err)
+	}
+	route := defaultCfg.AlertmanagerConfig.Route
+
+	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	revision.cfg.AlertmanagerConfig.Route = route
+
+	err = nps.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := saveConfiguration(ctx, orgID, nps.amStore, revision); err != nil {
+			return err
+		}
+		return nps.provenanceStore.DeleteProvenance(ctx, provenanceOrgAdapter{
+			inner: route,
+			orgID: orgID,
+		})
+	})
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	return *route, nil
+}
+
 type provenanceOrgAdapter struct {
 	inner models.ProvisionableInOrg
 	orgID int64
@@ -70,6 +70,23 @@ func TestNotificationPolicyService(t *testing.T) {
 		intercepted := fake.lastSaveCommand
 		require.Equal(t, expectedConcurrencyToken, intercepted.FetchedConfigurationHash)
 	})
+
+	t.Run("service resets policy tree and its provenance", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		newRoute := createTestRoutingTree()
+		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		tree, err := sut.ResetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "grafana-default-email", tree.Receiver)
+
+		updated, err := sut.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, "grafana-default-email", updated.Receiver)
+		require.Empty(t, updated.Routes)
+		require.Equal(t, models.ProvenanceNone, updated.Provenance)
+	})
 }
 
 func createNotificationPolicyServiceSut() *NotificationPolicyService {
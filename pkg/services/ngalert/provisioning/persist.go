@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 )
 
 // AMStore is a store of Alertmanager configurations.
@@ -24,3 +25,11 @@ type ProvisioningStore interface {
 type TransactionManager interface {
 	InTransaction(ctx context.Context, work func(ctx context.Context) error) error
 }
+
+// RuleStore represents the ability to persist and query alert rules.
+type RuleStore interface {
+	GetAlertRuleByUID(ctx context.Context, query *models.GetAlertRuleByUIDQuery) error
+	GetAlertRules(ctx context.Context, query *models.GetAlertRulesQuery) error
+	UpsertAlertRules(ctx context.Context, rule []store.UpsertRule) error
+	DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error
+}
@@ -0,0 +1,141 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type TemplateService struct {
+	config AMConfigStore
+	prov   ProvisioningStore
+	xact   TransactionManager
+	log    log.Logger
+}
+
+func NewTemplateService(config AMConfigStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *TemplateService {
+	return &TemplateService{
+		config: config,
+		prov:   prov,
+		xact:   xact,
+		log:    log,
+	}
+}
+
+// GetTemplates returns the message templates of the organization sorted by name.
+func (t *TemplateService) GetTemplates(ctx context.Context, orgID int64) ([]definitions.MessageTemplate, error) {
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return nil, err
+	}
+
+	provenances, err := t.prov.GetProvenances(ctx, orgID, (&definitions.MessageTemplate{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]definitions.MessageTemplate, 0, len(revision.cfg.TemplateFiles))
+	for name, content := range revision.cfg.TemplateFiles {
+		templates = append(templates, definitions.MessageTemplate{
+			Name:       name,
+			Template:   content,
+			Provenance: provenances[name],
+		})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// GetTemplate returns the message template with the given name or ErrNotFound if there is no such template.
+func (t *TemplateService) GetTemplate(ctx context.Context, orgID int64, name string) (definitions.MessageTemplate, error) {
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+
+	content, ok := revision.cfg.TemplateFiles[name]
+	if !ok {
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: template '%s'", ErrNotFound, name)
+	}
+
+	tmpl := definitions.MessageTemplate{
+		Name:     name,
+		Template: content,
+	}
+	tmpl.Provenance, err = t.prov.GetProvenance(ctx, provenanceOrgAdapter{inner: &tmpl, orgID: orgID})
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+	return tmpl, nil
+}
+
+// SetTemplate creates the message template or replaces the content of an existing one.
+func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl definitions.MessageTemplate, p models.Provenance) (definitions.MessageTemplate, error) {
+	if err := tmpl.Validate(); err != nil {
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	adapter := provenanceOrgAdapter{inner: &tmpl, orgID: orgID}
+	stored, err := t.prov.GetProvenance(ctx, adapter)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+	if err := checkProvenance(stored, p); err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+
+	if revision.cfg.TemplateFiles == nil {
+		revision.cfg.TemplateFiles = map[string]string{}
+	}
+	revision.cfg.TemplateFiles[tmpl.Name] = tmpl.Template
+
+	err = t.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := saveConfiguration(ctx, orgID, t.config, revision); err != nil {
+			return err
+		}
+		return t.prov.SetProvenance(ctx, adapter, p)
+	})
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+
+	tmpl.Provenance = p
+	return tmpl, nil
+}
+
+// DeleteTemplate deletes the message template. Deleting a template that does not exist is not an error.
+func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name string, p models.Provenance) error {
+	adapter := provenanceOrgAdapter{inner: &definitions.MessageTemplate{Name: name}, orgID: orgID}
+	stored, err := t.prov.GetProvenance(ctx, adapter)
+	if err != nil {
+		return err
+	}
+	if err := checkProvenance(stored, p); err != nil {
+		return err
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return err
+	}
+
+	delete(revision.cfg.TemplateFiles, name)
+
+	return t.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := saveConfiguration(ctx, orgID, t.config, revision); err != nil {
+			return err
+		}
+		return t.prov.DeleteProvenance(ctx, adapter)
+	})
+}
@@ -0,0 +1,100 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateService(t *testing.T) {
+	t.Run("service returns no templates if config has none", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+
+		templates, err := sut.GetTemplates(context.Background(), 1)
+		require.NoError(t, err)
+
+		require.Empty(t, templates)
+	})
+
+	t.Run("service creates and updates templates", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		tmpl := createTestTemplate()
+
+		created, err := sut.SetTemplate(context.Background(), 1, tmpl, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, created.Provenance)
+
+		tmpl.Template = "{{ define \"test\" }} updated {{ end }}"
+		_, err = sut.SetTemplate(context.Background(), 1, tmpl, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		templates, err := sut.GetTemplates(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, tmpl.Name, templates[0].Name)
+		require.Equal(t, tmpl.Template, templates[0].Template)
+		require.Equal(t, models.ProvenanceAPI, templates[0].Provenance)
+	})
+
+	t.Run("service rejects invalid templates", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		tmpl := createTestTemplate()
+		tmpl.Template = ""
+
+		_, err := sut.SetTemplate(context.Background(), 1, tmpl, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("service returns not found for missing template", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+
+		_, err := sut.GetTemplate(context.Background(), 1, "missing")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("service deletes templates", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		tmpl := createTestTemplate()
+		_, err := sut.SetTemplate(context.Background(), 1, tmpl, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		err = sut.DeleteTemplate(context.Background(), 1, tmpl.Name, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.GetTemplate(context.Background(), 1, tmpl.Name)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("service does not change templates provisioned from files", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		tmpl := createTestTemplate()
+		_, err := sut.SetTemplate(context.Background(), 1, tmpl, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = sut.SetTemplate(context.Background(), 1, tmpl, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		err = sut.DeleteTemplate(context.Background(), 1, tmpl.Name, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+	})
+}
+
+func createTemplateServiceSut() *TemplateService {
+	return &TemplateService{
+		config: newFakeAMConfigStore(),
+		prov:   newFakeProvisioningStore(),
+		xact:   newNopTransactionManager(),
+		log:    log.NewNopLogger(),
+	}
+}
+
+func createTestTemplate() definitions.MessageTemplate {
+	return definitions.MessageTemplate{
+		Name:     "test",
+		Template: "{{ define \"test\" }} test {{ end }}",
+	}
+}
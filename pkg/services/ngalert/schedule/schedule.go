@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
 
 	"github.com/benbjohnson/clock"
 	"golang.org/x/sync/errgroup"
@@ -99,6 +100,10 @@ type schedule struct {
 	adminConfigPollInterval time.Duration
 	disabledOrgs            map[int64]struct{}
 	minRuleInterval         time.Duration
+
+	// sharder divides the rules between the schedulers that share the database. It is nil if every scheduler
+	// evaluates all the rules.
+	sharder *ruleSharder
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -119,6 +124,12 @@ type SchedulerCfg struct {
 	AdminConfigPollInterval time.Duration
 	DisabledOrgs            map[int64]struct{}
 	MinRuleInterval         time.Duration
+
+	// ShardedEvaluation enables dividing the rules between the schedulers that share the database.
+	ShardedEvaluation         bool
+	SchedulerMemberStore      store.SchedulerMemberStore
+	ShardingHeartbeatInterval time.Duration
+	ShardingMemberTimeout     time.Duration
 }
 
 // NewScheduler returns a new schedule.
@@ -151,6 +162,11 @@ func NewScheduler(cfg SchedulerCfg, expressionService *expr.Service, appURL *url
 		disabledOrgs:            cfg.DisabledOrgs,
 		minRuleInterval:         cfg.MinRuleInterval,
 	}
+	if cfg.ShardedEvaluation {
+		memberID := util.GenerateShortUID()
+		sch.sharder = newRuleSharder(memberID, cfg.SchedulerMemberStore, cfg.C, cfg.ShardingHeartbeatInterval,
+			cfg.ShardingMemberTimeout, cfg.Logger.New("member", memberID), cfg.Metrics)
+	}
 	return &sch
 }
 
@@ -176,6 +192,20 @@ func (sch *schedule) Run(ctx context.Context) error {
 	var wg sync.WaitGroup
 	wg.Add(2)
 
+	if sch.sharder != nil {
+		// Join the members before the first tick so that the rules are divided from the start.
+		if err := sch.sharder.sync(ctx); err != nil {
+			sch.log.Error("unable to join scheduler members, all rules are evaluated until it succeeds", "err", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sch.sharder.run(ctx); err != nil {
+				sch.log.Error("failure while running the scheduler membership", "err", err)
+			}
+		}()
+	}
+
 	go func() {
 		defer wg.Done()
 		if err := sch.schedulePeriodic(ctx); err != nil {
@@ -397,10 +427,22 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 				version  int64
 			}
 
+			// handedOver are the rules that are evaluated by another scheduler now.
+			var handedOver []models.AlertRuleKey
+
 			readyToRun := make([]readyToRunItem, 0)
 			for _, item := range alertRules {
 				key := item.GetKey()
 				itemVersion := item.Version
+
+				if sch.sharder != nil && !sch.sharder.owns(key) {
+					if _, ok := registeredDefinitions[key]; ok {
+						handedOver = append(handedOver, key)
+						delete(registeredDefinitions, key)
+					}
+					continue
+				}
+
 				ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
 
 				// enforce minimum evaluation interval
@@ -412,6 +454,12 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 				invalidInterval := item.IntervalSeconds%int64(sch.baseInterval.Seconds()) != 0
 
 				if newRoutine && !invalidInterval {
+					if sch.sharder != nil {
+						// continue from the states saved by the scheduler that evaluated the rule before.
+						if err := sch.stateManager.WarmRule(ctx, item); err != nil {
+							sch.log.Error("unable to load the saved states of the rule", "key", key, "err", err)
+						}
+					}
 					dispatcherGroup.Go(func() error {
 						return sch.ruleRoutine(ruleInfo.ctx, key, ruleInfo.evalCh, ruleInfo.updateCh)
 					})
@@ -454,6 +502,12 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 				sch.DeleteAlertRule(key)
 			}
 
+			// stop routines of the alert rules that are evaluated by another scheduler now
+			for _, key := range handedOver {
+				sch.log.Debug("alert rule is handed over to another scheduler", "key", key)
+				sch.DeleteAlertRule(key)
+			}
+
 			sch.metrics.SchedulePeriodicDuration.Observe(time.Since(start).Seconds())
 		case <-ctx.Done():
 			waitErr := dispatcherGroup.Wait()
@@ -550,10 +604,15 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 		logger := logger.New("version", r.Version, "attempt", attempt, "now", e.scheduledAt)
 		start := sch.clock.Now()
 
+		queries, err := state.WithLoadedDimensions(r.Data, sch.stateManager.GetStatesForRuleUID(r.OrgID, r.UID))
+		if err != nil {
+			logger.Error("failed to set loaded dimensions on the rule queries", "err", err)
+			return err
+		}
 		condition := models.Condition{
 			Condition: r.Condition,
 			OrgID:     r.OrgID,
-			Data:      r.Data,
+			Data:      queries,
 		}
 		results, err := sch.evaluator.ConditionEval(&condition, e.scheduledAt, sch.expressionService)
 		dur := sch.clock.Now().Sub(start)
@@ -641,6 +700,19 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 				}
 			}()
 		case <-grafanaCtx.Done():
+			if sch.sharder != nil {
+				// The alerts stay active when the rule is still registered because the scheduler is stopping, or when
+				// the rule is handed over. Another scheduler takes over the rule and continues from the saved states.
+				if sch.registry.exists(key) {
+					logger.Debug("stopping alert rule routine")
+					return nil
+				}
+				if !sch.sharder.owns(key) {
+					sch.stateManager.RemoveByRuleUID(key.OrgID, key.UID)
+					logger.Debug("stopping alert rule routine of the rule handed over to another scheduler")
+					return nil
+				}
+			}
 			clearState()
 			logger.Debug("stopping alert rule routine")
 			return nil
@@ -1031,7 +1031,7 @@ func setupScheduler(t *testing.T, rs store.RuleStore, is store.InstanceStore, ac
 		Metrics:                 m.GetSchedulerMetrics(),
 		AdminConfigPollInterval: 10 * time.Minute, // do not poll in unit tests.
 	}
-	st := state.NewManager(schedCfg.Logger, m.GetStateMetrics(), nil, rs, is, mockstore.NewSQLStoreMock())
+	st := state.NewManager(schedCfg.Logger, m.GetStateMetrics(), nil, rs, is, &store.FakeStateHistoryStore{}, mockstore.NewSQLStoreMock())
 	appUrl := &url.URL{
 		Scheme: "http",
 		Host:   "localhost",
@@ -0,0 +1,175 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ringReplicas is the number of points each member gets on the hash ring. More points spread the rules more evenly.
+const ringReplicas = 128
+
+// ruleSharder divides the alert rules between the schedulers that share the database.
+// Each scheduler sends heartbeats to the database and the schedulers that sent a heartbeat recently are placed on
+// a consistent hash ring. A rule is evaluated by the scheduler that owns the position of its key on the ring, so that
+// only a part of the rules move when a scheduler joins or leaves.
+type ruleSharder struct {
+	memberID          string
+	store             store.SchedulerMemberStore
+	clock             clock.Clock
+	heartbeatInterval time.Duration
+	memberTimeout     time.Duration
+	log               log.Logger
+	metrics           *metrics.Scheduler
+
+	mtx  sync.RWMutex
+	ring *hashRing
+}
+
+func newRuleSharder(memberID string, store store.SchedulerMemberStore, c clock.Clock, heartbeatInterval, memberTimeout time.Duration, logger log.Logger, m *metrics.Scheduler) *ruleSharder {
+	return &ruleSharder{
+		memberID:          memberID,
+		store:             store,
+		clock:             c,
+		heartbeatInterval: heartbeatInterval,
+		memberTimeout:     memberTimeout,
+		log:               logger,
+		metrics:           m,
+	}
+}
+
+// owns returns true if the rule should be evaluated by this scheduler. Until the members are known,
+// the scheduler owns all the rules so that no rule stops being evaluated.
+func (s *ruleSharder) owns(key models.AlertRuleKey) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if s.ring == nil {
+		return true
+	}
+	return s.ring.get(ruleHashKey(key)) == s.memberID
+}
+
+// sync sends the heartbeat of this scheduler and rebuilds the hash ring if the members have changed.
+func (s *ruleSharder) sync(ctx context.Context) error {
+	now := s.clock.Now()
+	if err := s.store.HeartbeatSchedulerMember(ctx, s.memberID, now); err != nil {
+		return fmt.Errorf("failed to send heartbeat: %w", err)
+	}
+	if _, err := s.store.DeleteExpiredSchedulerMembers(ctx, now.Add(-s.memberTimeout)); err != nil {
+		s.log.Warn("failed to delete expired scheduler members", "err", err)
+	}
+	members, err := s.store.ListSchedulerMembers(ctx, now.Add(-s.memberTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to list scheduler members: %w", err)
+	}
+	// the heartbeat was just sent, so this scheduler is a member even if the query does not see it yet.
+	if i := sort.SearchStrings(members, s.memberID); i == len(members) || members[i] != s.memberID {
+		members = append(members, s.memberID)
+		sort.Strings(members)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.ring != nil && s.ring.hasMembers(members) {
+		return nil
+	}
+	s.ring = newHashRing(members)
+	s.metrics.ShardingMembers.Set(float64(len(members)))
+	s.log.Info("alert rules are divided between new scheduler members", "members", members)
+	return nil
+}
+
+// run keeps the membership of this scheduler up to date until the context is canceled, and then leaves
+// so that the other schedulers take over its rules without waiting for the member timeout.
+func (s *ruleSharder) run(ctx context.Context) error {
+	ticker := s.clock.Ticker(s.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.sync(ctx); err != nil {
+				s.log.Error("unable to sync scheduler members", "err", err)
+			}
+		case <-ctx.Done():
+			leaveCtx, cancel := context.WithTimeout(context.Background(), s.heartbeatInterval)
+			defer cancel()
+			if err := s.store.DeleteSchedulerMember(leaveCtx, s.memberID); err != nil {
+				s.log.Error("unable to leave scheduler members", "err", err)
+			}
+			return nil
+		}
+	}
+}
+
+func ruleHashKey(key models.AlertRuleKey) uint32 {
+	return hashString(fmt.Sprintf("%d/%s", key.OrgID, key.UID))
+}
+
+func hashString(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// hashRing is a consistent hash ring that maps keys to members.
+type hashRing struct {
+	members []string
+	points  []uint32
+	owners  map[uint32]string
+}
+
+// newHashRing returns a ring of the members. The members must be sorted.
+func newHashRing(members []string) *hashRing {
+	r := &hashRing{
+		members: members,
+		points:  make([]uint32, 0, len(members)*ringReplicas),
+		owners:  make(map[uint32]string, len(members)*ringReplicas),
+	}
+	for _, member := range members {
+		for i := 0; i < ringReplicas; i++ {
+			point := hashString(fmt.Sprintf("%s-%d", member, i))
+			// on collision, the member that comes first keeps the point so that all schedulers build the same ring.
+			if _, ok := r.owners[point]; ok {
+				continue
+			}
+			r.owners[point] = member
+			r.points = append(r.points, point)
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// get returns the member that owns the key, or an empty string if the ring has no members.
+func (r *hashRing) get(key uint32) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= key })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+func (r *hashRing) hasMembers(members []string) bool {
+	if len(r.members) != len(members) {
+		return false
+	}
+	for i := range members {
+		if r.members[i] != members[i] {
+			return false
+		}
+	}
+	return true
+}
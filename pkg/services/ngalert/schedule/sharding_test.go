@@ -0,0 +1,173 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestHashRing(t *testing.T) {
+	keys := make([]uint32, 0, 3000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, ruleHashKey(models.AlertRuleKey{OrgID: 1, UID: fmt.Sprintf("rule-%d", i)}))
+	}
+
+	t.Run("empty ring has no owner", func(t *testing.T) {
+		require.Equal(t, "", newHashRing(nil).get(keys[0]))
+	})
+
+	t.Run("keys are spread between the members", func(t *testing.T) {
+		ring := newHashRing([]string{"a", "b", "c"})
+		owned := map[string]int{}
+		for _, key := range keys {
+			owned[ring.get(key)]++
+		}
+		require.Len(t, owned, 3)
+		for member, count := range owned {
+			require.Greaterf(t, count, len(keys)/5, "member %s owns too few keys", member)
+		}
+	})
+
+	t.Run("only a part of the keys move when a member joins", func(t *testing.T) {
+		before := newHashRing([]string{"a", "b", "c"})
+		after := newHashRing([]string{"a", "b", "c", "d"})
+		moved := 0
+		for _, key := range keys {
+			owner := after.get(key)
+			if owner != before.get(key) {
+				require.Equal(t, "d", owner, "keys should only move to the new member")
+				moved++
+			}
+		}
+		require.Greater(t, moved, 0)
+		require.Less(t, moved, len(keys)*2/5)
+	})
+}
+
+func TestRuleSharder(t *testing.T) {
+	ctx := context.Background()
+	keys := make([]models.AlertRuleKey, 0, 100)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, models.AlertRuleKey{OrgID: int64(i%3 + 1), UID: fmt.Sprintf("rule-%d", i)})
+	}
+
+	setup := func(t *testing.T, memberStore store.SchedulerMemberStore, c clock.Clock, memberID string) *ruleSharder {
+		t.Helper()
+		m := metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetSchedulerMetrics()
+		return newRuleSharder(memberID, memberStore, c, 10*time.Second, time.Minute, log.New("test"), m)
+	}
+
+	t.Run("owns all rules until the members are known", func(t *testing.T) {
+		sharder := setup(t, store.NewFakeSchedulerMemberStore(), clock.NewMock(), "a")
+		for _, key := range keys {
+			require.True(t, sharder.owns(key))
+		}
+	})
+
+	t.Run("each rule is owned by exactly one member", func(t *testing.T) {
+		memberStore := store.NewFakeSchedulerMemberStore()
+		c := clock.NewMock()
+		a := setup(t, memberStore, c, "a")
+		b := setup(t, memberStore, c, "b")
+		require.NoError(t, a.sync(ctx))
+		require.NoError(t, b.sync(ctx))
+		require.NoError(t, a.sync(ctx))
+
+		ownedByA := 0
+		for _, key := range keys {
+			require.NotEqual(t, a.owns(key), b.owns(key), "rule %v", key)
+			if a.owns(key) {
+				ownedByA++
+			}
+		}
+		require.Greater(t, ownedByA, 0)
+		require.Less(t, ownedByA, len(keys))
+	})
+
+	t.Run("rules of a member that stops sending heartbeats are taken over", func(t *testing.T) {
+		memberStore := store.NewFakeSchedulerMemberStore()
+		c := clock.NewMock()
+		a := setup(t, memberStore, c, "a")
+		require.NoError(t, memberStore.HeartbeatSchedulerMember(ctx, "b", c.Now()))
+		require.NoError(t, a.sync(ctx))
+
+		c.Add(2 * time.Minute)
+		require.NoError(t, a.sync(ctx))
+		for _, key := range keys {
+			require.True(t, a.owns(key))
+		}
+		require.NotContains(t, memberStore.Heartbeats, "b")
+	})
+
+	t.Run("member leaves when it stops", func(t *testing.T) {
+		memberStore := store.NewFakeSchedulerMemberStore()
+		a := setup(t, memberStore, clock.NewMock(), "a")
+		require.NoError(t, a.sync(ctx))
+		require.Contains(t, memberStore.Heartbeats, "a")
+
+		runCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		require.NoError(t, a.run(runCtx))
+		require.NotContains(t, memberStore.Heartbeats, "a")
+	})
+}
+
+func TestSchedule_ShardedEvaluation(t *testing.T) {
+	ruleStore := store.NewFakeRuleStore(t)
+	memberStore := store.NewFakeSchedulerMemberStore()
+	sch, mockedClock := setupScheduler(t, ruleStore, &store.FakeInstanceStore{}, store.NewFakeAdminConfigStore(t), nil)
+	sch.sharder = newRuleSharder("a", memberStore, mockedClock, time.Hour, 2*time.Hour, log.New("test"), sch.metrics)
+
+	rules := make([]*models.AlertRule, 0, 20)
+	for i := 0; i < cap(rules); i++ {
+		rules = append(rules, CreateTestAlertRule(t, ruleStore, 1, 1, eval.Normal))
+	}
+
+	// another scheduler is a member, so only a part of the rules is evaluated by this one.
+	require.NoError(t, memberStore.HeartbeatSchedulerMember(context.Background(), "b", mockedClock.Now()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_ = sch.Run(ctx)
+	}()
+
+	assertRegistered := func(expectAll bool) {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			mockedClock.Add(time.Second)
+			for _, rule := range rules {
+				expected := expectAll || sch.sharder.owns(rule.GetKey())
+				if sch.registry.exists(rule.GetKey()) != expected {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 50*time.Millisecond)
+	}
+
+	assertRegistered(false)
+	owned := 0
+	for _, rule := range rules {
+		if sch.sharder.owns(rule.GetKey()) {
+			owned++
+		}
+	}
+	require.Less(t, owned, len(rules), "some rules should be evaluated by the other scheduler")
+
+	// the other scheduler leaves, so this one takes over all the rules.
+	require.NoError(t, memberStore.DeleteSchedulerMember(context.Background(), "b"))
+	require.NoError(t, sch.sharder.sync(context.Background()))
+	assertRegistered(true)
+}
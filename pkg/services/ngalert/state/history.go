@@ -0,0 +1,80 @@
+package state
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// StateHistoryCleanupInterval is how often the state history that is older than the retention period is deleted.
+var StateHistoryCleanupInterval = time.Hour
+
+// newStateHistoryEntry creates the record of the change of the state of the alert instance to the current state.
+// It copies everything it needs from the state because the state can change after the record is created.
+func newStateHistoryEntry(alertRule *ngModels.AlertRule, currentState *State, result eval.Result, previousState eval.State) *ngModels.AlertStateHistoryEntry {
+	values := make(map[string]*float64, len(result.Values))
+	for refID, capture := range result.Values {
+		if capture.Value == nil || math.IsNaN(*capture.Value) || math.IsInf(*capture.Value, 0) {
+			values[refID] = nil
+			continue
+		}
+		v := *capture.Value
+		values[refID] = &v
+	}
+
+	entry := &ngModels.AlertStateHistoryEntry{
+		RuleOrgID:     alertRule.OrgID,
+		RuleUID:       alertRule.UID,
+		Labels:        ngModels.InstanceLabels(RemovePrivateLabels(currentState.Labels)),
+		PreviousState: ngModels.InstanceStateType(previousState.String()),
+		CurrentState:  ngModels.InstanceStateType(currentState.State.String()),
+		Values:        values,
+		EvaluatedAt:   result.EvaluatedAt,
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+	return entry
+}
+
+func (st *Manager) saveStateHistory(ctx context.Context, entry *ngModels.AlertStateHistoryEntry) {
+	st.log.Debug("alert state changed saving state history", "alertRuleUID", entry.RuleUID, "newState", entry.CurrentState, "oldState", entry.PreviousState)
+	if err := st.historyStore.SaveAlertStateHistory(ctx, entry); err != nil {
+		st.log.Error("error saving alert state history", "alertRuleUID", entry.RuleUID, "error", err.Error())
+	}
+}
+
+// CleanStateHistory deletes the state history that is older than the retention period every StateHistoryCleanupInterval
+// until the context is cancelled. In HA setup the history is deleted by a single server, the one which takes the server lock.
+func (st *Manager) CleanStateHistory(ctx context.Context, retention time.Duration, serverLockService *serverlock.ServerLockService) error {
+	ticker := time.NewTicker(StateHistoryCleanupInterval)
+	defer ticker.Stop()
+	for {
+		err := serverLockService.LockAndExecute(ctx, "delete old alert state history", StateHistoryCleanupInterval, func(ctx context.Context) {
+			st.deleteOldStateHistory(ctx, retention)
+		})
+		if err != nil {
+			st.log.Error("failed to lock and execute cleanup of old alert state history", "error", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (st *Manager) deleteOldStateHistory(ctx context.Context, retention time.Duration) {
+	before := time.Now().Add(-retention)
+	deleted, err := st.historyStore.DeleteAlertStateHistory(ctx, before)
+	if err != nil {
+		st.log.Error("failed to delete old alert state history", "before", before, "error", err)
+		return
+	}
+	st.log.Debug("deleted old alert state history", "before", before, "count", deleted)
+}
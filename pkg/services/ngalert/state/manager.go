@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -35,28 +36,50 @@ type Manager struct {
 	cache       *cache
 	quit        chan struct{}
 	ResendDelay time.Duration
+	clock       clock.Clock
+	// inMemory is true if the manager must not change anything outside its cache, e.g. when it replays past evaluations.
+	inMemory bool
 
 	ruleStore     store.RuleStore
 	instanceStore store.InstanceStore
+	historyStore  store.StateHistoryStore
 	sqlStore      sqlstore.Store
 }
 
 func NewManager(logger log.Logger, metrics *metrics.State, externalURL *url.URL, ruleStore store.RuleStore,
-	instanceStore store.InstanceStore, sqlStore sqlstore.Store) *Manager {
+	instanceStore store.InstanceStore, historyStore store.StateHistoryStore, sqlStore sqlstore.Store) *Manager {
 	manager := &Manager{
 		cache:         newCache(logger, metrics, externalURL),
 		quit:          make(chan struct{}),
 		ResendDelay:   ResendDelay, // TODO: make this configurable
+		clock:         clock.New(),
 		log:           logger,
 		metrics:       metrics,
 		ruleStore:     ruleStore,
 		instanceStore: instanceStore,
+		historyStore:  historyStore,
 		sqlStore:      sqlStore,
 	}
 	go manager.recordMetrics()
 	return manager
 }
 
+// NewInMemoryManager returns a manager that keeps the states only in its cache. It does not create annotations,
+// save the state history or delete the alert instances, and it uses the clock to find out which states are stale,
+// so that it can be used to replay the evaluations of a rule over a past time range.
+// Unlike the manager returned by NewManager, it does not record the metrics of the cache and does not need to be closed.
+func NewInMemoryManager(logger log.Logger, metrics *metrics.State, externalURL *url.URL, clk clock.Clock) *Manager {
+	return &Manager{
+		cache:       newCache(logger, metrics, externalURL),
+		quit:        make(chan struct{}),
+		ResendDelay: ResendDelay,
+		clock:       clk,
+		inMemory:    true,
+		log:         logger,
+		metrics:     metrics,
+	}
+}
+
 func (st *Manager) Close() {
 	st.quit <- struct{}{}
 }
@@ -100,24 +123,7 @@ func (st *Manager) Warm(ctx context.Context) {
 				continue
 			}
 
-			lbs := map[string]string(entry.Labels)
-			cacheId, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("error getting cacheId for entry", "msg", err.Error())
-			}
-			stateForEntry := &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheId:              cacheId,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
-			}
-			states = append(states, stateForEntry)
+			states = append(states, st.stateFromInstance(entry, ruleForEntry))
 		}
 	}
 
@@ -126,6 +132,44 @@ func (st *Manager) Warm(ctx context.Context) {
 	}
 }
 
+// WarmRule replaces the cached states of the rule with the ones saved in the database.
+// It is used when the rule starts to be evaluated by this instance after it was evaluated by another one.
+func (st *Manager) WarmRule(ctx context.Context, rule *ngModels.AlertRule) error {
+	cmd := ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	}
+	if err := st.instanceStore.ListAlertInstances(ctx, &cmd); err != nil {
+		return err
+	}
+
+	st.RemoveByRuleUID(rule.OrgID, rule.UID)
+	for _, entry := range cmd.Result {
+		st.set(st.stateFromInstance(entry, rule))
+	}
+	return nil
+}
+
+func (st *Manager) stateFromInstance(entry *ngModels.ListAlertInstancesQueryResult, rule *ngModels.AlertRule) *State {
+	lbs := map[string]string(entry.Labels)
+	cacheId, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("error getting cacheId for entry", "msg", err.Error())
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheId:              cacheId,
+		Labels:               lbs,
+		State:                translateInstanceState(entry.CurrentState),
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          rule.Annotations,
+	}
+}
+
 func (st *Manager) getOrCreate(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
 	return st.cache.getOrCreate(ctx, alertRule, result)
 }
@@ -195,8 +239,9 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
 
 	st.set(currentState)
-	if oldState != currentState.State {
+	if oldState != currentState.State && !st.inMemory {
 		go st.annotateState(ctx, alertRule, currentState.Labels, result.EvaluatedAt, currentState.State, oldState)
+		go st.saveStateHistory(ctx, newStateHistoryEntry(alertRule, currentState, result, oldState))
 	}
 	return currentState
 }
@@ -247,7 +292,7 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 func (st *Manager) annotateState(ctx context.Context, alertRule *ngModels.AlertRule, labels data.Labels, evaluatedAt time.Time, state eval.State, previousState eval.State) {
 	st.log.Debug("alert state changed creating annotation", "alertRuleUID", alertRule.UID, "newState", state.String(), "oldState", previousState.String())
 
-	labels = removePrivateLabels(labels)
+	labels = RemovePrivateLabels(labels)
 	annotationText := fmt.Sprintf("%s {%s} - %s", alertRule.Title, labels.String(), state.String())
 
 	item := &annotations.Item{
@@ -295,9 +340,12 @@ func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.
 	allStates := st.GetStatesForRuleUID(alertRule.OrgID, alertRule.UID)
 	for _, s := range allStates {
 		_, ok := states[s.CacheId]
-		if !ok && isItStale(s.LastEvaluationTime, alertRule.IntervalSeconds) {
+		if !ok && isItStale(s.LastEvaluationTime, alertRule.IntervalSeconds, st.clock.Now()) {
 			st.log.Debug("removing stale state entry", "orgID", s.OrgID, "alertRuleUID", s.AlertRuleUID, "cacheID", s.CacheId)
 			st.cache.deleteEntry(s.OrgID, s.AlertRuleUID, s.CacheId)
+			if st.inMemory {
+				continue
+			}
 			ilbs := ngModels.InstanceLabels(s.Labels)
 			_, labelsHash, err := ilbs.StringAndHash()
 			if err != nil {
@@ -315,11 +363,12 @@ func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.
 	}
 }
 
-func isItStale(lastEval time.Time, intervalSeconds int64) bool {
-	return lastEval.Add(2 * time.Duration(intervalSeconds) * time.Second).Before(time.Now())
+func isItStale(lastEval time.Time, intervalSeconds int64, now time.Time) bool {
+	return lastEval.Add(2 * time.Duration(intervalSeconds) * time.Second).Before(now)
 }
 
-func removePrivateLabels(labels data.Labels) data.Labels {
+// RemovePrivateLabels returns a copy of the labels without the private ones, whose names start or end with "__".
+func RemovePrivateLabels(labels data.Labels) data.Labels {
 	result := make(data.Labels)
 	for k, v := range labels {
 		if !strings.HasPrefix(k, "__") && !strings.HasSuffix(k, "__") {
@@ -229,3 +229,27 @@ func (a *State) GetLastEvaluationValuesForCondition() map[string]float64 {
 
 	return r
 }
+
+// WithLoadedDimensions returns a copy of the queries in which threshold expressions are
+// given the labels of the alert instances that are pending or firing, so that they can
+// apply their recovery threshold to them.
+func WithLoadedDimensions(queries []ngModels.AlertQuery, states []*State) ([]ngModels.AlertQuery, error) {
+	var loaded []data.Labels
+	for _, s := range states {
+		if s.State == eval.Alerting || s.State == eval.Pending {
+			loaded = append(loaded, s.Labels)
+		}
+	}
+	if len(loaded) == 0 {
+		return queries, nil
+	}
+
+	result := make([]ngModels.AlertQuery, len(queries))
+	copy(result, queries)
+	for i := range result {
+		if err := result[i].SetLoadedDimensions(loaded); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -30,6 +30,8 @@ type UpdateRuleGroupCmd struct {
 type UpsertRule struct {
 	Existing *ngmodels.AlertRule
 	New      ngmodels.AlertRule
+	// RestoredFrom is the version of the rule that New restores, if any.
+	RestoredFrom int64
 }
 
 // RuleStore is the interface for persisting alert rules and instances
@@ -42,7 +44,10 @@ type RuleStore interface {
 	GetAlertRules(ctx context.Context, query *ngmodels.GetAlertRulesQuery) error
 	GetUserVisibleNamespaces(context.Context, int64, *models.SignedInUser) (map[string]*models.Folder, error)
 	GetNamespaceByTitle(context.Context, string, int64, *models.SignedInUser, bool) (*models.Folder, error)
+	GetNamespaceByUID(context.Context, string, int64, *models.SignedInUser, bool) (*models.Folder, error)
 	UpsertAlertRules(ctx context.Context, rule []UpsertRule) error
+	ListAlertRuleVersions(ctx context.Context, query *ngmodels.ListAlertRuleVersionsQuery) error
+	GetAlertRuleVersion(ctx context.Context, query *ngmodels.GetAlertRuleVersionQuery) error
 }
 
 func getAlertRuleByUID(sess *sqlstore.DBSession, alertRuleUID string, orgID int64) (*ngmodels.AlertRule, error) {
@@ -79,6 +84,12 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUI
 			return err
 		}
 		logger.Debug("deleted alert instances", "count", rows)
+
+		rows, err = sess.Table("alert_state_history").Where("rule_org_id = ?", orgID).In("rule_uid", ruleUID).Delete(ngmodels.AlertRule{})
+		if err != nil {
+			return err
+		}
+		logger.Debug("deleted alert state history", "count", rows)
 		return nil
 	})
 }
@@ -116,11 +127,14 @@ func (st DBstore) UpsertAlertRules(ctx context.Context, rules []UpsertRule) erro
 			var parentVersion int64
 			switch r.Existing {
 			case nil: // new rule
-				uid, err := GenerateNewAlertRuleUID(sess, r.New.OrgID, r.New.Title)
-				if err != nil {
-					return fmt.Errorf("failed to generate UID for alert rule %q: %w", r.New.Title, err)
+				// new rules created by provisioning can have a predefined UID
+				if r.New.UID == "" {
+					uid, err := GenerateNewAlertRuleUID(sess, r.New.OrgID, r.New.Title)
+					if err != nil {
+						return fmt.Errorf("failed to generate UID for alert rule %q: %w", r.New.Title, err)
+					}
+					r.New.UID = uid
 				}
-				r.New.UID = uid
 				r.New.Version = 1
 
 				if err := st.validateAlertRule(r.New); err != nil {
@@ -159,6 +173,7 @@ func (st DBstore) UpsertAlertRules(ctx context.Context, rules []UpsertRule) erro
 				RuleNamespaceUID: r.New.NamespaceUID,
 				RuleGroup:        r.New.RuleGroup,
 				ParentVersion:    parentVersion,
+				RestoredFrom:     r.RestoredFrom,
 				Version:          r.New.Version,
 				Created:          r.New.Updated,
 				Condition:        r.New.Condition,
@@ -192,6 +207,41 @@ func (st DBstore) UpsertAlertRules(ctx context.Context, rules []UpsertRule) erro
 	})
 }
 
+// ListAlertRuleVersions is a handler for retrieving the versions of an alert rule, newest first.
+func (st DBstore) ListAlertRuleVersions(ctx context.Context, query *ngmodels.ListAlertRuleVersionsQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		versions := make([]*ngmodels.AlertRuleVersion, 0)
+		err := sess.Table("alert_rule_version").
+			Where("rule_org_id = ? AND rule_uid = ?", query.OrgID, query.RuleUID).
+			Desc("version").
+			Find(&versions)
+		if err != nil {
+			return err
+		}
+		query.Result = versions
+		return nil
+	})
+}
+
+// GetAlertRuleVersion is a handler for retrieving a version of an alert rule.
+// It returns ngmodels.ErrAlertRuleVersionNotFound if the version does not exist.
+func (st DBstore) GetAlertRuleVersion(ctx context.Context, query *ngmodels.GetAlertRuleVersionQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		version := ngmodels.AlertRuleVersion{}
+		has, err := sess.Table("alert_rule_version").
+			Where("rule_org_id = ? AND rule_uid = ? AND version = ?", query.OrgID, query.RuleUID, query.Version).
+			Get(&version)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrAlertRuleVersionNotFound
+		}
+		query.Result = &version
+		return nil
+	})
+}
+
 // GetOrgAlertRules is a handler for retrieving alert rules of specific organisation.
 func (st DBstore) GetOrgAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) error {
 	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
@@ -303,20 +353,46 @@ func (st DBstore) GetNamespaceByTitle(ctx context.Context, namespace string, org
 		return nil, err
 	}
 
-	// if access control is disabled, check that the user is allowed to save in the folder.
-	if withCanSave && st.AccessControl.IsDisabled() {
-		g := guardian.New(ctx, folder.Id, orgID, user)
-		if canSave, err := g.CanSave(); err != nil || !canSave {
-			if err != nil {
-				st.Logger.Error("checking can save permission has failed", "userId", user.UserId, "username", user.Login, "namespace", namespace, "orgId", orgID, "error", err)
-			}
-			return nil, ngmodels.ErrCannotEditNamespace
+	if withCanSave {
+		if err := st.checkCanSaveNamespace(ctx, folder, orgID, user); err != nil {
+			return nil, err
 		}
 	}
 
 	return folder, nil
 }
 
+// GetNamespaceByUID is a handler for retrieving a namespace by its UID.
+func (st DBstore) GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user *models.SignedInUser, withCanSave bool) (*models.Folder, error) {
+	folder, err := st.FolderService.GetFolderByUID(ctx, user, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if withCanSave {
+		if err := st.checkCanSaveNamespace(ctx, folder, orgID, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return folder, nil
+}
+
+// checkCanSaveNamespace checks that the user is allowed to save in the folder if access control is disabled.
+func (st DBstore) checkCanSaveNamespace(ctx context.Context, folder *models.Folder, orgID int64, user *models.SignedInUser) error {
+	if !st.AccessControl.IsDisabled() {
+		return nil
+	}
+	g := guardian.New(ctx, folder.Id, orgID, user)
+	if canSave, err := g.CanSave(); err != nil || !canSave {
+		if err != nil {
+			st.Logger.Error("checking can save permission has failed", "userId", user.UserId, "username", user.Login, "namespace", folder.Title, "orgId", orgID, "error", err)
+		}
+		return ngmodels.ErrCannotEditNamespace
+	}
+	return nil
+}
+
 // GetAlertRulesForScheduling returns alert rule info (identifier, interval, version state)
 // that is useful for it's scheduling.
 func (st DBstore) GetAlertRulesForScheduling(ctx context.Context, query *ngmodels.ListAlertRulesQuery) error {
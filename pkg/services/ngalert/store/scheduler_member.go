@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// SchedulerMemberStore is the interface for the membership of the schedulers that divide the alert rules between them.
+type SchedulerMemberStore interface {
+	HeartbeatSchedulerMember(ctx context.Context, memberID string, now time.Time) error
+	ListSchedulerMembers(ctx context.Context, activeSince time.Time) ([]string, error)
+	DeleteSchedulerMember(ctx context.Context, memberID string) error
+	DeleteExpiredSchedulerMembers(ctx context.Context, before time.Time) (int64, error)
+}
+
+// HeartbeatSchedulerMember registers the scheduler as a member or updates the time of its last heartbeat.
+func (st DBstore) HeartbeatSchedulerMember(ctx context.Context, memberID string, now time.Time) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("UPDATE alert_scheduler_member SET heartbeat_at = ? WHERE member_id = ?", now.Unix(), memberID)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected > 0 {
+			return nil
+		}
+		_, err = sess.Exec("INSERT INTO alert_scheduler_member (member_id, heartbeat_at) VALUES (?, ?)", memberID, now.Unix())
+		return err
+	})
+}
+
+// ListSchedulerMembers returns the identifiers of the schedulers that sent a heartbeat since the given time.
+func (st DBstore) ListSchedulerMembers(ctx context.Context, activeSince time.Time) ([]string, error) {
+	members := make([]string, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Table("alert_scheduler_member").Where("heartbeat_at >= ?", activeSince.Unix()).Asc("member_id").Cols("member_id").Find(&members)
+	})
+	return members, err
+}
+
+// DeleteSchedulerMember removes the scheduler from the members.
+func (st DBstore) DeleteSchedulerMember(ctx context.Context, memberID string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("DELETE FROM alert_scheduler_member WHERE member_id = ?", memberID)
+		return err
+	})
+}
+
+// DeleteExpiredSchedulerMembers removes the schedulers whose last heartbeat is older than the given time.
+// It returns the number of removed members.
+func (st DBstore) DeleteExpiredSchedulerMembers(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_scheduler_member WHERE heartbeat_at < ?", before.Unix())
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
@@ -0,0 +1,59 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestSchedulerMemberOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	start := time.Unix(1000, 0)
+	require.NoError(t, dbstore.HeartbeatSchedulerMember(ctx, "b", start))
+	require.NoError(t, dbstore.HeartbeatSchedulerMember(ctx, "a", start))
+
+	t.Run("list members that sent a heartbeat since the given time", func(t *testing.T) {
+		members, err := dbstore.ListSchedulerMembers(ctx, start)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, members)
+
+		members, err = dbstore.ListSchedulerMembers(ctx, start.Add(time.Second))
+		require.NoError(t, err)
+		require.Empty(t, members)
+	})
+
+	t.Run("heartbeat updates the existing member", func(t *testing.T) {
+		require.NoError(t, dbstore.HeartbeatSchedulerMember(ctx, "a", start.Add(time.Minute)))
+
+		members, err := dbstore.ListSchedulerMembers(ctx, start.Add(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, []string{"a"}, members)
+	})
+
+	t.Run("delete expired members", func(t *testing.T) {
+		deleted, err := dbstore.DeleteExpiredSchedulerMembers(ctx, start.Add(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		members, err := dbstore.ListSchedulerMembers(ctx, start)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a"}, members)
+	})
+
+	t.Run("delete member", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteSchedulerMember(ctx, "a"))
+
+		members, err := dbstore.ListSchedulerMembers(ctx, start)
+		require.NoError(t, err)
+		require.Empty(t, members)
+	})
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// StateHistoryStore is the interface for persisting the state changes of alert instances.
+type StateHistoryStore interface {
+	SaveAlertStateHistory(ctx context.Context, entries ...*models.AlertStateHistoryEntry) error
+	ListAlertStateHistory(ctx context.Context, query *models.ListAlertStateHistoryQuery) error
+	DeleteAlertStateHistory(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SaveAlertStateHistory is a handler for saving the state changes of alert instances.
+func (st DBstore) SaveAlertStateHistory(ctx context.Context, entries ...*models.AlertStateHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		for _, entry := range entries {
+			labelTupleJSON, labelsHash, err := entry.Labels.StringAndHash()
+			if err != nil {
+				return err
+			}
+			values, err := json.Marshal(entry.Values)
+			if err != nil {
+				return err
+			}
+			_, err = sess.Exec("INSERT INTO alert_state_history (rule_org_id, rule_uid, labels, labels_hash, previous_state, current_state, evaluation_values, error, evaluated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				entry.RuleOrgID, entry.RuleUID, labelTupleJSON, labelsHash, entry.PreviousState, entry.CurrentState, string(values), entry.Error, entry.EvaluatedAt.Unix())
+			if err != nil {
+				return err
+			}
+			entry.LabelsHash = labelsHash
+		}
+		return nil
+	})
+}
+
+// stateHistoryBatchSize is the number of entries that are loaded at once when the entries are filtered by labels.
+const stateHistoryBatchSize = 1000
+
+// ListAlertStateHistory is a handler for retrieving the state changes of the alert instances of a rule.
+func (st DBstore) ListAlertStateHistory(ctx context.Context, query *models.ListAlertStateHistoryQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		// the entries are selected newest first to apply the limit.
+		newQuery := func() *xorm.Session {
+			q := sess.Table("alert_state_history").Where("rule_org_id = ? AND rule_uid = ?", query.OrgID, query.RuleUID)
+			if !query.From.IsZero() {
+				q = q.And("evaluated_at >= ?", query.From.Unix())
+			}
+			if !query.To.IsZero() {
+				q = q.And("evaluated_at <= ?", query.To.Unix())
+			}
+			return q.Desc("evaluated_at", "id")
+		}
+
+		result := make([]*models.AlertStateHistoryEntry, 0)
+		if len(query.Matchers) == 0 {
+			q := newQuery()
+			if query.Limit > 0 {
+				q = q.Limit(query.Limit)
+			}
+			if err := q.Find(&result); err != nil {
+				return err
+			}
+		} else {
+			// labels are stored as JSON, so the matchers are applied to batches of entries
+			// until the limit is reached.
+			for offset := 0; ; offset += stateHistoryBatchSize {
+				entries := make([]*models.AlertStateHistoryEntry, 0, stateHistoryBatchSize)
+				if err := newQuery().Limit(stateHistoryBatchSize, offset).Find(&entries); err != nil {
+					return err
+				}
+				for _, entry := range entries {
+					if query.Matches(entry) {
+						result = append(result, entry)
+					}
+					if query.Limit > 0 && len(result) >= query.Limit {
+						break
+					}
+				}
+				if len(entries) < stateHistoryBatchSize || (query.Limit > 0 && len(result) >= query.Limit) {
+					break
+				}
+			}
+		}
+
+		// return the entries oldest first.
+		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+			result[i], result[j] = result[j], result[i]
+		}
+		query.Result = result
+		return nil
+	})
+}
+
+// DeleteAlertStateHistory deletes the state changes of alert instances that were evaluated before the given time.
+// It returns the number of deleted entries.
+func (st DBstore) DeleteAlertStateHistory(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_state_history WHERE evaluated_at < ?", before.Unix())
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
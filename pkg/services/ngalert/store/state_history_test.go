@@ -0,0 +1,110 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestAlertStateHistoryOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+
+	value := 1.5
+	start := time.Unix(1000, 0)
+	entries := []*models.AlertStateHistoryEntry{
+		{
+			RuleOrgID:     rule.OrgID,
+			RuleUID:       rule.UID,
+			Labels:        models.InstanceLabels{"severity": "critical"},
+			PreviousState: models.InstanceStateNormal,
+			CurrentState:  models.InstanceStateFiring,
+			Values:        map[string]*float64{"A": &value, "B": nil},
+			EvaluatedAt:   start,
+		},
+		{
+			RuleOrgID:     rule.OrgID,
+			RuleUID:       rule.UID,
+			Labels:        models.InstanceLabels{"severity": "warning"},
+			PreviousState: models.InstanceStateNormal,
+			CurrentState:  models.InstanceStateError,
+			Error:         "failed to execute query",
+			EvaluatedAt:   start.Add(time.Minute),
+		},
+		{
+			RuleOrgID:     rule.OrgID,
+			RuleUID:       rule.UID,
+			Labels:        models.InstanceLabels{"severity": "critical"},
+			PreviousState: models.InstanceStateFiring,
+			CurrentState:  models.InstanceStateNormal,
+			EvaluatedAt:   start.Add(2 * time.Minute),
+		},
+	}
+	require.NoError(t, dbstore.SaveAlertStateHistory(ctx, entries...))
+
+	t.Run("can list state history oldest first", func(t *testing.T) {
+		q := &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 3)
+		for i, entry := range q.Result {
+			require.Equal(t, entries[i].Labels, entry.Labels)
+			require.Equal(t, entries[i].CurrentState, entry.CurrentState)
+			require.Equal(t, entries[i].PreviousState, entry.PreviousState)
+			require.Equal(t, entries[i].Error, entry.Error)
+			require.Equal(t, entries[i].EvaluatedAt.Unix(), entry.EvaluatedAt.Unix())
+		}
+		require.Equal(t, value, *q.Result[0].Values["A"])
+		require.Nil(t, q.Result[0].Values["B"])
+	})
+
+	t.Run("can filter state history by labels, time range and limit", func(t *testing.T) {
+		matcher, err := labels.NewMatcher(labels.MatchEqual, "severity", "critical")
+		require.NoError(t, err)
+		q := &models.ListAlertStateHistoryQuery{
+			OrgID:    rule.OrgID,
+			RuleUID:  rule.UID,
+			Matchers: []*labels.Matcher{matcher},
+		}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 2)
+
+		q = &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID, From: start.Add(time.Minute), To: start.Add(time.Minute)}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, models.InstanceStateError, q.Result[0].CurrentState)
+
+		q = &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID, Limit: 2}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 2)
+		require.Equal(t, models.InstanceStateError, q.Result[0].CurrentState)
+		require.Equal(t, models.InstanceStateNormal, q.Result[1].CurrentState)
+
+		q = &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID, Matchers: []*labels.Matcher{matcher}, Limit: 1}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, models.InstanceStateNormal, q.Result[0].CurrentState)
+		require.Equal(t, models.InstanceLabels{"severity": "critical"}, q.Result[0].Labels)
+	})
+
+	t.Run("can delete state history older than given time", func(t *testing.T) {
+		deleted, err := dbstore.DeleteAlertStateHistory(ctx, start.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		q := &models.ListAlertStateHistoryQuery{OrgID: rule.OrgID, RuleUID: rule.UID}
+		require.NoError(t, dbstore.ListAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 2)
+	})
+}
@@ -8,8 +8,10 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/util"
@@ -30,7 +32,8 @@ func NewFakeRuleStore(t *testing.T) *FakeRuleStore {
 		Hook: func(interface{}) error {
 			return nil
 		},
-		Folders: map[int64][]*models2.Folder{},
+		Folders:  map[int64][]*models2.Folder{},
+		Versions: map[int64][]*models.AlertRuleVersion{},
 	}
 }
 
@@ -43,6 +46,8 @@ type FakeRuleStore struct {
 	Hook        func(cmd interface{}) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []interface{}
 	Folders     map[int64][]*models2.Folder
+	// OrgID -> Versions of rules
+	Versions map[int64][]*models.AlertRuleVersion
 }
 
 type GenericRecordedQuery struct {
@@ -228,6 +233,16 @@ func (f *FakeRuleStore) GetNamespaceByTitle(_ context.Context, title string, org
 	return nil, fmt.Errorf("not found")
 }
 
+func (f *FakeRuleStore) GetNamespaceByUID(_ context.Context, uid string, orgID int64, _ *models2.SignedInUser, _ bool) (*models2.Folder, error) {
+	folders := f.Folders[orgID]
+	for _, folder := range folders {
+		if folder.Uid == uid {
+			return folder, nil
+		}
+	}
+	return nil, models2.ErrFolderNotFound
+}
+
 func (f *FakeRuleStore) UpsertAlertRules(_ context.Context, q []UpsertRule) error {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -238,6 +253,51 @@ func (f *FakeRuleStore) UpsertAlertRules(_ context.Context, q []UpsertRule) erro
 	return nil
 }
 
+// PutRuleVersion puts the versions in the Versions map.
+func (f *FakeRuleStore) PutRuleVersion(versions ...*models.AlertRuleVersion) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, v := range versions {
+		f.Versions[v.RuleOrgID] = append(f.Versions[v.RuleOrgID], v)
+	}
+}
+
+func (f *FakeRuleStore) ListAlertRuleVersions(_ context.Context, q *models.ListAlertRuleVersionsQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return err
+	}
+	var result []*models.AlertRuleVersion
+	for _, v := range f.Versions[q.OrgID] {
+		if v.RuleUID == q.RuleUID {
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version > result[j].Version
+	})
+	q.Result = result
+	return nil
+}
+
+func (f *FakeRuleStore) GetAlertRuleVersion(_ context.Context, q *models.GetAlertRuleVersionQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return err
+	}
+	for _, v := range f.Versions[q.OrgID] {
+		if v.RuleUID == q.RuleUID && v.Version == q.Version {
+			q.Result = v
+			return nil
+		}
+	}
+	return models.ErrAlertRuleVersionNotFound
+}
+
 func (f *FakeRuleStore) InTransaction(ctx context.Context, fn func(c context.Context) error) error {
 	return fn(ctx)
 }
@@ -271,6 +331,107 @@ func (f *FakeInstanceStore) DeleteAlertInstance(_ context.Context, _ int64, _, _
 	return nil
 }
 
+type FakeStateHistoryStore struct {
+	mtx     sync.Mutex
+	Entries []*models.AlertStateHistoryEntry
+}
+
+func (f *FakeStateHistoryStore) SaveAlertStateHistory(_ context.Context, entries ...*models.AlertStateHistoryEntry) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.Entries = append(f.Entries, entries...)
+	return nil
+}
+
+func (f *FakeStateHistoryStore) ListAlertStateHistory(_ context.Context, q *models.ListAlertStateHistoryQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var result []*models.AlertStateHistoryEntry
+	for _, e := range f.Entries {
+		if e.RuleOrgID != q.OrgID || e.RuleUID != q.RuleUID || !q.Matches(e) {
+			continue
+		}
+		if (!q.From.IsZero() && e.EvaluatedAt.Before(q.From)) || (!q.To.IsZero() && e.EvaluatedAt.After(q.To)) {
+			continue
+		}
+		result = append(result, e)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].EvaluatedAt.Before(result[j].EvaluatedAt)
+	})
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[len(result)-q.Limit:]
+	}
+	q.Result = result
+	return nil
+}
+
+func (f *FakeStateHistoryStore) DeleteAlertStateHistory(_ context.Context, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var deleted int64
+	result := f.Entries[:0]
+	for _, e := range f.Entries {
+		if e.EvaluatedAt.Before(before) {
+			deleted++
+			continue
+		}
+		result = append(result, e)
+	}
+	f.Entries = result
+	return deleted, nil
+}
+
+type FakeSchedulerMemberStore struct {
+	mtx sync.Mutex
+	// Heartbeats are the times of the last heartbeat of the members.
+	Heartbeats map[string]time.Time
+}
+
+func NewFakeSchedulerMemberStore() *FakeSchedulerMemberStore {
+	return &FakeSchedulerMemberStore{Heartbeats: map[string]time.Time{}}
+}
+
+func (f *FakeSchedulerMemberStore) HeartbeatSchedulerMember(_ context.Context, memberID string, now time.Time) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.Heartbeats[memberID] = now
+	return nil
+}
+
+func (f *FakeSchedulerMemberStore) ListSchedulerMembers(_ context.Context, activeSince time.Time) ([]string, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	members := make([]string, 0, len(f.Heartbeats))
+	for member, heartbeat := range f.Heartbeats {
+		if heartbeat.Unix() >= activeSince.Unix() {
+			members = append(members, member)
+		}
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+func (f *FakeSchedulerMemberStore) DeleteSchedulerMember(_ context.Context, memberID string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.Heartbeats, memberID)
+	return nil
+}
+
+func (f *FakeSchedulerMemberStore) DeleteExpiredSchedulerMembers(_ context.Context, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var deleted int64
+	for member, heartbeat := range f.Heartbeats {
+		if heartbeat.Unix() < before.Unix() {
+			delete(f.Heartbeats, member)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
 func NewFakeAdminConfigStore(t *testing.T) *FakeAdminConfigStore {
 	t.Helper()
 	return &FakeAdminConfigStore{Configs: map[int64]*models.AdminConfiguration{}}
@@ -422,6 +583,10 @@ func (repo *FakeAnnotationsRepo) Find(_ context.Context, query *annotations.Item
 	return annotations, nil
 }
 
+func (repo *FakeAnnotationsRepo) Count(_ context.Context, query *annotations.CountQuery) ([]*annotations.Bucket, error) {
+	return []*annotations.Bucket{}, nil
+}
+
 func (repo *FakeAnnotationsRepo) FindTags(_ context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
 	result := annotations.FindTagsResult{
 		Tags: []*annotations.TagsDTO{},
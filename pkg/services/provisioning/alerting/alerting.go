@@ -0,0 +1,452 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type AlertRuleService interface {
+	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (ngmodels.AlertRule, ngmodels.Provenance, error)
+	CreateAlertRule(ctx context.Context, rule ngmodels.AlertRule, p ngmodels.Provenance) (ngmodels.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule ngmodels.AlertRule, p ngmodels.Provenance) (ngmodels.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, p ngmodels.Provenance) error
+	UpdateRuleGroup(ctx context.Context, orgID int64, folderUID, group string, intervalSeconds int64, p ngmodels.Provenance) error
+}
+
+type ContactPointService interface {
+	GetContactPoints(ctx context.Context, orgID int64) ([]definitions.EmbeddedContactPoint, error)
+	CreateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, p ngmodels.Provenance) (definitions.EmbeddedContactPoint, error)
+	UpdateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, p ngmodels.Provenance) error
+	DeleteContactPoint(ctx context.Context, orgID int64, uid string) error
+}
+
+type NotificationPolicyService interface {
+	UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p ngmodels.Provenance) error
+	ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
+}
+
+type TemplateService interface {
+	SetTemplate(ctx context.Context, orgID int64, tmpl definitions.MessageTemplate, p ngmodels.Provenance) (definitions.MessageTemplate, error)
+	DeleteTemplate(ctx context.Context, orgID int64, name string, p ngmodels.Provenance) error
+}
+
+type MuteTimingService interface {
+	GetMuteTiming(ctx context.Context, orgID int64, name string) (definitions.MuteTimeInterval, error)
+	CreateMuteTiming(ctx context.Context, orgID int64, mt definitions.MuteTimeInterval, p ngmodels.Provenance) (definitions.MuteTimeInterval, error)
+	UpdateMuteTiming(ctx context.Context, orgID int64, mt definitions.MuteTimeInterval, p ngmodels.Provenance) (definitions.MuteTimeInterval, error)
+	DeleteMuteTiming(ctx context.Context, orgID int64, name string, p ngmodels.Provenance) error
+}
+
+// ProvenanceStore lists the resources of an organization that were provisioned.
+type ProvenanceStore interface {
+	GetProvenances(ctx context.Context, orgID int64, resourceType string) (map[string]ngmodels.Provenance, error)
+}
+
+type SQLStore interface {
+	utils.OrgStore
+	utils.DashboardStore
+	SearchOrgs(ctx context.Context, query *models.SearchOrgsQuery) error
+}
+
+// ProvisionerConfig is what the alerting provisioner needs to apply the provisioning files.
+type ProvisionerConfig struct {
+	Path                      string
+	SQLStore                  SQLStore
+	DashboardService          dashboards.DashboardProvisioningService
+	ProvenanceStore           ProvenanceStore
+	RuleService               AlertRuleService
+	ContactPointService       ContactPointService
+	NotificationPolicyService NotificationPolicyService
+	TemplateService           TemplateService
+	MuteTimingService         MuteTimingService
+}
+
+// Provision alerting resources
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	ap := newAlertingProvisioner(cfg, log.New("provisioning.alerting"))
+	return ap.applyChanges(ctx, cfg.Path)
+}
+
+// AlertingProvisioner is responsible for provisioning the alert rules, contact points, notification policies,
+// templates and mute timings of unified alerting. The resources are provisioned with the file provenance so that
+// they cannot be changed via the API or the UI.
+type AlertingProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	cfg         ProvisionerConfig
+}
+
+func newAlertingProvisioner(cfg ProvisionerConfig, log log.Logger) AlertingProvisioner {
+	return AlertingProvisioner{
+		log: log,
+		cfgProvider: &configReader{
+			log:      log,
+			orgStore: cfg.SQLStore,
+		},
+		cfg: cfg,
+	}
+}
+
+func (ap *AlertingProvisioner) applyChanges(ctx context.Context, path string) error {
+	files, err := ap.cfgProvider.readConfig(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	// Resources are created before the resources that refer to them and deleted after them.
+	for _, file := range files {
+		if err := ap.provisionTemplates(ctx, file.Templates); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+		if err := ap.provisionMuteTimes(ctx, file.MuteTimes); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+		if err := ap.provisionContactPoints(ctx, file.ContactPoints); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+		if err := ap.provisionPolicies(ctx, file.Policies); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+		if err := ap.provisionRuleGroups(ctx, file.Groups); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+
+	for _, file := range files {
+		if err := ap.resetPolicies(ctx, file.ResetPolicies); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+		if err := ap.deleteRules(ctx, file.DeleteRules); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+		if err := ap.deleteContactPoints(ctx, file.DeleteContactPoints); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+		if err := ap.deleteMuteTimes(ctx, file.DeleteMuteTimes); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+		if err := ap.deleteTemplates(ctx, file.DeleteTemplates); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+
+	return ap.deleteOrphans(ctx, files)
+}
+
+func (ap *AlertingProvisioner) provisionTemplates(ctx context.Context, templates []*messageTemplate) error {
+	for _, tmpl := range templates {
+		ap.log.Debug("Provisioning template", "org", tmpl.OrgID, "name", tmpl.Template.Name)
+		if _, err := ap.cfg.TemplateService.SetTemplate(ctx, tmpl.OrgID, tmpl.Template, ngmodels.ProvenanceFile); err != nil {
+			return fmt.Errorf("failed to provision template '%s': %w", tmpl.Template.Name, err)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) provisionMuteTimes(ctx context.Context, muteTimes []*muteTime) error {
+	for _, mt := range muteTimes {
+		ap.log.Debug("Provisioning mute time", "org", mt.OrgID, "name", mt.MuteTime.Name)
+		_, err := ap.cfg.MuteTimingService.GetMuteTiming(ctx, mt.OrgID, mt.MuteTime.Name)
+		switch {
+		case err == nil:
+			_, err = ap.cfg.MuteTimingService.UpdateMuteTiming(ctx, mt.OrgID, mt.MuteTime, ngmodels.ProvenanceFile)
+		case errors.Is(err, provisioning.ErrNotFound):
+			_, err = ap.cfg.MuteTimingService.CreateMuteTiming(ctx, mt.OrgID, mt.MuteTime, ngmodels.ProvenanceFile)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to provision mute time '%s': %w", mt.MuteTime.Name, err)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) provisionContactPoints(ctx context.Context, contactPoints []*contactPoint) error {
+	for _, cp := range contactPoints {
+		existing, err := ap.cfg.ContactPointService.GetContactPoints(ctx, cp.OrgID)
+		if err != nil {
+			return fmt.Errorf("failed to get contact points of org %d: %w", cp.OrgID, err)
+		}
+		existingNames := make(map[string]string, len(existing))
+		for _, e := range existing {
+			existingNames[e.UID] = e.Name
+		}
+
+		for _, receiver := range cp.Receivers {
+			ap.log.Debug("Provisioning contact point", "org", cp.OrgID, "name", cp.Name, "uid", receiver.UID)
+			name, ok := existingNames[receiver.UID]
+			if ok && name == receiver.Name {
+				err = ap.cfg.ContactPointService.UpdateContactPoint(ctx, cp.OrgID, receiver, ngmodels.ProvenanceFile)
+			} else {
+				// The receivers are grouped by the name of the contact point, so a receiver that is moved to another
+				// contact point has to be recreated.
+				if ok {
+					if err := ap.cfg.ContactPointService.DeleteContactPoint(ctx, cp.OrgID, receiver.UID); err != nil {
+						return fmt.Errorf("failed to move contact point '%s' to '%s': %w", receiver.UID, receiver.Name, err)
+					}
+				}
+				_, err = ap.cfg.ContactPointService.CreateContactPoint(ctx, cp.OrgID, receiver, ngmodels.ProvenanceFile)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to provision contact point '%s': %w", receiver.UID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) provisionPolicies(ctx context.Context, policies []*notificationPolicy) error {
+	for _, policy := range policies {
+		ap.log.Debug("Provisioning notification policies", "org", policy.OrgID)
+		if err := ap.cfg.NotificationPolicyService.UpdatePolicyTree(ctx, policy.OrgID, policy.Policy, ngmodels.ProvenanceFile); err != nil {
+			return fmt.Errorf("failed to provision notification policies of org %d: %w", policy.OrgID, err)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) provisionRuleGroups(ctx context.Context, groups []*alertRuleGroup) error {
+	for _, group := range groups {
+		folderUID, err := ap.getOrCreateFolderUID(ctx, group.OrgID, group.Folder)
+		if err != nil {
+			return fmt.Errorf("failed to provision rule group '%s': %w", group.Name, err)
+		}
+
+		for _, rule := range group.Rules {
+			ap.log.Debug("Provisioning alert rule", "org", rule.OrgID, "uid", rule.UID, "group", group.Name)
+			rule.NamespaceUID = folderUID
+			_, _, err := ap.cfg.RuleService.GetAlertRule(ctx, rule.OrgID, rule.UID)
+			switch {
+			case err == nil:
+				_, err = ap.cfg.RuleService.UpdateAlertRule(ctx, rule, ngmodels.ProvenanceFile)
+			case errors.Is(err, ngmodels.ErrAlertRuleNotFound):
+				_, err = ap.cfg.RuleService.CreateAlertRule(ctx, rule, ngmodels.ProvenanceFile)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to provision alert rule '%s': %w", rule.UID, err)
+			}
+		}
+
+		if group.Interval > 0 && len(group.Rules) > 0 {
+			if err := ap.cfg.RuleService.UpdateRuleGroup(ctx, group.OrgID, folderUID, group.Name, int64(group.Interval.Seconds()), ngmodels.ProvenanceFile); err != nil {
+				return fmt.Errorf("failed to set the interval of rule group '%s': %w", group.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) resetPolicies(ctx context.Context, orgIDs []int64) error {
+	for _, orgID := range orgIDs {
+		ap.log.Info("Resetting notification policies", "org", orgID)
+		if _, err := ap.cfg.NotificationPolicyService.ResetPolicyTree(ctx, orgID); err != nil {
+			return fmt.Errorf("failed to reset notification policies of org %d: %w", orgID, err)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) deleteRules(ctx context.Context, rules []*deleteRule) error {
+	for _, rule := range rules {
+		ap.log.Info("Deleting alert rule", "org", rule.OrgID, "uid", rule.UID)
+		if err := ap.cfg.RuleService.DeleteAlertRule(ctx, rule.OrgID, rule.UID, ngmodels.ProvenanceFile); err != nil {
+			return fmt.Errorf("failed to delete alert rule '%s': %w", rule.UID, err)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) deleteContactPoints(ctx context.Context, contactPoints []*deleteContactPoint) error {
+	for _, cp := range contactPoints {
+		ap.log.Info("Deleting contact point", "org", cp.OrgID, "uid", cp.UID)
+		if err := ap.cfg.ContactPointService.DeleteContactPoint(ctx, cp.OrgID, cp.UID); err != nil {
+			return fmt.Errorf("failed to delete contact point '%s': %w", cp.UID, err)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) deleteMuteTimes(ctx context.Context, muteTimes []*deleteByName) error {
+	for _, mt := range muteTimes {
+		ap.log.Info("Deleting mute time", "org", mt.OrgID, "name", mt.Name)
+		if err := ap.cfg.MuteTimingService.DeleteMuteTiming(ctx, mt.OrgID, mt.Name, ngmodels.ProvenanceFile); err != nil {
+			return fmt.Errorf("failed to delete mute time '%s': %w", mt.Name, err)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) deleteTemplates(ctx context.Context, templates []*deleteByName) error {
+	for _, tmpl := range templates {
+		ap.log.Info("Deleting template", "org", tmpl.OrgID, "name", tmpl.Name)
+		if err := ap.cfg.TemplateService.DeleteTemplate(ctx, tmpl.OrgID, tmpl.Name, ngmodels.ProvenanceFile); err != nil {
+			return fmt.Errorf("failed to delete template '%s': %w", tmpl.Name, err)
+		}
+	}
+	return nil
+}
+
+// deleteOrphans deletes the resources that were provisioned from files but are not in the provisioning files anymore.
+func (ap *AlertingProvisioner) deleteOrphans(ctx context.Context, files []*alertingFile) error {
+	provisioned := newProvisionedResources(files)
+
+	query := &models.SearchOrgsQuery{}
+	if err := ap.cfg.SQLStore.SearchOrgs(ctx, query); err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	for _, org := range query.Result {
+		orphans, err := ap.getOrphans(ctx, org.Id, (&ngmodels.AlertRule{}).ResourceType(), provisioned.rules)
+		if err != nil {
+			return err
+		}
+		for _, uid := range orphans {
+			if err := ap.deleteRules(ctx, []*deleteRule{{OrgID: org.Id, UID: uid}}); err != nil {
+				return err
+			}
+		}
+
+		orphans, err = ap.getOrphans(ctx, org.Id, (&definitions.Route{}).ResourceType(), provisioned.policies)
+		if err != nil {
+			return err
+		}
+		if len(orphans) > 0 {
+			if err := ap.resetPolicies(ctx, []int64{org.Id}); err != nil {
+				return err
+			}
+		}
+
+		orphans, err = ap.getOrphans(ctx, org.Id, (&definitions.EmbeddedContactPoint{}).ResourceType(), provisioned.contactPoints)
+		if err != nil {
+			return err
+		}
+		for _, uid := range orphans {
+			if err := ap.deleteContactPoints(ctx, []*deleteContactPoint{{OrgID: org.Id, UID: uid}}); err != nil {
+				return err
+			}
+		}
+
+		orphans, err = ap.getOrphans(ctx, org.Id, (&definitions.MuteTimeInterval{}).ResourceType(), provisioned.muteTimes)
+		if err != nil {
+			return err
+		}
+		for _, name := range orphans {
+			if err := ap.deleteMuteTimes(ctx, []*deleteByName{{OrgID: org.Id, Name: name}}); err != nil {
+				return err
+			}
+		}
+
+		orphans, err = ap.getOrphans(ctx, org.Id, (&definitions.MessageTemplate{}).ResourceType(), provisioned.templates)
+		if err != nil {
+			return err
+		}
+		for _, name := range orphans {
+			if err := ap.deleteTemplates(ctx, []*deleteByName{{OrgID: org.Id, Name: name}}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getOrphans returns the identifiers of the resources of the given type that have the file provenance
+// but are not in the provisioning files.
+func (ap *AlertingProvisioner) getOrphans(ctx context.Context, orgID int64, resourceType string, provisioned map[int64]map[string]struct{}) ([]string, error) {
+	provenances, err := ap.cfg.ProvenanceStore.GetProvenances(ctx, orgID, resourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provenances of %s resources: %w", resourceType, err)
+	}
+	var orphans []string
+	for id, provenance := range provenances {
+		if provenance != ngmodels.ProvenanceFile {
+			continue
+		}
+		if _, ok := provisioned[orgID][id]; !ok {
+			orphans = append(orphans, id)
+		}
+	}
+	return orphans, nil
+}
+
+func (ap *AlertingProvisioner) getOrCreateFolderUID(ctx context.Context, orgID int64, folderName string) (string, error) {
+	cmd := &models.GetDashboardQuery{Slug: models.SlugifyTitle(folderName), OrgId: orgID}
+	err := ap.cfg.SQLStore.GetDashboard(ctx, cmd)
+	if err != nil && !errors.Is(err, models.ErrDashboardNotFound) {
+		return "", err
+	}
+
+	// folder not found. create one.
+	if errors.Is(err, models.ErrDashboardNotFound) {
+		dash := &dashboards.SaveDashboardDTO{}
+		dash.Dashboard = models.NewDashboardFolder(folderName)
+		dash.Dashboard.IsFolder = true
+		dash.Overwrite = true
+		dash.OrgId = orgID
+		dbDash, err := ap.cfg.DashboardService.SaveFolderForProvisionedDashboards(ctx, dash)
+		if err != nil {
+			return "", err
+		}
+		return dbDash.Uid, nil
+	}
+
+	if !cmd.Result.IsFolder {
+		return "", fmt.Errorf("got invalid response. expected folder, found dashboard")
+	}
+
+	return cmd.Result.Uid, nil
+}
+
+// provisionedResources are the identifiers of the resources in the provisioning files by organization.
+type provisionedResources struct {
+	rules         map[int64]map[string]struct{}
+	contactPoints map[int64]map[string]struct{}
+	policies      map[int64]map[string]struct{}
+	templates     map[int64]map[string]struct{}
+	muteTimes     map[int64]map[string]struct{}
+}
+
+func newProvisionedResources(files []*alertingFile) provisionedResources {
+	r := provisionedResources{
+		rules:         map[int64]map[string]struct{}{},
+		contactPoints: map[int64]map[string]struct{}{},
+		policies:      map[int64]map[string]struct{}{},
+		templates:     map[int64]map[string]struct{}{},
+		muteTimes:     map[int64]map[string]struct{}{},
+	}
+	add := func(m map[int64]map[string]struct{}, orgID int64, id string) {
+		if _, ok := m[orgID]; !ok {
+			m[orgID] = map[string]struct{}{}
+		}
+		m[orgID][id] = struct{}{}
+	}
+	for _, file := range files {
+		for _, group := range file.Groups {
+			for _, rule := range group.Rules {
+				add(r.rules, group.OrgID, rule.UID)
+			}
+		}
+		for _, cp := range file.ContactPoints {
+			for _, receiver := range cp.Receivers {
+				add(r.contactPoints, cp.OrgID, receiver.UID)
+			}
+		}
+		for _, policy := range file.Policies {
+			add(r.policies, policy.OrgID, policy.Policy.ResourceID())
+		}
+		for _, tmpl := range file.Templates {
+			add(r.templates, tmpl.OrgID, tmpl.Template.Name)
+		}
+		for _, mt := range file.MuteTimes {
+			add(r.muteTimes, mt.OrgID, mt.MuteTime.Name)
+		}
+	}
+	return r
+}
@@ -0,0 +1,329 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+func TestAlertingProvisioner(t *testing.T) {
+	t.Run("creates the resources with the file provenance", func(t *testing.T) {
+		sut, fakes := createProvisioner(t)
+
+		require.NoError(t, sut.applyChanges(context.Background(), fullConfig))
+
+		require.Equal(t, []string{"cpu-usage"}, fakes.rules.created)
+		require.Empty(t, fakes.rules.updated)
+		require.Equal(t, "new-folder-uid", fakes.rules.namespaceUIDs["cpu-usage"])
+		require.Equal(t, int64(120), fakes.rules.intervals["cpu"])
+		require.Equal(t, []string{"ops-email"}, fakes.contactPoints.created)
+		require.Equal(t, []string{"weekends"}, fakes.muteTimes.created)
+		require.Equal(t, []string{"ops"}, fakes.templates.set)
+		require.Equal(t, []int64{1}, fakes.policies.updated)
+		for _, p := range fakes.provenances() {
+			require.Equal(t, ngmodels.ProvenanceFile, p)
+		}
+	})
+
+	t.Run("updates the existing resources", func(t *testing.T) {
+		sut, fakes := createProvisioner(t)
+		fakes.sqlStore.folder = &models.Dashboard{Uid: "folder-uid", IsFolder: true}
+		fakes.rules.existing = map[string]struct{}{"cpu-usage": {}}
+		fakes.contactPoints.existing = []definitions.EmbeddedContactPoint{{UID: "ops-email", Name: "ops"}}
+		fakes.muteTimes.existing = map[string]struct{}{"weekends": {}}
+
+		require.NoError(t, sut.applyChanges(context.Background(), fullConfig))
+
+		require.Equal(t, []string{"cpu-usage"}, fakes.rules.updated)
+		require.Empty(t, fakes.rules.created)
+		require.Equal(t, "folder-uid", fakes.rules.namespaceUIDs["cpu-usage"])
+		require.Equal(t, []string{"ops-email"}, fakes.contactPoints.updated)
+		require.Empty(t, fakes.contactPoints.created)
+		require.Equal(t, []string{"weekends"}, fakes.muteTimes.updated)
+		require.Empty(t, fakes.muteTimes.created)
+	})
+
+	t.Run("recreates the contact points that are moved to another contact point", func(t *testing.T) {
+		sut, fakes := createProvisioner(t)
+		fakes.contactPoints.existing = []definitions.EmbeddedContactPoint{{UID: "ops-email", Name: "team"}}
+
+		require.NoError(t, sut.applyChanges(context.Background(), fullConfig))
+
+		require.Contains(t, fakes.contactPoints.deleted, "ops-email")
+		require.Equal(t, []string{"ops-email"}, fakes.contactPoints.created)
+	})
+
+	t.Run("deletes the resources listed for deletion", func(t *testing.T) {
+		sut, fakes := createProvisioner(t)
+
+		require.NoError(t, sut.applyChanges(context.Background(), fullConfig))
+
+		require.Equal(t, []string{"old-rule"}, fakes.rules.deleted)
+		require.Equal(t, []string{"old-contact-point"}, fakes.contactPoints.deleted)
+		require.Equal(t, []string{"old-template"}, fakes.templates.deleted)
+		require.Equal(t, []string{"old-mute-time"}, fakes.muteTimes.deleted)
+	})
+
+	t.Run("deletes the provisioned resources that are not in the files anymore", func(t *testing.T) {
+		sut, fakes := createProvisioner(t)
+		fakes.provenanceStore.records = map[int64]map[string]map[string]ngmodels.Provenance{
+			1: {
+				(&ngmodels.AlertRule{}).ResourceType():               {"cpu-usage": ngmodels.ProvenanceFile, "removed-rule": ngmodels.ProvenanceFile, "api-rule": ngmodels.ProvenanceAPI},
+				(&definitions.EmbeddedContactPoint{}).ResourceType(): {"removed-cp": ngmodels.ProvenanceFile},
+				(&definitions.MessageTemplate{}).ResourceType():      {"removed-template": ngmodels.ProvenanceFile},
+				(&definitions.MuteTimeInterval{}).ResourceType():     {"api-mute-time": ngmodels.ProvenanceAPI},
+			},
+			2: {
+				(&definitions.Route{}).ResourceType(): {"": ngmodels.ProvenanceFile},
+			},
+		}
+
+		require.NoError(t, sut.applyChanges(context.Background(), fullConfig))
+
+		require.ElementsMatch(t, []string{"old-rule", "removed-rule"}, fakes.rules.deleted)
+		require.ElementsMatch(t, []string{"old-contact-point", "removed-cp"}, fakes.contactPoints.deleted)
+		require.ElementsMatch(t, []string{"old-template", "removed-template"}, fakes.templates.deleted)
+		require.Equal(t, []string{"old-mute-time"}, fakes.muteTimes.deleted)
+		require.Equal(t, []int64{2}, fakes.policies.reset)
+	})
+
+	t.Run("fails if the folder of a rule group is a dashboard", func(t *testing.T) {
+		sut, fakes := createProvisioner(t)
+		fakes.sqlStore.folder = &models.Dashboard{Uid: "dashboard-uid"}
+
+		err := sut.applyChanges(context.Background(), fullConfig)
+		require.ErrorContains(t, err, "expected folder, found dashboard")
+	})
+}
+
+type provisionerFakes struct {
+	sqlStore        *fakeSQLStore
+	provenanceStore *fakeProvenanceStore
+	rules           *fakeAlertRuleService
+	contactPoints   *fakeContactPointService
+	policies        *fakeNotificationPolicyService
+	templates       *fakeTemplateService
+	muteTimes       *fakeMuteTimingService
+}
+
+// provenances returns the provenances the resources were created or updated with.
+func (f provisionerFakes) provenances() []ngmodels.Provenance {
+	var result []ngmodels.Provenance
+	result = append(result, f.rules.provenances...)
+	result = append(result, f.contactPoints.provenances...)
+	result = append(result, f.policies.provenances...)
+	result = append(result, f.templates.provenances...)
+	result = append(result, f.muteTimes.provenances...)
+	return result
+}
+
+func createProvisioner(t *testing.T) (AlertingProvisioner, provisionerFakes) {
+	t.Helper()
+
+	dashboardService := &dashboards.FakeDashboardProvisioning{}
+	dashboardService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+		Return(&models.Dashboard{Uid: "new-folder-uid", IsFolder: true}, nil).Maybe()
+
+	fakes := provisionerFakes{
+		sqlStore:        &fakeSQLStore{orgs: []*models.OrgDTO{{Id: 1}, {Id: 2}}},
+		provenanceStore: &fakeProvenanceStore{},
+		rules:           &fakeAlertRuleService{namespaceUIDs: map[string]string{}, intervals: map[string]int64{}},
+		contactPoints:   &fakeContactPointService{},
+		policies:        &fakeNotificationPolicyService{},
+		templates:       &fakeTemplateService{},
+		muteTimes:       &fakeMuteTimingService{},
+	}
+	cfg := ProvisionerConfig{
+		SQLStore:                  fakes.sqlStore,
+		DashboardService:          dashboardService,
+		ProvenanceStore:           fakes.provenanceStore,
+		RuleService:               fakes.rules,
+		ContactPointService:       fakes.contactPoints,
+		NotificationPolicyService: fakes.policies,
+		TemplateService:           fakes.templates,
+		MuteTimingService:         fakes.muteTimes,
+	}
+	return newAlertingProvisioner(cfg, log.New("fake.log")), fakes
+}
+
+type fakeSQLStore struct {
+	orgErr error
+	orgs   []*models.OrgDTO
+	folder *models.Dashboard
+}
+
+func (s *fakeSQLStore) GetOrgById(_ context.Context, q *models.GetOrgByIdQuery) error {
+	if s.orgErr != nil {
+		return s.orgErr
+	}
+	q.Result = &models.Org{Id: q.Id}
+	return nil
+}
+
+func (s *fakeSQLStore) GetDashboard(_ context.Context, q *models.GetDashboardQuery) error {
+	if s.folder == nil {
+		return models.ErrDashboardNotFound
+	}
+	q.Result = s.folder
+	return nil
+}
+
+func (s *fakeSQLStore) SearchOrgs(_ context.Context, q *models.SearchOrgsQuery) error {
+	q.Result = s.orgs
+	return nil
+}
+
+type fakeProvenanceStore struct {
+	records map[int64]map[string]map[string]ngmodels.Provenance
+}
+
+func (s *fakeProvenanceStore) GetProvenances(_ context.Context, orgID int64, resourceType string) (map[string]ngmodels.Provenance, error) {
+	return s.records[orgID][resourceType], nil
+}
+
+type fakeAlertRuleService struct {
+	existing      map[string]struct{}
+	created       []string
+	updated       []string
+	deleted       []string
+	namespaceUIDs map[string]string
+	intervals     map[string]int64
+	provenances   []ngmodels.Provenance
+}
+
+func (s *fakeAlertRuleService) GetAlertRule(_ context.Context, _ int64, ruleUID string) (ngmodels.AlertRule, ngmodels.Provenance, error) {
+	if _, ok := s.existing[ruleUID]; ok {
+		return ngmodels.AlertRule{UID: ruleUID}, ngmodels.ProvenanceFile, nil
+	}
+	return ngmodels.AlertRule{}, ngmodels.ProvenanceNone, ngmodels.ErrAlertRuleNotFound
+}
+
+func (s *fakeAlertRuleService) CreateAlertRule(_ context.Context, rule ngmodels.AlertRule, p ngmodels.Provenance) (ngmodels.AlertRule, error) {
+	s.created = append(s.created, rule.UID)
+	s.namespaceUIDs[rule.UID] = rule.NamespaceUID
+	s.provenances = append(s.provenances, p)
+	return rule, nil
+}
+
+func (s *fakeAlertRuleService) UpdateAlertRule(_ context.Context, rule ngmodels.AlertRule, p ngmodels.Provenance) (ngmodels.AlertRule, error) {
+	s.updated = append(s.updated, rule.UID)
+	s.namespaceUIDs[rule.UID] = rule.NamespaceUID
+	s.provenances = append(s.provenances, p)
+	return rule, nil
+}
+
+func (s *fakeAlertRuleService) DeleteAlertRule(_ context.Context, _ int64, ruleUID string, _ ngmodels.Provenance) error {
+	s.deleted = append(s.deleted, ruleUID)
+	return nil
+}
+
+func (s *fakeAlertRuleService) UpdateRuleGroup(_ context.Context, _ int64, _, group string, intervalSeconds int64, _ ngmodels.Provenance) error {
+	s.intervals[group] = intervalSeconds
+	return nil
+}
+
+type fakeContactPointService struct {
+	existing    []definitions.EmbeddedContactPoint
+	created     []string
+	updated     []string
+	deleted     []string
+	provenances []ngmodels.Provenance
+}
+
+func (s *fakeContactPointService) GetContactPoints(context.Context, int64) ([]definitions.EmbeddedContactPoint, error) {
+	return s.existing, nil
+}
+
+func (s *fakeContactPointService) CreateContactPoint(_ context.Context, _ int64, cp definitions.EmbeddedContactPoint, p ngmodels.Provenance) (definitions.EmbeddedContactPoint, error) {
+	s.created = append(s.created, cp.UID)
+	s.provenances = append(s.provenances, p)
+	return cp, nil
+}
+
+func (s *fakeContactPointService) UpdateContactPoint(_ context.Context, _ int64, cp definitions.EmbeddedContactPoint, p ngmodels.Provenance) error {
+	s.updated = append(s.updated, cp.UID)
+	s.provenances = append(s.provenances, p)
+	return nil
+}
+
+func (s *fakeContactPointService) DeleteContactPoint(_ context.Context, _ int64, uid string) error {
+	s.deleted = append(s.deleted, uid)
+	return nil
+}
+
+type fakeNotificationPolicyService struct {
+	updated     []int64
+	reset       []int64
+	provenances []ngmodels.Provenance
+}
+
+func (s *fakeNotificationPolicyService) UpdatePolicyTree(_ context.Context, orgID int64, _ definitions.Route, p ngmodels.Provenance) error {
+	s.updated = append(s.updated, orgID)
+	s.provenances = append(s.provenances, p)
+	return nil
+}
+
+func (s *fakeNotificationPolicyService) ResetPolicyTree(_ context.Context, orgID int64) (definitions.Route, error) {
+	s.reset = append(s.reset, orgID)
+	return definitions.Route{}, nil
+}
+
+type fakeTemplateService struct {
+	set         []string
+	deleted     []string
+	provenances []ngmodels.Provenance
+}
+
+func (s *fakeTemplateService) SetTemplate(_ context.Context, _ int64, tmpl definitions.MessageTemplate, p ngmodels.Provenance) (definitions.MessageTemplate, error) {
+	s.set = append(s.set, tmpl.Name)
+	s.provenances = append(s.provenances, p)
+	return tmpl, nil
+}
+
+func (s *fakeTemplateService) DeleteTemplate(_ context.Context, _ int64, name string, _ ngmodels.Provenance) error {
+	s.deleted = append(s.deleted, name)
+	return nil
+}
+
+type fakeMuteTimingService struct {
+	existing    map[string]struct{}
+	created     []string
+	updated     []string
+	deleted     []string
+	provenances []ngmodels.Provenance
+}
+
+func (s *fakeMuteTimingService) GetMuteTiming(_ context.Context, _ int64, name string) (definitions.MuteTimeInterval, error) {
+	if _, ok := s.existing[name]; ok {
+		mt := definitions.MuteTimeInterval{}
+		mt.Name = name
+		return mt, nil
+	}
+	return definitions.MuteTimeInterval{}, provisioning.ErrNotFound
+}
+
+func (s *fakeMuteTimingService) CreateMuteTiming(_ context.Context, _ int64, mt definitions.MuteTimeInterval, p ngmodels.Provenance) (definitions.MuteTimeInterval, error) {
+	s.created = append(s.created, mt.Name)
+	s.provenances = append(s.provenances, p)
+	return mt, nil
+}
+
+func (s *fakeMuteTimingService) UpdateMuteTiming(_ context.Context, _ int64, mt definitions.MuteTimeInterval, p ngmodels.Provenance) (definitions.MuteTimeInterval, error) {
+	s.updated = append(s.updated, mt.Name)
+	s.provenances = append(s.provenances, p)
+	return mt, nil
+}
+
+func (s *fakeMuteTimingService) DeleteMuteTiming(_ context.Context, _ int64, name string, _ ngmodels.Provenance) error {
+	s.deleted = append(s.deleted, name)
+	return nil
+}
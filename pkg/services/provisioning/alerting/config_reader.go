@@ -0,0 +1,191 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log      log.Logger
+	orgStore utils.OrgStore
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*alertingFile, error) {
+	var alertingFiles []*alertingFile
+	cr.log.Debug("Looking for alerting provisioning files", "path", path)
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Can't read alerting provisioning files from directory", "path", path, "error", err)
+		return alertingFiles, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing alerting provisioning file", "path", path, "file.Name", file.Name())
+			alertingFile, err := cr.parseConfig(path, file)
+			if err != nil {
+				return nil, fmt.Errorf("failure to parse file %s: %w", file.Name(), err)
+			}
+
+			if alertingFile != nil {
+				alertingFiles = append(alertingFiles, alertingFile)
+			}
+		}
+	}
+
+	cr.log.Debug("Validating alerting provisioning files")
+	if err := cr.validate(ctx, alertingFiles); err != nil {
+		return nil, err
+	}
+
+	return alertingFiles, nil
+}
+
+func (cr *configReader) parseConfig(path string, file os.FileInfo) (*alertingFile, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	if err := yaml.Unmarshal(yamlFile, &apiVersion); err != nil {
+		return nil, err
+	}
+	if apiVersion == nil || apiVersion.APIVersion.Value() != 1 {
+		return nil, fmt.Errorf("unsupported apiVersion, only version 1 is supported")
+	}
+
+	var cfg *alertingFileV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToModel(filename)
+}
+
+// validate checks the fields that are required to provision the resources and sets the organization of the resources
+// that do not have one to the main organization.
+func (cr *configReader) validate(ctx context.Context, alertingFiles []*alertingFile) error {
+	var errStrings []string
+	orgIDs := map[int64]struct{}{}
+	ruleUIDs := map[int64]map[string]string{}
+	useOrg := func(orgID *int64) {
+		if *orgID < 1 {
+			*orgID = 1
+		}
+		orgIDs[*orgID] = struct{}{}
+	}
+
+	for _, file := range alertingFiles {
+		for i, group := range file.Groups {
+			useOrg(&group.OrgID)
+			if group.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: rule group %d doesn't contain required field name", file.Filename, i+1))
+			}
+			if group.Folder == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: rule group %d doesn't contain required field folder", file.Filename, i+1))
+			}
+			for j := range group.Rules {
+				rule := &group.Rules[j]
+				rule.OrgID = group.OrgID
+				if rule.UID == "" {
+					errStrings = append(errStrings, fmt.Sprintf("%s: rule %d of rule group '%s' doesn't contain required field uid", file.Filename, j+1, group.Name))
+					continue
+				}
+				if _, ok := ruleUIDs[rule.OrgID]; !ok {
+					ruleUIDs[rule.OrgID] = map[string]string{}
+				}
+				if previous, ok := ruleUIDs[rule.OrgID][rule.UID]; ok {
+					errStrings = append(errStrings, fmt.Sprintf("%s: rule uid '%s' is already used in %s", file.Filename, rule.UID, previous))
+				}
+				ruleUIDs[rule.OrgID][rule.UID] = file.Filename
+			}
+		}
+
+		for i, rule := range file.DeleteRules {
+			useOrg(&rule.OrgID)
+			if rule.UID == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: deleted rule %d doesn't contain required field uid", file.Filename, i+1))
+			}
+		}
+
+		for i, cp := range file.ContactPoints {
+			useOrg(&cp.OrgID)
+			if cp.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: contact point %d doesn't contain required field name", file.Filename, i+1))
+			}
+			for j, receiver := range cp.Receivers {
+				if receiver.UID == "" {
+					errStrings = append(errStrings, fmt.Sprintf("%s: receiver %d of contact point '%s' doesn't contain required field uid", file.Filename, j+1, cp.Name))
+				}
+			}
+		}
+
+		for i, cp := range file.DeleteContactPoints {
+			useOrg(&cp.OrgID)
+			if cp.UID == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: deleted contact point %d doesn't contain required field uid", file.Filename, i+1))
+			}
+		}
+
+		for i, policy := range file.Policies {
+			useOrg(&policy.OrgID)
+			if policy.Policy.Receiver == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: policy %d doesn't contain required field receiver", file.Filename, i+1))
+			}
+		}
+
+		for i := range file.ResetPolicies {
+			useOrg(&file.ResetPolicies[i])
+		}
+
+		for i, tmpl := range file.Templates {
+			useOrg(&tmpl.OrgID)
+			if tmpl.Template.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: template %d doesn't contain required field name", file.Filename, i+1))
+			}
+		}
+
+		for i, mt := range file.MuteTimes {
+			useOrg(&mt.OrgID)
+			if mt.MuteTime.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: mute time %d doesn't contain required field name", file.Filename, i+1))
+			}
+		}
+
+		for _, deletes := range [][]*deleteByName{file.DeleteTemplates, file.DeleteMuteTimes} {
+			for _, d := range deletes {
+				useOrg(&d.OrgID)
+				if d.Name == "" {
+					errStrings = append(errStrings, fmt.Sprintf("%s: deleted template or mute time doesn't contain required field name", file.Filename))
+				}
+			}
+		}
+	}
+
+	if len(errStrings) != 0 {
+		return fmt.Errorf(strings.Join(errStrings, "\n"))
+	}
+
+	for orgID := range orgIDs {
+		if err := utils.CheckOrgExists(ctx, cr.orgStore, orgID); err != nil {
+			return fmt.Errorf("failed to provision alerting resources of org %d: %w", orgID, err)
+		}
+	}
+
+	return nil
+}
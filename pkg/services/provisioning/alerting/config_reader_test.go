@@ -0,0 +1,122 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	fullConfig         = "testdata/full"
+	missingUID         = "testdata/missing-uid"
+	unsupportedVersion = "testdata/unsupported-version"
+	duplicateUID       = "testdata/duplicate-uid"
+)
+
+func TestConfigReader(t *testing.T) {
+	logger := log.New("fake.log")
+
+	t.Run("can read all the resources", func(t *testing.T) {
+		cr := &configReader{log: logger, orgStore: &fakeSQLStore{}}
+
+		files, err := cr.readConfig(context.Background(), fullConfig)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		file := files[0]
+
+		require.Len(t, file.Groups, 1)
+		group := file.Groups[0]
+		require.Equal(t, int64(1), group.OrgID)
+		require.Equal(t, "cpu", group.Name)
+		require.Equal(t, "Infrastructure", group.Folder)
+		require.Equal(t, 2*time.Minute, group.Interval)
+		require.Len(t, group.Rules, 1)
+
+		rule := group.Rules[0]
+		require.Equal(t, "cpu-usage", rule.UID)
+		require.Equal(t, int64(1), rule.OrgID)
+		require.Equal(t, "cpu", rule.RuleGroup)
+		require.Equal(t, "B", rule.Condition)
+		require.Equal(t, ngmodels.OK, rule.NoDataState)
+		require.Equal(t, ngmodels.AlertingErrState, rule.ExecErrState)
+		require.Equal(t, 5*time.Minute, rule.For)
+		require.Equal(t, map[string]string{"summary": "CPU usage is {{ $values.A }}"}, rule.Annotations)
+		require.Equal(t, map[string]string{"team": "infra"}, rule.Labels)
+		require.Len(t, rule.Data, 2)
+		require.Equal(t, ngmodels.Duration(10*time.Minute), rule.Data[0].RelativeTimeRange.From)
+		require.JSONEq(t, `{"type":"math","expression":"$A > 0.9"}`, string(rule.Data[1].Model))
+
+		require.Equal(t, []*deleteRule{{OrgID: 1, UID: "old-rule"}}, file.DeleteRules)
+
+		require.Len(t, file.ContactPoints, 1)
+		require.Equal(t, int64(1), file.ContactPoints[0].OrgID)
+		require.Len(t, file.ContactPoints[0].Receivers, 1)
+		receiver := file.ContactPoints[0].Receivers[0]
+		require.Equal(t, "ops-email", receiver.UID)
+		require.Equal(t, "ops", receiver.Name)
+		require.Equal(t, "email", receiver.Type)
+		require.True(t, receiver.DisableResolveMessage)
+		require.Equal(t, "ops@example.com", receiver.Settings.Get("addresses").MustString())
+		require.Equal(t, []*deleteContactPoint{{OrgID: 1, UID: "old-contact-point"}}, file.DeleteContactPoints)
+
+		require.Len(t, file.Policies, 1)
+		policy := file.Policies[0].Policy
+		require.Equal(t, "ops", policy.Receiver)
+		require.Len(t, policy.GroupBy, 1)
+		require.Len(t, policy.Routes, 1)
+		require.Len(t, policy.Routes[0].ObjectMatchers, 1)
+		require.Equal(t, []string{"weekends"}, policy.Routes[0].MuteTimeIntervals)
+
+		require.Len(t, file.Templates, 1)
+		require.Equal(t, `{{ define "ops" }}{{ $labels.team }}{{ end }}`, file.Templates[0].Template.Template)
+		require.Equal(t, []*deleteByName{{OrgID: 1, Name: "old-template"}}, file.DeleteTemplates)
+
+		require.Len(t, file.MuteTimes, 1)
+		require.Equal(t, "weekends", file.MuteTimes[0].MuteTime.Name)
+		require.Len(t, file.MuteTimes[0].MuteTime.TimeIntervals, 1)
+		require.Len(t, file.MuteTimes[0].MuteTime.TimeIntervals[0].Weekdays, 2)
+		require.Equal(t, []*deleteByName{{OrgID: 1, Name: "old-mute-time"}}, file.DeleteMuteTimes)
+	})
+
+	t.Run("rules without uid are rejected", func(t *testing.T) {
+		cr := &configReader{log: logger, orgStore: &fakeSQLStore{}}
+
+		_, err := cr.readConfig(context.Background(), missingUID)
+		require.ErrorContains(t, err, "doesn't contain required field uid")
+	})
+
+	t.Run("rules with the same uid are rejected", func(t *testing.T) {
+		cr := &configReader{log: logger, orgStore: &fakeSQLStore{}}
+
+		_, err := cr.readConfig(context.Background(), duplicateUID)
+		require.ErrorContains(t, err, "rule uid 'cpu-usage' is already used")
+	})
+
+	t.Run("files with an unsupported version are rejected", func(t *testing.T) {
+		cr := &configReader{log: logger, orgStore: &fakeSQLStore{}}
+
+		_, err := cr.readConfig(context.Background(), unsupportedVersion)
+		require.ErrorContains(t, err, "unsupported apiVersion")
+	})
+
+	t.Run("resources of unknown organizations are rejected", func(t *testing.T) {
+		cr := &configReader{log: logger, orgStore: &fakeSQLStore{orgErr: models.ErrOrgNotFound}}
+
+		_, err := cr.readConfig(context.Background(), fullConfig)
+		require.ErrorIs(t, err, models.ErrOrgNotFound)
+	})
+
+	t.Run("missing directory is not an error", func(t *testing.T) {
+		cr := &configReader{log: logger, orgStore: &fakeSQLStore{}}
+
+		files, err := cr.readConfig(context.Background(), "testdata/does-not-exist")
+		require.NoError(t, err)
+		require.Empty(t, files)
+	})
+}
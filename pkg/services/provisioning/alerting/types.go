@@ -0,0 +1,402 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion values.Int64Value `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// alertingFile is the normalized content of an alerting provisioning file. Any config version should be mappable
+// to this type.
+type alertingFile struct {
+	Filename            string
+	Groups              []*alertRuleGroup
+	DeleteRules         []*deleteRule
+	ContactPoints       []*contactPoint
+	DeleteContactPoints []*deleteContactPoint
+	Policies            []*notificationPolicy
+	ResetPolicies       []int64
+	Templates           []*messageTemplate
+	DeleteTemplates     []*deleteByName
+	MuteTimes           []*muteTime
+	DeleteMuteTimes     []*deleteByName
+}
+
+type alertRuleGroup struct {
+	OrgID    int64
+	Name     string
+	Folder   string
+	Interval time.Duration
+	Rules    []ngmodels.AlertRule
+}
+
+type deleteRule struct {
+	OrgID int64
+	UID   string
+}
+
+type contactPoint struct {
+	OrgID     int64
+	Name      string
+	Receivers []definitions.EmbeddedContactPoint
+}
+
+type deleteContactPoint struct {
+	OrgID int64
+	UID   string
+}
+
+type notificationPolicy struct {
+	OrgID  int64
+	Policy definitions.Route
+}
+
+type messageTemplate struct {
+	OrgID    int64
+	Template definitions.MessageTemplate
+}
+
+type muteTime struct {
+	OrgID    int64
+	MuteTime definitions.MuteTimeInterval
+}
+
+type deleteByName struct {
+	OrgID int64
+	Name  string
+}
+
+// alertingFileV1 is the mapping of the version 1 of the alerting provisioning files.
+type alertingFileV1 struct {
+	configVersion
+	Groups              []*alertRuleGroupV1     `json:"groups" yaml:"groups"`
+	DeleteRules         []*deleteRuleV1         `json:"deleteRules" yaml:"deleteRules"`
+	ContactPoints       []*contactPointV1       `json:"contactPoints" yaml:"contactPoints"`
+	DeleteContactPoints []*deleteContactPointV1 `json:"deleteContactPoints" yaml:"deleteContactPoints"`
+	Policies            []*notificationPolicyV1 `json:"policies" yaml:"policies"`
+	ResetPolicies       []values.Int64Value     `json:"resetPolicies" yaml:"resetPolicies"`
+	Templates           []*messageTemplateV1    `json:"templates" yaml:"templates"`
+	DeleteTemplates     []*deleteByNameV1       `json:"deleteTemplates" yaml:"deleteTemplates"`
+	MuteTimes           []*muteTimeV1           `json:"muteTimes" yaml:"muteTimes"`
+	DeleteMuteTimes     []*deleteByNameV1       `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
+}
+
+type alertRuleGroupV1 struct {
+	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name     values.StringValue `json:"name" yaml:"name"`
+	Folder   values.StringValue `json:"folder" yaml:"folder"`
+	Interval values.StringValue `json:"interval" yaml:"interval"`
+	Rules    []*alertRuleV1     `json:"rules" yaml:"rules"`
+}
+
+type alertRuleV1 struct {
+	UID          values.StringValue `json:"uid" yaml:"uid"`
+	Title        values.StringValue `json:"title" yaml:"title"`
+	Condition    values.StringValue `json:"condition" yaml:"condition"`
+	Data         []*alertQueryV1    `json:"data" yaml:"data"`
+	DashboardUID values.StringValue `json:"dashboardUid" yaml:"dashboardUid"`
+	PanelID      values.Int64Value  `json:"panelId" yaml:"panelId"`
+	NoDataState  values.StringValue `json:"noDataState" yaml:"noDataState"`
+	ExecErrState values.StringValue `json:"execErrState" yaml:"execErrState"`
+	For          values.StringValue `json:"for" yaml:"for"`
+	// Annotations are not interpolated because they are templates that commonly refer to $labels and $values.
+	Annotations map[string]string     `json:"annotations" yaml:"annotations"`
+	Labels      values.StringMapValue `json:"labels" yaml:"labels"`
+}
+
+type alertQueryV1 struct {
+	RefID             values.StringValue  `json:"refId" yaml:"refId"`
+	QueryType         values.StringValue  `json:"queryType" yaml:"queryType"`
+	RelativeTimeRange relativeTimeRangeV1 `json:"relativeTimeRange" yaml:"relativeTimeRange"`
+	DatasourceUID     values.StringValue  `json:"datasourceUid" yaml:"datasourceUid"`
+	// Model is not interpolated because expressions refer to other queries as $RefID.
+	Model values.JSONValue `json:"model" yaml:"model"`
+}
+
+// relativeTimeRangeV1 is the relative time range of a query in seconds.
+type relativeTimeRangeV1 struct {
+	From values.Int64Value `json:"from" yaml:"from"`
+	To   values.Int64Value `json:"to" yaml:"to"`
+}
+
+type deleteRuleV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+type contactPointV1 struct {
+	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name      values.StringValue `json:"name" yaml:"name"`
+	Receivers []*receiverV1      `json:"receivers" yaml:"receivers"`
+}
+
+type receiverV1 struct {
+	UID                   values.StringValue `json:"uid" yaml:"uid"`
+	Type                  values.StringValue `json:"type" yaml:"type"`
+	Settings              values.JSONValue   `json:"settings" yaml:"settings"`
+	DisableResolveMessage values.BoolValue   `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+}
+
+type deleteContactPointV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+// notificationPolicyV1 is a notification policy tree with the organization it belongs to.
+// The tree is unmarshalled with the unmarshaller of the route so that the matchers and group by labels are parsed.
+type notificationPolicyV1 struct {
+	OrgID  values.Int64Value
+	Policy definitions.Route
+}
+
+func (p *notificationPolicyV1) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	org := struct {
+		OrgID values.Int64Value `yaml:"orgId"`
+	}{}
+	if err := unmarshal(&org); err != nil {
+		return err
+	}
+	p.OrgID = org.OrgID
+	return unmarshal(&p.Policy)
+}
+
+type messageTemplateV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+	// Template is not interpolated because Go templates commonly use variables.
+	Template string `json:"template" yaml:"template"`
+}
+
+// muteTimeV1 is a mute time interval with the organization it belongs to.
+// The interval is unmarshalled with the unmarshaller of the Alertmanager so that the time ranges are parsed.
+type muteTimeV1 struct {
+	OrgID    values.Int64Value
+	MuteTime definitions.MuteTimeInterval
+}
+
+func (m *muteTimeV1) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	org := struct {
+		OrgID values.Int64Value `yaml:"orgId"`
+	}{}
+	if err := unmarshal(&org); err != nil {
+		return err
+	}
+	m.OrgID = org.OrgID
+	return unmarshal(&m.MuteTime.MuteTimeInterval)
+}
+
+type deleteByNameV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+// mapToModel maps config syntax to the normalized alertingFile object.
+func (cfg *alertingFileV1) mapToModel(filename string) (*alertingFile, error) {
+	r := &alertingFile{Filename: filename}
+	if cfg == nil {
+		return r, nil
+	}
+
+	for _, group := range cfg.Groups {
+		if group == nil {
+			continue
+		}
+		g, err := group.mapToModel()
+		if err != nil {
+			return nil, fmt.Errorf("rule group '%s': %w", group.Name.Value(), err)
+		}
+		r.Groups = append(r.Groups, g)
+	}
+
+	for _, rule := range cfg.DeleteRules {
+		if rule == nil {
+			continue
+		}
+		r.DeleteRules = append(r.DeleteRules, &deleteRule{
+			OrgID: rule.OrgID.Value(),
+			UID:   rule.UID.Value(),
+		})
+	}
+
+	for _, cp := range cfg.ContactPoints {
+		if cp == nil {
+			continue
+		}
+		r.ContactPoints = append(r.ContactPoints, cp.mapToModel())
+	}
+
+	for _, cp := range cfg.DeleteContactPoints {
+		if cp == nil {
+			continue
+		}
+		r.DeleteContactPoints = append(r.DeleteContactPoints, &deleteContactPoint{
+			OrgID: cp.OrgID.Value(),
+			UID:   cp.UID.Value(),
+		})
+	}
+
+	for _, policy := range cfg.Policies {
+		if policy == nil {
+			continue
+		}
+		r.Policies = append(r.Policies, &notificationPolicy{
+			OrgID:  policy.OrgID.Value(),
+			Policy: policy.Policy,
+		})
+	}
+
+	for _, orgID := range cfg.ResetPolicies {
+		r.ResetPolicies = append(r.ResetPolicies, orgID.Value())
+	}
+
+	for _, tmpl := range cfg.Templates {
+		if tmpl == nil {
+			continue
+		}
+		r.Templates = append(r.Templates, &messageTemplate{
+			OrgID: tmpl.OrgID.Value(),
+			Template: definitions.MessageTemplate{
+				Name:     tmpl.Name.Value(),
+				Template: tmpl.Template,
+			},
+		})
+	}
+
+	for _, tmpl := range cfg.DeleteTemplates {
+		if tmpl == nil {
+			continue
+		}
+		r.DeleteTemplates = append(r.DeleteTemplates, &deleteByName{
+			OrgID: tmpl.OrgID.Value(),
+			Name:  tmpl.Name.Value(),
+		})
+	}
+
+	for _, mt := range cfg.MuteTimes {
+		if mt == nil {
+			continue
+		}
+		r.MuteTimes = append(r.MuteTimes, &muteTime{
+			OrgID:    mt.OrgID.Value(),
+			MuteTime: mt.MuteTime,
+		})
+	}
+
+	for _, mt := range cfg.DeleteMuteTimes {
+		if mt == nil {
+			continue
+		}
+		r.DeleteMuteTimes = append(r.DeleteMuteTimes, &deleteByName{
+			OrgID: mt.OrgID.Value(),
+			Name:  mt.Name.Value(),
+		})
+	}
+
+	return r, nil
+}
+
+func (group *alertRuleGroupV1) mapToModel() (*alertRuleGroup, error) {
+	g := &alertRuleGroup{
+		OrgID:  group.OrgID.Value(),
+		Name:   group.Name.Value(),
+		Folder: group.Folder.Value(),
+	}
+	if interval := group.Interval.Value(); interval != "" {
+		d, err := model.ParseDuration(interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+		g.Interval = time.Duration(d)
+	}
+
+	for _, rule := range group.Rules {
+		if rule == nil {
+			continue
+		}
+		r, err := rule.mapToModel()
+		if err != nil {
+			return nil, fmt.Errorf("rule '%s': %w", rule.Title.Value(), err)
+		}
+		r.OrgID = g.OrgID
+		r.RuleGroup = g.Name
+		g.Rules = append(g.Rules, r)
+	}
+	return g, nil
+}
+
+func (rule *alertRuleV1) mapToModel() (ngmodels.AlertRule, error) {
+	r := ngmodels.AlertRule{
+		UID:          rule.UID.Value(),
+		Title:        rule.Title.Value(),
+		Condition:    rule.Condition.Value(),
+		NoDataState:  ngmodels.NoDataState(rule.NoDataState.Value()),
+		ExecErrState: ngmodels.ExecutionErrorState(rule.ExecErrState.Value()),
+		Annotations:  rule.Annotations,
+		Labels:       rule.Labels.Value(),
+	}
+	if dashboardUID := rule.DashboardUID.Value(); dashboardUID != "" {
+		panelID := rule.PanelID.Value()
+		r.DashboardUID = &dashboardUID
+		r.PanelID = &panelID
+	}
+	if forValue := rule.For.Value(); forValue != "" {
+		d, err := model.ParseDuration(forValue)
+		if err != nil {
+			return ngmodels.AlertRule{}, fmt.Errorf("invalid for: %w", err)
+		}
+		r.For = time.Duration(d)
+	}
+
+	for _, query := range rule.Data {
+		if query == nil {
+			continue
+		}
+		m, err := json.Marshal(query.Model.Raw)
+		if err != nil {
+			return ngmodels.AlertRule{}, fmt.Errorf("invalid model of query '%s': %w", query.RefID.Value(), err)
+		}
+		r.Data = append(r.Data, ngmodels.AlertQuery{
+			RefID:     query.RefID.Value(),
+			QueryType: query.QueryType.Value(),
+			RelativeTimeRange: ngmodels.RelativeTimeRange{
+				From: ngmodels.Duration(time.Duration(query.RelativeTimeRange.From.Value()) * time.Second),
+				To:   ngmodels.Duration(time.Duration(query.RelativeTimeRange.To.Value()) * time.Second),
+			},
+			DatasourceUID: query.DatasourceUID.Value(),
+			Model:         m,
+		})
+	}
+	return r, nil
+}
+
+func (cp *contactPointV1) mapToModel() *contactPoint {
+	c := &contactPoint{
+		OrgID: cp.OrgID.Value(),
+		Name:  cp.Name.Value(),
+	}
+	for _, receiver := range cp.Receivers {
+		if receiver == nil {
+			continue
+		}
+		c.Receivers = append(c.Receivers, definitions.EmbeddedContactPoint{
+			UID:                   receiver.UID.Value(),
+			Name:                  c.Name,
+			Type:                  receiver.Type.Value(),
+			Settings:              simplejson.NewFromAny(receiver.Settings.Value()),
+			DisableResolveMessage: receiver.DisableResolveMessage.Value(),
+		})
+	}
+	return c
+}
@@ -0,0 +1,222 @@
+package identities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+const (
+	permissionMember = "Member"
+	permissionAdmin  = "Admin"
+)
+
+type configReader struct {
+	log      log.Logger
+	orgStore utils.OrgStore
+}
+
+// readConfig returns the identities of the config files in path. It returns nil if the directory can't be read,
+// which is different from an empty directory: the provisioned identities are only removed in the latter case.
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		cr.log.Error("can't read identity provisioning files from directory", "path", path, "error", err)
+		return nil, nil
+	}
+
+	identities := []*configs{}
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cfg, err := cr.parseIdentityConfig(path, file)
+			if err != nil {
+				return nil, fmt.Errorf("failure to parse file %s: %w", file.Name(), err)
+			}
+
+			if cfg != nil {
+				identities = append(identities, cfg)
+			}
+		}
+	}
+
+	if err := cr.validate(ctx, identities); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+func (cr *configReader) parseIdentityConfig(path string, file os.FileInfo) (*configs, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	if err := yaml.Unmarshal(yamlFile, &apiVersion); err != nil {
+		return nil, err
+	}
+	if apiVersion == nil || apiVersion.APIVersion.Value() != 1 {
+		return nil, errors.New("unsupported apiVersion, only version 1 is supported")
+	}
+
+	var v1 *configsV1
+	if err := yaml.Unmarshal(yamlFile, &v1); err != nil {
+		return nil, err
+	}
+
+	return v1.mapToIdentitiesFromConfig(filename), nil
+}
+
+// validate checks the fields that are required to provision the identities, sets the defaults of the optional fields,
+// and sets the organization of the identities that do not have one to the main organization.
+func (cr *configReader) validate(ctx context.Context, identities []*configs) error {
+	var errStrings []string
+	addError := func(format string, args ...interface{}) {
+		errStrings = append(errStrings, fmt.Sprintf(format, args...))
+	}
+	orgIDs := map[int64]struct{}{}
+	useOrg := func(orgID *int64) {
+		if *orgID < 1 {
+			*orgID = 1
+		}
+		orgIDs[*orgID] = struct{}{}
+	}
+	serviceAccountNames := map[string]string{}
+	tokenNames := map[string]string{}
+	teamNames := map[string]string{}
+
+	for _, cfg := range identities {
+		for i, sa := range cfg.ServiceAccounts {
+			useOrg(&sa.OrgID)
+			if sa.Name == "" {
+				addError("%s: service account %d doesn't contain required field name", cfg.Filename, i+1)
+				continue
+			}
+			key := fmt.Sprintf("%d/%s", sa.OrgID, sa.Name)
+			if previous, ok := serviceAccountNames[key]; ok {
+				addError("%s: service account '%s' is already provisioned in %s", cfg.Filename, sa.Name, previous)
+			}
+			serviceAccountNames[key] = cfg.Filename
+
+			if sa.Role == "" {
+				sa.Role = models.ROLE_VIEWER
+			}
+			if !sa.Role.IsValid() {
+				addError("%s: service account '%s' has invalid role '%s'", cfg.Filename, sa.Name, sa.Role)
+			}
+			for _, err := range validateRoles(sa.Roles) {
+				addError("%s: service account '%s' %s", cfg.Filename, sa.Name, err)
+			}
+
+			for j, token := range sa.Tokens {
+				if token.Name == "" {
+					addError("%s: token %d of service account '%s' doesn't contain required field name", cfg.Filename, j+1, sa.Name)
+					continue
+				}
+				tokenKey := fmt.Sprintf("%d/%s", sa.OrgID, token.Name)
+				if previous, ok := tokenNames[tokenKey]; ok {
+					addError("%s: token '%s' is already provisioned in %s", cfg.Filename, token.Name, previous)
+				}
+				tokenNames[tokenKey] = cfg.Filename
+
+				if err := validateTokenKey(sa.OrgID, token); err != nil {
+					addError("%s: token '%s' of service account '%s' %s", cfg.Filename, token.Name, sa.Name, err)
+				}
+			}
+		}
+
+		for i, team := range cfg.Teams {
+			useOrg(&team.OrgID)
+			if team.Name == "" {
+				addError("%s: team %d doesn't contain required field name", cfg.Filename, i+1)
+				continue
+			}
+			key := fmt.Sprintf("%d/%s", team.OrgID, team.Name)
+			if previous, ok := teamNames[key]; ok {
+				addError("%s: team '%s' is already provisioned in %s", cfg.Filename, team.Name, previous)
+			}
+			teamNames[key] = cfg.Filename
+
+			for _, err := range validateRoles(team.Roles) {
+				addError("%s: team '%s' %s", cfg.Filename, team.Name, err)
+			}
+
+			for j, member := range team.Members {
+				if (member.Login == "") == (member.ServiceAccount == "") {
+					addError("%s: member %d of team '%s' must contain either login or serviceAccount", cfg.Filename, j+1, team.Name)
+				}
+				if member.Permission == "" {
+					member.Permission = permissionMember
+				}
+				if member.Permission != permissionMember && member.Permission != permissionAdmin {
+					addError("%s: member %d of team '%s' has invalid permission '%s', it must be %s or %s", cfg.Filename, j+1, team.Name,
+						member.Permission, permissionMember, permissionAdmin)
+				}
+			}
+		}
+	}
+
+	if len(errStrings) > 0 {
+		return errors.New(strings.Join(errStrings, "\n"))
+	}
+
+	for orgID := range orgIDs {
+		if err := utils.CheckOrgExists(ctx, cr.orgStore, orgID); err != nil {
+			return fmt.Errorf("failed to provision identities of org %d: %w", orgID, err)
+		}
+	}
+
+	return nil
+}
+
+func validateRoles(roles []string) []string {
+	var errs []string
+	seen := map[string]struct{}{}
+	for _, role := range roles {
+		if !strings.HasPrefix(role, accesscontrol.FixedRolePrefix) {
+			errs = append(errs, fmt.Sprintf("has role '%s' that is not a fixed role", role))
+		}
+		if _, ok := seen[role]; ok {
+			errs = append(errs, fmt.Sprintf("has role '%s' more than once", role))
+		}
+		seen[role] = struct{}{}
+	}
+	return errs
+}
+
+// validateTokenKey checks that the key of the token is a reference to a secret that contains a token
+// for the organization of the service account with the name of the token.
+func validateTokenKey(orgID int64, token *tokenFromConfig) error {
+	if token.Key == "" {
+		return errors.New("doesn't contain required field key")
+	}
+	if token.Key == token.RawKey {
+		return errors.New("has a key that is not a reference to a secret, use $__file{} or $__env{}")
+	}
+
+	decoded, err := apikeygen.Decode(token.Key)
+	if err != nil || decoded.Key == "" {
+		return errors.New("has a key that is not a valid token")
+	}
+	if decoded.Name != token.Name || decoded.OrgId != orgID {
+		return fmt.Errorf("has a key that was generated for the token '%s' of org %d", decoded.Name, decoded.OrgId)
+	}
+	return nil
+}
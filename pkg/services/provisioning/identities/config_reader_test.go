@@ -0,0 +1,121 @@
+package identities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+var (
+	logger = log.New("fake.log")
+
+	fullConfig         = "testdata/full"
+	unsupportedVersion = "testdata/unsupported-version"
+	missingName        = "testdata/missing-name"
+	plainToken         = "testdata/plain-token"
+	invalidMember      = "testdata/invalid-member"
+	invalidRole        = "testdata/invalid-role"
+	duplicateTeam      = "testdata/duplicate-team"
+
+	// ciDeployToken is the token {"k":"secret","n":"ci-deploy","id":1}
+	ciDeployToken = "eyJrIjoic2VjcmV0IiwibiI6ImNpLWRlcGxveSIsImlkIjoxfQ=="
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("can read all properties", func(t *testing.T) {
+		t.Setenv("CI_DEPLOY_TOKEN", ciDeployToken)
+		reader := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		cfgs, err := reader.readConfig(context.Background(), fullConfig)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		cfg := cfgs[0]
+		require.Len(t, cfg.ServiceAccounts, 2)
+		require.Equal(t, &serviceAccountFromConfig{
+			OrgID: 1,
+			Name:  "ci",
+			Role:  models.ROLE_EDITOR,
+			Roles: []string{"fixed:dashboards:writer"},
+			Tokens: []*tokenFromConfig{
+				{Name: "ci-deploy", Key: ciDeployToken, RawKey: "$__env{CI_DEPLOY_TOKEN}"},
+			},
+		}, cfg.ServiceAccounts[0])
+
+		// service accounts without an org and a role are applied the defaults
+		require.Equal(t, int64(1), cfg.ServiceAccounts[1].OrgID)
+		require.Equal(t, models.ROLE_VIEWER, cfg.ServiceAccounts[1].Role)
+		require.True(t, cfg.ServiceAccounts[1].IsDisabled)
+
+		require.Len(t, cfg.Teams, 1)
+		require.Equal(t, &teamFromConfig{
+			OrgID: 1,
+			Name:  "SRE",
+			Email: "sre@example.com",
+			Roles: []string{"fixed:users:reader"},
+			Members: []*memberFromConfig{
+				{Login: "admin", Permission: permissionAdmin},
+				{ServiceAccount: "ci", Permission: permissionMember},
+				{Login: "unknown-user", Permission: permissionMember},
+			},
+		}, cfg.Teams[0])
+	})
+
+	t.Run("skip invalid directory", func(t *testing.T) {
+		reader := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		cfgs, err := reader.readConfig(context.Background(), "./invalid-directory")
+		require.NoError(t, err)
+		require.Nil(t, cfgs)
+	})
+
+	t.Run("invalid configs return an error", func(t *testing.T) {
+		testCases := []struct {
+			desc string
+			path string
+			err  string
+		}{
+			{desc: "unsupported version", path: unsupportedVersion, err: "unsupported apiVersion, only version 1 is supported"},
+			{desc: "missing name", path: missingName, err: "service account 1 doesn't contain required field name"},
+			{desc: "token that is not a secret", path: plainToken, err: "has a key that is not a reference to a secret"},
+			{desc: "token that is not valid", path: fullConfig, err: "token 'ci-deploy' of service account 'ci' doesn't contain required field key"},
+			{desc: "invalid member", path: invalidMember, err: "member 1 of team 'SRE' must contain either login or serviceAccount"},
+			{desc: "invalid role", path: invalidRole, err: "team 'SRE' has role 'custom:sre' that is not a fixed role"},
+			{desc: "duplicate team", path: duplicateTeam, err: "team 'SRE' is already provisioned in"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				reader := &configReader{log: logger, orgStore: &mockOrgStore{}}
+				_, err := reader.readConfig(context.Background(), tc.path)
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+
+	t.Run("token must be generated for the token name and organization", func(t *testing.T) {
+		// {"k":"secret","n":"other","id":1}
+		t.Setenv("CI_DEPLOY_TOKEN", "eyJrIjoic2VjcmV0IiwibiI6Im90aGVyIiwiaWQiOjF9")
+		reader := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		_, err := reader.readConfig(context.Background(), fullConfig)
+		require.ErrorContains(t, err, "has a key that was generated for the token 'other' of org 1")
+	})
+
+	t.Run("organization must exist", func(t *testing.T) {
+		t.Setenv("CI_DEPLOY_TOKEN", ciDeployToken)
+		reader := &configReader{log: logger, orgStore: &mockOrgStore{err: models.ErrOrgNotFound}}
+		_, err := reader.readConfig(context.Background(), fullConfig)
+		require.ErrorIs(t, err, models.ErrOrgNotFound)
+	})
+}
+
+type mockOrgStore struct{ err error }
+
+func (m *mockOrgStore) GetOrgById(c context.Context, cmd *models.GetOrgByIdQuery) error {
+	if m.err != nil {
+		return m.err
+	}
+	cmd.Result = &models.Org{Id: cmd.Id}
+	return nil
+}
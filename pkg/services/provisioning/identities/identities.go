@@ -0,0 +1,428 @@
+package identities
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tokenexchange"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type Store interface {
+	CreateTeam(name, email string, orgID int64) (models.Team, error)
+	UpdateTeam(ctx context.Context, cmd *models.UpdateTeamCommand) error
+	DeleteTeam(ctx context.Context, cmd *models.DeleteTeamCommand) error
+	SearchTeams(ctx context.Context, query *models.SearchTeamsQuery) error
+	GetTeamMembers(ctx context.Context, query *models.GetTeamMembersQuery) error
+	GetUserByLogin(ctx context.Context, query *models.GetUserByLoginQuery) error
+	SaveIdentityProvisioning(ctx context.Context, cmd *models.SaveIdentityProvisioningCommand) error
+	DeleteIdentityProvisioning(ctx context.Context, cmd *models.DeleteIdentityProvisioningCommand) error
+	GetProvisionedIdentities(ctx context.Context, query *models.GetProvisionedIdentitiesQuery) error
+}
+
+type ServiceAccountStore interface {
+	CreateServiceAccount(ctx context.Context, orgID int64, name string) (*serviceaccounts.ServiceAccountDTO, error)
+	RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error)
+	UpdateServiceAccount(ctx context.Context, orgID, serviceAccountID int64,
+		saForm *serviceaccounts.UpdateServiceAccountForm) (*serviceaccounts.ServiceAccountProfileDTO, error)
+	DeleteServiceAccount(ctx context.Context, orgID, serviceAccountID int64) error
+	ListTokens(ctx context.Context, orgID int64, serviceAccountID int64) ([]*models.ApiKey, error)
+	AddServiceAccountToken(ctx context.Context, saID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) error
+	DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) error
+}
+
+type RoleStore interface {
+	SetUserFixedRoles(ctx context.Context, orgID, userID int64, roleNames []string) error
+	SetTeamFixedRoles(ctx context.Context, orgID, teamID int64, roleNames []string) error
+}
+
+// Provision scans a directory for provisioning config files
+// and provisions the service accounts and teams in those files.
+func Provision(ctx context.Context, configDirectory string, store Store, serviceAccounts ServiceAccountStore,
+	roles RoleStore, teamPermissions accesscontrol.PermissionsService, orgStore utils.OrgStore) error {
+	ip := newIdentityProvisioner(log.New("provisioning.identities"), store, serviceAccounts, roles, teamPermissions, orgStore)
+	return ip.applyChanges(ctx, configDirectory)
+}
+
+// IdentityProvisioner is responsible for provisioning service accounts, their tokens, teams, team members
+// and the fixed roles assigned to them based on configuration read by the `configReader`. The provisioned
+// identities are read-only in the API, and the ones that are removed from the configuration are deleted.
+type IdentityProvisioner struct {
+	log             log.Logger
+	cfgProvider     *configReader
+	store           Store
+	serviceAccounts ServiceAccountStore
+	roles           RoleStore
+	teamPermissions accesscontrol.PermissionsService
+}
+
+func newIdentityProvisioner(log log.Logger, store Store, serviceAccounts ServiceAccountStore, roles RoleStore,
+	teamPermissions accesscontrol.PermissionsService, orgStore utils.OrgStore) IdentityProvisioner {
+	return IdentityProvisioner{
+		log:             log,
+		cfgProvider:     &configReader{log: log, orgStore: orgStore},
+		store:           store,
+		serviceAccounts: serviceAccounts,
+		roles:           roles,
+		teamPermissions: teamPermissions,
+	}
+}
+
+func (ip *IdentityProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := ip.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+	if configs == nil {
+		return nil
+	}
+
+	// service accounts are provisioned first so that they can be members of the provisioned teams
+	serviceAccountIDs := map[int64]struct{}{}
+	for _, cfg := range configs {
+		for _, sa := range cfg.ServiceAccounts {
+			id, err := ip.applyServiceAccount(ctx, cfg.Filename, sa)
+			if err != nil {
+				return err
+			}
+			serviceAccountIDs[id] = struct{}{}
+		}
+	}
+
+	teamIDs := map[int64]struct{}{}
+	for _, cfg := range configs {
+		for _, team := range cfg.Teams {
+			id, err := ip.applyTeam(ctx, cfg.Filename, team)
+			if err != nil {
+				return err
+			}
+			teamIDs[id] = struct{}{}
+		}
+	}
+
+	if err := ip.deleteRemovedTeams(ctx, teamIDs); err != nil {
+		return err
+	}
+	return ip.deleteRemovedServiceAccounts(ctx, serviceAccountIDs)
+}
+
+func (ip *IdentityProvisioner) applyServiceAccount(ctx context.Context, filename string, sa *serviceAccountFromConfig) (int64, error) {
+	id, err := ip.serviceAccounts.RetrieveServiceAccountIdByName(ctx, sa.OrgID, sa.Name)
+	if err != nil && !errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+		return 0, err
+	}
+
+	if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+		ip.log.Info("inserting service account from configuration", "name", sa.Name, "orgId", sa.OrgID)
+		created, err := ip.serviceAccounts.CreateServiceAccount(ctx, sa.OrgID, sa.Name)
+		if err != nil {
+			return 0, err
+		}
+		id = created.Id
+	} else {
+		ip.log.Debug("updating service account from configuration", "name", sa.Name, "orgId", sa.OrgID)
+	}
+
+	form := &serviceaccounts.UpdateServiceAccountForm{Role: &sa.Role, IsDisabled: &sa.IsDisabled}
+	if _, err := ip.serviceAccounts.UpdateServiceAccount(ctx, sa.OrgID, id, form); err != nil {
+		return 0, err
+	}
+
+	if err := ip.store.SaveIdentityProvisioning(ctx, &models.SaveIdentityProvisioningCommand{
+		OrgId:      sa.OrgID,
+		Kind:       models.IdentityKindServiceAccount,
+		IdentityId: id,
+		ExternalId: filename,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := ip.roles.SetUserFixedRoles(ctx, sa.OrgID, id, sa.Roles); err != nil {
+		return 0, err
+	}
+
+	return id, ip.applyTokens(ctx, sa, id)
+}
+
+// applyTokens adds the tokens of the service account, and replaces the ones whose secret changed. The tokens
+// that are not in the configuration are deleted, except the short-lived ones issued by the token exchange.
+func (ip *IdentityProvisioner) applyTokens(ctx context.Context, sa *serviceAccountFromConfig, id int64) error {
+	existing, err := ip.serviceAccounts.ListTokens(ctx, sa.OrgID, id)
+	if err != nil {
+		return err
+	}
+	existingByName := make(map[string]*models.ApiKey, len(existing))
+	for _, key := range existing {
+		existingByName[key.Name] = key
+	}
+
+	configured := make(map[string]struct{}, len(sa.Tokens))
+	for _, token := range sa.Tokens {
+		configured[token.Name] = struct{}{}
+
+		decoded, err := apikeygen.Decode(token.Key)
+		if err != nil {
+			return err
+		}
+
+		if key, ok := existingByName[token.Name]; ok {
+			valid, err := apikeygen.IsValid(decoded, key.Key)
+			if err != nil {
+				return err
+			}
+			if valid {
+				continue
+			}
+
+			ip.log.Info("replacing service account token from configuration", "name", token.Name, "serviceAccount", sa.Name)
+			if err := ip.serviceAccounts.DeleteServiceAccountToken(ctx, sa.OrgID, id, key.Id); err != nil {
+				return err
+			}
+		} else {
+			ip.log.Info("inserting service account token from configuration", "name", token.Name, "serviceAccount", sa.Name)
+		}
+
+		hashedKey, err := util.EncodePassword(decoded.Key, decoded.Name)
+		if err != nil {
+			return err
+		}
+		cmd := &serviceaccounts.AddServiceAccountTokenCommand{Name: token.Name, OrgId: sa.OrgID, Key: hashedKey}
+		if err := ip.serviceAccounts.AddServiceAccountToken(ctx, id, cmd); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range existing {
+		if _, ok := configured[key.Name]; ok || strings.HasPrefix(key.Name, tokenexchange.TokenNamePrefix) {
+			continue
+		}
+		if err := ip.serviceAccounts.DeleteServiceAccountToken(ctx, sa.OrgID, id, key.Id); err != nil {
+			return err
+		}
+		ip.log.Info("deleted service account token based on configuration", "name", key.Name, "serviceAccount", sa.Name)
+	}
+
+	return nil
+}
+
+func (ip *IdentityProvisioner) applyTeam(ctx context.Context, filename string, team *teamFromConfig) (int64, error) {
+	query := &models.SearchTeamsQuery{
+		OrgId:        team.OrgID,
+		Name:         team.Name,
+		UserIdFilter: models.FilterIgnoreUser,
+		SignedInUser: provisionerUser(team.OrgID),
+		Limit:        1,
+		Page:         1,
+	}
+	if err := ip.store.SearchTeams(ctx, query); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	if len(query.Result.Teams) == 0 {
+		ip.log.Info("inserting team from configuration", "name", team.Name, "orgId", team.OrgID)
+		created, err := ip.store.CreateTeam(team.Name, team.Email, team.OrgID)
+		if err != nil {
+			return 0, err
+		}
+		id = created.Id
+	} else {
+		existing := query.Result.Teams[0]
+		id = existing.Id
+		if existing.Email != team.Email {
+			ip.log.Debug("updating team from configuration", "name", team.Name, "orgId", team.OrgID)
+			cmd := &models.UpdateTeamCommand{Id: id, Name: team.Name, Email: team.Email, OrgId: team.OrgID}
+			if err := ip.store.UpdateTeam(ctx, cmd); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := ip.store.SaveIdentityProvisioning(ctx, &models.SaveIdentityProvisioningCommand{
+		OrgId:      team.OrgID,
+		Kind:       models.IdentityKindTeam,
+		IdentityId: id,
+		ExternalId: filename,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := ip.roles.SetTeamFixedRoles(ctx, team.OrgID, id, team.Roles); err != nil {
+		return 0, err
+	}
+
+	return id, ip.applyTeamMembers(ctx, team, id)
+}
+
+// applyTeamMembers makes the members of the configuration the only members of the team, except the members
+// that are synced from an external auth provider. Members that don't exist are skipped.
+func (ip *IdentityProvisioner) applyTeamMembers(ctx context.Context, team *teamFromConfig, teamID int64) error {
+	desired := map[int64]string{}
+	for _, member := range team.Members {
+		userID, err := ip.getMemberID(ctx, team.OrgID, member)
+		if err != nil {
+			return err
+		}
+		if userID == 0 {
+			ip.log.Warn("skipping team member that doesn't exist", "team", team.Name, "login", member.Login,
+				"serviceAccount", member.ServiceAccount)
+			continue
+		}
+		desired[userID] = member.Permission
+	}
+
+	query := &models.GetTeamMembersQuery{OrgId: team.OrgID, TeamId: teamID, SignedInUser: provisionerUser(team.OrgID)}
+	if err := ip.store.GetTeamMembers(ctx, query); err != nil {
+		return err
+	}
+	current := make(map[int64]*models.TeamMemberDTO, len(query.Result))
+	for _, member := range query.Result {
+		current[member.UserId] = member
+	}
+
+	teamIDString := strconv.FormatInt(teamID, 10)
+	setPermission := func(userID int64, permission string) error {
+		_, err := ip.teamPermissions.SetUserPermission(ctx, team.OrgID, accesscontrol.User{ID: userID}, teamIDString, permission)
+		if errors.Is(err, models.ErrLastTeamAdmin) {
+			ip.log.Warn("can't remove the last admin of the team", "team", team.Name, "userId", userID)
+			return nil
+		}
+		if errors.Is(err, models.ErrUserNotFound) {
+			ip.log.Warn("skipping team member that is not in the organization of the team", "team", team.Name, "userId", userID)
+			return nil
+		}
+		return err
+	}
+
+	// the admins are set before the other members are updated so that the team always keeps an admin
+	for _, permission := range []string{permissionAdmin, permissionMember} {
+		for userID, p := range desired {
+			if p != permission {
+				continue
+			}
+			if member, ok := current[userID]; ok && member.Permission == permissionType(permission) {
+				continue
+			}
+			if err := setPermission(userID, permission); err != nil {
+				return err
+			}
+		}
+	}
+
+	for userID, member := range current {
+		if _, ok := desired[userID]; ok || member.External {
+			continue
+		}
+		if err := setPermission(userID, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getMemberID returns the ID of the user or service account of the member, or 0 if it doesn't exist.
+func (ip *IdentityProvisioner) getMemberID(ctx context.Context, orgID int64, member *memberFromConfig) (int64, error) {
+	if member.ServiceAccount != "" {
+		id, err := ip.serviceAccounts.RetrieveServiceAccountIdByName(ctx, orgID, member.ServiceAccount)
+		if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+			return 0, nil
+		}
+		return id, err
+	}
+
+	query := &models.GetUserByLoginQuery{LoginOrEmail: member.Login}
+	if err := ip.store.GetUserByLogin(ctx, query); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return query.Result.Id, nil
+}
+
+func (ip *IdentityProvisioner) deleteRemovedServiceAccounts(ctx context.Context, provisioned map[int64]struct{}) error {
+	query := &models.GetProvisionedIdentitiesQuery{Kind: models.IdentityKindServiceAccount}
+	if err := ip.store.GetProvisionedIdentities(ctx, query); err != nil {
+		return err
+	}
+
+	for _, identity := range query.Result {
+		if _, ok := provisioned[identity.IdentityId]; ok {
+			continue
+		}
+
+		err := ip.serviceAccounts.DeleteServiceAccount(ctx, identity.OrgId, identity.IdentityId)
+		if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+			err = ip.store.DeleteIdentityProvisioning(ctx, &models.DeleteIdentityProvisioningCommand{
+				Kind:       identity.Kind,
+				IdentityId: identity.IdentityId,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		ip.log.Info("deleted service account removed from configuration", "id", identity.IdentityId, "orgId", identity.OrgId)
+	}
+
+	return nil
+}
+
+func (ip *IdentityProvisioner) deleteRemovedTeams(ctx context.Context, provisioned map[int64]struct{}) error {
+	query := &models.GetProvisionedIdentitiesQuery{Kind: models.IdentityKindTeam}
+	if err := ip.store.GetProvisionedIdentities(ctx, query); err != nil {
+		return err
+	}
+
+	for _, identity := range query.Result {
+		if _, ok := provisioned[identity.IdentityId]; ok {
+			continue
+		}
+
+		err := ip.store.DeleteTeam(ctx, &models.DeleteTeamCommand{OrgId: identity.OrgId, Id: identity.IdentityId})
+		if errors.Is(err, models.ErrTeamNotFound) {
+			err = ip.store.DeleteIdentityProvisioning(ctx, &models.DeleteIdentityProvisioningCommand{
+				Kind:       identity.Kind,
+				IdentityId: identity.IdentityId,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		ip.log.Info("deleted team removed from configuration", "id", identity.IdentityId, "orgId", identity.OrgId)
+	}
+
+	return nil
+}
+
+func permissionType(permission string) models.PermissionType {
+	if permission == permissionAdmin {
+		return models.PERMISSION_ADMIN
+	}
+	return 0
+}
+
+// provisionerUser returns the user used to read the teams and their members, the searches are filtered
+// by the permissions of the user when access control is enabled.
+func provisionerUser(orgID int64) *models.SignedInUser {
+	return &models.SignedInUser{
+		OrgId:   orgID,
+		OrgRole: models.ROLE_ADMIN,
+		Permissions: map[int64]map[string][]string{
+			orgID: {
+				accesscontrol.ActionTeamsRead:    {accesscontrol.ScopeTeamsAll},
+				accesscontrol.ActionOrgUsersRead: {accesscontrol.ScopeUsersAll},
+			},
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package identities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/models"
+	acdatabase "github.com/grafana/grafana/pkg/services/accesscontrol/database"
+	accesscontrolmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/ossaccesscontrol"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	sadatabase "github.com/grafana/grafana/pkg/services/serviceaccounts/database"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIdentityProvisioner(t *testing.T) {
+	t.Setenv("CI_DEPLOY_TOKEN", ciDeployToken)
+	ctx := context.Background()
+	sqlStore := sqlstore.InitTestDB(t)
+	autoAssignOrg := setting.AutoAssignOrg
+	setting.AutoAssignOrg = true
+	defer func() {
+		setting.AutoAssignOrg = autoAssignOrg
+	}()
+	require.NoError(t, sqlstore.CreateOrg(ctx, &models.CreateOrgCommand{Name: "Main Org."}))
+	saStore := sadatabase.NewServiceAccountsStore(sqlStore)
+	acStore := acdatabase.ProvideService(sqlStore)
+	teamPermissions, err := ossaccesscontrol.ProvideTeamPermissions(sqlStore.Cfg, routing.NewRouteRegister(), sqlStore,
+		accesscontrolmock.New(), acStore)
+	require.NoError(t, err)
+	ip := newIdentityProvisioner(logger, sqlStore, saStore, acStore, teamPermissions, &mockOrgStore{})
+
+	admin, err := sqlStore.CreateUser(ctx, models.CreateUserCommand{Login: "admin", OrgId: 1})
+	require.NoError(t, err)
+	editor, err := sqlStore.CreateUser(ctx, models.CreateUserCommand{Login: "editor", OrgId: 1})
+	require.NoError(t, err)
+
+	getTeam := func(t *testing.T) *models.TeamDTO {
+		query := &models.SearchTeamsQuery{OrgId: 1, Name: "SRE", UserIdFilter: models.FilterIgnoreUser, SignedInUser: provisionerUser(1)}
+		require.NoError(t, sqlStore.SearchTeams(ctx, query))
+		require.Len(t, query.Result.Teams, 1)
+		return query.Result.Teams[0]
+	}
+
+	t.Run("service accounts and teams are created and marked as provisioned", func(t *testing.T) {
+		require.NoError(t, ip.applyChanges(ctx, fullConfig))
+
+		ciID, err := saStore.RetrieveServiceAccountIdByName(ctx, 1, "ci")
+		require.NoError(t, err)
+		ci, err := saStore.RetrieveServiceAccount(ctx, 1, ciID)
+		require.NoError(t, err)
+		require.Equal(t, string(models.ROLE_EDITOR), ci.Role)
+		require.True(t, ci.IsProvisioned)
+
+		monitoringID, err := saStore.RetrieveServiceAccountIdByName(ctx, 1, "monitoring")
+		require.NoError(t, err)
+		monitoring, err := saStore.RetrieveServiceAccount(ctx, 1, monitoringID)
+		require.NoError(t, err)
+		require.True(t, monitoring.IsDisabled)
+
+		tokens, err := saStore.ListTokens(ctx, 1, ciID)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		decoded, err := apikeygen.Decode(ciDeployToken)
+		require.NoError(t, err)
+		valid, err := apikeygen.IsValid(decoded, tokens[0].Key)
+		require.NoError(t, err)
+		require.True(t, valid)
+
+		roles, err := acStore.GetUserFixedRoles(ctx, 1, ciID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"fixed:dashboards:writer", "fixed:users:reader"}, roles)
+
+		team := getTeam(t)
+		require.Equal(t, "sre@example.com", team.Email)
+		provisioned := &models.IsIdentityProvisionedQuery{Kind: models.IdentityKindTeam, IdentityId: team.Id}
+		require.NoError(t, sqlStore.IsIdentityProvisioned(ctx, provisioned))
+		require.True(t, provisioned.Result)
+
+		members := &models.GetTeamMembersQuery{OrgId: 1, TeamId: team.Id, SignedInUser: provisionerUser(1)}
+		require.NoError(t, sqlStore.GetTeamMembers(ctx, members))
+		permissions := map[int64]models.PermissionType{}
+		for _, m := range members.Result {
+			permissions[m.UserId] = m.Permission
+		}
+		require.Equal(t, map[int64]models.PermissionType{admin.Id: models.PERMISSION_ADMIN, ciID: 0}, permissions)
+	})
+
+	t.Run("changes are reconciled", func(t *testing.T) {
+		ciID, err := saStore.RetrieveServiceAccountIdByName(ctx, 1, "ci")
+		require.NoError(t, err)
+		role := models.ROLE_ADMIN
+		_, err = saStore.UpdateServiceAccount(ctx, 1, ciID, &serviceaccounts.UpdateServiceAccountForm{Role: &role})
+		require.NoError(t, err)
+		manual := &serviceaccounts.AddServiceAccountTokenCommand{Name: "manual", OrgId: 1, Key: "hashed"}
+		require.NoError(t, saStore.AddServiceAccountToken(ctx, ciID, manual))
+		team := getTeam(t)
+		require.NoError(t, sqlStore.AddTeamMember(editor.Id, 1, team.Id, false, 0))
+
+		require.NoError(t, ip.applyChanges(ctx, fullConfig))
+
+		ci, err := saStore.RetrieveServiceAccount(ctx, 1, ciID)
+		require.NoError(t, err)
+		require.Equal(t, string(models.ROLE_EDITOR), ci.Role)
+		tokens, err := saStore.ListTokens(ctx, 1, ciID)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		require.Equal(t, "ci-deploy", tokens[0].Name)
+		isMember, err := sqlStore.IsTeamMember(1, team.Id, editor.Id)
+		require.NoError(t, err)
+		require.False(t, isMember)
+	})
+
+	t.Run("identities removed from the configuration are deleted", func(t *testing.T) {
+		require.NoError(t, ip.applyChanges(ctx, t.TempDir()))
+
+		_, err := saStore.RetrieveServiceAccountIdByName(ctx, 1, "ci")
+		require.ErrorIs(t, err, serviceaccounts.ErrServiceAccountNotFound)
+		query := &models.SearchTeamsQuery{OrgId: 1, Name: "SRE", UserIdFilter: models.FilterIgnoreUser, SignedInUser: provisionerUser(1)}
+		require.NoError(t, sqlStore.SearchTeams(ctx, query))
+		require.Empty(t, query.Result.Teams)
+		provisioned := &models.GetProvisionedIdentitiesQuery{Kind: models.IdentityKindServiceAccount}
+		require.NoError(t, sqlStore.GetProvisionedIdentities(ctx, provisioned))
+		require.Empty(t, provisioned.Result)
+	})
+}
@@ -0,0 +1,147 @@
+package identities
+
+import (
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion values.Int64Value `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// configs is the normalized content of an identity provisioning file.
+type configs struct {
+	Filename        string
+	ServiceAccounts []*serviceAccountFromConfig
+	Teams           []*teamFromConfig
+}
+
+type serviceAccountFromConfig struct {
+	OrgID      int64
+	Name       string
+	Role       models.RoleType
+	IsDisabled bool
+	Roles      []string
+	Tokens     []*tokenFromConfig
+}
+
+type tokenFromConfig struct {
+	Name string
+	// Key is the token as used by the clients. It must be a reference to a secret,
+	// e.g. $__file{/run/secrets/token}, and RawKey is the reference.
+	Key    string
+	RawKey string
+}
+
+type teamFromConfig struct {
+	OrgID   int64
+	Name    string
+	Email   string
+	Members []*memberFromConfig
+	Roles   []string
+}
+
+// memberFromConfig is a member of a team, either a user identified by their login or email, or a service account
+// of the organization of the team identified by its name.
+type memberFromConfig struct {
+	Login          string
+	ServiceAccount string
+	Permission     string
+}
+
+type configsV1 struct {
+	configVersion
+
+	ServiceAccounts []*serviceAccountFromConfigV1 `json:"serviceAccounts" yaml:"serviceAccounts"`
+	Teams           []*teamFromConfigV1           `json:"teams" yaml:"teams"`
+}
+
+type serviceAccountFromConfigV1 struct {
+	OrgID      values.Int64Value    `json:"orgId" yaml:"orgId"`
+	Name       values.StringValue   `json:"name" yaml:"name"`
+	Role       values.StringValue   `json:"role" yaml:"role"`
+	IsDisabled values.BoolValue     `json:"isDisabled" yaml:"isDisabled"`
+	Roles      []values.StringValue `json:"roles" yaml:"roles"`
+	Tokens     []*tokenFromConfigV1 `json:"tokens" yaml:"tokens"`
+}
+
+type tokenFromConfigV1 struct {
+	Name values.StringValue `json:"name" yaml:"name"`
+	Key  values.StringValue `json:"key" yaml:"key"`
+}
+
+type teamFromConfigV1 struct {
+	OrgID   values.Int64Value     `json:"orgId" yaml:"orgId"`
+	Name    values.StringValue    `json:"name" yaml:"name"`
+	Email   values.StringValue    `json:"email" yaml:"email"`
+	Members []*memberFromConfigV1 `json:"members" yaml:"members"`
+	Roles   []values.StringValue  `json:"roles" yaml:"roles"`
+}
+
+type memberFromConfigV1 struct {
+	Login          values.StringValue `json:"login" yaml:"login"`
+	ServiceAccount values.StringValue `json:"serviceAccount" yaml:"serviceAccount"`
+	Permission     values.StringValue `json:"permission" yaml:"permission"`
+}
+
+func (cfg *configsV1) mapToIdentitiesFromConfig(filename string) *configs {
+	r := &configs{Filename: filename}
+
+	for _, sa := range cfg.ServiceAccounts {
+		if sa == nil {
+			continue
+		}
+		serviceAccount := &serviceAccountFromConfig{
+			OrgID:      sa.OrgID.Value(),
+			Name:       sa.Name.Value(),
+			Role:       models.RoleType(sa.Role.Value()),
+			IsDisabled: sa.IsDisabled.Value(),
+			Roles:      mapRoles(sa.Roles),
+		}
+		for _, token := range sa.Tokens {
+			if token == nil {
+				continue
+			}
+			serviceAccount.Tokens = append(serviceAccount.Tokens, &tokenFromConfig{
+				Name:   token.Name.Value(),
+				Key:    token.Key.Value(),
+				RawKey: token.Key.Raw,
+			})
+		}
+		r.ServiceAccounts = append(r.ServiceAccounts, serviceAccount)
+	}
+
+	for _, t := range cfg.Teams {
+		if t == nil {
+			continue
+		}
+		team := &teamFromConfig{
+			OrgID: t.OrgID.Value(),
+			Name:  t.Name.Value(),
+			Email: t.Email.Value(),
+			Roles: mapRoles(t.Roles),
+		}
+		for _, member := range t.Members {
+			if member == nil {
+				continue
+			}
+			team.Members = append(team.Members, &memberFromConfig{
+				Login:          member.Login.Value(),
+				ServiceAccount: member.ServiceAccount.Value(),
+				Permission:     member.Permission.Value(),
+			})
+		}
+		r.Teams = append(r.Teams, team)
+	}
+
+	return r
+}
+
+func mapRoles(roles []values.StringValue) []string {
+	result := make([]string, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, role.Value())
+	}
+	return result
+}
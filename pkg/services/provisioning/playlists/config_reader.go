@@ -0,0 +1,149 @@
+package playlists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log      log.Logger
+	orgStore utils.OrgStore
+}
+
+// readConfig returns the playlists of the config files in path. It returns nil if the directory doesn't exist,
+// which is different from an empty directory: the provisioned playlists are only removed in the latter case.
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			cr.log.Warn("playlist provisioning directory does not exist", "path", path)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("can't read playlist provisioning files from directory %s: %w", path, err)
+	}
+
+	playlists := []*configs{}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			playlist, err := cr.parsePlaylistConfig(path, file)
+			if err != nil {
+				return nil, fmt.Errorf("failure to parse file %s: %w", file.Name(), err)
+			}
+
+			if playlist != nil {
+				playlists = append(playlists, playlist)
+			}
+		}
+	}
+
+	if err := cr.validate(ctx, playlists); err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+func (cr *configReader) parsePlaylistConfig(path string, file os.FileInfo) (*configs, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	if err := yaml.Unmarshal(yamlFile, &apiVersion); err != nil {
+		return nil, err
+	}
+	if apiVersion == nil || apiVersion.APIVersion.Value() != 1 {
+		return nil, errors.New("unsupported apiVersion, only version 1 is supported")
+	}
+
+	var v1 *configsV1
+	if err := yaml.Unmarshal(yamlFile, &v1); err != nil {
+		return nil, err
+	}
+
+	return v1.mapToPlaylistsFromConfig(filename), nil
+}
+
+// validate checks the fields that are required to provision the playlists and sets the organization of the playlists
+// that do not have one to the main organization.
+func (cr *configReader) validate(ctx context.Context, playlists []*configs) error {
+	var errStrings []string
+	orgIDs := map[int64]struct{}{}
+	uids := map[int64]map[string]string{}
+	useOrg := func(orgID *int64) {
+		if *orgID < 1 {
+			*orgID = 1
+		}
+		orgIDs[*orgID] = struct{}{}
+	}
+
+	for _, cfg := range playlists {
+		for i, playlist := range cfg.Playlists {
+			useOrg(&playlist.OrgID)
+			if playlist.UID == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: playlist %d doesn't contain required field uid", cfg.Filename, i+1))
+				continue
+			}
+			if playlist.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: playlist '%s' doesn't contain required field name", cfg.Filename, playlist.UID))
+			}
+			if playlist.Interval == "" {
+				playlist.Interval = "5m"
+			}
+			for j, item := range playlist.Items {
+				switch item.Type {
+				case models.PlaylistItemTypeDashboardByID, models.PlaylistItemTypeDashboardByUID, models.PlaylistItemTypeDashboardByTag:
+				default:
+					errStrings = append(errStrings, fmt.Sprintf("%s: item %d of playlist '%s' has unknown type '%s'", cfg.Filename, j+1, playlist.UID, item.Type))
+				}
+				if item.Value == "" {
+					errStrings = append(errStrings, fmt.Sprintf("%s: item %d of playlist '%s' doesn't contain required field value", cfg.Filename, j+1, playlist.UID))
+				}
+			}
+
+			if _, ok := uids[playlist.OrgID]; !ok {
+				uids[playlist.OrgID] = map[string]string{}
+			}
+			if previous, ok := uids[playlist.OrgID][playlist.UID]; ok {
+				errStrings = append(errStrings, fmt.Sprintf("%s: playlist uid '%s' is already used in %s", cfg.Filename, playlist.UID, previous))
+			}
+			uids[playlist.OrgID][playlist.UID] = cfg.Filename
+		}
+
+		for i, playlist := range cfg.DeletePlaylists {
+			useOrg(&playlist.OrgID)
+			if playlist.UID == "" {
+				errStrings = append(errStrings, fmt.Sprintf("%s: playlist %d to delete doesn't contain required field uid", cfg.Filename, i+1))
+			}
+		}
+	}
+
+	if len(errStrings) > 0 {
+		return errors.New(strings.Join(errStrings, "\n"))
+	}
+
+	for orgID := range orgIDs {
+		if err := utils.CheckOrgExists(ctx, cr.orgStore, orgID); err != nil {
+			return fmt.Errorf("failed to provision playlists of org %d: %w", orgID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package playlists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+var (
+	logger = log.New("fake.log")
+
+	fullConfig         = "testdata/full"
+	missingUID         = "testdata/missing-uid"
+	invalidItemType    = "testdata/invalid-item-type"
+	unsupportedVersion = "testdata/unsupported-version"
+	duplicateUID       = "testdata/duplicate-uid"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("can read all properties", func(t *testing.T) {
+		reader := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		cfgs, err := reader.readConfig(context.Background(), fullConfig)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		cfg := cfgs[0]
+		require.Len(t, cfg.Playlists, 2)
+		require.Equal(t, &upsertPlaylistFromConfig{
+			OrgID:    1,
+			UID:      "noc-wall",
+			Name:     "NOC wall",
+			Interval: "1m",
+			Items: []models.PlaylistItemDTO{
+				{Type: models.PlaylistItemTypeDashboardByUID, Value: "cluster-overview", Title: "Cluster overview", Order: 1},
+				{Type: models.PlaylistItemTypeDashboardByTag, Value: "noc", Title: "noc", Order: 2},
+			},
+		}, cfg.Playlists[0])
+
+		// playlists without an org and an interval are applied the defaults
+		require.Equal(t, int64(1), cfg.Playlists[1].OrgID)
+		require.Equal(t, "5m", cfg.Playlists[1].Interval)
+
+		require.Equal(t, []*deletePlaylistConfig{{OrgID: 1, UID: "old-noc-wall"}, {OrgID: 1, UID: "not-provisioned"}}, cfg.DeletePlaylists)
+	})
+
+	t.Run("skip invalid directory", func(t *testing.T) {
+		reader := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		cfgs, err := reader.readConfig(context.Background(), "./invalid-directory")
+		require.NoError(t, err)
+		require.Nil(t, cfgs)
+	})
+
+	t.Run("unreadable directory returns an error", func(t *testing.T) {
+		reader := &configReader{log: logger, orgStore: &mockOrgStore{}}
+		_, err := reader.readConfig(context.Background(), "./config_reader.go")
+		require.Error(t, err)
+	})
+
+	t.Run("invalid configs return an error", func(t *testing.T) {
+		testCases := []struct {
+			desc string
+			path string
+			err  string
+		}{
+			{desc: "missing uid", path: missingUID, err: "playlist 1 doesn't contain required field uid"},
+			{desc: "invalid item type", path: invalidItemType, err: "item 1 of playlist 'noc-wall' has unknown type 'panel_by_id'"},
+			{desc: "unsupported version", path: unsupportedVersion, err: "unsupported apiVersion, only version 1 is supported"},
+			{desc: "duplicate uid", path: duplicateUID, err: "playlist uid 'noc-wall' is already used in"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				reader := &configReader{log: logger, orgStore: &mockOrgStore{}}
+				_, err := reader.readConfig(context.Background(), tc.path)
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+
+	t.Run("organization must exist", func(t *testing.T) {
+		reader := &configReader{log: logger, orgStore: &mockOrgStore{err: models.ErrOrgNotFound}}
+		_, err := reader.readConfig(context.Background(), fullConfig)
+		require.ErrorIs(t, err, models.ErrOrgNotFound)
+	})
+}
+
+type mockOrgStore struct{ err error }
+
+func (m *mockOrgStore) GetOrgById(c context.Context, cmd *models.GetOrgByIdQuery) error {
+	if m.err != nil {
+		return m.err
+	}
+	cmd.Result = &models.Org{Id: cmd.Id}
+	return nil
+}
@@ -0,0 +1,159 @@
+package playlists
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type Store interface {
+	GetPlaylistByUid(ctx context.Context, query *models.GetPlaylistByUidQuery) error
+	CreatePlaylist(ctx context.Context, cmd *models.CreatePlaylistCommand) error
+	UpdatePlaylist(ctx context.Context, cmd *models.UpdatePlaylistCommand) error
+	DeletePlaylist(ctx context.Context, cmd *models.DeletePlaylistCommand) error
+	GetProvisionedPlaylists(ctx context.Context, query *models.GetProvisionedPlaylistsQuery) error
+}
+
+// Provision scans a directory for provisioning config files
+// and provisions the playlists in those files.
+func Provision(ctx context.Context, configDirectory string, store Store, orgStore utils.OrgStore) error {
+	pp := newPlaylistProvisioner(log.New("provisioning.playlists"), store, orgStore)
+	return pp.applyChanges(ctx, configDirectory)
+}
+
+// PlaylistProvisioner is responsible for provisioning playlists based on
+// configuration read by the `configReader`. The provisioned playlists are
+// read-only in the API. Provisioned playlists which are no longer in the
+// configuration are deleted, like dashboards of removed files are.
+type PlaylistProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	store       Store
+}
+
+func newPlaylistProvisioner(log log.Logger, store Store, orgStore utils.OrgStore) PlaylistProvisioner {
+	return PlaylistProvisioner{
+		log:         log,
+		cfgProvider: &configReader{log: log, orgStore: orgStore},
+		store:       store,
+	}
+}
+
+func (pp *PlaylistProvisioner) apply(ctx context.Context, cfg *configs) error {
+	if err := pp.deletePlaylists(ctx, cfg.DeletePlaylists); err != nil {
+		return err
+	}
+
+	for _, playlist := range cfg.Playlists {
+		query := &models.GetPlaylistByUidQuery{OrgId: playlist.OrgID, UID: playlist.UID}
+		err := pp.store.GetPlaylistByUid(ctx, query)
+		if err != nil && !errors.Is(err, models.ErrPlaylistNotFound) {
+			return err
+		}
+
+		if errors.Is(err, models.ErrPlaylistNotFound) {
+			pp.log.Info("inserting playlist from configuration", "name", playlist.Name, "uid", playlist.UID)
+			cmd := &models.CreatePlaylistCommand{
+				UID:      playlist.UID,
+				Name:     playlist.Name,
+				Interval: playlist.Interval,
+				Items:    playlist.Items,
+				ReadOnly: true,
+				OrgId:    playlist.OrgID,
+			}
+			if err := pp.store.CreatePlaylist(ctx, cmd); err != nil {
+				return err
+			}
+		} else {
+			pp.log.Debug("updating playlist from configuration", "name", playlist.Name, "uid", playlist.UID)
+			cmd := &models.UpdatePlaylistCommand{
+				OrgId:    playlist.OrgID,
+				Id:       query.Result.Id,
+				Name:     playlist.Name,
+				Interval: playlist.Interval,
+				Items:    playlist.Items,
+				ReadOnly: true,
+			}
+			if err := pp.store.UpdatePlaylist(ctx, cmd); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (pp *PlaylistProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := pp.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+	if configs == nil {
+		return nil
+	}
+
+	provisioned := map[playlistKey]struct{}{}
+	for _, cfg := range configs {
+		if err := pp.apply(ctx, cfg); err != nil {
+			return err
+		}
+		for _, playlist := range cfg.Playlists {
+			provisioned[playlistKey{orgID: playlist.OrgID, uid: playlist.UID}] = struct{}{}
+		}
+	}
+
+	return pp.deleteOrphanedPlaylists(ctx, provisioned)
+}
+
+type playlistKey struct {
+	orgID int64
+	uid   string
+}
+
+// deleteOrphanedPlaylists deletes the read-only playlists which are not in the configuration
+// anymore, otherwise they could not be changed or deleted by anyone.
+func (pp *PlaylistProvisioner) deleteOrphanedPlaylists(ctx context.Context, provisioned map[playlistKey]struct{}) error {
+	query := &models.GetProvisionedPlaylistsQuery{}
+	if err := pp.store.GetProvisionedPlaylists(ctx, query); err != nil {
+		return err
+	}
+
+	for _, playlist := range query.Result {
+		if _, ok := provisioned[playlistKey{orgID: playlist.OrgId, uid: playlist.UID}]; ok {
+			continue
+		}
+
+		cmd := &models.DeletePlaylistCommand{OrgId: playlist.OrgId, Id: playlist.Id}
+		if err := pp.store.DeletePlaylist(ctx, cmd); err != nil {
+			return err
+		}
+
+		pp.log.Info("deleted provisioned playlist which is not in the configuration anymore", "orgId", playlist.OrgId, "uid", playlist.UID)
+	}
+
+	return nil
+}
+
+func (pp *PlaylistProvisioner) deletePlaylists(ctx context.Context, playlistsToDelete []*deletePlaylistConfig) error {
+	for _, playlist := range playlistsToDelete {
+		query := &models.GetPlaylistByUidQuery{OrgId: playlist.OrgID, UID: playlist.UID}
+		if err := pp.store.GetPlaylistByUid(ctx, query); err != nil {
+			if errors.Is(err, models.ErrPlaylistNotFound) {
+				continue
+			}
+			return err
+		}
+
+		cmd := &models.DeletePlaylistCommand{OrgId: playlist.OrgID, Id: query.Result.Id}
+		if err := pp.store.DeletePlaylist(ctx, cmd); err != nil {
+			return err
+		}
+
+		pp.log.Info("deleted playlist based on configuration", "uid", playlist.UID)
+	}
+
+	return nil
+}
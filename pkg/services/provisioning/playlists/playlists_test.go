@@ -0,0 +1,117 @@
+package playlists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestPlaylistProvisioner(t *testing.T) {
+	t.Run("playlists that don't exist are created read-only", func(t *testing.T) {
+		store := &spyStore{}
+		pp := newPlaylistProvisioner(logger, store, &mockOrgStore{})
+		require.NoError(t, pp.applyChanges(context.Background(), fullConfig))
+
+		require.Len(t, store.inserted, 2)
+		require.Len(t, store.updated, 0)
+		require.Len(t, store.deleted, 0)
+		require.Equal(t, "noc-wall", store.inserted[0].UID)
+		require.Len(t, store.inserted[0].Items, 2)
+		require.True(t, store.inserted[0].ReadOnly)
+	})
+
+	t.Run("existing playlists are updated and deleted by uid", func(t *testing.T) {
+		store := &spyStore{items: []*models.Playlist{
+			{Id: 1, UID: "noc-wall", OrgId: 1, Name: "NOC wall"},
+			{Id: 2, UID: "old-noc-wall", OrgId: 1, Name: "Old NOC wall"},
+			{Id: 3, UID: "on-call", OrgId: 2, Name: "On call"},
+		}}
+		pp := newPlaylistProvisioner(logger, store, &mockOrgStore{})
+		require.NoError(t, pp.applyChanges(context.Background(), fullConfig))
+
+		require.Len(t, store.deleted, 1)
+		require.Equal(t, int64(2), store.deleted[0].Id)
+		require.Len(t, store.updated, 1)
+		require.Equal(t, int64(1), store.updated[0].Id)
+		require.Equal(t, "1m", store.updated[0].Interval)
+		require.True(t, store.updated[0].ReadOnly)
+		// the playlist of another organization with the same uid is left alone
+		require.Len(t, store.inserted, 1)
+		require.Equal(t, "on-call", store.inserted[0].UID)
+		require.Equal(t, int64(1), store.inserted[0].OrgId)
+	})
+
+	t.Run("provisioned playlists which are not in the configuration are deleted", func(t *testing.T) {
+		store := &spyStore{items: []*models.Playlist{
+			{Id: 1, UID: "noc-wall", OrgId: 1, Name: "NOC wall", ReadOnly: true},
+			{Id: 2, UID: "removed", OrgId: 1, Name: "Removed", ReadOnly: true},
+			{Id: 3, UID: "on-call", OrgId: 2, Name: "On call", ReadOnly: true},
+			{Id: 4, UID: "manual", OrgId: 1, Name: "Manual"},
+		}}
+		pp := newPlaylistProvisioner(logger, store, &mockOrgStore{})
+		require.NoError(t, pp.applyChanges(context.Background(), fullConfig))
+
+		require.Len(t, store.deleted, 2)
+		require.Equal(t, int64(2), store.deleted[0].Id)
+		require.Equal(t, int64(3), store.deleted[1].Id)
+	})
+
+	t.Run("provisioned playlists are only deleted when the configuration directory can be read", func(t *testing.T) {
+		store := &spyStore{items: []*models.Playlist{
+			{Id: 1, UID: "noc-wall", OrgId: 1, Name: "NOC wall", ReadOnly: true},
+		}}
+		pp := newPlaylistProvisioner(logger, store, &mockOrgStore{})
+		require.NoError(t, pp.applyChanges(context.Background(), "./invalid-directory"))
+		require.Len(t, store.deleted, 0)
+
+		require.Error(t, pp.applyChanges(context.Background(), "./playlists.go"))
+		require.Len(t, store.deleted, 0)
+
+		require.NoError(t, pp.applyChanges(context.Background(), t.TempDir()))
+		require.Len(t, store.deleted, 1)
+	})
+}
+
+type spyStore struct {
+	inserted []*models.CreatePlaylistCommand
+	updated  []*models.UpdatePlaylistCommand
+	deleted  []*models.DeletePlaylistCommand
+	items    []*models.Playlist
+}
+
+func (s *spyStore) GetPlaylistByUid(ctx context.Context, query *models.GetPlaylistByUidQuery) error {
+	for _, p := range s.items {
+		if p.OrgId == query.OrgId && p.UID == query.UID {
+			query.Result = p
+			return nil
+		}
+	}
+	return models.ErrPlaylistNotFound
+}
+
+func (s *spyStore) CreatePlaylist(ctx context.Context, cmd *models.CreatePlaylistCommand) error {
+	s.inserted = append(s.inserted, cmd)
+	return nil
+}
+
+func (s *spyStore) UpdatePlaylist(ctx context.Context, cmd *models.UpdatePlaylistCommand) error {
+	s.updated = append(s.updated, cmd)
+	return nil
+}
+
+func (s *spyStore) GetProvisionedPlaylists(ctx context.Context, query *models.GetProvisionedPlaylistsQuery) error {
+	for _, p := range s.items {
+		if p.ReadOnly {
+			query.Result = append(query.Result, p)
+		}
+	}
+	return nil
+}
+
+func (s *spyStore) DeletePlaylist(ctx context.Context, cmd *models.DeletePlaylistCommand) error {
+	s.deleted = append(s.deleted, cmd)
+	return nil
+}
@@ -0,0 +1,101 @@
+package playlists
+
+import (
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion values.Int64Value `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// configs is the normalized content of a playlist provisioning file.
+type configs struct {
+	Filename        string
+	Playlists       []*upsertPlaylistFromConfig
+	DeletePlaylists []*deletePlaylistConfig
+}
+
+type upsertPlaylistFromConfig struct {
+	OrgID    int64
+	UID      string
+	Name     string
+	Interval string
+	Items    []models.PlaylistItemDTO
+}
+
+type deletePlaylistConfig struct {
+	OrgID int64
+	UID   string
+}
+
+type configsV1 struct {
+	configVersion
+
+	Playlists       []*upsertPlaylistFromConfigV1 `json:"playlists" yaml:"playlists"`
+	DeletePlaylists []*deletePlaylistConfigV1     `json:"deletePlaylists" yaml:"deletePlaylists"`
+}
+
+type upsertPlaylistFromConfigV1 struct {
+	OrgID    values.Int64Value           `json:"orgId" yaml:"orgId"`
+	UID      values.StringValue          `json:"uid" yaml:"uid"`
+	Name     values.StringValue          `json:"name" yaml:"name"`
+	Interval values.StringValue          `json:"interval" yaml:"interval"`
+	Items    []*playlistItemFromConfigV1 `json:"items" yaml:"items"`
+}
+
+type playlistItemFromConfigV1 struct {
+	Type  values.StringValue `json:"type" yaml:"type"`
+	Value values.StringValue `json:"value" yaml:"value"`
+	Title values.StringValue `json:"title" yaml:"title"`
+}
+
+type deletePlaylistConfigV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (cfg *configsV1) mapToPlaylistsFromConfig(filename string) *configs {
+	r := &configs{Filename: filename}
+
+	for _, p := range cfg.Playlists {
+		if p == nil {
+			continue
+		}
+		playlist := &upsertPlaylistFromConfig{
+			OrgID:    p.OrgID.Value(),
+			UID:      p.UID.Value(),
+			Name:     p.Name.Value(),
+			Interval: p.Interval.Value(),
+		}
+		for i, item := range p.Items {
+			if item == nil {
+				continue
+			}
+			title := item.Title.Value()
+			if title == "" {
+				title = item.Value.Value()
+			}
+			playlist.Items = append(playlist.Items, models.PlaylistItemDTO{
+				Type:  item.Type.Value(),
+				Value: item.Value.Value(),
+				Title: title,
+				Order: i + 1,
+			})
+		}
+		r.Playlists = append(r.Playlists, playlist)
+	}
+
+	for _, p := range cfg.DeletePlaylists {
+		if p == nil {
+			continue
+		}
+		r.DeletePlaylists = append(r.DeletePlaylists, &deletePlaylistConfig{
+			OrgID: p.OrgID.Value(),
+			UID:   p.UID.Value(),
+		})
+	}
+
+	return r
+}
@@ -8,17 +8,26 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	plugifaces "github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acdatabase "github.com/grafana/grafana/pkg/services/accesscontrol/database"
 	"github.com/grafana/grafana/pkg/services/alerting"
 	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards"
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/pluginsettings"
+	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/identities"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
+	"github.com/grafana/grafana/pkg/services/provisioning/playlists"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	sadatabase "github.com/grafana/grafana/pkg/services/serviceaccounts/database"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/errutil"
@@ -29,6 +38,7 @@ func ProvideService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, pluginStore p
 	dashboardService dashboardservice.DashboardProvisioningService,
 	datasourceService datasourceservice.DataSourceService,
 	alertingService *alerting.AlertNotificationService, pluginSettings pluginsettings.Service,
+	secretsService secrets.Service, permissionsServices accesscontrol.PermissionsServices,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                     cfg,
@@ -41,10 +51,17 @@ func ProvideService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, pluginStore p
 		provisionNotifiers:      notifiers.Provision,
 		provisionDatasources:    datasources.Provision,
 		provisionPlugins:        plugins.Provision,
+		provisionAlerting:       prov_alerting.Provision,
+		provisionPlaylists:      playlists.Provision,
+		provisionIdentities:     identities.Provision,
 		dashboardService:        dashboardService,
 		datasourceService:       datasourceService,
 		alertingService:         alertingService,
 		pluginsSettings:         pluginSettings,
+		secretsService:          secretsService,
+		serviceAccountsStore:    sadatabase.NewServiceAccountsStore(sqlStore),
+		roleStore:               acdatabase.ProvideService(sqlStore),
+		teamPermissionsService:  permissionsServices.GetTeamService(),
 	}
 	return s, nil
 }
@@ -56,6 +73,9 @@ type ProvisioningService interface {
 	ProvisionPlugins(ctx context.Context) error
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
+	ProvisionAlerting(ctx context.Context) error
+	ProvisionPlaylists(ctx context.Context) error
+	ProvisionIdentities(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 }
@@ -68,6 +88,9 @@ func NewProvisioningServiceImpl() *ProvisioningServiceImpl {
 		provisionNotifiers:      notifiers.Provision,
 		provisionDatasources:    datasources.Provision,
 		provisionPlugins:        plugins.Provision,
+		provisionAlerting:       prov_alerting.Provision,
+		provisionPlaylists:      playlists.Provision,
+		provisionIdentities:     identities.Provision,
 	}
 }
 
@@ -100,11 +123,18 @@ type ProvisioningServiceImpl struct {
 	provisionNotifiers      func(context.Context, string, notifiers.Manager, notifiers.SQLStore, encryption.Internal, *notifications.NotificationService) error
 	provisionDatasources    func(context.Context, string, datasources.Store, utils.OrgStore) error
 	provisionPlugins        func(context.Context, string, plugins.Store, plugifaces.Store, pluginsettings.Service) error
+	provisionAlerting       func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionPlaylists      func(context.Context, string, playlists.Store, utils.OrgStore) error
+	provisionIdentities     func(context.Context, string, identities.Store, identities.ServiceAccountStore, identities.RoleStore, accesscontrol.PermissionsService, utils.OrgStore) error
 	mutex                   sync.Mutex
 	dashboardService        dashboardservice.DashboardProvisioningService
 	datasourceService       datasourceservice.DataSourceService
 	alertingService         *alerting.AlertNotificationService
 	pluginsSettings         pluginsettings.Service
+	secretsService          secrets.Service
+	serviceAccountsStore    identities.ServiceAccountStore
+	roleStore               identities.RoleStore
+	teamPermissionsService  accesscontrol.PermissionsService
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -123,6 +153,21 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	err = ps.ProvisionAlerting(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = ps.ProvisionPlaylists(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = ps.ProvisionIdentities(ctx)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -185,6 +230,58 @@ func (ps *ProvisioningServiceImpl) ProvisionNotifications(ctx context.Context) e
 	return nil
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error {
+	if !ps.Cfg.UnifiedAlerting.IsEnabled() {
+		return nil
+	}
+
+	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
+	st := &store.DBstore{
+		BaseInterval:    ps.Cfg.UnifiedAlerting.BaseInterval,
+		DefaultInterval: ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval,
+		SQLStore:        ps.SQLStore,
+		Logger:          ps.log,
+	}
+	cfg := prov_alerting.ProvisionerConfig{
+		Path:                      alertingPath,
+		SQLStore:                  ps.SQLStore,
+		DashboardService:          ps.dashboardService,
+		ProvenanceStore:           st,
+		RuleService:               provisioning.NewAlertRuleService(st, st, st, int64(ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()), int64(ps.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ps.log),
+		ContactPointService:       provisioning.NewContactPointService(st, ps.secretsService, st, st, ps.log),
+		NotificationPolicyService: provisioning.NewNotificationPolicyService(st, st, st, ps.log),
+		TemplateService:           provisioning.NewTemplateService(st, st, st, ps.log),
+		MuteTimingService:         provisioning.NewMuteTimingService(st, st, st, ps.log),
+	}
+	if err := ps.provisionAlerting(ctx, cfg); err != nil {
+		err = errutil.Wrap("Alerting provisioning error", err)
+		ps.log.Error("Failed to provision alerting", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionPlaylists(ctx context.Context) error {
+	playlistPath := filepath.Join(ps.Cfg.ProvisioningPath, "playlists")
+	if err := ps.provisionPlaylists(ctx, playlistPath, ps.SQLStore, ps.SQLStore); err != nil {
+		err = errutil.Wrap("Playlist provisioning error", err)
+		ps.log.Error("Failed to provision playlists", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionIdentities(ctx context.Context) error {
+	identitiesPath := filepath.Join(ps.Cfg.ProvisioningPath, "identities")
+	if err := ps.provisionIdentities(ctx, identitiesPath, ps.SQLStore, ps.serviceAccountsStore, ps.roleStore,
+		ps.teamPermissionsService, ps.SQLStore); err != nil {
+		err = errutil.Wrap("Identity provisioning error", err)
+		ps.log.Error("Failed to provision identities", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) error {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
 	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.dashboardService, ps.SQLStore, ps.SQLStore)
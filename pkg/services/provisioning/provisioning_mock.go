@@ -8,6 +8,9 @@ type Calls struct {
 	ProvisionPlugins                    []interface{}
 	ProvisionNotifications              []interface{}
 	ProvisionDashboards                 []interface{}
+	ProvisionAlerting                   []interface{}
+	ProvisionPlaylists                  []interface{}
+	ProvisionIdentities                 []interface{}
 	GetDashboardProvisionerResolvedPath []interface{}
 	GetAllowUIUpdatesFromConfig         []interface{}
 	Run                                 []interface{}
@@ -20,6 +23,9 @@ type ProvisioningServiceMock struct {
 	ProvisionPluginsFunc                    func() error
 	ProvisionNotificationsFunc              func() error
 	ProvisionDashboardsFunc                 func() error
+	ProvisionAlertingFunc                   func() error
+	ProvisionPlaylistsFunc                  func() error
+	ProvisionIdentitiesFunc                 func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
 	RunFunc                                 func(ctx context.Context) error
@@ -71,6 +77,30 @@ func (mock *ProvisioningServiceMock) ProvisionDashboards(ctx context.Context) er
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ProvisionAlerting(ctx context.Context) error {
+	mock.Calls.ProvisionAlerting = append(mock.Calls.ProvisionAlerting, nil)
+	if mock.ProvisionAlertingFunc != nil {
+		return mock.ProvisionAlertingFunc()
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionPlaylists(ctx context.Context) error {
+	mock.Calls.ProvisionPlaylists = append(mock.Calls.ProvisionPlaylists, nil)
+	if mock.ProvisionPlaylistsFunc != nil {
+		return mock.ProvisionPlaylistsFunc()
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionIdentities(ctx context.Context) error {
+	mock.Calls.ProvisionIdentities = append(mock.Calls.ProvisionIdentities, nil)
+	if mock.ProvisionIdentitiesFunc != nil {
+		return mock.ProvisionIdentitiesFunc()
+	}
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) GetDashboardProvisionerResolvedPath(name string) string {
 	mock.Calls.GetDashboardProvisionerResolvedPath = append(mock.Calls.GetDashboardProvisionerResolvedPath, name)
 	if mock.GetDashboardProvisionerResolvedPathFunc != nil {
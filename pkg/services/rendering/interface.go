@@ -17,6 +17,7 @@ type RenderType string
 const (
 	RenderCSV RenderType = "csv"
 	RenderPNG RenderType = "png"
+	RenderPDF RenderType = "pdf"
 )
 
 type TimeoutOpts struct {
@@ -62,6 +63,29 @@ type CSVOpts struct {
 	Headers         map[string][]string
 }
 
+// PDFOpts are options for rendering panels to a PDF document. Each panel is
+// rendered to an image with the size of Width and Height and placed on its own page.
+type PDFOpts struct {
+	TimeoutOpts
+	AuthOpts
+	Title             string
+	Panels            []PDFPanel
+	Width             int
+	Height            int
+	Timezone          string
+	ConcurrentLimit   int
+	DeviceScaleFactor float64
+	Headers           map[string][]string
+	Theme             models.Theme
+}
+
+// PDFPanel is a panel rendered to a page of a PDF document. Path is the path
+// of the solo panel page, the same as in Opts.
+type PDFPanel struct {
+	Title string
+	Path  string
+}
+
 type RenderResult struct {
 	FilePath string
 }
@@ -71,6 +95,10 @@ type RenderCSVResult struct {
 	FileName string
 }
 
+type RenderPDFResult struct {
+	FilePath string
+}
+
 type renderFunc func(ctx context.Context, renderKey string, options Opts) (*RenderResult, error)
 type renderCSVFunc func(ctx context.Context, renderKey string, options CSVOpts) (*RenderCSVResult, error)
 
@@ -99,6 +127,7 @@ type Service interface {
 	Version() string
 	Render(ctx context.Context, opts Opts, session Session) (*RenderResult, error)
 	RenderCSV(ctx context.Context, opts CSVOpts, session Session) (*RenderCSVResult, error)
+	RenderPDF(ctx context.Context, opts PDFOpts, session Session) (*RenderPDFResult, error)
 	RenderErrorImage(theme models.Theme, error error) (*RenderResult, error)
 	GetRenderUser(ctx context.Context, key string) (*RenderUser, bool)
 	HasCapability(capability CapabilityName) (CapabilitySupportRequestResult, error)
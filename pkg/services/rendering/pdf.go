@@ -0,0 +1,158 @@
+package rendering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+var ErrNoPanels = errors.New("no panels to render")
+
+const (
+	pdfMargin         = 10.0
+	pdfTitleFontSize  = 10.0
+	pdfHeaderFontSize = 14.0
+	pdfLineHeight     = 7.0
+
+	pdfRenderRetryInterval = 500 * time.Millisecond
+)
+
+type pdfPage struct {
+	title     string
+	imagePath string
+}
+
+// RenderPDF renders each panel to an image and composes the images to a multi-page PDF document.
+func (rs *RenderingService) RenderPDF(ctx context.Context, opts PDFOpts, session Session) (*RenderPDFResult, error) {
+	startTime := time.Now()
+
+	renderKeyProvider := rs.perRequestRenderKeyProvider
+	if session != nil {
+		renderKeyProvider = session
+	}
+	result, err := rs.renderPDF(ctx, opts, renderKeyProvider)
+
+	elapsedTime := time.Since(startTime).Milliseconds()
+	saveMetrics(elapsedTime, err, RenderPDF)
+
+	return result, err
+}
+
+func (rs *RenderingService) renderPDF(ctx context.Context, opts PDFOpts, renderKeyProvider renderKeyProvider) (*RenderPDFResult, error) {
+	if len(opts.Panels) == 0 {
+		return nil, ErrNoPanels
+	}
+
+	if !rs.IsAvailable() {
+		return nil, ErrRenderUnavailable
+	}
+
+	pages := make([]pdfPage, 0, len(opts.Panels))
+	defer func() {
+		for _, page := range pages {
+			if err := os.Remove(page.imagePath); err != nil {
+				rs.log.Warn("Failed to remove rendered panel image", "path", page.imagePath, "error", err)
+			}
+		}
+	}()
+
+	for _, panel := range opts.Panels {
+		// Panels are rendered one by one to not take over the renderer with a single document.
+		result, err := rs.renderPanel(ctx, Opts{
+			TimeoutOpts:       opts.TimeoutOpts,
+			AuthOpts:          opts.AuthOpts,
+			Width:             opts.Width,
+			Height:            opts.Height,
+			Path:              panel.Path,
+			Timezone:          opts.Timezone,
+			ConcurrentLimit:   opts.ConcurrentLimit,
+			DeviceScaleFactor: opts.DeviceScaleFactor,
+			Headers:           opts.Headers,
+			Theme:             opts.Theme,
+		}, renderKeyProvider)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render panel %q: %w", panel.Title, err)
+		}
+		pages = append(pages, pdfPage{title: panel.Title, imagePath: result.FilePath})
+	}
+
+	filePath, err := rs.getNewFilePath(RenderPDF)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := composePDF(filePath, opts.Title, pages); err != nil {
+		return nil, err
+	}
+
+	return &RenderPDFResult{FilePath: filePath}, nil
+}
+
+// renderPanel renders the image of a panel. The document would be incomplete with the placeholder images of render,
+// so when the concurrent limit is reached it waits for the other renders to complete, until the timeout of the panel.
+func (rs *RenderingService) renderPanel(ctx context.Context, opts Opts, renderKeyProvider renderKeyProvider) (*RenderResult, error) {
+	timeout := time.NewTimer(opts.Timeout)
+	defer timeout.Stop()
+
+	for {
+		result, err := rs.renderImage(ctx, opts, renderKeyProvider)
+		if !errors.Is(err, ErrConcurrentLimitReached) {
+			return result, err
+		}
+
+		select {
+		case <-time.After(pdfRenderRetryInterval):
+		case <-timeout.C:
+			return nil, err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// composePDF writes a landscape A4 document with a page for each image. Every page has
+// the document title and the panel title at the top, the image is scaled to fit the
+// rest of the page keeping its aspect ratio.
+func composePDF(filePath string, title string, pages []pdfPage) error {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("Grafana", true)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+
+	// Core fonts only support cp1252, titles are translated from UTF-8.
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, pageHeight := pdf.GetPageSize()
+	contentWidth := pageWidth - 2*pdfMargin
+
+	for _, page := range pages {
+		pdf.AddPage()
+		if title != "" {
+			pdf.SetFont("Helvetica", "", pdfTitleFontSize)
+			pdf.SetTextColor(110, 110, 110)
+			pdf.CellFormat(contentWidth, pdfLineHeight, tr(title), "", 1, "L", false, 0, "")
+		}
+		pdf.SetFont("Helvetica", "B", pdfHeaderFontSize)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(contentWidth, pdfLineHeight, tr(page.title), "", 1, "L", false, 0, "")
+
+		imageOpts := gofpdf.ImageOptions{ImageType: "PNG"}
+		info := pdf.RegisterImageOptions(page.imagePath, imageOpts)
+		if pdf.Err() {
+			return fmt.Errorf("failed to add image of panel %q: %w", page.title, pdf.Error())
+		}
+
+		top := pdf.GetY() + pdfMargin/2
+		maxWidth, maxHeight := contentWidth, pageHeight-top-pdfMargin
+		scale := math.Min(maxWidth/info.Width(), maxHeight/info.Height())
+		width, height := info.Width()*scale, info.Height()*scale
+		pdf.ImageOptions(page.imagePath, pdfMargin+(maxWidth-width)/2, top, width, height, false, imageOpts, 0, "")
+	}
+
+	return pdf.OutputFileAndClose(filePath)
+}
@@ -0,0 +1,115 @@
+package rendering
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type testRenderKeyProvider struct{}
+
+func (p *testRenderKeyProvider) get(_ context.Context, _ AuthOpts) (string, error) {
+	return "key", nil
+}
+
+func (p *testRenderKeyProvider) afterRequest(_ context.Context, _ AuthOpts, _ string) {}
+
+func TestRenderPDF(t *testing.T) {
+	path, err := filepath.Abs("../../../")
+	require.NoError(t, err)
+
+	image, err := os.ReadFile(filepath.Join(path, "public/img/rendering_error_dark.png"))
+	require.NoError(t, err)
+	imagesDir := t.TempDir()
+
+	var renderedPaths []string
+	rs := &RenderingService{
+		Cfg: &setting.Cfg{
+			HomePath:    path,
+			RendererUrl: "http://localhost:8081/render",
+			PDFsDir:     t.TempDir(),
+		},
+		log:                         log.New("test"),
+		perRequestRenderKeyProvider: &testRenderKeyProvider{},
+		renderAction: func(_ context.Context, _ string, opts Opts) (*RenderResult, error) {
+			renderedPaths = append(renderedPaths, opts.Path)
+			imagePath := filepath.Join(imagesDir, fmt.Sprintf("panel-%d.png", len(renderedPaths)))
+			if err := os.WriteFile(imagePath, image, 0600); err != nil {
+				return nil, err
+			}
+			return &RenderResult{FilePath: imagePath}, nil
+		},
+	}
+
+	t.Run("Renders each panel to a page", func(t *testing.T) {
+		renderedPaths = nil
+		result, err := rs.RenderPDF(context.Background(), PDFOpts{
+			Title: "SLO – weekly",
+			Panels: []PDFPanel{
+				{Title: "Availability", Path: "d-solo/uid/slo?panelId=1"},
+				{Title: "Latency", Path: "d-solo/uid/slo?panelId=2"},
+			},
+			Width:           1000,
+			Height:          500,
+			ConcurrentLimit: 1,
+		}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"d-solo/uid/slo?panelId=1", "d-solo/uid/slo?panelId=2"}, renderedPaths)
+		require.Equal(t, ".pdf", filepath.Ext(result.FilePath))
+
+		content, err := os.ReadFile(result.FilePath)
+		require.NoError(t, err)
+		require.Equal(t, "%PDF", string(content[:4]))
+		require.Contains(t, string(content), "/Count 2")
+
+		// the images of the panels are removed once they are in the document
+		images, err := os.ReadDir(imagesDir)
+		require.NoError(t, err)
+		require.Empty(t, images)
+	})
+
+	t.Run("Waits for the other renders to complete when the concurrent limit is reached", func(t *testing.T) {
+		renderedPaths = nil
+		atomic.StoreInt32(&rs.inProgressCount, 2)
+		go func() {
+			time.Sleep(2 * pdfRenderRetryInterval)
+			atomic.StoreInt32(&rs.inProgressCount, 0)
+		}()
+
+		_, err := rs.RenderPDF(context.Background(), PDFOpts{
+			TimeoutOpts:     TimeoutOpts{Timeout: time.Minute},
+			Panels:          []PDFPanel{{Title: "Availability", Path: "d-solo/uid/slo?panelId=1"}},
+			ConcurrentLimit: 1,
+		}, nil)
+		require.NoError(t, err)
+		require.Len(t, renderedPaths, 1)
+	})
+
+	t.Run("Returns error when the concurrent limit is still reached after the timeout", func(t *testing.T) {
+		renderedPaths = nil
+		atomic.StoreInt32(&rs.inProgressCount, 2)
+		t.Cleanup(func() { atomic.StoreInt32(&rs.inProgressCount, 0) })
+
+		_, err := rs.RenderPDF(context.Background(), PDFOpts{
+			TimeoutOpts:     TimeoutOpts{Timeout: pdfRenderRetryInterval},
+			Panels:          []PDFPanel{{Title: "Availability", Path: "d-solo/uid/slo?panelId=1"}},
+			ConcurrentLimit: 1,
+		}, nil)
+		require.ErrorIs(t, err, ErrConcurrentLimitReached)
+		require.Empty(t, renderedPaths)
+	})
+
+	t.Run("Returns error without panels", func(t *testing.T) {
+		_, err := rs.RenderPDF(context.Background(), PDFOpts{}, nil)
+		require.ErrorIs(t, err, ErrNoPanels)
+	})
+}
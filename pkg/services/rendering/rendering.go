@@ -59,6 +59,12 @@ func ProvideService(cfg *setting.Cfg, remoteCache *remotecache.RemoteCache, rm p
 		return nil, fmt.Errorf("failed to create CSVs directory %q: %w", cfg.CSVsDir, err)
 	}
 
+	// ensure PDFsDir exists
+	err = os.MkdirAll(cfg.PDFsDir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PDFs directory %q: %w", cfg.PDFsDir, err)
+	}
+
 	var domain string
 	// set value used for domain attribute of renderKey cookie
 	switch {
@@ -233,7 +239,9 @@ func (rs *RenderingService) Render(ctx context.Context, opts Opts, session Sessi
 }
 
 func (rs *RenderingService) render(ctx context.Context, opts Opts, renderKeyProvider renderKeyProvider) (*RenderResult, error) {
-	if int(atomic.LoadInt32(&rs.inProgressCount)) > opts.ConcurrentLimit {
+	result, err := rs.renderImage(ctx, opts, renderKeyProvider)
+	switch {
+	case errors.Is(err, ErrConcurrentLimitReached):
 		rs.log.Warn("Could not render image, hit the currency limit", "concurrencyLimit", opts.ConcurrentLimit, "path", opts.Path)
 
 		theme := models.ThemeDark
@@ -244,14 +252,25 @@ func (rs *RenderingService) render(ctx context.Context, opts Opts, renderKeyProv
 		return &RenderResult{
 			FilePath: filepath.Join(rs.Cfg.HomePath, filePath),
 		}, nil
-	}
-
-	if !rs.IsAvailable() {
+	case errors.Is(err, ErrRenderUnavailable):
 		rs.log.Warn("Could not render image, no image renderer found/installed. " +
 			"For image rendering support please install the grafana-image-renderer plugin. " +
 			"Read more at https://grafana.com/docs/grafana/latest/administration/image_rendering/")
 		return rs.renderUnavailableImage(), nil
 	}
+	return result, err
+}
+
+// renderImage renders the image like render, but returns ErrConcurrentLimitReached and ErrRenderUnavailable
+// instead of the placeholder images.
+func (rs *RenderingService) renderImage(ctx context.Context, opts Opts, renderKeyProvider renderKeyProvider) (*RenderResult, error) {
+	if int(atomic.LoadInt32(&rs.inProgressCount)) > opts.ConcurrentLimit {
+		return nil, ErrConcurrentLimitReached
+	}
+
+	if !rs.IsAvailable() {
+		return nil, ErrRenderUnavailable
+	}
 
 	rs.log.Info("Rendering", "path", opts.Path)
 	if math.IsInf(opts.DeviceScaleFactor, 0) || math.IsNaN(opts.DeviceScaleFactor) || opts.DeviceScaleFactor == 0 {
@@ -320,9 +339,13 @@ func (rs *RenderingService) getNewFilePath(rt RenderType) (string, error) {
 
 	ext := "png"
 	folder := rs.Cfg.ImagesDir
-	if rt == RenderCSV {
+	switch rt {
+	case RenderCSV:
 		ext = "csv"
 		folder = rs.Cfg.CSVsDir
+	case RenderPDF:
+		ext = "pdf"
+		folder = rs.Cfg.PDFsDir
 	}
 
 	return filepath.Abs(filepath.Join(folder, fmt.Sprintf("%s.%s", rand, ext)))
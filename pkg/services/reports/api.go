@@ -0,0 +1,117 @@
+package reports
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *ReportService) registerAPIEndpoints() {
+	s.RouteRegister.Group("/api/scheduled-reports", func(entities routing.RouteRegister) {
+		entities.Get("/", middleware.ReqEditorRole, routing.Wrap(s.searchHandler))
+		entities.Post("/", middleware.ReqEditorRole, routing.Wrap(s.createHandler))
+		entities.Get("/:uid", middleware.ReqEditorRole, routing.Wrap(s.getHandler))
+		entities.Put("/:uid", middleware.ReqEditorRole, routing.Wrap(s.updateHandler))
+		entities.Delete("/:uid", middleware.ReqEditorRole, routing.Wrap(s.deleteHandler))
+		entities.Post("/:uid/send", middleware.ReqEditorRole, routing.Wrap(s.sendHandler))
+	})
+}
+
+func (s *ReportService) searchHandler(c *models.ReqContext) response.Response {
+	reports, err := s.SearchReports(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get reports", err)
+	}
+
+	return response.JSON(http.StatusOK, ReportsResponse{Result: reports})
+}
+
+func (s *ReportService) createHandler(c *models.ReqContext) response.Response {
+	cmd := CreateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	report, err := s.CreateReport(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		return reportErrorResponse(err, "Failed to create report")
+	}
+
+	return response.JSON(http.StatusOK, ReportResponse{Result: report})
+}
+
+func (s *ReportService) getHandler(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(uid) {
+		return response.Error(http.StatusNotFound, "Report not found", nil)
+	}
+
+	report, err := s.GetReport(c.Req.Context(), c.SignedInUser, uid)
+	if err != nil {
+		return reportErrorResponse(err, "Failed to get report")
+	}
+
+	return response.JSON(http.StatusOK, ReportResponse{Result: report})
+}
+
+func (s *ReportService) updateHandler(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(uid) {
+		return response.Error(http.StatusNotFound, "Report not found", nil)
+	}
+
+	cmd := UpdateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	report, err := s.UpdateReport(c.Req.Context(), c.SignedInUser, uid, cmd)
+	if err != nil {
+		return reportErrorResponse(err, "Failed to update report")
+	}
+
+	return response.JSON(http.StatusOK, ReportResponse{Result: report})
+}
+
+func (s *ReportService) deleteHandler(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(uid) {
+		return response.Error(http.StatusNotFound, "Report not found", nil)
+	}
+
+	if err := s.DeleteReport(c.Req.Context(), c.SignedInUser, uid); err != nil {
+		return reportErrorResponse(err, "Failed to delete report")
+	}
+
+	return response.Success("Report deleted")
+}
+
+func (s *ReportService) sendHandler(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(uid) {
+		return response.Error(http.StatusNotFound, "Report not found", nil)
+	}
+
+	if err := s.SendReport(c.Req.Context(), c.SignedInUser, uid); err != nil {
+		return reportErrorResponse(err, "Failed to send report")
+	}
+
+	return response.Success("Report sent")
+}
+
+func reportErrorResponse(err error, message string) response.Response {
+	switch {
+	case errors.Is(err, ErrReportNotFound):
+		return response.Error(http.StatusNotFound, "Report not found", err)
+	case errors.Is(err, ErrInvalidReport):
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	default:
+		return response.Error(http.StatusInternalServerError, message, err)
+	}
+}
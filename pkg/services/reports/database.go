@@ -0,0 +1,206 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func (s *ReportService) createReport(ctx context.Context, user *models.SignedInUser, cmd CreateReportCommand) (ReportDTO, error) {
+	if err := s.checkReportCommand(ctx, user, &cmd.ReportCommand); err != nil {
+		return ReportDTO{}, err
+	}
+
+	now := time.Now()
+	report := Report{
+		UID:       util.GenerateShortUID(),
+		OrgID:     user.OrgId,
+		CreatedBy: user.UserId,
+		UpdatedBy: user.UserId,
+		Created:   now.Unix(),
+	}
+	if err := applyReportCommand(&report, cmd.ReportCommand, now); err != nil {
+		return ReportDTO{}, err
+	}
+
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Insert(&report)
+		return err
+	})
+	if err != nil {
+		return ReportDTO{}, err
+	}
+
+	return report.toDTO(), nil
+}
+
+func (s *ReportService) updateReport(ctx context.Context, user *models.SignedInUser, uid string, cmd UpdateReportCommand) (ReportDTO, error) {
+	if err := s.checkReportCommand(ctx, user, &cmd.ReportCommand); err != nil {
+		return ReportDTO{}, err
+	}
+
+	var report Report
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		exists, err := session.Where("org_id = ? AND uid = ?", user.OrgId, uid).Get(&report)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrReportNotFound
+		}
+
+		if err := applyReportCommand(&report, cmd.ReportCommand, time.Now()); err != nil {
+			return err
+		}
+		report.UpdatedBy = user.UserId
+
+		_, err = session.ID(report.ID).AllCols().Update(&report)
+		return err
+	})
+	if err != nil {
+		return ReportDTO{}, err
+	}
+
+	return report.toDTO(), nil
+}
+
+func (s *ReportService) deleteReport(ctx context.Context, orgID int64, uid string) error {
+	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		affected, err := session.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&Report{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrReportNotFound
+		}
+		return nil
+	})
+}
+
+func (s *ReportService) getReport(ctx context.Context, orgID int64, uid string) (Report, error) {
+	var report Report
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		exists, err := session.Where("org_id = ? AND uid = ?", orgID, uid).Get(&report)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrReportNotFound
+		}
+		return nil
+	})
+	return report, err
+}
+
+func (s *ReportService) searchReports(ctx context.Context, orgID int64) ([]Report, error) {
+	reports := make([]Report, 0)
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		return session.Where("org_id = ?", orgID).Asc("name").Find(&reports)
+	})
+	return reports, err
+}
+
+// getDueReports returns enabled reports of all organizations which should have been
+// sent by now.
+func (s *ReportService) getDueReports(ctx context.Context, now time.Time) ([]Report, error) {
+	reports := make([]Report, 0)
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		return session.
+			Where("enabled = "+s.SQLStore.Dialect.BooleanStr(true)+" AND next_run_at <= ?", now.Unix()).
+			Asc("next_run_at").
+			Find(&reports)
+	})
+	return reports, err
+}
+
+// claimReport moves the next run of the report, it returns false when the report was
+// updated or claimed in the meantime.
+func (s *ReportService) claimReport(ctx context.Context, report Report, nextRunAt int64) (bool, error) {
+	var claimed bool
+	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		res, err := session.Exec("UPDATE scheduled_report SET next_run_at = ? WHERE id = ? AND next_run_at = ? AND updated = ?",
+			nextRunAt, report.ID, report.NextRunAt, report.Updated)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		claimed = affected == 1
+		return err
+	})
+	return claimed, err
+}
+
+func (s *ReportService) saveRunResult(ctx context.Context, id int64, lastRunAt int64, lastError string) error {
+	return s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+		_, err := session.Exec("UPDATE scheduled_report SET last_run_at = ?, last_error = ? WHERE id = ?", lastRunAt, lastError, id)
+		return err
+	})
+}
+
+// checkReportCommand validates the command and checks that the user can view the
+// dashboard of the report.
+func (s *ReportService) checkReportCommand(ctx context.Context, user *models.SignedInUser, cmd *ReportCommand) error {
+	if err := cmd.validate(); err != nil {
+		return err
+	}
+
+	canView, err := s.canViewDashboard(ctx, user, cmd.DashboardUID)
+	if err != nil {
+		if errors.Is(err, models.ErrDashboardNotFound) {
+			return fmt.Errorf("%w: dashboard not found", ErrInvalidReport)
+		}
+		return err
+	}
+	if !canView {
+		return fmt.Errorf("%w: dashboard not found", ErrInvalidReport)
+	}
+	return nil
+}
+
+// canViewReport checks that the user can view the dashboard of the report. Reports of
+// deleted dashboards can be viewed, so that they can be fixed or deleted.
+func (s *ReportService) canViewReport(ctx context.Context, user *models.SignedInUser, report Report) (bool, error) {
+	canView, err := s.canViewDashboard(ctx, user, report.DashboardUID)
+	if errors.Is(err, models.ErrDashboardNotFound) {
+		return true, nil
+	}
+	return canView, err
+}
+
+// canViewDashboard checks that the user can view the dashboard, it returns
+// models.ErrDashboardNotFound if there is no such dashboard.
+func (s *ReportService) canViewDashboard(ctx context.Context, user *models.SignedInUser, dashboardUID string) (bool, error) {
+	query := models.GetDashboardQuery{Uid: dashboardUID, OrgId: user.OrgId}
+	if err := s.SQLStore.GetDashboard(ctx, &query); err != nil {
+		return false, err
+	}
+
+	g := guardian.New(ctx, query.Result.Id, user.OrgId, user)
+	return g.CanView()
+}
+
+func applyReportCommand(report *Report, cmd ReportCommand, now time.Time) error {
+	report.Name = cmd.Name
+	report.DashboardUID = cmd.DashboardUID
+	report.TimeFrom = cmd.TimeFrom
+	report.TimeTo = cmd.TimeTo
+	report.Variables = cmd.Variables
+	report.Recipients = cmd.Recipients
+	report.Schedule = cmd.Schedule
+	report.Timezone = cmd.Timezone
+	report.Enabled = cmd.Enabled
+	report.Updated = now.Unix()
+
+	next, err := nextRun(cmd.Schedule, cmd.Timezone, now)
+	if err != nil {
+		return err
+	}
+	report.NextRunAt = next.Unix()
+	return nil
+}
@@ -0,0 +1,113 @@
+package reports
+
+import (
+	"errors"
+)
+
+var (
+	ErrReportNotFound = errors.New("report not found")
+	ErrInvalidReport  = errors.New("invalid report")
+)
+
+// Report is a schedule for sending panels of a dashboard as a PDF document by email.
+type Report struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	UID          string `xorm:"uid"`
+	OrgID        int64  `xorm:"org_id"`
+	Name         string
+	DashboardUID string `xorm:"dashboard_uid"`
+	TimeFrom     string
+	TimeTo       string
+	Variables    map[string][]string
+	Recipients   []string
+	Schedule     string
+	Timezone     string
+	Enabled      bool
+	NextRunAt    int64
+	LastRunAt    int64
+	LastError    string
+	CreatedBy    int64
+	UpdatedBy    int64
+	Created      int64
+	Updated      int64
+}
+
+func (Report) TableName() string {
+	return "scheduled_report"
+}
+
+// ReportCommand holds the settings of a report for creating and updating it.
+type ReportCommand struct {
+	Name         string `json:"name"`
+	DashboardUID string `json:"dashboardUid"`
+	// TimeFrom and TimeTo is the time range of the dashboard, e.g. now-7d and now.
+	TimeFrom string `json:"timeFrom"`
+	TimeTo   string `json:"timeTo"`
+	// Variables are values of dashboard template variables by variable name.
+	Variables  map[string][]string `json:"variables"`
+	Recipients []string            `json:"recipients"`
+	// Schedule is a cron expression with five fields or a descriptor such as @weekly.
+	Schedule string `json:"schedule"`
+	// Timezone is an IANA time zone name used for the schedule and for rendering.
+	Timezone string `json:"timezone"`
+	Enabled  bool   `json:"enabled"`
+}
+
+type CreateReportCommand struct {
+	ReportCommand
+}
+
+type UpdateReportCommand struct {
+	ReportCommand
+}
+
+type ReportDTO struct {
+	UID          string              `json:"uid"`
+	Name         string              `json:"name"`
+	DashboardUID string              `json:"dashboardUid"`
+	TimeFrom     string              `json:"timeFrom"`
+	TimeTo       string              `json:"timeTo"`
+	Variables    map[string][]string `json:"variables"`
+	Recipients   []string            `json:"recipients"`
+	Schedule     string              `json:"schedule"`
+	Timezone     string              `json:"timezone"`
+	Enabled      bool                `json:"enabled"`
+	NextRunAt    int64               `json:"nextRunAt"`
+	LastRunAt    int64               `json:"lastRunAt"`
+	LastError    string              `json:"lastError"`
+	CreatedBy    int64               `json:"createdBy"`
+	UpdatedBy    int64               `json:"updatedBy"`
+	Created      int64               `json:"created"`
+	Updated      int64               `json:"updated"`
+}
+
+// ReportResponse is a response struct for ReportDTO
+type ReportResponse struct {
+	Result ReportDTO `json:"result"`
+}
+
+type ReportsResponse struct {
+	Result []ReportDTO `json:"result"`
+}
+
+func (r Report) toDTO() ReportDTO {
+	return ReportDTO{
+		UID:          r.UID,
+		Name:         r.Name,
+		DashboardUID: r.DashboardUID,
+		TimeFrom:     r.TimeFrom,
+		TimeTo:       r.TimeTo,
+		Variables:    r.Variables,
+		Recipients:   r.Recipients,
+		Schedule:     r.Schedule,
+		Timezone:     r.Timezone,
+		Enabled:      r.Enabled,
+		NextRunAt:    r.NextRunAt,
+		LastRunAt:    r.LastRunAt,
+		LastError:    r.LastError,
+		CreatedBy:    r.CreatedBy,
+		UpdatedBy:    r.UpdatedBy,
+		Created:      r.Created,
+		Updated:      r.Updated,
+	}
+}
@@ -0,0 +1,215 @@
+package reports
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// schedulerInterval is how often the scheduler checks for reports which are due.
+const schedulerInterval = time.Minute
+
+func ProvideService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, routeRegister routing.RouteRegister,
+	serverLockService *serverlock.ServerLockService, renderService rendering.Service,
+	emailSender notifications.EmailSender) *ReportService {
+	s := &ReportService{
+		Cfg:               cfg,
+		SQLStore:          sqlStore,
+		RouteRegister:     routeRegister,
+		ServerLockService: serverLockService,
+		RenderService:     renderService,
+		EmailSender:       emailSender,
+		log:               log.New("reports"),
+	}
+
+	// Register routes only when reports are enabled
+	if s.Cfg.ReportsEnabled {
+		s.registerAPIEndpoints()
+	}
+
+	return s
+}
+
+type Service interface {
+	CreateReport(ctx context.Context, user *models.SignedInUser, cmd CreateReportCommand) (ReportDTO, error)
+	UpdateReport(ctx context.Context, user *models.SignedInUser, uid string, cmd UpdateReportCommand) (ReportDTO, error)
+	DeleteReport(ctx context.Context, user *models.SignedInUser, uid string) error
+	GetReport(ctx context.Context, user *models.SignedInUser, uid string) (ReportDTO, error)
+	SearchReports(ctx context.Context, user *models.SignedInUser) ([]ReportDTO, error)
+	SendReport(ctx context.Context, user *models.SignedInUser, uid string) error
+}
+
+type ReportService struct {
+	Cfg               *setting.Cfg
+	SQLStore          *sqlstore.SQLStore
+	RouteRegister     routing.RouteRegister
+	ServerLockService *serverlock.ServerLockService
+	RenderService     rendering.Service
+	EmailSender       notifications.EmailSender
+	log               log.Logger
+}
+
+func (s *ReportService) IsDisabled() bool {
+	return !s.Cfg.ReportsEnabled
+}
+
+// Run sends reports which are due. In HA setup the reports are sent by a single
+// server, the one which takes the server lock.
+func (s *ReportService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// The lock interval is shorter than the ticker one so that ticker jitter
+			// does not make servers skip a tick.
+			err := s.ServerLockService.LockAndExecute(ctx, "send scheduled reports", schedulerInterval/2, func(ctx context.Context) {
+				s.sendDueReports(ctx, time.Now())
+			})
+			if err != nil {
+				s.log.Error("failed to lock and execute sending of scheduled reports", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *ReportService) sendDueReports(ctx context.Context, now time.Time) {
+	reports, err := s.getDueReports(ctx, now)
+	if err != nil {
+		s.log.Error("failed to get scheduled reports", "error", err)
+		return
+	}
+
+	for _, report := range reports {
+		if ctx.Err() != nil {
+			return
+		}
+
+		next, err := nextRun(report.Schedule, report.Timezone, now)
+		if err != nil {
+			s.log.Error("failed to schedule report", "uid", report.UID, "orgId", report.OrgID, "error", err)
+			continue
+		}
+
+		// Moving the next run claims the report, runs missed while the server was down
+		// are not caught up.
+		claimed, err := s.claimReport(ctx, report, next.Unix())
+		if err != nil {
+			s.log.Error("failed to claim report", "uid", report.UID, "orgId", report.OrgID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		// Errors are logged and stored with the report.
+		_ = s.send(ctx, report, now)
+	}
+}
+
+// send renders and sends the report and records the result, errors are stored
+// with the report so that they are visible to users.
+func (s *ReportService) send(ctx context.Context, report Report, now time.Time) error {
+	s.log.Debug("sending report", "uid", report.UID, "orgId", report.OrgID)
+
+	sendErr := s.renderAndSend(ctx, report)
+	lastError := ""
+	if sendErr != nil {
+		s.log.Error("failed to send report", "uid", report.UID, "orgId", report.OrgID, "error", sendErr)
+		lastError = sendErr.Error()
+	}
+
+	if err := s.saveRunResult(ctx, report.ID, now.Unix(), lastError); err != nil {
+		s.log.Error("failed to save result of report", "uid", report.UID, "orgId", report.OrgID, "error", err)
+	}
+	return sendErr
+}
+
+func (s *ReportService) CreateReport(ctx context.Context, user *models.SignedInUser, cmd CreateReportCommand) (ReportDTO, error) {
+	return s.createReport(ctx, user, cmd)
+}
+
+// UpdateReport updates the report, which is then rendered with the permissions of the user.
+func (s *ReportService) UpdateReport(ctx context.Context, user *models.SignedInUser, uid string, cmd UpdateReportCommand) (ReportDTO, error) {
+	if _, err := s.getVisibleReport(ctx, user, uid); err != nil {
+		return ReportDTO{}, err
+	}
+	return s.updateReport(ctx, user, uid, cmd)
+}
+
+func (s *ReportService) DeleteReport(ctx context.Context, user *models.SignedInUser, uid string) error {
+	if _, err := s.getVisibleReport(ctx, user, uid); err != nil {
+		return err
+	}
+	return s.deleteReport(ctx, user.OrgId, uid)
+}
+
+func (s *ReportService) GetReport(ctx context.Context, user *models.SignedInUser, uid string) (ReportDTO, error) {
+	report, err := s.getVisibleReport(ctx, user, uid)
+	if err != nil {
+		return ReportDTO{}, err
+	}
+	return report.toDTO(), nil
+}
+
+// SearchReports returns the reports of the organization of the user, except the ones
+// of dashboards the user cannot view.
+func (s *ReportService) SearchReports(ctx context.Context, user *models.SignedInUser) ([]ReportDTO, error) {
+	reports, err := s.searchReports(ctx, user.OrgId)
+	if err != nil {
+		return nil, err
+	}
+	canView := map[string]bool{}
+	dtos := make([]ReportDTO, 0, len(reports))
+	for _, report := range reports {
+		visible, ok := canView[report.DashboardUID]
+		if !ok {
+			visible, err = s.canViewReport(ctx, user, report)
+			if err != nil {
+				return nil, err
+			}
+			canView[report.DashboardUID] = visible
+		}
+		if visible {
+			dtos = append(dtos, report.toDTO())
+		}
+	}
+	return dtos, nil
+}
+
+// SendReport sends the report immediately, the schedule of the report is not changed.
+func (s *ReportService) SendReport(ctx context.Context, user *models.SignedInUser, uid string) error {
+	report, err := s.getVisibleReport(ctx, user, uid)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, report, time.Now())
+}
+
+// getVisibleReport returns the report, or ErrReportNotFound if the user cannot view
+// the dashboard of the report.
+func (s *ReportService) getVisibleReport(ctx context.Context, user *models.SignedInUser, uid string) (Report, error) {
+	report, err := s.getReport(ctx, user.OrgId, uid)
+	if err != nil {
+		return Report{}, err
+	}
+	canView, err := s.canViewReport(ctx, user, report)
+	if err != nil {
+		return Report{}, err
+	}
+	if !canView {
+		return Report{}, ErrReportNotFound
+	}
+	return report, nil
+}
@@ -0,0 +1,268 @@
+//go:build integration
+// +build integration
+
+package reports
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeSession struct {
+	rendering.Session
+}
+
+func (s *fakeSession) Dispose(_ context.Context) {}
+
+type fakeRenderService struct {
+	rendering.Service
+	dir  string
+	opts []rendering.PDFOpts
+	err  error
+}
+
+func (s *fakeRenderService) CreateRenderingSession(_ context.Context, _ rendering.AuthOpts, _ rendering.SessionOpts) (rendering.Session, error) {
+	return &fakeSession{}, nil
+}
+
+func (s *fakeRenderService) RenderPDF(_ context.Context, opts rendering.PDFOpts, _ rendering.Session) (*rendering.RenderPDFResult, error) {
+	s.opts = append(s.opts, opts)
+	if s.err != nil {
+		return nil, s.err
+	}
+	filePath := filepath.Join(s.dir, "report.pdf")
+	if err := os.WriteFile(filePath, []byte("%PDF-1.3"), 0600); err != nil {
+		return nil, err
+	}
+	return &rendering.RenderPDFResult{FilePath: filePath}, nil
+}
+
+type scenarioContext struct {
+	service   *ReportService
+	renderer  *fakeRenderService
+	mailer    *notifications.NotificationServiceMock
+	user      *models.SignedInUser
+	dashboard *models.Dashboard
+}
+
+func setupTestScenario(t *testing.T) scenarioContext {
+	t.Helper()
+
+	sqlStore := sqlstore.InitTestDB(t)
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+
+	u, err := sqlStore.CreateUser(context.Background(), models.CreateUserCommand{
+		Email: "signed.in.user@test.com",
+		Login: "signed_in_user",
+	})
+	require.NoError(t, err)
+
+	dashboard, err := database.ProvideDashboardStore(sqlStore).SaveDashboard(models.SaveDashboardCommand{
+		OrgId: u.OrgId,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{
+			"title": "SLO",
+			"panels": []interface{}{
+				map[string]interface{}{"id": 1, "type": "timeseries", "title": "Availability"},
+				map[string]interface{}{"id": 2, "type": "stat", "title": "Error budget"},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	renderer := &fakeRenderService{dir: t.TempDir()}
+	mailer := notifications.MockNotificationService()
+	return scenarioContext{
+		service: &ReportService{
+			Cfg:           setting.NewCfg(),
+			SQLStore:      sqlStore,
+			RenderService: renderer,
+			EmailSender:   mailer,
+			log:           log.New("reports.test"),
+		},
+		renderer: renderer,
+		mailer:   mailer,
+		user: &models.SignedInUser{
+			UserId:  u.Id,
+			OrgId:   u.OrgId,
+			OrgRole: models.ROLE_EDITOR,
+		},
+		dashboard: dashboard,
+	}
+}
+
+func (sc scenarioContext) createReport(t *testing.T, enabled bool) ReportDTO {
+	t.Helper()
+	report, err := sc.service.CreateReport(context.Background(), sc.user, CreateReportCommand{ReportCommand{
+		Name:         "Weekly SLO",
+		DashboardUID: sc.dashboard.Uid,
+		Variables:    map[string][]string{"env": {"prod"}},
+		Recipients:   []string{"team@example.com"},
+		Schedule:     "0 9 * * 1",
+		Enabled:      enabled,
+	}})
+	require.NoError(t, err)
+	return report
+}
+
+func TestReportService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create, get, update and delete report", func(t *testing.T) {
+		sc := setupTestScenario(t)
+		created := sc.createReport(t, true)
+		require.Equal(t, "now-7d", created.TimeFrom)
+		require.Equal(t, "UTC", created.Timezone)
+		require.Equal(t, time.Monday, time.Unix(created.NextRunAt, 0).UTC().Weekday())
+
+		report, err := sc.service.GetReport(ctx, sc.user, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, created, report)
+
+		cmd := UpdateReportCommand{ReportCommand{
+			Name:         "Daily SLO",
+			DashboardUID: sc.dashboard.Uid,
+			Recipients:   []string{"team@example.com", "lead@example.com"},
+			Schedule:     "@daily",
+			Enabled:      true,
+		}}
+		updated, err := sc.service.UpdateReport(ctx, sc.user, created.UID, cmd)
+		require.NoError(t, err)
+		require.Equal(t, "Daily SLO", updated.Name)
+		require.Len(t, updated.Recipients, 2)
+
+		reports, err := sc.service.SearchReports(ctx, sc.user)
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		require.Equal(t, updated, reports[0])
+
+		require.NoError(t, sc.service.DeleteReport(ctx, sc.user, created.UID))
+		_, err = sc.service.GetReport(ctx, sc.user, created.UID)
+		require.ErrorIs(t, err, ErrReportNotFound)
+		require.ErrorIs(t, sc.service.DeleteReport(ctx, sc.user, created.UID), ErrReportNotFound)
+	})
+
+	t.Run("Reports of other organizations are not visible", func(t *testing.T) {
+		sc := setupTestScenario(t)
+		created := sc.createReport(t, true)
+
+		otherUser := *sc.user
+		otherUser.OrgId++
+		_, err := sc.service.GetReport(ctx, &otherUser, created.UID)
+		require.ErrorIs(t, err, ErrReportNotFound)
+		reports, err := sc.service.SearchReports(ctx, &otherUser)
+		require.NoError(t, err)
+		require.Empty(t, reports)
+	})
+
+	t.Run("Reports of dashboards the user cannot view are not visible", func(t *testing.T) {
+		sc := setupTestScenario(t)
+		created := sc.createReport(t, true)
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: false})
+
+		_, err := sc.service.GetReport(ctx, sc.user, created.UID)
+		require.ErrorIs(t, err, ErrReportNotFound)
+		reports, err := sc.service.SearchReports(ctx, sc.user)
+		require.NoError(t, err)
+		require.Empty(t, reports)
+		require.ErrorIs(t, sc.service.SendReport(ctx, sc.user, created.UID), ErrReportNotFound)
+		require.ErrorIs(t, sc.service.DeleteReport(ctx, sc.user, created.UID), ErrReportNotFound)
+	})
+
+	t.Run("Reports are rendered as the user who last updated them", func(t *testing.T) {
+		sc := setupTestScenario(t)
+		created := sc.createReport(t, false)
+		u, err := sc.service.SQLStore.CreateUser(ctx, models.CreateUserCommand{
+			Email: "other.user@test.com",
+			Login: "other_user",
+			OrgId: sc.user.OrgId,
+		})
+		require.NoError(t, err)
+		otherUser := &models.SignedInUser{UserId: u.Id, OrgId: sc.user.OrgId, OrgRole: models.ROLE_EDITOR}
+
+		updated, err := sc.service.UpdateReport(ctx, otherUser, created.UID, UpdateReportCommand{ReportCommand{
+			Name:         "Weekly SLO",
+			DashboardUID: sc.dashboard.Uid,
+			Recipients:   []string{"team@example.com"},
+			Schedule:     "@weekly",
+		}})
+		require.NoError(t, err)
+		require.Equal(t, sc.user.UserId, updated.CreatedBy)
+		require.Equal(t, otherUser.UserId, updated.UpdatedBy)
+
+		require.NoError(t, sc.service.SendReport(ctx, sc.user, created.UID))
+		require.Len(t, sc.renderer.opts, 1)
+		require.Equal(t, otherUser.UserId, sc.renderer.opts[0].UserID)
+	})
+
+	t.Run("Report of unknown dashboard is invalid", func(t *testing.T) {
+		sc := setupTestScenario(t)
+		_, err := sc.service.CreateReport(ctx, sc.user, CreateReportCommand{ReportCommand{
+			Name:         "Weekly SLO",
+			DashboardUID: "unknown",
+			Recipients:   []string{"team@example.com"},
+			Schedule:     "@weekly",
+		}})
+		require.ErrorIs(t, err, ErrInvalidReport)
+	})
+
+	t.Run("Due reports are sent once", func(t *testing.T) {
+		sc := setupTestScenario(t)
+		enabled := sc.createReport(t, true)
+		sc.createReport(t, false)
+		now := time.Unix(enabled.NextRunAt, 0).Add(time.Minute)
+
+		sc.service.sendDueReports(ctx, now)
+		require.Len(t, sc.renderer.opts, 1)
+		opts := sc.renderer.opts[0]
+		require.Equal(t, sc.user.UserId, opts.UserID)
+		require.Len(t, opts.Panels, 2)
+		require.Equal(t, "Availability", opts.Panels[0].Title)
+		require.Contains(t, opts.Panels[0].Path, "d-solo/"+sc.dashboard.Uid+"/slo?")
+		require.Contains(t, opts.Panels[0].Path, "var-env=prod")
+
+		email := sc.mailer.EmailSync
+		require.Equal(t, []string{"team@example.com"}, email.To)
+		require.Equal(t, reportEmailTemplate, email.Template)
+		require.Len(t, email.AttachedFiles, 1)
+		require.Equal(t, "weekly-slo.pdf", email.AttachedFiles[0].Name)
+
+		report, err := sc.service.GetReport(ctx, sc.user, enabled.UID)
+		require.NoError(t, err)
+		require.Equal(t, now.Unix(), report.LastRunAt)
+		require.Empty(t, report.LastError)
+		require.Greater(t, report.NextRunAt, now.Unix())
+
+		sc.service.sendDueReports(ctx, now)
+		require.Len(t, sc.renderer.opts, 1)
+	})
+
+	t.Run("Errors are stored with the report", func(t *testing.T) {
+		sc := setupTestScenario(t)
+		created := sc.createReport(t, false)
+		sc.renderer.err = errors.New("renderer is down")
+
+		err := sc.service.SendReport(ctx, sc.user, created.UID)
+		require.Error(t, err)
+
+		report, err := sc.service.GetReport(ctx, sc.user, created.UID)
+		require.NoError(t, err)
+		require.Contains(t, report.LastError, "renderer is down")
+		require.Equal(t, created.NextRunAt, report.NextRunAt)
+	})
+}
@@ -0,0 +1,64 @@
+package reports
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	defaultTimeFrom = "now-7d"
+	defaultTimeTo   = "now"
+	defaultTimezone = "UTC"
+)
+
+// nextRun returns the first time after t when a report with the schedule should be
+// sent. The schedule is evaluated in the timezone, so @daily means the midnight of
+// the timezone.
+func nextRun(schedule string, timezone string, t time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: unknown timezone %q", ErrInvalidReport, timezone)
+	}
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid schedule %q: %s", ErrInvalidReport, schedule, err)
+	}
+	next := sched.Next(t.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("%w: schedule %q never runs", ErrInvalidReport, schedule)
+	}
+	return next, nil
+}
+
+// validate checks the settings of a report and fills defaults.
+func (cmd *ReportCommand) validate() error {
+	if cmd.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidReport)
+	}
+	if cmd.DashboardUID == "" {
+		return fmt.Errorf("%w: dashboard UID is required", ErrInvalidReport)
+	}
+	if len(cmd.Recipients) == 0 {
+		return fmt.Errorf("%w: at least one recipient is required", ErrInvalidReport)
+	}
+	for _, recipient := range cmd.Recipients {
+		if !util.IsEmail(recipient) {
+			return fmt.Errorf("%w: invalid recipient email address %q", ErrInvalidReport, recipient)
+		}
+	}
+	if cmd.TimeFrom == "" {
+		cmd.TimeFrom = defaultTimeFrom
+	}
+	if cmd.TimeTo == "" {
+		cmd.TimeTo = defaultTimeTo
+	}
+	if cmd.Timezone == "" {
+		cmd.Timezone = defaultTimezone
+	}
+	_, err := nextRun(cmd.Schedule, cmd.Timezone, time.Now())
+	return err
+}
@@ -0,0 +1,72 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRun(t *testing.T) {
+	// Wednesday
+	now := time.Date(2022, 3, 16, 10, 30, 0, 0, time.UTC)
+
+	t.Run("Cron expression in UTC", func(t *testing.T) {
+		next, err := nextRun("0 9 * * 1", "UTC", now)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2022, 3, 21, 9, 0, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("Descriptor in timezone", func(t *testing.T) {
+		next, err := nextRun("@daily", "Europe/Stockholm", now)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2022, 3, 16, 23, 0, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("Invalid schedule", func(t *testing.T) {
+		_, err := nextRun("every monday", "UTC", now)
+		require.ErrorIs(t, err, ErrInvalidReport)
+	})
+
+	t.Run("Unknown timezone", func(t *testing.T) {
+		_, err := nextRun("@weekly", "Mars/Olympus", now)
+		require.ErrorIs(t, err, ErrInvalidReport)
+	})
+}
+
+func TestReportCommandValidate(t *testing.T) {
+	valid := func() ReportCommand {
+		return ReportCommand{
+			Name:         "SLO",
+			DashboardUID: "slo",
+			Recipients:   []string{"team@example.com"},
+			Schedule:     "@weekly",
+		}
+	}
+
+	t.Run("Fills defaults", func(t *testing.T) {
+		cmd := valid()
+		require.NoError(t, cmd.validate())
+		require.Equal(t, "now-7d", cmd.TimeFrom)
+		require.Equal(t, "now", cmd.TimeTo)
+		require.Equal(t, "UTC", cmd.Timezone)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(cmd *ReportCommand)
+	}{
+		{name: "Missing name", modify: func(cmd *ReportCommand) { cmd.Name = "" }},
+		{name: "Missing dashboard", modify: func(cmd *ReportCommand) { cmd.DashboardUID = "" }},
+		{name: "Missing recipients", modify: func(cmd *ReportCommand) { cmd.Recipients = nil }},
+		{name: "Invalid recipient", modify: func(cmd *ReportCommand) { cmd.Recipients = []string{"team"} }},
+		{name: "Invalid schedule", modify: func(cmd *ReportCommand) { cmd.Schedule = "" }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := valid()
+			tc.modify(&cmd)
+			require.ErrorIs(t, cmd.validate(), ErrInvalidReport)
+		})
+	}
+}
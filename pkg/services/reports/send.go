@@ -0,0 +1,148 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/rendering"
+)
+
+const (
+	reportEmailTemplate = "report"
+	panelWidth          = 1200
+	panelHeight         = 600
+)
+
+type dashboardPanel struct {
+	ID    int64
+	Title string
+}
+
+// renderAndSend renders panels of the report dashboard to a PDF document with the
+// permissions of the user who last updated the report and sends it to the recipients.
+// Any editor can update a report, so the permissions of the creator are not used.
+func (s *ReportService) renderAndSend(ctx context.Context, report Report) error {
+	dashQuery := models.GetDashboardQuery{Uid: report.DashboardUID, OrgId: report.OrgID}
+	if err := s.SQLStore.GetDashboard(ctx, &dashQuery); err != nil {
+		return fmt.Errorf("failed to get dashboard: %w", err)
+	}
+	dash := dashQuery.Result
+
+	userQuery := models.GetSignedInUserQuery{UserId: report.UpdatedBy, OrgId: report.OrgID}
+	if err := s.SQLStore.GetSignedInUser(ctx, &userQuery); err != nil {
+		return fmt.Errorf("failed to get the user who last updated the report: %w", err)
+	}
+
+	panels := dashboardPanels(dash.Data)
+	pdfPanels := make([]rendering.PDFPanel, 0, len(panels))
+	for _, panel := range panels {
+		pdfPanels = append(pdfPanels, rendering.PDFPanel{
+			Title: panel.Title,
+			Path:  fmt.Sprintf("d-solo/%s/%s?%s", dash.Uid, dash.Slug, reportURLValues(report, panel.ID).Encode()),
+		})
+	}
+
+	authOpts := rendering.AuthOpts{
+		OrgID:   report.OrgID,
+		UserID:  userQuery.Result.UserId,
+		OrgRole: userQuery.Result.OrgRole,
+	}
+	// A single session is used for all panels of the report.
+	session, err := s.RenderService.CreateRenderingSession(ctx, authOpts, rendering.SessionOpts{
+		Expiry:                     5 * time.Minute,
+		RefreshExpiryOnEachRequest: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create rendering session: %w", err)
+	}
+	defer session.Dispose(ctx)
+
+	result, err := s.RenderService.RenderPDF(ctx, rendering.PDFOpts{
+		TimeoutOpts:     rendering.TimeoutOpts{Timeout: s.Cfg.ReportsRenderingTimeout},
+		AuthOpts:        authOpts,
+		Title:           dash.Title,
+		Panels:          pdfPanels,
+		Width:           panelWidth,
+		Height:          panelHeight,
+		Timezone:        report.Timezone,
+		ConcurrentLimit: s.Cfg.ReportsConcurrentRenderLimit,
+		Theme:           models.ThemeLight,
+	}, session)
+	if err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	// Safe to ignore gosec warning G304, the path is created by the rendering service.
+	// nolint:gosec
+	content, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read rendered report: %w", err)
+	}
+	if err := os.Remove(result.FilePath); err != nil {
+		s.log.Warn("failed to remove rendered report", "path", result.FilePath, "error", err)
+	}
+
+	dashboardURL := models.GetFullDashboardUrl(dash.Uid, dash.Slug) + "?" + reportURLValues(report, 0).Encode()
+	err = s.EmailSender.SendEmailCommandHandlerSync(ctx, &models.SendEmailCommandSync{
+		SendEmailCommand: models.SendEmailCommand{
+			To:       report.Recipients,
+			Template: reportEmailTemplate,
+			Data: map[string]interface{}{
+				"ReportName":     report.Name,
+				"DashboardTitle": dash.Title,
+				"DashboardURL":   dashboardURL,
+				"TimeFrom":       report.TimeFrom,
+				"TimeTo":         report.TimeTo,
+			},
+			AttachedFiles: []*models.SendEmailAttachFile{
+				{Name: models.SlugifyTitle(report.Name) + ".pdf", Content: content},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send report email: %w", err)
+	}
+	return nil
+}
+
+// reportURLValues returns query parameters of the report dashboard with the time range
+// and variables of the report, panelID is added when it's not zero.
+func reportURLValues(report Report, panelID int64) url.Values {
+	values := url.Values{}
+	values.Set("orgId", strconv.FormatInt(report.OrgID, 10))
+	if panelID != 0 {
+		values.Set("panelId", strconv.FormatInt(panelID, 10))
+	}
+	values.Set("from", report.TimeFrom)
+	values.Set("to", report.TimeTo)
+	for name, vals := range report.Variables {
+		for _, v := range vals {
+			values.Add("var-"+name, v)
+		}
+	}
+	return values
+}
+
+// dashboardPanels returns panels of a dashboard in the order of the dashboard JSON,
+// panels of collapsed rows are included and rows themselves are skipped.
+func dashboardPanels(data *simplejson.Json) []dashboardPanel {
+	var panels []dashboardPanel
+	for _, p := range data.Get("panels").MustArray() {
+		panel := simplejson.NewFromAny(p)
+		if panel.Get("type").MustString() == "row" {
+			panels = append(panels, dashboardPanels(panel)...)
+			continue
+		}
+		panels = append(panels, dashboardPanel{
+			ID:    panel.Get("id").MustInt64(),
+			Title: panel.Get("title").MustString(),
+		})
+	}
+	return panels
+}
@@ -0,0 +1,41 @@
+package reports
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestDashboardPanels(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "type": "timeseries", "title": "Availability"},
+			{"id": 2, "type": "row", "title": "Expanded", "panels": []},
+			{"id": 3, "type": "stat", "title": "Error budget"},
+			{"id": 4, "type": "row", "title": "Collapsed", "panels": [
+				{"id": 5, "type": "timeseries", "title": "Latency"}
+			]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	require.Equal(t, []dashboardPanel{
+		{ID: 1, Title: "Availability"},
+		{ID: 3, Title: "Error budget"},
+		{ID: 5, Title: "Latency"},
+	}, dashboardPanels(data))
+}
+
+func TestReportURLValues(t *testing.T) {
+	report := Report{
+		OrgID:     2,
+		TimeFrom:  "now-7d",
+		TimeTo:    "now",
+		Variables: map[string][]string{"env": {"prod", "staging"}},
+	}
+
+	require.Equal(t, "from=now-7d&orgId=2&panelId=3&to=now&var-env=prod&var-env=staging", reportURLValues(report, 3).Encode())
+	require.Equal(t, "from=now-7d&orgId=2&to=now&var-env=prod&var-env=staging", reportURLValues(report, 0).Encode())
+}
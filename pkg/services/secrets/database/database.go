@@ -32,8 +32,9 @@ func (ss *SecretsStoreImpl) GetDataKey(ctx context.Context, name string) (*secre
 
 	err := ss.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		var err error
+		// deactivated data keys are still used to decrypt the secrets that were encrypted with them
 		exists, err = sess.Table(dataKeysTable).
-			Where("name = ? AND active = ?", name, ss.sqlStore.Dialect.BooleanStr(true)).
+			Where("name = ?", name).
 			Get(dataKey)
 		return err
 	})
@@ -77,6 +78,21 @@ func (ss *SecretsStoreImpl) CreateDataKeyWithDBSession(_ context.Context, dataKe
 	return err
 }
 
+func (ss *SecretsStoreImpl) DisableDataKeys(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	return ss.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Table(dataKeysTable).
+			In("name", names).
+			UseBool("active").
+			Cols("active", "updated").
+			Update(&secrets.DataKey{Active: false, Updated: time.Now()})
+		return err
+	})
+}
+
 func (ss *SecretsStoreImpl) DeleteDataKey(ctx context.Context, name string) error {
 	if len(name) == 0 {
 		return fmt.Errorf("data key name is missing")
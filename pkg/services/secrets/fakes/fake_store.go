@@ -41,6 +41,15 @@ func (f FakeSecretsStore) CreateDataKeyWithDBSession(_ context.Context, dataKey
 	return nil
 }
 
+func (f FakeSecretsStore) DisableDataKeys(_ context.Context, names []string) error {
+	for _, name := range names {
+		if key, ok := f.store[name]; ok {
+			key.Active = false
+		}
+	}
+	return nil
+}
+
 func (f FakeSecretsStore) DeleteDataKey(_ context.Context, name string) error {
 	delete(f.store, name)
 	return nil
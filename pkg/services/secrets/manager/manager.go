@@ -30,6 +30,7 @@ type SecretsService struct {
 	currentProviderID secrets.ProviderID
 	providers         map[secrets.ProviderID]secrets.Provider
 	dataKeyCache      *dataKeyCache
+	rotationInterval  time.Duration
 	log               log.Logger
 }
 
@@ -65,6 +66,11 @@ func ProvideSecretsService(
 	ttl := settings.KeyValue("security.encryption", "data_keys_cache_ttl").MustDuration(15 * time.Minute)
 	cache := newDataKeyCache(ttl)
 
+	rotationInterval := settings.KeyValue("security.encryption", "data_keys_rotation_interval").MustDuration(0)
+	if rotationInterval > 0 && rotationInterval < time.Minute {
+		return nil, fmt.Errorf("data keys rotation interval %s is too short, it must be at least 1m", rotationInterval)
+	}
+
 	s := &SecretsService{
 		store:             store,
 		enc:               enc,
@@ -73,6 +79,7 @@ func ProvideSecretsService(
 		providers:         providers,
 		currentProviderID: currentProviderID,
 		dataKeyCache:      cache,
+		rotationInterval:  rotationInterval,
 		features:          features,
 		log:               logger,
 	}
@@ -164,6 +171,11 @@ func (s *SecretsService) EncryptWithDBSession(ctx context.Context, payload []byt
 }
 
 func (s *SecretsService) keyName(scope string) string {
+	if s.rotationInterval > 0 {
+		// a new data key is used for each rotation period
+		period := now().UTC().Truncate(s.rotationInterval)
+		return fmt.Sprintf("%s/%s@%s", period.Format("2006-01-02T15:04"), scope, s.currentProviderID)
+	}
 	return fmt.Sprintf("%s/%s@%s", now().Format("2006-01-02"), scope, s.currentProviderID)
 }
 
@@ -178,28 +190,20 @@ func (s *SecretsService) Decrypt(ctx context.Context, payload []byte) ([]byte, e
 		return nil, fmt.Errorf("unable to decrypt empty payload")
 	}
 
+	keyName, payload, err := splitPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
 	var dataKey []byte
 
-	if payload[0] != '#' {
+	if keyName == "" {
 		secretKey := s.settings.KeyValue("security", "secret_key").Value()
 		dataKey = []byte(secretKey)
 	} else {
-		payload = payload[1:]
-		endOfKey := bytes.Index(payload, []byte{'#'})
-		if endOfKey == -1 {
-			return nil, fmt.Errorf("could not find valid key in encrypted payload")
-		}
-		b64Key := payload[:endOfKey]
-		payload = payload[endOfKey+1:]
-		key := make([]byte, b64.DecodedLen(len(b64Key)))
-		_, err := b64.Decode(key, b64Key)
-		if err != nil {
-			return nil, err
-		}
-
-		dataKey, err = s.dataKey(ctx, string(key))
+		dataKey, err = s.dataKey(ctx, keyName)
 		if err != nil {
-			s.log.Error("Failed to lookup data key", "name", string(key), "error", err)
+			s.log.Error("Failed to lookup data key", "name", keyName, "error", err)
 			return nil, err
 		}
 	}
@@ -207,6 +211,33 @@ func (s *SecretsService) Decrypt(ctx context.Context, payload []byte) ([]byte, e
 	return s.enc.Decrypt(ctx, payload, string(dataKey))
 }
 
+// DataKeyName returns the name of the data key the payload was encrypted with,
+// or an empty string if it was encrypted with the secret key.
+func DataKeyName(payload []byte) (string, error) {
+	keyName, _, err := splitPayload(payload)
+	return keyName, err
+}
+
+// splitPayload returns the name of the data key of the payload and its encrypted value.
+func splitPayload(payload []byte) (string, []byte, error) {
+	if len(payload) == 0 || payload[0] != '#' {
+		return "", payload, nil
+	}
+
+	payload = payload[1:]
+	endOfKey := bytes.Index(payload, []byte{'#'})
+	if endOfKey == -1 {
+		return "", nil, fmt.Errorf("could not find valid key in encrypted payload")
+	}
+	b64Key := payload[:endOfKey]
+	key := make([]byte, b64.DecodedLen(len(b64Key)))
+	if _, err := b64.Decode(key, b64Key); err != nil {
+		return "", nil, err
+	}
+
+	return string(key), payload[endOfKey+1:], nil
+}
+
 func (s *SecretsService) EncryptJsonData(ctx context.Context, kv map[string]string, opt secrets.EncryptionOptions) (map[string][]byte, error) {
 	return s.EncryptJsonDataWithDBSession(ctx, kv, opt, nil)
 }
@@ -346,6 +377,75 @@ func (s *SecretsService) ReEncryptDataKeys(ctx context.Context) error {
 	return nil
 }
 
+// RotationInterval returns how often the data keys are rotated, it is 0 if they are not rotated.
+func (s *SecretsService) RotationInterval() time.Duration {
+	return s.rotationInterval
+}
+
+// RotateDataKeys deactivates the data keys of the previous rotation periods and creates the data keys
+// of the current period for their scopes. Deactivated data keys are only used to decrypt the secrets
+// that have not been encrypted again since, refer to InactiveDataKeys.
+// All the data keys are then re-encrypted with the current encryption provider, so that the latest key
+// of the provider is used for them.
+func (s *SecretsService) RotateDataKeys(ctx context.Context) error {
+	dataKeys, err := s.store.GetAllDataKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	outdated := make([]string, 0)
+	scopes := make(map[string]struct{})
+	for _, k := range dataKeys {
+		if !k.Active || k.Name == s.keyName(k.Scope) {
+			continue
+		}
+		outdated = append(outdated, k.Name)
+		scopes[k.Scope] = struct{}{}
+	}
+
+	for scope := range scopes {
+		name := s.keyName(scope)
+		_, err := s.dataKey(ctx, name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, secrets.ErrDataKeyNotFound) {
+			return err
+		}
+		if _, err := s.newDataKey(ctx, name, scope, nil); err != nil {
+			// another instance might have created the data key in the meantime
+			if _, getErr := s.store.GetDataKey(ctx, name); getErr != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.store.DisableDataKeys(ctx, outdated); err != nil {
+		return err
+	}
+
+	s.log.Info("Rotated data encryption keys", "deactivated", len(outdated), "scopes", len(scopes))
+
+	return s.ReEncryptDataKeys(ctx)
+}
+
+// InactiveDataKeys returns the scopes of the deactivated data keys by data key name.
+// The secrets encrypted with them have to be encrypted again to be moved to the data keys of the current period.
+func (s *SecretsService) InactiveDataKeys(ctx context.Context) (map[string]string, error) {
+	dataKeys, err := s.store.GetAllDataKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inactive := make(map[string]string)
+	for _, k := range dataKeys {
+		if !k.Active {
+			inactive[k.Name] = k.Scope
+		}
+	}
+	return inactive, nil
+}
+
 func (s *SecretsService) Run(ctx context.Context) error {
 	gc := time.NewTicker(
 		s.settings.KeyValue("security.encryption", "data_keys_cache_cleanup_interval").
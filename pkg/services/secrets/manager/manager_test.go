@@ -2,6 +2,13 @@ package manager
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -336,3 +343,148 @@ func TestSecretsService_ReEncryptDataKeys(t *testing.T) {
 		assert.Empty(t, svc.dataKeyCache.entries)
 	})
 }
+
+func TestSecretsService_ReEncryptDataKeysWithHashiCorpVault(t *testing.T) {
+	ctx := context.Background()
+	store := database.ProvideSecretsStore(sqlstore.InitTestDB(t))
+	svc := SetupTestService(t, store)
+
+	ciphertext, err := svc.Encrypt(ctx, []byte("grafana"), secrets.WithoutScope())
+	require.NoError(t, err)
+
+	// fake transit engine that prefixes the plaintexts with the key version
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "root-token", r.Header.Get("X-Vault-Token"))
+		data := map[string]string{}
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/grafana":
+			data["ciphertext"] = "vault:v1:" + body["plaintext"]
+		case "/v1/transit/decrypt/grafana":
+			data["plaintext"] = strings.TrimPrefix(body["ciphertext"], "vault:v1:")
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+	t.Cleanup(vault.Close)
+
+	raw, err := ini.Load([]byte(`
+		[security]
+		secret_key = ` + svc.settings.KeyValue("security", "secret_key").Value() + `
+		encryption_provider = hashicorpvault.test
+
+		[security.encryption.hashicorpvault.test]
+		url = ` + vault.URL + `
+		key_ring = grafana
+		token = root-token`))
+	require.NoError(t, err)
+
+	features := featuremgmt.WithFeatures(featuremgmt.FlagEnvelopeEncryption)
+	settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw, IsFeatureToggleEnabled: features.IsEnabled}}
+	newService := func() *SecretsService {
+		encr := ossencryption.ProvideService()
+		vaultSvc, err := ProvideSecretsService(store, osskmsproviders.ProvideService(encr, settings, features), encr, settings, features, &usagestats.UsageStatsMock{T: t})
+		require.NoError(t, err)
+		return vaultSvc
+	}
+
+	require.NoError(t, newService().ReEncryptDataKeys(ctx))
+
+	dataKeys, err := store.GetAllDataKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, dataKeys, 1)
+	assert.Equal(t, secrets.ProviderID("hashicorpvault.test"), dataKeys[0].Provider)
+	assert.True(t, strings.HasPrefix(string(dataKeys[0].EncryptedData), "vault:v1:"))
+
+	decrypted, err := newService().Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("grafana"), decrypted)
+}
+
+func TestSecretsService_RotateDataKeys(t *testing.T) {
+	ctx := context.Background()
+	store := database.ProvideSecretsStore(sqlstore.InitTestDB(t))
+
+	keyring := filepath.Join(t.TempDir(), "keyring")
+	writeKeyring := func(keys ...string) {
+		require.NoError(t, os.WriteFile(keyring, []byte(strings.Join(keys, "\n")), 0600))
+	}
+	writeKeyring("1 " + base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 32))))
+
+	raw, err := ini.Load([]byte(`
+		[security]
+		secret_key = SdlklWklckeLS
+		encryption_provider = keyfile.v1
+
+		[security.encryption]
+		data_keys_rotation_interval = 1h
+
+		[security.encryption.keyfile.v1]
+		path = ` + keyring))
+	require.NoError(t, err)
+
+	features := featuremgmt.WithFeatures(featuremgmt.FlagEnvelopeEncryption)
+	settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw, IsFeatureToggleEnabled: features.IsEnabled}}
+	encr := ossencryption.ProvideService()
+	newService := func() *SecretsService {
+		svc, err := ProvideSecretsService(store, osskmsproviders.ProvideService(encr, settings, features), encr, settings, features, &usagestats.UsageStatsMock{T: t})
+		require.NoError(t, err)
+		return svc
+	}
+	svc := newService()
+
+	ciphertext, err := svc.Encrypt(ctx, []byte("grafana"), secrets.WithScope("user:10"))
+	require.NoError(t, err)
+	firstKeyName := svc.keyName("user:10")
+
+	t.Run("data keys of the current period are not rotated", func(t *testing.T) {
+		require.NoError(t, svc.RotateDataKeys(ctx))
+
+		dataKeys, err := store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, dataKeys, 1)
+		assert.True(t, dataKeys[0].Active)
+	})
+
+	t.Run("data keys of the previous periods are deactivated and replaced", func(t *testing.T) {
+		t.Cleanup(func() { now = time.Now })
+		now = func() time.Time { return time.Now().Add(time.Hour) }
+
+		// rotate the key encryption key as well
+		writeKeyring(
+			"1 "+base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 32))),
+			"2 "+base64.StdEncoding.EncodeToString([]byte(strings.Repeat("b", 32))),
+		)
+		svc := newService()
+
+		require.NoError(t, svc.RotateDataKeys(ctx))
+
+		dataKeys, err := store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, dataKeys, 2)
+		for _, k := range dataKeys {
+			assert.Equal(t, k.Name != firstKeyName, k.Active, k.Name)
+			assert.True(t, strings.HasPrefix(string(k.EncryptedData), "v2:"), "data keys should be encrypted with the latest key version")
+		}
+
+		// secrets encrypted with deactivated data keys can still be decrypted
+		decrypted, err := svc.Decrypt(ctx, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("grafana"), decrypted)
+
+		// new secrets are encrypted with the new data key
+		_, err = svc.Encrypt(ctx, []byte("grafana"), secrets.WithScope("user:10"))
+		require.NoError(t, err)
+		dataKeys, err = store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, dataKeys, 2)
+	})
+
+	t.Run("rotation interval must be at least a minute", func(t *testing.T) {
+		raw, err := ini.Load([]byte("[security.encryption]\ndata_keys_rotation_interval = 10s"))
+		require.NoError(t, err)
+		settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw, IsFeatureToggleEnabled: features.IsEnabled}}
+		_, err = ProvideSecretsService(store, osskmsproviders.ProvideService(encr, settings, features), encr, settings, features, &usagestats.UsageStatsMock{T: t})
+		require.EqualError(t, err, "data keys rotation interval 10s is too short, it must be at least 1m")
+	})
+}
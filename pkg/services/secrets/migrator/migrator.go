@@ -0,0 +1,124 @@
+// Package migrator re-encrypts the secrets stored in the database, so that they are moved to the current data keys.
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"xorm.io/xorm"
+)
+
+// selectFunc returns whether the encrypted payload has to be re-encrypted, and the options to re-encrypt it with.
+type selectFunc func(payload []byte) (secrets.EncryptionOptions, bool)
+
+type secretsTable interface {
+	reencrypt(ctx context.Context, secretsSrv *manager.SecretsService, sess *xorm.Session, selectSecret selectFunc, logger log.Logger)
+}
+
+// SecretsMigrator rotates the data keys at the rotation interval, and then re-encrypts the secrets
+// that are encrypted with the deactivated data keys.
+type SecretsMigrator struct {
+	secretsSrv *manager.SecretsService
+	sqlStore   *sqlstore.SQLStore
+	features   featuremgmt.FeatureToggles
+	tables     []secretsTable
+	log        log.Logger
+}
+
+func ProvideSecretsMigrator(secretsSrv *manager.SecretsService, sqlStore *sqlstore.SQLStore, features featuremgmt.FeatureToggles) *SecretsMigrator {
+	return &SecretsMigrator{
+		secretsSrv: secretsSrv,
+		sqlStore:   sqlStore,
+		features:   features,
+		tables: []secretsTable{
+			simpleSecret{tableName: "dashboard_snapshot", columnName: "dashboard_encrypted"},
+			b64Secret{simpleSecret{tableName: "user_auth", columnName: "o_auth_access_token"}},
+			b64Secret{simpleSecret{tableName: "user_auth", columnName: "o_auth_refresh_token"}},
+			b64Secret{simpleSecret{tableName: "user_auth", columnName: "o_auth_token_type"}},
+			jsonSecret{tableName: "data_source"},
+			jsonSecret{tableName: "plugin_setting"},
+			alertingSecret{},
+		},
+		log: log.New("secrets.migrator"),
+	}
+}
+
+// IsDisabled returns true if the data keys are not rotated.
+func (m *SecretsMigrator) IsDisabled() bool {
+	return !m.features.IsEnabled(featuremgmt.FlagEnvelopeEncryption) || m.secretsSrv.RotationInterval() <= 0
+}
+
+func (m *SecretsMigrator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.secretsSrv.RotationInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.secretsSrv.RotateDataKeys(ctx); err != nil {
+				m.log.Error("Failed to rotate data encryption keys", "error", err)
+				continue
+			}
+			if err := m.ReEncryptInactiveSecrets(ctx); err != nil {
+				m.log.Error("Failed to re-encrypt the secrets of deactivated data encryption keys", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ReEncryptSecrets re-encrypts all the secrets with the data keys of the current period, without scope.
+func (m *SecretsMigrator) ReEncryptSecrets(ctx context.Context) error {
+	return m.reEncrypt(ctx, func([]byte) (secrets.EncryptionOptions, bool) {
+		return secrets.WithoutScope(), true
+	})
+}
+
+// ReEncryptInactiveSecrets re-encrypts the secrets that are encrypted with deactivated data keys with the data keys
+// of the current period for the same scope. The other secrets are left as they are.
+func (m *SecretsMigrator) ReEncryptInactiveSecrets(ctx context.Context) error {
+	inactive, err := m.secretsSrv.InactiveDataKeys(ctx)
+	if err != nil {
+		return err
+	}
+	if len(inactive) == 0 {
+		return nil
+	}
+
+	return m.reEncrypt(ctx, func(payload []byte) (secrets.EncryptionOptions, bool) {
+		name, err := manager.DataKeyName(payload)
+		if err != nil {
+			return nil, false
+		}
+		scope, ok := inactive[name]
+		if !ok {
+			return nil, false
+		}
+		return secrets.WithScope(scope), true
+	})
+}
+
+func (m *SecretsMigrator) reEncrypt(ctx context.Context, selectSecret selectFunc) error {
+	return m.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errors.New(fmt.Sprint(r))
+				m.log.Error("Secrets re-encryption failed, rolling back transaction...", "error", err)
+			}
+		}()
+
+		for _, t := range m.tables {
+			t.reencrypt(ctx, m.secretsSrv, sess.Session, selectSecret, m.log)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,88 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/infra/usagestats"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/encryption/ossencryption"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/osskmsproviders"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSecretsMigrator_ReEncryptInactiveSecrets(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := sqlstore.InitTestDB(t)
+	store := database.ProvideSecretsStore(sqlStore)
+	features := featuremgmt.WithFeatures(featuremgmt.FlagEnvelopeEncryption)
+
+	newService := func(rotationInterval string) *manager.SecretsService {
+		raw, err := ini.Load([]byte(`
+			[security]
+			secret_key = SdlklWklckeLS
+
+			[security.encryption]
+			data_keys_rotation_interval = ` + rotationInterval))
+		require.NoError(t, err)
+		settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw, IsFeatureToggleEnabled: features.IsEnabled}}
+		encr := ossencryption.ProvideService()
+		svc, err := manager.ProvideSecretsService(store, osskmsproviders.ProvideService(encr, settings, features), encr, settings, features, &usagestats.UsageStatsMock{T: t})
+		require.NoError(t, err)
+		return svc
+	}
+
+	// the data keys of a service without rotation are named after the day, so they are outdated for a service with rotation.
+	encrypted, err := newService("").EncryptJsonData(ctx, map[string]string{"password": "grafana"}, secrets.WithScope("datasource:1"))
+	require.NoError(t, err)
+	require.NoError(t, sqlStore.AddDataSource(ctx, &models.AddDataSourceCommand{
+		OrgId:                   1,
+		Name:                    "test",
+		Type:                    "prometheus",
+		Access:                  models.DS_ACCESS_PROXY,
+		EncryptedSecureJsonData: encrypted,
+	}))
+	oldKeyName, err := manager.DataKeyName(encrypted["password"])
+	require.NoError(t, err)
+
+	secretsSrv := newService("1h")
+	require.NoError(t, secretsSrv.RotateDataKeys(ctx))
+	inactive, err := secretsSrv.InactiveDataKeys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{oldKeyName: "datasource:1"}, inactive)
+
+	migrator := ProvideSecretsMigrator(secretsSrv, sqlStore, features)
+	require.NoError(t, migrator.ReEncryptInactiveSecrets(ctx))
+
+	query := &models.GetDataSourceQuery{OrgId: 1, Name: "test"}
+	require.NoError(t, sqlStore.GetDataSource(ctx, query))
+	newKeyName, err := manager.DataKeyName(query.Result.SecureJsonData["password"])
+	require.NoError(t, err)
+	assert.NotEqual(t, oldKeyName, newKeyName)
+
+	dataKey, err := store.GetDataKey(ctx, newKeyName)
+	require.NoError(t, err)
+	assert.True(t, dataKey.Active)
+	assert.Equal(t, "datasource:1", dataKey.Scope)
+
+	decrypted, err := secretsSrv.DecryptJsonData(ctx, query.Result.SecureJsonData)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"password": "grafana"}, decrypted)
+
+	t.Run("secrets of active data keys are left as they are", func(t *testing.T) {
+		require.NoError(t, migrator.ReEncryptInactiveSecrets(ctx))
+
+		unchanged := &models.GetDataSourceQuery{OrgId: 1, Name: "test"}
+		require.NoError(t, sqlStore.GetDataSource(ctx, unchanged))
+		assert.Equal(t, query.Result.SecureJsonData, unchanged.Result.SecureJsonData)
+	})
+}
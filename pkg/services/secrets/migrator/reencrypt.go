@@ -0,0 +1,302 @@
+package migrator
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"xorm.io/xorm"
+)
+
+type simpleSecret struct {
+	tableName  string
+	columnName string
+}
+
+type b64Secret struct {
+	simpleSecret
+}
+
+type jsonSecret struct {
+	tableName string
+}
+
+type alertingSecret struct{}
+
+func nowInUTC() string {
+	return time.Now().UTC().Format("2006-01-02 15:04:05")
+}
+
+func (s simpleSecret) reencrypt(ctx context.Context, secretsSrv *manager.SecretsService, sess *xorm.Session, selectSecret selectFunc, logger log.Logger) {
+	var rows []struct {
+		Id     int
+		Secret []byte
+	}
+
+	if err := sess.Table(s.tableName).Select(fmt.Sprintf("id, %s as secret", s.columnName)).Find(&rows); err != nil {
+		logger.Warn("Could not find any secret to re-encrypt", "table", s.tableName)
+		return
+	}
+
+	var anyFailure bool
+
+	for _, row := range rows {
+		if len(row.Secret) == 0 {
+			continue
+		}
+
+		opt, ok := selectSecret(row.Secret)
+		if !ok {
+			continue
+		}
+
+		decrypted, err := secretsSrv.Decrypt(ctx, row.Secret)
+		if err != nil {
+			anyFailure = true
+			logger.Warn("Could not decrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			continue
+		}
+
+		encrypted, err := secretsSrv.EncryptWithDBSession(ctx, decrypted, opt, sess)
+		if err != nil {
+			anyFailure = true
+			logger.Warn("Could not encrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			continue
+		}
+
+		updateSQL := fmt.Sprintf("UPDATE %s SET %s = ?, updated = ? WHERE id = ?", s.tableName, s.columnName)
+		if _, err = sess.Exec(updateSQL, encrypted, nowInUTC(), row.Id); err != nil {
+			anyFailure = true
+			logger.Warn("Could not update secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			continue
+		}
+	}
+
+	if anyFailure {
+		logger.Warn(fmt.Sprintf("Column %s from %s has been re-encrypted with errors", s.columnName, s.tableName))
+	} else {
+		logger.Info(fmt.Sprintf("Column %s from %s has been re-encrypted successfully", s.columnName, s.tableName))
+	}
+}
+
+func (s b64Secret) reencrypt(ctx context.Context, secretsSrv *manager.SecretsService, sess *xorm.Session, selectSecret selectFunc, logger log.Logger) {
+	var rows []struct {
+		Id     int
+		Secret string
+	}
+
+	if err := sess.Table(s.tableName).Select(fmt.Sprintf("id, %s as secret", s.columnName)).Find(&rows); err != nil {
+		logger.Warn("Could not find any secret to re-encrypt", "table", s.tableName)
+		return
+	}
+
+	var anyFailure bool
+
+	for _, row := range rows {
+		if len(row.Secret) == 0 {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(row.Secret)
+		if err != nil {
+			anyFailure = true
+			logger.Warn("Could not decode base64-encoded secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			continue
+		}
+
+		opt, ok := selectSecret(decoded)
+		if !ok {
+			continue
+		}
+
+		decrypted, err := secretsSrv.Decrypt(ctx, decoded)
+		if err != nil {
+			anyFailure = true
+			logger.Warn("Could not decrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			continue
+		}
+
+		encrypted, err := secretsSrv.EncryptWithDBSession(ctx, decrypted, opt, sess)
+		if err != nil {
+			anyFailure = true
+			logger.Warn("Could not encrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			continue
+		}
+
+		encoded := base64.StdEncoding.EncodeToString(encrypted)
+		updateSQL := fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ?", s.tableName, s.columnName)
+		_, err = sess.Exec(updateSQL, encoded, row.Id)
+
+		if err != nil {
+			anyFailure = true
+			logger.Warn("Could not update secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			continue
+		}
+	}
+
+	if anyFailure {
+		logger.Warn(fmt.Sprintf("Column %s from %s has been re-encrypted with errors", s.columnName, s.tableName))
+	} else {
+		logger.Info(fmt.Sprintf("Column %s from %s has been re-encrypted successfully", s.columnName, s.tableName))
+	}
+}
+
+func (s jsonSecret) reencrypt(ctx context.Context, secretsSrv *manager.SecretsService, sess *xorm.Session, selectSecret selectFunc, logger log.Logger) {
+	var rows []struct {
+		Id             int
+		SecureJsonData map[string][]byte
+	}
+
+	if err := sess.Table(s.tableName).Cols("id", "secure_json_data").Find(&rows); err != nil {
+		logger.Warn("Could not find any secret to re-encrypt", "table", s.tableName)
+		return
+	}
+
+	var anyFailure bool
+
+	for _, row := range rows {
+		if len(row.SecureJsonData) == 0 {
+			continue
+		}
+
+		toUpdate := struct {
+			SecureJsonData map[string][]byte
+			Updated        string
+		}{SecureJsonData: make(map[string][]byte, len(row.SecureJsonData)), Updated: nowInUTC()}
+
+		var changed, rowFailure bool
+		for k, v := range row.SecureJsonData {
+			toUpdate.SecureJsonData[k] = v
+
+			opt, ok := selectSecret(v)
+			if !ok {
+				continue
+			}
+
+			decrypted, err := secretsSrv.Decrypt(ctx, v)
+			if err != nil {
+				rowFailure = true
+				logger.Warn("Could not decrypt secrets while re-encrypting them", "table", s.tableName, "id", row.Id, "key", k, "error", err)
+				break
+			}
+
+			toUpdate.SecureJsonData[k], err = secretsSrv.EncryptWithDBSession(ctx, decrypted, opt, sess)
+			if err != nil {
+				rowFailure = true
+				logger.Warn("Could not re-encrypt secrets", "table", s.tableName, "id", row.Id, "key", k, "error", err)
+				break
+			}
+			changed = true
+		}
+
+		if rowFailure {
+			anyFailure = true
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		if _, err := sess.Table(s.tableName).Where("id = ?", row.Id).Update(toUpdate); err != nil {
+			anyFailure = true
+			logger.Warn("Could not update secrets while re-encrypting them", "table", s.tableName, "id", row.Id, "error", err)
+			continue
+		}
+	}
+
+	if anyFailure {
+		logger.Warn(fmt.Sprintf("Secure json data secrets from %s have been re-encrypted with errors", s.tableName))
+	} else {
+		logger.Info(fmt.Sprintf("Secure json data secrets from %s have been re-encrypted successfully", s.tableName))
+	}
+}
+
+func (s alertingSecret) reencrypt(ctx context.Context, secretsSrv *manager.SecretsService, sess *xorm.Session, selectSecret selectFunc, logger log.Logger) {
+	var results []struct {
+		Id                        int
+		AlertmanagerConfiguration string
+	}
+
+	selectSQL := "SELECT id, alertmanager_configuration FROM alert_configuration"
+	if err := sess.SQL(selectSQL).Find(&results); err != nil {
+		logger.Warn("Could not find any alert_configuration secret to re-encrypt")
+		return
+	}
+
+	var anyFailure bool
+
+	for _, result := range results {
+		result := result
+		postableUserConfig, err := notifier.Load([]byte(result.AlertmanagerConfiguration))
+		if err != nil {
+			anyFailure = true
+			logger.Warn("Could not load alert_configuration while re-encrypting it", "id", result.Id, "error", err)
+			continue
+		}
+
+		var changed bool
+		for _, receiver := range postableUserConfig.AlertmanagerConfig.Receivers {
+			for _, gmr := range receiver.GrafanaManagedReceivers {
+				for k, v := range gmr.SecureSettings {
+					decoded, err := base64.StdEncoding.DecodeString(v)
+					if err != nil {
+						anyFailure = true
+						logger.Warn("Could not decode base64-encoded alert_configuration secret", "id", result.Id, "key", k, "error", err)
+						continue
+					}
+
+					opt, ok := selectSecret(decoded)
+					if !ok {
+						continue
+					}
+
+					decrypted, err := secretsSrv.Decrypt(ctx, decoded)
+					if err != nil {
+						anyFailure = true
+						logger.Warn("Could not decrypt alert_configuration secret", "id", result.Id, "key", k, "error", err)
+						continue
+					}
+
+					reencrypted, err := secretsSrv.EncryptWithDBSession(ctx, decrypted, opt, sess)
+					if err != nil {
+						anyFailure = true
+						logger.Warn("Could not re-encrypt alert_configuration secret", "id", result.Id, "key", k, "error", err)
+						continue
+					}
+
+					gmr.SecureSettings[k] = base64.StdEncoding.EncodeToString(reencrypted)
+					changed = true
+				}
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		marshalled, err := json.Marshal(postableUserConfig)
+		if err != nil {
+			anyFailure = true
+			logger.Warn("Could not marshal alert_configuration while re-encrypting it", "id", result.Id, "error", err)
+			continue
+		}
+
+		result.AlertmanagerConfiguration = string(marshalled)
+		if _, err := sess.Table("alert_configuration").Where("id = ?", result.Id).Update(&result); err != nil {
+			anyFailure = true
+			logger.Warn("Could not update alert_configuration secret while re-encrypting it", "id", result.Id, "error", err)
+			continue
+		}
+	}
+
+	if anyFailure {
+		logger.Warn("Alerting configuration secrets have been re-encrypted with errors")
+	} else {
+		logger.Info("Alerting configuration secrets have been re-encrypted successfully")
+	}
+}
@@ -34,6 +34,7 @@ type Store interface {
 	GetAllDataKeys(ctx context.Context) ([]*DataKey, error)
 	CreateDataKey(ctx context.Context, dataKey DataKey) error
 	CreateDataKeyWithDBSession(ctx context.Context, dataKey DataKey, sess *xorm.Session) error
+	DisableDataKeys(ctx context.Context, names []string) error
 	DeleteDataKey(ctx context.Context, name string) error
 	ReEncryptDataKeys(ctx context.Context, providers map[ProviderID]Provider, currProvider ProviderID) error
 }
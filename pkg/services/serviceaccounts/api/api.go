@@ -77,6 +77,8 @@ func (api *ServiceAccountsAPI) RegisterAPIEndpoints(
 			accesscontrol.EvalPermission(serviceaccounts.ActionWrite, serviceaccounts.ScopeID)), routing.Wrap(api.CreateToken))
 		serviceAccountsRoute.Delete("/:serviceAccountId/tokens/:tokenId", auth(middleware.ReqOrgAdmin,
 			accesscontrol.EvalPermission(serviceaccounts.ActionWrite, serviceaccounts.ScopeID)), routing.Wrap(api.DeleteToken))
+		serviceAccountsRoute.Post("/:serviceAccountId/tokens/:tokenId/rotate", auth(middleware.ReqOrgAdmin,
+			accesscontrol.EvalPermission(serviceaccounts.ActionWrite, serviceaccounts.ScopeID)), routing.Wrap(api.RotateToken))
 	})
 }
 
@@ -106,6 +108,9 @@ func (api *ServiceAccountsAPI) DeleteServiceAccount(ctx *models.ReqContext) resp
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "serviceAccountId is invalid", err)
 	}
+	if resp := api.checkNotProvisioned(ctx, scopeID); resp != nil {
+		return resp
+	}
 	err = api.service.DeleteServiceAccount(ctx.Req.Context(), ctx.OrgId, scopeID)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Service account deletion error", err)
@@ -191,6 +196,10 @@ func (api *ServiceAccountsAPI) updateServiceAccount(c *models.ReqContext) respon
 		return response.Error(http.StatusForbidden, "Cannot assign a role higher than user's role", nil)
 	}
 
+	if resp := api.checkNotProvisioned(c, scopeID); resp != nil {
+		return resp
+	}
+
 	resp, err := api.store.UpdateServiceAccount(c.Req.Context(), c.OrgId, scopeID, &cmd)
 	if err != nil {
 		switch {
@@ -209,6 +218,24 @@ func (api *ServiceAccountsAPI) updateServiceAccount(c *models.ReqContext) respon
 	return response.JSON(http.StatusOK, resp)
 }
 
+// checkNotProvisioned returns an error response if the service account doesn't exist or is provisioned.
+// Provisioned service accounts and their tokens can only be changed in their provisioning config files.
+func (api *ServiceAccountsAPI) checkNotProvisioned(c *models.ReqContext, saID int64) response.Response {
+	serviceAccount, err := api.store.RetrieveServiceAccount(c.Req.Context(), c.OrgId, saID)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
+			return response.Error(http.StatusNotFound, "Failed to retrieve service account", err)
+		default:
+			return response.Error(http.StatusInternalServerError, "Failed to retrieve service account", err)
+		}
+	}
+	if serviceAccount.IsProvisioned {
+		return response.Error(http.StatusForbidden, "Cannot change provisioned service account", nil)
+	}
+	return nil
+}
+
 // SearchOrgServiceAccountsWithPaging is an HTTP handler to search for org users with paging.
 // GET /api/serviceaccounts/search
 func (api *ServiceAccountsAPI) SearchOrgServiceAccountsWithPaging(c *models.ReqContext) response.Response {
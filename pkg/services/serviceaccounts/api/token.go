@@ -23,6 +23,9 @@ type TokenDTO struct {
 	Expiration             *time.Time `json:"expiration"`
 	SecondsUntilExpiration *float64   `json:"secondsUntilExpiration"`
 	HasExpired             bool       `json:"hasExpired"`
+	LastUsedAt             *time.Time `json:"lastUsedAt"`
+	LastUsedIP             string     `json:"lastUsedIp"`
+	IsRevoked              bool       `json:"isRevoked"`
 }
 
 func hasExpired(expiration *int64) bool {
@@ -63,6 +66,9 @@ func (api *ServiceAccountsAPI) ListTokens(ctx *models.ReqContext) response.Respo
 				Expiration:             expiration,
 				SecondsUntilExpiration: &secondsUntilExpiration,
 				HasExpired:             isExpired,
+				LastUsedAt:             t.LastUsedAt,
+				LastUsedIP:             t.LastUsedIP,
+				IsRevoked:              t.IsRevoked != nil && *t.IsRevoked,
 			}
 		}
 
@@ -79,14 +85,9 @@ func (api *ServiceAccountsAPI) CreateToken(c *models.ReqContext) response.Respon
 		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
 	}
 
-	// confirm service account exists
-	if _, err := api.store.RetrieveServiceAccount(c.Req.Context(), c.OrgId, saID); err != nil {
-		switch {
-		case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
-			return response.Error(http.StatusNotFound, "Failed to retrieve service account", err)
-		default:
-			return response.Error(http.StatusInternalServerError, "Failed to retrieve service account", err)
-		}
+	// confirm service account exists and can be changed
+	if resp := api.checkNotProvisioned(c, saID); resp != nil {
+		return resp
 	}
 
 	cmd := serviceaccounts.AddServiceAccountTokenCommand{}
@@ -139,14 +140,9 @@ func (api *ServiceAccountsAPI) DeleteToken(c *models.ReqContext) response.Respon
 		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
 	}
 
-	// confirm service account exists
-	if _, err := api.store.RetrieveServiceAccount(c.Req.Context(), c.OrgId, saID); err != nil {
-		switch {
-		case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
-			return response.Error(http.StatusNotFound, "Failed to retrieve service account", err)
-		default:
-			return response.Error(http.StatusInternalServerError, "Failed to retrieve service account", err)
-		}
+	// confirm service account exists and can be changed
+	if resp := api.checkNotProvisioned(c, saID); resp != nil {
+		return resp
 	}
 
 	tokenID, err := strconv.ParseInt(web.Params(c.Req)[":tokenId"], 10, 64)
@@ -167,3 +163,76 @@ func (api *ServiceAccountsAPI) DeleteToken(c *models.ReqContext) response.Respon
 
 	return response.Success("API key deleted")
 }
+
+// RotateToken replaces the key of a service account token, the previous key can't be used anymore
+func (api *ServiceAccountsAPI) RotateToken(c *models.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+
+	tokenID, err := strconv.ParseInt(web.Params(c.Req)[":tokenId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Token ID is invalid", err)
+	}
+
+	if resp := api.checkNotProvisioned(c, saID); resp != nil {
+		return resp
+	}
+
+	cmd := serviceaccounts.RotateServiceAccountTokenCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+	cmd.OrgId = c.OrgId
+
+	if api.cfg.ApiKeyMaxSecondsToLive != -1 {
+		if cmd.SecondsToLive == 0 {
+			return response.Error(http.StatusBadRequest, "Number of seconds before expiration should be set", nil)
+		}
+		if cmd.SecondsToLive > api.cfg.ApiKeyMaxSecondsToLive {
+			return response.Error(http.StatusBadRequest, "Number of seconds before expiration is greater than the global limit", nil)
+		}
+	}
+
+	// the name of the token is part of the generated key
+	tokens, err := api.store.ListTokens(c.Req.Context(), c.OrgId, saID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list service account tokens", err)
+	}
+	var token *models.ApiKey
+	for _, t := range tokens {
+		if t.Id == tokenID {
+			token = t
+			break
+		}
+	}
+	if token == nil {
+		return response.Error(http.StatusNotFound, "Failed to rotate API key", models.ErrApiKeyNotFound)
+	}
+
+	newKeyInfo, err := apikeygen.New(cmd.OrgId, token.Name)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Generating API key failed", err)
+	}
+	cmd.Key = newKeyInfo.HashedKey
+
+	if err := api.store.RotateServiceAccountToken(c.Req.Context(), saID, tokenID, &cmd); err != nil {
+		switch {
+		case errors.Is(err, models.ErrApiKeyNotFound):
+			return response.Error(http.StatusNotFound, "Failed to rotate API key", err)
+		case errors.Is(err, models.ErrInvalidApiKeyExpiration), errors.Is(err, models.ErrApiKeyRevoked):
+			return response.Error(http.StatusBadRequest, err.Error(), nil)
+		default:
+			return response.Error(http.StatusInternalServerError, "Failed to rotate API key", err)
+		}
+	}
+
+	result := &dtos.NewApiKeyResult{
+		ID:   tokenID,
+		Name: token.Name,
+		Key:  newKeyInfo.ClientSecret,
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
@@ -240,6 +240,74 @@ func TestServiceAccountsAPI_DeleteToken(t *testing.T) {
 	}
 }
 
+func TestServiceAccountsAPI_RotateToken(t *testing.T) {
+	store := sqlstore.InitTestDB(t)
+	svcMock := &tests.ServiceAccountMock{}
+	saStore := database.NewServiceAccountsStore(store)
+	sa := tests.SetupUserServiceAccount(t, store, tests.TestUser{Login: "sa", IsServiceAccount: true})
+
+	writeScoped := func(scope string) *accesscontrolmock.Mock {
+		return tests.SetupMockAccesscontrol(
+			t,
+			func(c context.Context, siu *models.SignedInUser, _ accesscontrol.Options) ([]*accesscontrol.Permission, error) {
+				return []*accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: scope}}, nil
+			},
+			false,
+		)
+	}
+
+	rotate := func(t *testing.T, acmock *accesscontrolmock.Mock, tokenID int64, body string) (int, map[string]interface{}) {
+		t.Helper()
+		server, _ := setupTestServer(t, svcMock, routing.NewRouteRegister(), acmock, store, saStore)
+		endpoint := fmt.Sprintf(serviceaccountIDTokensDetailPath+"/rotate", sa.Id, tokenID)
+		req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Add("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+
+		actualBody := map[string]interface{}{}
+		_ = json.Unmarshal(recorder.Body.Bytes(), &actualBody)
+		return recorder.Code, actualBody
+	}
+
+	t.Run("should rotate serviceaccount token with scope id permissions", func(t *testing.T) {
+		token := createTokenforSA(t, saStore, "Test1", sa.OrgId, sa.Id, 100)
+
+		code, body := rotate(t, writeScoped("serviceaccounts:id:1"), token.Id, `{"secondsToLive": 3600}`)
+		require.Equal(t, http.StatusOK, code, body)
+		assert.Equal(t, "Test1", body["name"])
+
+		decoded, err := apikeygen.Decode(body["key"].(string))
+		require.NoError(t, err)
+		query := models.GetApiKeyByNameQuery{KeyName: "Test1", OrgId: sa.OrgId}
+		require.NoError(t, store.GetApiKeyByName(context.Background(), &query))
+		valid, err := apikeygen.IsValid(decoded, query.Result.Key)
+		require.NoError(t, err)
+		assert.True(t, valid)
+		assert.NotEqual(t, token.Key, query.Result.Key)
+		assert.InDelta(t, time.Now().Add(time.Hour).Unix(), *query.Result.Expires, 5)
+	})
+
+	t.Run("should be forbidden to rotate serviceaccount token if wrong scoped", func(t *testing.T) {
+		token := createTokenforSA(t, saStore, "Test2", sa.OrgId, sa.Id, 100)
+
+		code, _ := rotate(t, writeScoped("serviceaccounts:id:10"), token.Id, `{}`)
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("should not rotate missing or revoked serviceaccount tokens", func(t *testing.T) {
+		token := createTokenforSA(t, saStore, "Test3", sa.OrgId, sa.Id, 100)
+
+		code, _ := rotate(t, writeScoped(serviceaccounts.ScopeAll), token.Id+100, `{}`)
+		require.Equal(t, http.StatusNotFound, code)
+
+		require.NoError(t, saStore.RevokeServiceAccountTokens(context.Background(), []int64{token.Id}))
+		code, _ = rotate(t, writeScoped(serviceaccounts.ScopeAll), token.Id, `{}`)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
 type saStoreMockTokens struct {
 	serviceaccounts.Store
 	saAPIKeys []*models.ApiKey
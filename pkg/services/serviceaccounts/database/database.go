@@ -57,6 +57,8 @@ func (s *ServiceAccountsStoreImpl) CreateServiceAccount(ctx context.Context, org
 func ServiceAccountDeletions() []string {
 	deletes := []string{
 		"DELETE FROM api_key WHERE service_account_id = ?",
+		"DELETE FROM user_role WHERE user_id = ?",
+		"DELETE FROM identity_provisioning WHERE identity_id = ? and kind = '" + models.IdentityKindServiceAccount + "'",
 	}
 	deletes = append(deletes, sqlstore.UserDeletions()...)
 	return deletes
@@ -228,6 +230,12 @@ func (s *ServiceAccountsStoreImpl) RetrieveServiceAccount(ctx context.Context, o
 
 	serviceAccount.Teams = teams
 
+	provisionedQuery := models.IsIdentityProvisionedQuery{Kind: models.IdentityKindServiceAccount, IdentityId: serviceAccountID}
+	if err := s.sqlStore.IsIdentityProvisioned(ctx, &provisionedQuery); err != nil {
+		return nil, err
+	}
+	serviceAccount.IsProvisioned = provisionedQuery.Result
+
 	return serviceAccount, nil
 }
 
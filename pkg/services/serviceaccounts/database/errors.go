@@ -39,6 +39,17 @@ func (e *ErrInvalidExpirationSAToken) Unwrap() error {
 	return models.ErrInvalidApiKeyExpiration
 }
 
+type ErrRevokedSAToken struct {
+}
+
+func (e *ErrRevokedSAToken) Error() string {
+	return "service account token has been revoked"
+}
+
+func (e *ErrRevokedSAToken) Unwrap() error {
+	return models.ErrApiKeyRevoked
+}
+
 type ErrDuplicateSAToken struct {
 	name string
 }
@@ -63,6 +63,93 @@ func (s *ServiceAccountsStoreImpl) DeleteServiceAccountToken(ctx context.Context
 	})
 }
 
+// RotateServiceAccountToken replaces the key of a service account token. The token keeps its previous lifetime
+// unless cmd.SecondsToLive is set, and its last use is reset.
+func (s *ServiceAccountsStoreImpl) RotateServiceAccountToken(ctx context.Context, saID, tokenID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) error {
+	return s.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		key := models.ApiKey{Id: tokenID, OrgId: cmd.OrgId, ServiceAccountId: &saID}
+		exists, err := sess.Get(&key)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &ErrMisingSAToken{}
+		}
+		if key.IsRevoked != nil && *key.IsRevoked {
+			return &ErrRevokedSAToken{}
+		}
+
+		updated := time.Now()
+		var expires *int64 = nil
+		if cmd.SecondsToLive > 0 {
+			v := updated.Add(time.Second * time.Duration(cmd.SecondsToLive)).Unix()
+			expires = &v
+		} else if cmd.SecondsToLive < 0 {
+			return &ErrInvalidExpirationSAToken{}
+		} else if key.Expires != nil {
+			v := updated.Unix() + *key.Expires - key.Updated.Unix()
+			expires = &v
+		}
+
+		key.Key = cmd.Key
+		key.Updated = updated
+		key.Expires = expires
+		key.LastUsedAt = nil
+		key.LastUsedIP = ""
+
+		if _, err := sess.ID(key.Id).Cols("key", "updated", "expires", "last_used_at", "last_used_ip").Update(&key); err != nil {
+			return err
+		}
+		cmd.Result = &key
+		return nil
+	})
+}
+
+// ListStaleTokens returns the service account tokens that are not revoked and have expired, or have not been used
+// for maxUnused. Tokens that have never been used are stale when they were created or rotated more than maxUnused ago.
+// A maxUnused of zero only returns the expired tokens.
+func (s *ServiceAccountsStoreImpl) ListStaleTokens(ctx context.Context, now time.Time, maxUnused time.Duration) ([]*models.ApiKey, error) {
+	result := make([]*models.ApiKey, 0)
+	err := s.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		sess.Where("service_account_id IS NOT NULL AND (is_revoked IS NULL OR is_revoked = ?)", s.sqlStore.Dialect.BooleanStr(false))
+		if maxUnused > 0 {
+			sess.And("(expires <= ? OR COALESCE(last_used_at, updated) < ?)", now.Unix(), now.Add(-maxUnused))
+		} else {
+			sess.And("expires <= ?", now.Unix())
+		}
+		return sess.Asc("id").Find(&result)
+	})
+	return result, err
+}
+
+// RevokeServiceAccountTokens revokes the service account tokens with the given IDs,
+// they can't be used to authenticate anymore.
+func (s *ServiceAccountsStoreImpl) RevokeServiceAccountTokens(ctx context.Context, tokenIDs []int64) error {
+	if len(tokenIDs) == 0 {
+		return nil
+	}
+	revoked := true
+	return s.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.In("id", tokenIDs).Where("service_account_id IS NOT NULL").Cols("is_revoked").Update(&models.ApiKey{IsRevoked: &revoked})
+		return err
+	})
+}
+
+// DeleteExpiredServiceAccountTokens deletes the expired service account tokens whose name starts with namePrefix.
+func (s *ServiceAccountsStoreImpl) DeleteExpiredServiceAccountTokens(ctx context.Context, now time.Time, namePrefix string) (int64, error) {
+	var deleted int64
+	err := s.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		rawSQL := "DELETE FROM api_key WHERE service_account_id IS NOT NULL AND expires <= ? AND name " + s.sqlStore.Dialect.LikeStr() + " ?"
+		result, err := sess.Exec(rawSQL, now.Unix(), namePrefix+"%")
+		if err != nil {
+			return err
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
+
 // assignApiKeyToServiceAccount sets the API key service account ID
 func (s *ServiceAccountsStoreImpl) assignApiKeyToServiceAccount(ctx context.Context, apikeyId int64, saccountId int64) error {
 	return s.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
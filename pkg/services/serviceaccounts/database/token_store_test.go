@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/apikeygen"
 	"github.com/grafana/grafana/pkg/models"
@@ -112,3 +113,143 @@ func TestStore_DeleteServiceAccountToken(t *testing.T) {
 		}
 	}
 }
+
+func TestStore_RotateServiceAccountToken(t *testing.T) {
+	userToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	user := tests.SetupUserServiceAccount(t, db, userToCreate)
+
+	key, err := apikeygen.New(user.OrgId, t.Name())
+	require.NoError(t, err)
+	addCmd := serviceaccounts.AddServiceAccountTokenCommand{
+		Name:          t.Name(),
+		OrgId:         user.OrgId,
+		Key:           key.HashedKey,
+		SecondsToLive: 3600,
+	}
+	require.NoError(t, store.AddServiceAccountToken(context.Background(), user.Id, &addCmd))
+	require.NoError(t, db.UpdateAPIKeyLastUsed(context.Background(), addCmd.Result.Id, time.Now(), "127.0.0.1"))
+
+	rotate := func(t *testing.T, saID, tokenID int64, secondsToLive int64) (*models.ApiKey, error) {
+		t.Helper()
+		newKey, err := apikeygen.New(user.OrgId, addCmd.Name)
+		require.NoError(t, err)
+		cmd := serviceaccounts.RotateServiceAccountTokenCommand{OrgId: user.OrgId, Key: newKey.HashedKey, SecondsToLive: secondsToLive}
+		if err := store.RotateServiceAccountToken(context.Background(), saID, tokenID, &cmd); err != nil {
+			return nil, err
+		}
+		require.Equal(t, newKey.HashedKey, cmd.Result.Key)
+		return cmd.Result, nil
+	}
+
+	t.Run("token keeps its lifetime and its last use is reset", func(t *testing.T) {
+		rotated, err := rotate(t, user.Id, addCmd.Result.Id, 0)
+		require.NoError(t, err)
+
+		keys, err := store.ListTokens(context.Background(), user.OrgId, user.Id)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, rotated.Key, keys[0].Key)
+		require.NotEqual(t, addCmd.Result.Key, keys[0].Key)
+		require.NotNil(t, keys[0].Expires)
+		require.InDelta(t, keys[0].Updated.Unix()+3600, *keys[0].Expires, 1)
+		require.Nil(t, keys[0].LastUsedAt)
+		require.Empty(t, keys[0].LastUsedIP)
+	})
+
+	t.Run("token gets a new lifetime", func(t *testing.T) {
+		_, err := rotate(t, user.Id, addCmd.Result.Id, 60)
+		require.NoError(t, err)
+
+		keys, err := store.ListTokens(context.Background(), user.OrgId, user.Id)
+		require.NoError(t, err)
+		require.InDelta(t, keys[0].Updated.Unix()+60, *keys[0].Expires, 1)
+
+		_, err = rotate(t, user.Id, addCmd.Result.Id, -1)
+		require.ErrorIs(t, err, models.ErrInvalidApiKeyExpiration)
+	})
+
+	t.Run("missing and revoked tokens are not rotated", func(t *testing.T) {
+		_, err := rotate(t, user.Id+2, addCmd.Result.Id, 0)
+		require.ErrorIs(t, err, models.ErrApiKeyNotFound)
+
+		require.NoError(t, store.RevokeServiceAccountTokens(context.Background(), []int64{addCmd.Result.Id}))
+		_, err = rotate(t, user.Id, addCmd.Result.Id, 0)
+		require.ErrorIs(t, err, models.ErrApiKeyRevoked)
+	})
+}
+
+func TestStore_ListStaleTokens(t *testing.T) {
+	userToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	user := tests.SetupUserServiceAccount(t, db, userToCreate)
+	ctx := context.Background()
+
+	addToken := func(t *testing.T, name string, secondsToLive int64) *models.ApiKey {
+		t.Helper()
+		key, err := apikeygen.New(user.OrgId, name)
+		require.NoError(t, err)
+		cmd := serviceaccounts.AddServiceAccountTokenCommand{Name: name, OrgId: user.OrgId, Key: key.HashedKey, SecondsToLive: secondsToLive}
+		require.NoError(t, store.AddServiceAccountToken(ctx, user.Id, &cmd))
+		return cmd.Result
+	}
+
+	expired := addToken(t, "expired", 1)
+	used := addToken(t, "used", 0)
+	unused := addToken(t, "unused", 0)
+	revoked := addToken(t, "revoked", 1)
+	require.NoError(t, db.UpdateAPIKeyLastUsed(ctx, used.Id, time.Now().Add(48*time.Hour), "127.0.0.1"))
+	require.NoError(t, store.RevokeServiceAccountTokens(ctx, []int64{revoked.Id}))
+
+	names := func(keys []*models.ApiKey) []string {
+		result := make([]string, 0, len(keys))
+		for _, k := range keys {
+			result = append(result, k.Name)
+		}
+		return result
+	}
+
+	// two days from now the tokens with a lifetime of one second have expired
+	now := time.Now().Add(48 * time.Hour)
+
+	stale, err := store.ListStaleTokens(ctx, now, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{expired.Name}, names(stale))
+
+	stale, err = store.ListStaleTokens(ctx, now, 24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []string{expired.Name, unused.Name}, names(stale))
+
+	keys, err := store.ListTokens(ctx, user.OrgId, user.Id)
+	require.NoError(t, err)
+	for _, k := range keys {
+		require.Equal(t, k.Name == revoked.Name, k.IsRevoked != nil && *k.IsRevoked, k.Name)
+	}
+}
+
+func TestStore_DeleteExpiredServiceAccountTokens(t *testing.T) {
+	userToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	user := tests.SetupUserServiceAccount(t, db, userToCreate)
+	ctx := context.Background()
+
+	for _, name := range []string{"exchanged-1", "exchanged-2", "other"} {
+		key, err := apikeygen.New(user.OrgId, name)
+		require.NoError(t, err)
+		cmd := serviceaccounts.AddServiceAccountTokenCommand{Name: name, OrgId: user.OrgId, Key: key.HashedKey, SecondsToLive: 1}
+		require.NoError(t, store.AddServiceAccountToken(ctx, user.Id, &cmd))
+	}
+
+	deleted, err := store.DeleteExpiredServiceAccountTokens(ctx, time.Now(), "exchanged-")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), deleted)
+
+	deleted, err = store.DeleteExpiredServiceAccountTokens(ctx, time.Now().Add(time.Hour), "exchanged-")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+
+	keys, err := store.ListTokens(ctx, user.OrgId, user.Id)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, "other", keys[0].Name)
+}
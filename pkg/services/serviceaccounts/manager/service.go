@@ -2,15 +2,18 @@ package manager
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/api"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/database"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tokenexchange"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -20,6 +23,7 @@ var (
 )
 
 type ServiceAccountsService struct {
+	cfg      *setting.Cfg
 	store    serviceaccounts.Store
 	features featuremgmt.FeatureToggles
 	log      log.Logger
@@ -32,8 +36,10 @@ func ProvideServiceAccountsService(
 	ac accesscontrol.AccessControl,
 	routeRegister routing.RouteRegister,
 	usageStats usagestats.Service,
+	remoteCache *remotecache.RemoteCache,
 ) (*ServiceAccountsService, error) {
 	s := &ServiceAccountsService{
+		cfg:      cfg,
 		features: features,
 		store:    database.NewServiceAccountsStore(store),
 		log:      log.New("serviceaccounts"),
@@ -50,6 +56,14 @@ func ProvideServiceAccountsService(
 	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, routeRegister, s.store)
 	serviceaccountsAPI.RegisterAPIEndpoints(features)
 
+	if features.IsEnabled(featuremgmt.FlagServiceAccounts) && cfg.TokenExchangeEnabled {
+		tokenExchange, err := tokenexchange.ProvideService(cfg, s.store, remoteCache)
+		if err != nil {
+			return nil, err
+		}
+		tokenExchange.RegisterAPIEndpoints(routeRegister)
+	}
+
 	return s, nil
 }
 
@@ -76,3 +90,48 @@ func (sa *ServiceAccountsService) RetrieveServiceAccountIdByName(ctx context.Con
 	}
 	return sa.store.RetrieveServiceAccountIdByName(ctx, orgID, name)
 }
+
+// HandleStaleTokens looks for service account tokens that have expired or have not been used for
+// service_account_token_max_unused. They are revoked when service_account_revoke_stale_tokens is enabled,
+// and only logged otherwise.
+func (sa *ServiceAccountsService) HandleStaleTokens(ctx context.Context) error {
+	if !sa.features.IsEnabled(featuremgmt.FlagServiceAccounts) {
+		sa.log.Debug(ServiceAccountFeatureToggleNotFound)
+		return nil
+	}
+
+	now := time.Now()
+
+	// the short-lived tokens issued in exchange of external tokens are not kept once expired
+	deleted, err := sa.store.DeleteExpiredServiceAccountTokens(ctx, now, tokenexchange.TokenNamePrefix)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		sa.log.Debug("Deleted expired service account tokens issued by token exchange", "count", deleted)
+	}
+
+	tokens, err := sa.store.ListStaleTokens(ctx, now, sa.cfg.ServiceAccountTokenMaxUnused)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(tokens))
+	for _, t := range tokens {
+		ids = append(ids, t.Id)
+		sa.log.Warn("Found stale service account token", "orgId", t.OrgId, "serviceAccountId", t.ServiceAccountId, "tokenId", t.Id,
+			"name", t.Name, "lastUsedAt", t.LastUsedAt, "revoke", sa.cfg.ServiceAccountRevokeStaleTokens)
+	}
+
+	if !sa.cfg.ServiceAccountRevokeStaleTokens {
+		return nil
+	}
+	if err := sa.store.RevokeServiceAccountTokens(ctx, ids); err != nil {
+		return err
+	}
+	sa.log.Info("Revoked stale service account tokens", "count", len(ids))
+	return nil
+}
@@ -3,10 +3,14 @@ package manager
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tokenexchange"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -34,3 +38,49 @@ func TestProvideServiceAccount_DeleteServiceAccount(t *testing.T) {
 		assert.Len(t, svcMock.Calls.DeleteServiceAccount, 0)
 	})
 }
+
+func TestProvideServiceAccount_HandleStaleTokens(t *testing.T) {
+	staleTokens := []*models.ApiKey{{Id: 1, Name: "expired"}, {Id: 2, Name: "unused"}}
+
+	t.Run("stale tokens are only logged by default", func(t *testing.T) {
+		storeMock := &tests.ServiceAccountsStoreMock{Calls: tests.Calls{}, ExpectedStaleTokens: staleTokens}
+		svc := ServiceAccountsService{
+			cfg:      &setting.Cfg{ServiceAccountTokenMaxUnused: 24 * time.Hour},
+			features: featuremgmt.WithFeatures("service-accounts", true),
+			store:    storeMock,
+			log:      log.New("serviceaccounts-manager-test"),
+		}
+		require.NoError(t, svc.HandleStaleTokens(context.Background()))
+		require.Len(t, storeMock.Calls.ListStaleTokens, 1)
+		assert.Equal(t, 24*time.Hour, storeMock.Calls.ListStaleTokens[0].([]interface{})[2])
+		require.Len(t, storeMock.Calls.DeleteExpiredTokens, 1)
+		assert.Equal(t, tokenexchange.TokenNamePrefix, storeMock.Calls.DeleteExpiredTokens[0].([]interface{})[2])
+		assert.Len(t, storeMock.Calls.RevokeServiceAccountTokens, 0)
+	})
+
+	t.Run("stale tokens are revoked when enabled", func(t *testing.T) {
+		storeMock := &tests.ServiceAccountsStoreMock{Calls: tests.Calls{}, ExpectedStaleTokens: staleTokens}
+		svc := ServiceAccountsService{
+			cfg:      &setting.Cfg{ServiceAccountRevokeStaleTokens: true},
+			features: featuremgmt.WithFeatures("service-accounts", true),
+			store:    storeMock,
+			log:      log.New("serviceaccounts-manager-test"),
+		}
+		require.NoError(t, svc.HandleStaleTokens(context.Background()))
+		require.Len(t, storeMock.Calls.RevokeServiceAccountTokens, 1)
+		assert.Equal(t, []int64{1, 2}, storeMock.Calls.RevokeServiceAccountTokens[0].([]interface{})[1])
+	})
+
+	t.Run("no feature toggle present, should not call store function", func(t *testing.T) {
+		storeMock := &tests.ServiceAccountsStoreMock{Calls: tests.Calls{}, ExpectedStaleTokens: staleTokens}
+		svc := ServiceAccountsService{
+			cfg:      &setting.Cfg{ServiceAccountRevokeStaleTokens: true},
+			features: featuremgmt.WithFeatures("service-accounts", false),
+			store:    storeMock,
+			log:      log.New("serviceaccounts-manager-test"),
+		}
+		require.NoError(t, svc.HandleStaleTokens(context.Background()))
+		assert.Len(t, storeMock.Calls.ListStaleTokens, 0)
+		assert.Len(t, storeMock.Calls.RevokeServiceAccountTokens, 0)
+	})
+}
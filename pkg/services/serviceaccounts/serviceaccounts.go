@@ -2,6 +2,7 @@ package serviceaccounts
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/models"
 )
@@ -11,6 +12,7 @@ type Service interface {
 	CreateServiceAccount(ctx context.Context, orgID int64, name string) (*ServiceAccountDTO, error)
 	DeleteServiceAccount(ctx context.Context, orgID, serviceAccountID int64) error
 	RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error)
+	HandleStaleTokens(ctx context.Context) error
 }
 
 type Store interface {
@@ -27,5 +29,9 @@ type Store interface {
 	ListTokens(ctx context.Context, orgID int64, serviceAccount int64) ([]*models.ApiKey, error)
 	DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) error
 	AddServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *AddServiceAccountTokenCommand) error
+	RotateServiceAccountToken(ctx context.Context, serviceAccountID, tokenID int64, cmd *RotateServiceAccountTokenCommand) error
+	ListStaleTokens(ctx context.Context, now time.Time, maxUnused time.Duration) ([]*models.ApiKey, error)
+	RevokeServiceAccountTokens(ctx context.Context, tokenIDs []int64) error
+	DeleteExpiredServiceAccountTokens(ctx context.Context, now time.Time, namePrefix string) (int64, error)
 	GetUsageMetrics(ctx context.Context) (map[string]interface{}, error)
 }
@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -50,6 +51,10 @@ func (s *ServiceAccountMock) DeleteServiceAccount(ctx context.Context, orgID, se
 	return nil
 }
 
+func (s *ServiceAccountMock) HandleStaleTokens(ctx context.Context) error {
+	return nil
+}
+
 func (s *ServiceAccountMock) Migrated(ctx context.Context, orgID int64) bool {
 	return false
 }
@@ -81,12 +86,18 @@ type Calls struct {
 	DeleteServiceAccountToken      []interface{}
 	UpdateServiceAccount           []interface{}
 	AddServiceAccountToken         []interface{}
+	RotateServiceAccountToken      []interface{}
+	ListStaleTokens                []interface{}
+	RevokeServiceAccountTokens     []interface{}
+	DeleteExpiredTokens            []interface{}
 	SearchOrgServiceAccounts       []interface{}
 	RetrieveServiceAccountIdByName []interface{}
 }
 
 type ServiceAccountsStoreMock struct {
 	Calls Calls
+
+	ExpectedStaleTokens []*models.ApiKey
 }
 
 func (s *ServiceAccountsStoreMock) RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error) {
@@ -156,6 +167,26 @@ func (s *ServiceAccountsStoreMock) AddServiceAccountToken(ctx context.Context, s
 	return nil
 }
 
+func (s *ServiceAccountsStoreMock) RotateServiceAccountToken(ctx context.Context, serviceAccountID, tokenID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) error {
+	s.Calls.RotateServiceAccountToken = append(s.Calls.RotateServiceAccountToken, []interface{}{ctx, serviceAccountID, tokenID, cmd})
+	return nil
+}
+
+func (s *ServiceAccountsStoreMock) ListStaleTokens(ctx context.Context, now time.Time, maxUnused time.Duration) ([]*models.ApiKey, error) {
+	s.Calls.ListStaleTokens = append(s.Calls.ListStaleTokens, []interface{}{ctx, now, maxUnused})
+	return s.ExpectedStaleTokens, nil
+}
+
+func (s *ServiceAccountsStoreMock) RevokeServiceAccountTokens(ctx context.Context, tokenIDs []int64) error {
+	s.Calls.RevokeServiceAccountTokens = append(s.Calls.RevokeServiceAccountTokens, []interface{}{ctx, tokenIDs})
+	return nil
+}
+
+func (s *ServiceAccountsStoreMock) DeleteExpiredServiceAccountTokens(ctx context.Context, now time.Time, namePrefix string) (int64, error) {
+	s.Calls.DeleteExpiredTokens = append(s.Calls.DeleteExpiredTokens, []interface{}{ctx, now, namePrefix})
+	return 0, nil
+}
+
 func (s *ServiceAccountsStoreMock) GetUsageMetrics(ctx context.Context) (map[string]interface{}, error) {
 	return map[string]interface{}{}, nil
 }
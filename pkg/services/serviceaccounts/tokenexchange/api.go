@@ -0,0 +1,81 @@
+package tokenexchange
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeJWT           = "urn:ietf:params:oauth:token-type:jwt"
+	TokenTypeIDToken       = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+type tokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+}
+
+type errorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// RegisterAPIEndpoints registers the token endpoint. It doesn't require authentication,
+// the subject token of the request is the credential.
+func (s *Service) RegisterAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Post("/api/serviceaccounts/token", routing.Wrap(s.exchangeToken))
+}
+
+func (s *Service) exchangeToken(c *models.ReqContext) response.Response {
+	if err := c.Req.ParseForm(); err != nil {
+		return oauthError(http.StatusBadRequest, "invalid_request", "invalid form data")
+	}
+
+	if c.Req.PostForm.Get("grant_type") != GrantTypeTokenExchange {
+		return oauthError(http.StatusBadRequest, "unsupported_grant_type", "grant_type must be "+GrantTypeTokenExchange)
+	}
+	subjectToken := c.Req.PostForm.Get("subject_token")
+	if subjectToken == "" {
+		return oauthError(http.StatusBadRequest, "invalid_request", "subject_token is required")
+	}
+	switch c.Req.PostForm.Get("subject_token_type") {
+	case TokenTypeJWT, TokenTypeIDToken:
+	default:
+		return oauthError(http.StatusBadRequest, "invalid_request", "subject_token_type must be "+TokenTypeJWT+" or "+TokenTypeIDToken)
+	}
+	if tokenType := c.Req.PostForm.Get("requested_token_type"); tokenType != "" && tokenType != TokenTypeAccessToken {
+		return oauthError(http.StatusBadRequest, "invalid_request", "requested_token_type must be "+TokenTypeAccessToken)
+	}
+
+	result, err := s.Exchange(c.Req.Context(), subjectToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidSubjectToken):
+			return oauthError(http.StatusBadRequest, "invalid_grant", "subject_token is invalid or expired")
+		case errors.Is(err, errNoMatchingRule), errors.Is(err, ErrServiceAccountNotAvailable):
+			return oauthError(http.StatusBadRequest, "invalid_grant", "subject_token is not mapped to a service account")
+		default:
+			s.log.Error("Failed to exchange token", "error", err)
+			return oauthError(http.StatusInternalServerError, "server_error", "failed to issue a service account token")
+		}
+	}
+
+	return response.JSON(http.StatusOK, tokenResponse{
+		AccessToken:     result.Key,
+		IssuedTokenType: TokenTypeAccessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(result.ExpiresIn.Seconds()),
+	}).SetHeader("Cache-Control", "no-store")
+}
+
+func oauthError(status int, code, description string) response.Response {
+	return response.JSON(status, errorResponse{Error: code, ErrorDescription: description}).SetHeader("Cache-Control", "no-store")
+}
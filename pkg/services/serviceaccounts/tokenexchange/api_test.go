@@ -0,0 +1,261 @@
+package tokenexchange
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/database"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type claims struct {
+	jwt.Claims
+	Repository string `json:"repository"`
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, c claims) string {
+	t.Helper()
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, (&jose.SignerOptions{}).WithType("JWT"))
+	require.NoError(t, err)
+	token, err := jwt.Signed(sig).Claims(c).CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func writePublicKey(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+	blockBytes, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "public-key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: blockBytes}), 0600))
+	return path
+}
+
+func TestTokenExchange(t *testing.T) {
+	// the fake remote cache initializes the test database, it must be created first
+	remoteCache := remotecache.NewFakeStore(t)
+	store := sqlstore.InitTestDB(t)
+	saStore := database.NewServiceAccountsStore(store)
+	sa := tests.SetupUserServiceAccount(t, store, tests.TestUser{Name: "ci", Login: "sa-ci", IsServiceAccount: true})
+	disabledSA := tests.SetupUserServiceAccount(t, store, tests.TestUser{Name: "disabled", Login: "sa-disabled", IsServiceAccount: true})
+	disabled := true
+	_, err := saStore.UpdateServiceAccount(context.Background(), disabledSA.OrgId, disabledSA.Id, &serviceaccounts.UpdateServiceAccountForm{IsDisabled: &disabled})
+	require.NoError(t, err)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	unknownKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	cfg := setting.NewCfg()
+	cfg.ApiKeyMaxSecondsToLive = -1
+	cfg.TokenExchangeKeyFile = writePublicKey(t, key)
+	cfg.TokenExchangeExpectClaims = `{"iss": "https://ci.example.com", "aud": "grafana"}`
+	cfg.TokenExchangeMappings = fmt.Sprintf(`[
+		{"claims": {"repository": "acme/*"}, "org_id": %d, "service_account": "ci"},
+		{"claims": {"repository": "other/disabled"}, "org_id": %d, "service_account": "disabled"},
+		{"claims": {"repository": "other/missing"}, "org_id": %d, "service_account": "missing"}
+	]`, sa.OrgId, disabledSA.OrgId, sa.OrgId)
+	cfg.TokenExchangeTokenTTL = 10 * time.Minute
+
+	svc, err := ProvideService(cfg, saStore, remoteCache)
+	require.NoError(t, err)
+
+	routeRegister := routing.NewRouteRegister()
+	svc.RegisterAPIEndpoints(routeRegister)
+	m := web.New()
+	m.Use(func(c *web.Context) {
+		c.Map(&models.ReqContext{Context: c, SignedInUser: &models.SignedInUser{}, Logger: log.New("tokenexchange-test")})
+	})
+	routeRegister.Register(m.Router)
+
+	exchange := func(t *testing.T, form url.Values) (int, map[string]interface{}) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, "/api/serviceaccounts/token", strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		m.ServeHTTP(recorder, req)
+		assert.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+
+		body := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		return recorder.Code, body
+	}
+
+	formFor := func(token string) url.Values {
+		return url.Values{
+			"grant_type":         {GrantTypeTokenExchange},
+			"subject_token":      {token},
+			"subject_token_type": {TokenTypeJWT},
+		}
+	}
+
+	validClaims := claims{
+		Claims: jwt.Claims{
+			Issuer:   "https://ci.example.com",
+			Subject:  "repo:acme/app",
+			Audience: jwt.Audience{"grafana"},
+			Expiry:   jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		Repository: "acme/app",
+	}
+
+	t.Run("issues a short-lived token for the mapped service account", func(t *testing.T) {
+		code, body := exchange(t, formFor(sign(t, key, validClaims)))
+		require.Equal(t, http.StatusOK, code, body)
+		assert.Equal(t, TokenTypeAccessToken, body["issued_token_type"])
+		assert.Equal(t, "Bearer", body["token_type"])
+		assert.Equal(t, float64(600), body["expires_in"])
+
+		decoded, err := apikeygen.Decode(body["access_token"].(string))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(decoded.Name, TokenNamePrefix))
+
+		query := models.GetApiKeyByNameQuery{KeyName: decoded.Name, OrgId: decoded.OrgId}
+		require.NoError(t, store.GetApiKeyByName(context.Background(), &query))
+		assert.Equal(t, sa.Id, *query.Result.ServiceAccountId)
+		assert.InDelta(t, time.Now().Add(10*time.Minute).Unix(), *query.Result.Expires, 5)
+		valid, err := apikeygen.IsValid(decoded, query.Result.Key)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("rejects invalid subject tokens", func(t *testing.T) {
+		code, body := exchange(t, formFor(sign(t, unknownKey, validClaims)))
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		expired := validClaims
+		expired.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+		code, body = exchange(t, formFor(sign(t, key, expired)))
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		otherIssuer := validClaims
+		otherIssuer.Issuer = "https://other.example.com"
+		code, body = exchange(t, formFor(sign(t, key, otherIssuer)))
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		otherAudience := validClaims
+		otherAudience.Audience = jwt.Audience{"other-service"}
+		code, body = exchange(t, formFor(sign(t, key, otherAudience)))
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		noExpiry := validClaims
+		noExpiry.Expiry = nil
+		code, body = exchange(t, formFor(sign(t, key, noExpiry)))
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_grant", body["error"])
+		assert.Equal(t, "subject_token is invalid or expired", body["error_description"])
+	})
+
+	t.Run("rejects subject tokens that are not mapped to an enabled service account", func(t *testing.T) {
+		for _, repository := range []string{"other/app", "other/disabled", "other/missing"} {
+			c := validClaims
+			c.Repository = repository
+			code, body := exchange(t, formFor(sign(t, key, c)))
+			require.Equal(t, http.StatusBadRequest, code, repository)
+			assert.Equal(t, "invalid_grant", body["error"], repository)
+			assert.Equal(t, "subject_token is not mapped to a service account", body["error_description"], repository)
+		}
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		form := formFor(sign(t, key, validClaims))
+		form.Set("grant_type", "client_credentials")
+		code, body := exchange(t, form)
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "unsupported_grant_type", body["error"])
+
+		form = formFor(sign(t, key, validClaims))
+		form.Set("subject_token_type", "urn:ietf:params:oauth:token-type:saml2")
+		code, body = exchange(t, form)
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_request", body["error"])
+
+		code, body = exchange(t, formFor(""))
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_request", body["error"])
+	})
+}
+
+func TestProvideService(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	newCfg := func() *setting.Cfg {
+		cfg := setting.NewCfg()
+		cfg.ApiKeyMaxSecondsToLive = -1
+		cfg.TokenExchangeKeyFile = writePublicKey(t, key)
+		cfg.TokenExchangeExpectClaims = `{"iss": "https://ci.example.com", "aud": "grafana"}`
+		cfg.TokenExchangeMappings = `[{"claims": {"sub": "ci"}, "org_id": 1, "service_account": "ci"}]`
+		cfg.TokenExchangeTokenTTL = time.Hour
+		return cfg
+	}
+
+	_, err = ProvideService(newCfg(), nil, remotecache.NewFakeStore(t))
+	require.NoError(t, err)
+
+	cfg := newCfg()
+	cfg.TokenExchangeKeyFile = ""
+	_, err = ProvideService(cfg, nil, remotecache.NewFakeStore(t))
+	require.EqualError(t, err, "service account token exchange: key set for jwt verification is not configured")
+
+	cfg = newCfg()
+	cfg.ApiKeyMaxSecondsToLive = 60
+	_, err = ProvideService(cfg, nil, remotecache.NewFakeStore(t))
+	require.EqualError(t, err, "service account token exchange: token_ttl is greater than api_key_max_seconds_to_live")
+
+	cfg = newCfg()
+	cfg.TokenExchangeMappings = `[{"org_id": 1, "service_account": "ci"}]`
+	_, err = ProvideService(cfg, nil, remotecache.NewFakeStore(t))
+	require.EqualError(t, err, "service account token exchange: mapping 0: at least one claim is required")
+
+	cfg = newCfg()
+	cfg.TokenExchangeExpectClaims = `{"iss": "https://ci.example.com"}`
+	_, err = ProvideService(cfg, nil, remotecache.NewFakeStore(t))
+	require.EqualError(t, err, "service account token exchange: mapping 0: aud and iss claims are required when they are not in expect_claims")
+
+	cfg = newCfg()
+	cfg.TokenExchangeExpectClaims = "{}"
+	cfg.TokenExchangeMappings = `[
+		{"claims": {"sub": "ci", "iss": "https://ci.example.com", "aud": "grafana"}, "org_id": 1, "service_account": "ci"},
+		{"claims": {"sub": "ci", "iss": "https://ci.example.com", "aud": "*"}, "org_id": 1, "service_account": "ci"}
+	]`
+	_, err = ProvideService(cfg, nil, remotecache.NewFakeStore(t))
+	require.EqualError(t, err, "service account token exchange: mapping 1: aud and iss claims are required when they are not in expect_claims")
+
+	cfg.TokenExchangeMappings = `[{"claims": {"sub": "ci", "iss": "https://ci.example.com", "aud": "grafana"}, "org_id": 1, "service_account": "ci"}]`
+	_, err = ProvideService(cfg, nil, remotecache.NewFakeStore(t))
+	require.NoError(t, err)
+}
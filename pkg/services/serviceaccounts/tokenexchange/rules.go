@@ -0,0 +1,103 @@
+package tokenexchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// Rule maps the external tokens that have all the claims of the rule to a service account.
+// String claim values can use * as a wildcard, e.g. "refs/heads/*".
+type Rule struct {
+	Claims         map[string]interface{} `json:"claims"`
+	OrgID          int64                  `json:"org_id"`
+	ServiceAccount string                 `json:"service_account"`
+
+	patterns map[string]*regexp.Regexp
+}
+
+func parseRules(raw string) ([]*Rule, error) {
+	var rules []*Rule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("invalid mappings: %w", err)
+	}
+
+	for i, rule := range rules {
+		if len(rule.Claims) == 0 {
+			return nil, fmt.Errorf("mapping %d: at least one claim is required", i)
+		}
+		if rule.OrgID < 1 {
+			return nil, fmt.Errorf("mapping %d: org_id is required", i)
+		}
+		if rule.ServiceAccount == "" {
+			return nil, fmt.Errorf("mapping %d: service_account is required", i)
+		}
+
+		rule.patterns = make(map[string]*regexp.Regexp)
+		for claim, value := range rule.Claims {
+			if s, ok := value.(string); ok && strings.Contains(s, "*") {
+				pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(s), `\*`, ".*") + "$"
+				rule.patterns[claim] = regexp.MustCompile(pattern)
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+// matches returns true if the claims of the token have the values of all the claims of the rule.
+// A string value of the rule matches an array claim, such as aud, when it matches one of its elements.
+func (r *Rule) matches(claims models.JWTClaims) bool {
+	for claim, expected := range r.Claims {
+		value, ok := claims[claim]
+		if !ok {
+			return false
+		}
+		if values, ok := value.([]interface{}); ok {
+			if !r.matchesAny(claim, expected, values) {
+				return false
+			}
+			continue
+		}
+		if !r.matchesValue(claim, expected, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Rule) matchesAny(claim string, expected interface{}, values []interface{}) bool {
+	if reflect.DeepEqual(expected, values) {
+		return true
+	}
+	for _, value := range values {
+		if r.matchesValue(claim, expected, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesValue(claim string, expected interface{}, value interface{}) bool {
+	if pattern, ok := r.patterns[claim]; ok {
+		s, ok := value.(string)
+		return ok && pattern.MatchString(s)
+	}
+	return reflect.DeepEqual(expected, value)
+}
+
+var errNoMatchingRule = errors.New("no mapping matches the claims of the token")
+
+func findRule(rules []*Rule, claims models.JWTClaims) (*Rule, error) {
+	for _, rule := range rules {
+		if rule.matches(claims) {
+			return rule, nil
+		}
+	}
+	return nil, errNoMatchingRule
+}
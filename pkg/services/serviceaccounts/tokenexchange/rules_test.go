@@ -0,0 +1,63 @@
+package tokenexchange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestParseRules(t *testing.T) {
+	t.Run("invalid rules are rejected", func(t *testing.T) {
+		testCases := []struct {
+			desc  string
+			rules string
+			err   string
+		}{
+			{desc: "invalid json", rules: `{}`, err: "invalid mappings: json: cannot unmarshal object into Go value of type []*tokenexchange.Rule"},
+			{desc: "no claims", rules: `[{"org_id": 1, "service_account": "ci"}]`, err: "mapping 0: at least one claim is required"},
+			{desc: "no org", rules: `[{"claims": {"sub": "ci"}, "service_account": "ci"}]`, err: "mapping 0: org_id is required"},
+			{desc: "no service account", rules: `[{"claims": {"sub": "ci"}, "org_id": 1}]`, err: "mapping 0: service_account is required"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				_, err := parseRules(tc.rules)
+				require.EqualError(t, err, tc.err)
+			})
+		}
+	})
+
+	t.Run("first matching rule is used", func(t *testing.T) {
+		rules, err := parseRules(`[
+			{"claims": {"repository": "acme/deployments", "ref": "refs/heads/main"}, "org_id": 1, "service_account": "deployer"},
+			{"claims": {"repository": "acme/*", "aud": "grafana"}, "org_id": 2, "service_account": "ci"}
+		]`)
+		require.NoError(t, err)
+
+		rule, err := findRule(rules, models.JWTClaims{"repository": "acme/deployments", "ref": "refs/heads/main", "aud": "grafana"})
+		require.NoError(t, err)
+		require.Equal(t, "deployer", rule.ServiceAccount)
+
+		rule, err = findRule(rules, models.JWTClaims{"repository": "acme/deployments", "ref": "refs/heads/dev", "aud": []interface{}{"other", "grafana"}})
+		require.NoError(t, err)
+		require.Equal(t, "ci", rule.ServiceAccount)
+
+		_, err = findRule(rules, models.JWTClaims{"repository": "other/acme/deployments", "aud": "grafana"})
+		require.ErrorIs(t, err, errNoMatchingRule)
+
+		_, err = findRule(rules, models.JWTClaims{"repository": "acme/deployments"})
+		require.ErrorIs(t, err, errNoMatchingRule)
+	})
+
+	t.Run("non string claims are compared as is", func(t *testing.T) {
+		rules, err := parseRules(`[{"claims": {"run_attempt": 1, "groups": ["a", "b"]}, "org_id": 1, "service_account": "ci"}]`)
+		require.NoError(t, err)
+
+		_, err = findRule(rules, models.JWTClaims{"run_attempt": float64(1), "groups": []interface{}{"a", "b"}})
+		require.NoError(t, err)
+
+		_, err = findRule(rules, models.JWTClaims{"run_attempt": "1", "groups": []interface{}{"a", "b"}})
+		require.ErrorIs(t, err, errNoMatchingRule)
+	})
+}
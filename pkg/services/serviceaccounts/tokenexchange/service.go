@@ -0,0 +1,175 @@
+// Package tokenexchange lets workloads exchange the JSON Web Tokens issued by their platform, e.g. the OIDC tokens
+// of a CI system, for short-lived service account tokens, following the OAuth 2.0 token exchange (RFC 8693).
+package tokenexchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth/jwt"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// TokenNamePrefix is the prefix of the names of the service account tokens issued in exchange of external tokens.
+const TokenNamePrefix = "token-exchange-"
+
+var (
+	ErrInvalidSubjectToken        = errors.New("invalid subject token")
+	ErrServiceAccountNotAvailable = errors.New("service account not found or disabled")
+)
+
+type Service struct {
+	store    serviceaccounts.Store
+	verifier models.JWTService
+	rules    []*Rule
+	tokenTTL time.Duration
+	log      log.Logger
+}
+
+// Result is a service account token issued in exchange of an external token.
+type Result struct {
+	Key       string
+	ExpiresIn time.Duration
+}
+
+// ProvideService returns a token exchange service that verifies the external tokens with the keys of the
+// [auth.service_account_token_exchange] section.
+func ProvideService(cfg *setting.Cfg, store serviceaccounts.Store, remoteCache *remotecache.RemoteCache) (*Service, error) {
+	logger := log.New("serviceaccounts.tokenexchange")
+	verifier, err := jwt.NewVerifier(jwt.VerifierSettings{
+		KeyFile:      cfg.TokenExchangeKeyFile,
+		JWKSetFile:   cfg.TokenExchangeJWKSetFile,
+		JWKSetURL:    cfg.TokenExchangeJWKSetURL,
+		CacheTTL:     cfg.TokenExchangeCacheTTL,
+		ExpectClaims: cfg.TokenExchangeExpectClaims,
+	}, remoteCache, logger)
+	if err != nil {
+		return nil, fmt.Errorf("service account token exchange: %w", err)
+	}
+
+	return newService(cfg, store, verifier, logger)
+}
+
+func newService(cfg *setting.Cfg, store serviceaccounts.Store, verifier models.JWTService, logger log.Logger) (*Service, error) {
+	rules, err := parseRules(cfg.TokenExchangeMappings)
+	if err != nil {
+		return nil, fmt.Errorf("service account token exchange: %w", err)
+	}
+	if err := checkAudienceAndIssuer(cfg.TokenExchangeExpectClaims, rules); err != nil {
+		return nil, fmt.Errorf("service account token exchange: %w", err)
+	}
+
+	if cfg.TokenExchangeTokenTTL < time.Second {
+		return nil, errors.New("service account token exchange: token_ttl must be at least 1s")
+	}
+	if cfg.ApiKeyMaxSecondsToLive != -1 && int64(cfg.TokenExchangeTokenTTL.Seconds()) > cfg.ApiKeyMaxSecondsToLive {
+		return nil, errors.New("service account token exchange: token_ttl is greater than api_key_max_seconds_to_live")
+	}
+
+	return &Service{
+		store:    store,
+		verifier: verifier,
+		rules:    rules,
+		tokenTTL: cfg.TokenExchangeTokenTTL,
+		log:      logger,
+	}, nil
+}
+
+// Exchange verifies the subject token and issues a token for the service account of the first rule
+// that matches its claims.
+func (s *Service) Exchange(ctx context.Context, subjectToken string) (*Result, error) {
+	claims, err := s.verifier.Verify(ctx, subjectToken)
+	if err != nil {
+		s.log.Debug("Failed to verify subject token", "error", err)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSubjectToken, err)
+	}
+	// the verifier accepts tokens without expiry, they could be exchanged forever
+	if _, ok := claims["exp"].(float64); !ok {
+		s.log.Debug("Rejected subject token without expiry", "iss", claims["iss"], "sub", claims["sub"])
+		return nil, fmt.Errorf("%w: %q claim is missing", ErrInvalidSubjectToken, "exp")
+	}
+
+	rule, err := findRule(s.rules, claims)
+	if err != nil {
+		s.log.Info("Rejected token exchange", "iss", claims["iss"], "sub", claims["sub"], "error", err)
+		return nil, err
+	}
+
+	saID, err := s.store.RetrieveServiceAccountIdByName(ctx, rule.OrgID, rule.ServiceAccount)
+	if err != nil {
+		if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+			return nil, ErrServiceAccountNotAvailable
+		}
+		return nil, err
+	}
+	sa, err := s.store.RetrieveServiceAccount(ctx, rule.OrgID, saID)
+	if err != nil {
+		return nil, err
+	}
+	if sa.IsDisabled {
+		return nil, ErrServiceAccountNotAvailable
+	}
+
+	name := TokenNamePrefix + util.GenerateShortUID()
+	newKeyInfo, err := apikeygen.New(rule.OrgID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := serviceaccounts.AddServiceAccountTokenCommand{
+		Name:          name,
+		OrgId:         rule.OrgID,
+		Key:           newKeyInfo.HashedKey,
+		SecondsToLive: int64(s.tokenTTL.Seconds()),
+	}
+	if err := s.store.AddServiceAccountToken(ctx, saID, &cmd); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Issued service account token in exchange of external token", "orgId", rule.OrgID, "serviceAccountId", saID,
+		"tokenId", cmd.Result.Id, "iss", claims["iss"], "sub", claims["sub"])
+
+	return &Result{Key: newKeyInfo.ClientSecret, ExpiresIn: s.tokenTTL}, nil
+}
+
+// checkAudienceAndIssuer makes sure that the tokens signed by the configured keys for other audiences or
+// by other issuers can't be exchanged, either because the expected claims or every mapping pin aud and iss.
+func checkAudienceAndIssuer(expectClaims string, rules []*Rule) error {
+	var expect map[string]interface{}
+	if err := json.Unmarshal([]byte(expectClaims), &expect); err != nil {
+		return fmt.Errorf("invalid expect_claims: %w", err)
+	}
+	if pinsAudienceAndIssuer(expect) {
+		return nil
+	}
+
+	for i, rule := range rules {
+		if !pinsAudienceAndIssuer(rule.Claims) {
+			return fmt.Errorf("mapping %d: aud and iss claims are required when they are not in expect_claims", i)
+		}
+	}
+	return nil
+}
+
+func pinsAudienceAndIssuer(claims map[string]interface{}) bool {
+	for _, claim := range []string{"aud", "iss"} {
+		value, ok := claims[claim]
+		if !ok || value == nil || value == "" {
+			return false
+		}
+		if s, ok := value.(string); ok && strings.Contains(s, "*") {
+			return false
+		}
+	}
+	return true
+}
@@ -11,9 +11,11 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotations/loki"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/sqlstore/permissions"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 // Update the item so that EpochEnd >= Epoch
@@ -41,6 +43,24 @@ func NewSQLAnnotationRepo(sql *SQLStore) SQLAnnotationRepo {
 	return SQLAnnotationRepo{sql: sql}
 }
 
+// newAnnotationRepository returns the repository of the annotation backend of the configuration.
+func newAnnotationRepository(ss *SQLStore) (annotations.Repository, error) {
+	sqlRepo := &SQLAnnotationRepo{sql: ss}
+	storage := ss.Cfg.AnnotationStorage
+	if storage.Backend != setting.AnnotationBackendLoki {
+		return sqlRepo, nil
+	}
+	if storage.LokiURL == "" {
+		return nil, errors.New("annotations are stored in Loki but [annotations.loki] url is not set")
+	}
+
+	lokiRepo := loki.NewRepository(storage, sqlRepo.ReadFilter)
+	if !storage.ReadSQL {
+		return lokiRepo, nil
+	}
+	return annotations.NewCompositeRepository(lokiRepo, sqlRepo), nil
+}
+
 func (r *SQLAnnotationRepo) Save(item *annotations.Item) error {
 	return inTransaction(func(sess *DBSession) error {
 		tags := models.ParseTagPairs(item.Tags)
@@ -157,117 +177,186 @@ func (r *SQLAnnotationRepo) Find(ctx context.Context, query *annotations.ItemQue
 				SELECT a.id from annotation a
 			`)
 
-		sql.WriteString(`WHERE a.org_id = ?`)
-		params = append(params, query.OrgId)
-
-		if query.AnnotationId != 0 {
-			// fmt.Print("annotation query")
-			sql.WriteString(` AND a.id = ?`)
-			params = append(params, query.AnnotationId)
+		filter, filterParams, err := r.annotationFilter(sess, query)
+		if err != nil {
+			return err
 		}
+		sql.WriteString(filter)
+		params = append(params, filterParams...)
 
-		if query.AlertId != 0 {
-			sql.WriteString(` AND a.alert_id = ?`)
-			params = append(params, query.AlertId)
+		if query.Limit == 0 {
+			query.Limit = 100
 		}
 
-		if query.DashboardId != 0 {
-			sql.WriteString(` AND a.dashboard_id = ?`)
-			params = append(params, query.DashboardId)
-		}
+		// order of ORDER BY arguments match the order of a sql index for performance
+		sql.WriteString(" ORDER BY a.org_id, a.epoch_end DESC, a.epoch DESC" + dialect.Limit(query.Limit) + " ) dt on dt.id = annotation.id")
 
-		if query.PanelId != 0 {
-			sql.WriteString(` AND a.panel_id = ?`)
-			params = append(params, query.PanelId)
+		if err := sess.SQL(sql.String(), params...).Find(&items); err != nil {
+			items = nil
+			return err
 		}
+		return nil
+	},
+	)
 
-		if query.UserId != 0 {
-			sql.WriteString(` AND a.user_id = ?`)
-			params = append(params, query.UserId)
-		}
+	return items, err
+}
 
-		if query.From > 0 && query.To > 0 {
-			sql.WriteString(` AND a.epoch <= ? AND a.epoch_end >= ?`)
-			params = append(params, query.To, query.From)
-		}
+// annotationFilter returns the WHERE clause of the annotations, aliased as "a", that match the query.
+func (r *SQLAnnotationRepo) annotationFilter(sess *DBSession, query *annotations.ItemQuery) (string, []interface{}, error) {
+	var sql bytes.Buffer
+	params := make([]interface{}, 0)
 
-		if query.Type == "alert" {
-			sql.WriteString(` AND a.alert_id > 0`)
-		} else if query.Type == "annotation" {
-			sql.WriteString(` AND a.alert_id = 0`)
-		}
+	sql.WriteString(`WHERE a.org_id = ?`)
+	params = append(params, query.OrgId)
 
-		if len(query.Tags) > 0 {
-			keyValueFilters := []string{}
+	if query.AnnotationId != 0 {
+		// fmt.Print("annotation query")
+		sql.WriteString(` AND a.id = ?`)
+		params = append(params, query.AnnotationId)
+	}
 
-			tags := models.ParseTagPairs(query.Tags)
-			for _, tag := range tags {
-				if tag.Value == "" {
-					keyValueFilters = append(keyValueFilters, "(tag."+dialect.Quote("key")+" = ?)")
-					params = append(params, tag.Key)
-				} else {
-					keyValueFilters = append(keyValueFilters, "(tag."+dialect.Quote("key")+" = ? AND tag."+dialect.Quote("value")+" = ?)")
-					params = append(params, tag.Key, tag.Value)
-				}
+	if query.AlertId != 0 {
+		sql.WriteString(` AND a.alert_id = ?`)
+		params = append(params, query.AlertId)
+	}
+
+	if query.DashboardId != 0 {
+		sql.WriteString(` AND a.dashboard_id = ?`)
+		params = append(params, query.DashboardId)
+	}
+
+	if query.PanelId != 0 {
+		sql.WriteString(` AND a.panel_id = ?`)
+		params = append(params, query.PanelId)
+	}
+
+	if query.UserId != 0 {
+		sql.WriteString(` AND a.user_id = ?`)
+		params = append(params, query.UserId)
+	}
+
+	if query.From > 0 && query.To > 0 {
+		sql.WriteString(` AND a.epoch <= ? AND a.epoch_end >= ?`)
+		params = append(params, query.To, query.From)
+	}
+
+	if query.Type == "alert" {
+		sql.WriteString(` AND a.alert_id > 0`)
+	} else if query.Type == "annotation" {
+		sql.WriteString(` AND a.alert_id = 0`)
+	} else if query.Type == "region" {
+		sql.WriteString(` AND a.epoch_end > a.epoch`)
+	}
+
+	for _, word := range strings.Fields(query.Text) {
+		sql.WriteString(` AND a.text ` + dialect.LikeStr() + ` ?`)
+		params = append(params, "%"+word+"%")
+	}
+
+	if len(query.Tags) > 0 {
+		keyValueFilters := []string{}
+
+		tags := models.ParseTagPairs(query.Tags)
+		for _, tag := range tags {
+			if tag.Value == "" {
+				keyValueFilters = append(keyValueFilters, "(tag."+dialect.Quote("key")+" = ?)")
+				params = append(params, tag.Key)
+			} else {
+				keyValueFilters = append(keyValueFilters, "(tag."+dialect.Quote("key")+" = ? AND tag."+dialect.Quote("value")+" = ?)")
+				params = append(params, tag.Key, tag.Value)
 			}
+		}
 
-			if len(tags) > 0 {
-				tagsSubQuery := fmt.Sprintf(`
-			SELECT SUM(1) FROM annotation_tag at
-			INNER JOIN tag on tag.id = at.tag_id
-			WHERE at.annotation_id = a.id
-				AND (
-				%s
-				)
-		`, strings.Join(keyValueFilters, " OR "))
-
-				if query.MatchAny {
-					sql.WriteString(fmt.Sprintf(" AND (%s) > 0 ", tagsSubQuery))
-				} else {
-					sql.WriteString(fmt.Sprintf(" AND (%s) = %d ", tagsSubQuery, len(tags)))
-				}
+		if len(tags) > 0 {
+			tagsSubQuery := fmt.Sprintf(`
+		SELECT SUM(1) FROM annotation_tag at
+		INNER JOIN tag on tag.id = at.tag_id
+		WHERE at.annotation_id = a.id
+			AND (
+			%s
+			)
+	`, strings.Join(keyValueFilters, " OR "))
+
+			if query.MatchAny {
+				sql.WriteString(fmt.Sprintf(" AND (%s) > 0 ", tagsSubQuery))
+			} else {
+				sql.WriteString(fmt.Sprintf(" AND (%s) = %d ", tagsSubQuery, len(tags)))
 			}
 		}
+	}
 
-		if r.sql.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagAccesscontrol) {
-			acFilter, acArgs, err := getAccessControlFilter(query.SignedInUser)
-			if err != nil {
-				return err
+	for _, matcher := range query.TagMatchers {
+		tagIDs, err := matchingTagIDs(sess, matcher)
+		if err != nil {
+			return "", nil, err
+		}
+		switch {
+		case len(tagIDs) == 0 && matcher.Negative():
+			// no annotation has a matching tag, so none is excluded.
+		case len(tagIDs) == 0:
+			sql.WriteString(` AND 1 = 0`)
+		default:
+			exists := "EXISTS"
+			if matcher.Negative() {
+				exists = "NOT EXISTS"
+			}
+			sql.WriteString(fmt.Sprintf(` AND %s (SELECT 1 FROM annotation_tag at WHERE at.annotation_id = a.id AND at.tag_id IN (?%s))`, exists, strings.Repeat(",?", len(tagIDs)-1)))
+			for _, id := range tagIDs {
+				params = append(params, id)
 			}
-			sql.WriteString(fmt.Sprintf(" AND (%s)", acFilter))
-			params = append(params, acArgs...)
 		}
+	}
 
-		if query.Limit == 0 {
-			query.Limit = 100
+	if r.sql.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagAccesscontrol) {
+		acFilter, acArgs, err := getAccessControlFilter(query.SignedInUser)
+		if err != nil {
+			return "", nil, err
 		}
+		sql.WriteString(fmt.Sprintf(" AND (%s)", acFilter))
+		params = append(params, acArgs...)
+	}
 
-		// order of ORDER BY arguments match the order of a sql index for performance
-		sql.WriteString(" ORDER BY a.org_id, a.epoch_end DESC, a.epoch DESC" + dialect.Limit(query.Limit) + " ) dt on dt.id = annotation.id")
+	return sql.String(), params, nil
+}
 
-		if err := sess.SQL(sql.String(), params...).Find(&items); err != nil {
-			items = nil
-			return err
+// matchingTagIDs returns the IDs of the tags with the key of the matcher whose values match.
+// The regular expressions are not supported by every database, so they are matched here.
+func matchingTagIDs(sess *DBSession, matcher *annotations.TagMatcher) ([]int64, error) {
+	var tags []*models.Tag
+	if err := sess.Table("tag").Where(dialect.Quote("key")+" = ?", matcher.Key).Find(&tags); err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(tags))
+	for _, tag := range tags {
+		if matcher.MatchesValue(tag.Value) {
+			ids = append(ids, tag.Id)
 		}
-		return nil
-	},
-	)
-
-	return items, err
+	}
+	return ids, nil
 }
 
-func getAccessControlFilter(user *models.SignedInUser) (string, []interface{}, error) {
+// readableAnnotationTypes returns the types of the annotations that the user can read.
+func readableAnnotationTypes(user *models.SignedInUser) (map[interface{}]struct{}, error) {
 	if user == nil || user.Permissions[user.OrgId] == nil {
-		return "", nil, errors.New("missing permissions")
+		return nil, errors.New("missing permissions")
 	}
 	scopes, has := user.Permissions[user.OrgId][ac.ActionAnnotationsRead]
 	if !has {
-		return "", nil, errors.New("missing permissions")
+		return nil, errors.New("missing permissions")
 	}
 	types, hasWildcardScope := ac.ParseScopes(ac.ScopeAnnotationsProvider.GetResourceScopeType(""), scopes)
 	if hasWildcardScope {
 		types = map[interface{}]struct{}{annotations.Dashboard.String(): {}, annotations.Organization.String(): {}}
 	}
+	return types, nil
+}
+
+func getAccessControlFilter(user *models.SignedInUser) (string, []interface{}, error) {
+	types, err := readableAnnotationTypes(user)
+	if err != nil {
+		return "", nil, err
+	}
 
 	var filters []string
 	var params []interface{}
@@ -287,6 +376,43 @@ func getAccessControlFilter(user *models.SignedInUser) (string, []interface{}, e
 	return strings.Join(filters, " OR "), params, nil
 }
 
+// ReadFilter returns a function that tells whether the user can read the annotations of a dashboard, where the
+// dashboard ID 0 stands for the annotations of the organization. It applies the access control of Find to
+// the annotations that are stored outside of the database.
+func (r *SQLAnnotationRepo) ReadFilter(ctx context.Context, user *models.SignedInUser) (func(dashboardID int64) bool, error) {
+	if !r.sql.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagAccesscontrol) {
+		return func(int64) bool { return true }, nil
+	}
+	types, err := readableAnnotationTypes(user)
+	if err != nil {
+		return nil, err
+	}
+
+	_, canReadOrganization := types[annotations.Organization.String()]
+	dashboards := map[int64]struct{}{}
+	if _, ok := types[annotations.Dashboard.String()]; ok {
+		var ids []int64
+		err := r.sql.WithDbSession(ctx, func(sess *DBSession) error {
+			filter, params := permissions.NewAccessControlDashboardPermissionFilter(user, models.PERMISSION_VIEW, searchstore.TypeDashboard).Where()
+			return sess.SQL(fmt.Sprintf("SELECT id FROM dashboard WHERE org_id = ? AND (%s)", filter), append([]interface{}{user.OrgId}, params...)...).Find(&ids)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			dashboards[id] = struct{}{}
+		}
+	}
+
+	return func(dashboardID int64) bool {
+		if dashboardID == 0 {
+			return canReadOrganization
+		}
+		_, ok := dashboards[dashboardID]
+		return ok
+	}, nil
+}
+
 func (r *SQLAnnotationRepo) Delete(ctx context.Context, params *annotations.DeleteParams) error {
 	return r.sql.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
 		var (
@@ -323,6 +449,34 @@ func (r *SQLAnnotationRepo) Delete(ctx context.Context, params *annotations.Dele
 	})
 }
 
+func (r *SQLAnnotationRepo) Count(ctx context.Context, query *annotations.CountQuery) ([]*annotations.Bucket, error) {
+	buckets, err := annotations.NewBuckets(query)
+	if err != nil {
+		return nil, err
+	}
+
+	type timeRange struct {
+		Epoch    int64
+		EpochEnd int64
+	}
+	var ranges []timeRange
+	err = r.sql.WithDbSession(ctx, func(sess *DBSession) error {
+		filter, params, err := r.annotationFilter(sess, &query.ItemQuery)
+		if err != nil {
+			return err
+		}
+		return sess.SQL("SELECT a.epoch, a.epoch_end FROM annotation a "+filter, params...).Find(&ranges)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tr := range ranges {
+		annotations.AddToBuckets(buckets, query, tr.Epoch, tr.EpochEnd)
+	}
+	return buckets, nil
+}
+
 func (r *SQLAnnotationRepo) FindTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
 	var items []*annotations.Tag
 	err := r.sql.WithDbSession(ctx, func(dbSession *DBSession) error {
@@ -189,6 +189,113 @@ func TestAnnotations(t *testing.T) {
 			assert.Len(t, items, 1)
 		})
 
+		t.Run("Should find annotations using tag matchers", func(t *testing.T) {
+			matcher := func(s string) *annotations.TagMatcher {
+				m, err := annotations.ParseTagMatcher(s)
+				require.NoError(t, err)
+				return m
+			}
+			testCases := []struct {
+				matchers []string
+				expected []int64
+			}{
+				{matchers: []string{"server=~server-.*"}, expected: []int64{annotation.Id, annotation2.Id}},
+				{matchers: []string{"server=~server"}, expected: []int64{}},
+				{matchers: []string{"server!~server-[0-9]+"}, expected: []int64{organizationAnnotation1.Id, globalAnnotation2.Id}},
+				{matchers: []string{"type!=outage", "rollback=~.*"}, expected: []int64{globalAnnotation2.Id}},
+				{matchers: []string{"unknown!=value"}, expected: []int64{annotation.Id, annotation2.Id, organizationAnnotation1.Id, globalAnnotation2.Id}},
+				{matchers: []string{"unknown=value"}, expected: []int64{}},
+			}
+			for _, tc := range testCases {
+				query := &annotations.ItemQuery{OrgId: 1, From: 1, To: 30}
+				for _, m := range tc.matchers {
+					query.TagMatchers = append(query.TagMatchers, matcher(m))
+				}
+				items, err := repo.Find(context.Background(), query)
+				require.NoError(t, err)
+				ids := make([]int64, 0, len(items))
+				for _, item := range items {
+					ids = append(ids, item.Id)
+				}
+				assert.ElementsMatch(t, tc.expected, ids, "matchers %v", tc.matchers)
+			}
+		})
+
+		t.Run("Should find annotations by text", func(t *testing.T) {
+			items, err := repo.Find(context.Background(), &annotations.ItemQuery{
+				OrgId: 1,
+				Text:  "roll",
+			})
+			require.NoError(t, err)
+			require.Len(t, items, 1)
+			assert.Equal(t, globalAnnotation2.Id, items[0].Id)
+
+			items, err = repo.Find(context.Background(), &annotations.ItemQuery{
+				OrgId: 1,
+				Text:  "hello rollback",
+			})
+			require.NoError(t, err)
+			assert.Empty(t, items)
+		})
+
+		t.Run("Should find only regions that overlap the time range", func(t *testing.T) {
+			items, err := repo.Find(context.Background(), &annotations.ItemQuery{
+				OrgId: 1,
+				From:  21,
+				To:    30,
+				Type:  "region",
+			})
+			require.NoError(t, err)
+			require.Len(t, items, 1)
+			assert.Equal(t, annotation2.Id, items[0].Id)
+
+			items, err = repo.Find(context.Background(), &annotations.ItemQuery{
+				OrgId: 1,
+				From:  1,
+				To:    19,
+				Type:  "region",
+			})
+			require.NoError(t, err)
+			assert.Empty(t, items)
+		})
+
+		t.Run("Should count annotations in each bucket", func(t *testing.T) {
+			buckets, err := repo.Count(context.Background(), &annotations.CountQuery{
+				ItemQuery: annotations.ItemQuery{OrgId: 1, From: 10, To: 30},
+				Interval:  5,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []*annotations.Bucket{
+				{Time: 10, Count: 1},
+				{Time: 15, Count: 2},
+				{Time: 20, Count: 1},
+				{Time: 25, Count: 0},
+			}, buckets)
+
+			buckets, err = repo.Count(context.Background(), &annotations.CountQuery{
+				ItemQuery: annotations.ItemQuery{OrgId: 1, From: 10, To: 30, Tags: []string{"deploy"}},
+				Interval:  10,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []*annotations.Bucket{
+				{Time: 10, Count: 1},
+				{Time: 20, Count: 0},
+			}, buckets)
+		})
+
+		t.Run("Should not count annotations with an invalid interval", func(t *testing.T) {
+			_, err := repo.Count(context.Background(), &annotations.CountQuery{
+				ItemQuery: annotations.ItemQuery{OrgId: 1, From: 10, To: 30},
+			})
+			assert.ErrorIs(t, err, annotations.ErrInvalidInterval)
+
+			_, err = repo.Count(context.Background(), &annotations.CountQuery{
+				ItemQuery: annotations.ItemQuery{OrgId: 1, From: 1, To: annotations.MaxBuckets + 2},
+				Interval:  1,
+			})
+			assert.ErrorIs(t, err, annotations.ErrInvalidInterval)
+		})
+
 		t.Run("Can update annotation and remove all tags", func(t *testing.T) {
 			query := &annotations.ItemQuery{
 				OrgId:       1,
@@ -457,8 +564,10 @@ func TestAnnotationListingWithFGAC(t *testing.T) {
 				OrgId:        1,
 				SignedInUser: user,
 			})
+			canRead, filterErr := repo.ReadFilter(context.Background(), user)
 			if tc.expectedError {
 				require.Error(t, err)
+				require.Error(t, filterErr)
 				return
 			}
 			require.NoError(t, err)
@@ -466,6 +575,16 @@ func TestAnnotationListingWithFGAC(t *testing.T) {
 			for _, r := range results {
 				assert.Contains(t, tc.expectedAnnotationIds, r.Id)
 			}
+
+			// the annotations stored outside of the database are filtered like the ones in the database.
+			require.NoError(t, filterErr)
+			for _, a := range []*annotations.Item{dash1Annotation, dash2Annotation, organizationAnnotation} {
+				expected := false
+				for _, id := range tc.expectedAnnotationIds {
+					expected = expected || id == a.Id
+				}
+				assert.Equal(t, expected, canRead(a.DashboardId), "annotation %d", a.Id)
+			}
 		})
 	}
 }
@@ -104,6 +104,14 @@ func (ss *SQLStore) AddAPIKey(ctx context.Context, cmd *models.AddApiKeyCommand)
 	})
 }
 
+// UpdateAPIKeyLastUsed records when and from which address the API key was last used.
+func (ss *SQLStore) UpdateAPIKeyLastUsed(ctx context.Context, id int64, lastUsedAt time.Time, ip string) error {
+	return ss.WithDbSession(ctx, func(sess *DBSession) error {
+		_, err := sess.Exec("UPDATE api_key SET last_used_at = ?, last_used_ip = ? WHERE id = ?", lastUsedAt, ip, id)
+		return err
+	})
+}
+
 func (ss *SQLStore) GetApiKeyById(ctx context.Context, query *models.GetApiKeyByIdQuery) error {
 	return ss.WithDbSession(ctx, func(sess *DBSession) error {
 		var apikey models.ApiKey
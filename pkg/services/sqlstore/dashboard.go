@@ -223,13 +223,18 @@ func (ss *SQLStore) GetDashboardTags(ctx context.Context, query *models.GetDashb
 
 func (ss *SQLStore) GetDashboards(ctx context.Context, query *models.GetDashboardsQuery) error {
 	return ss.WithDbSession(ctx, func(dbSession *DBSession) error {
-		if len(query.DashboardIds) == 0 {
+		if len(query.DashboardIds) == 0 && len(query.DashboardUIds) == 0 {
 			return models.ErrCommandValidationFailed
 		}
 
 		var dashboards = make([]*models.Dashboard, 0)
 
-		err := dbSession.In("id", query.DashboardIds).Find(&dashboards)
+		var err error
+		if len(query.DashboardIds) > 0 {
+			err = dbSession.In("id", query.DashboardIds).Find(&dashboards)
+		} else {
+			err = dbSession.Where("org_id = ?", query.OrgId).In("uid", query.DashboardUIds).Find(&dashboards)
+		}
 		query.Result = dashboards
 		return err
 	})
@@ -0,0 +1,54 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// SaveIdentityProvisioning marks an identity as provisioned, or updates the config file it is provisioned from.
+func (ss *SQLStore) SaveIdentityProvisioning(ctx context.Context, cmd *models.SaveIdentityProvisioningCommand) error {
+	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
+		provisioning := models.IdentityProvisioning{}
+		exists, err := sess.Where("kind=? AND identity_id=?", cmd.Kind, cmd.IdentityId).Get(&provisioning)
+		if err != nil {
+			return err
+		}
+
+		provisioning.OrgId = cmd.OrgId
+		provisioning.Kind = cmd.Kind
+		provisioning.IdentityId = cmd.IdentityId
+		provisioning.ExternalId = cmd.ExternalId
+		provisioning.Updated = time.Now().Unix()
+
+		if exists {
+			_, err = sess.ID(provisioning.Id).Update(&provisioning)
+			return err
+		}
+		_, err = sess.Insert(&provisioning)
+		return err
+	})
+}
+
+func (ss *SQLStore) DeleteIdentityProvisioning(ctx context.Context, cmd *models.DeleteIdentityProvisioningCommand) error {
+	return ss.WithDbSession(ctx, func(sess *DBSession) error {
+		_, err := sess.Exec("DELETE FROM identity_provisioning WHERE kind=? AND identity_id=?", cmd.Kind, cmd.IdentityId)
+		return err
+	})
+}
+
+func (ss *SQLStore) GetProvisionedIdentities(ctx context.Context, query *models.GetProvisionedIdentitiesQuery) error {
+	return ss.WithDbSession(ctx, func(sess *DBSession) error {
+		query.Result = make([]*models.IdentityProvisioning, 0)
+		return sess.Where("kind=?", query.Kind).Asc("id").Find(&query.Result)
+	})
+}
+
+func (ss *SQLStore) IsIdentityProvisioned(ctx context.Context, query *models.IsIdentityProvisionedQuery) error {
+	return ss.WithDbSession(ctx, func(sess *DBSession) error {
+		exists, err := sess.Where("kind=? AND identity_id=?", query.Kind, query.IdentityId).Get(&models.IdentityProvisioning{})
+		query.Result = exists
+		return err
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addIdentityProvisioningMigrations(mg *Migrator) {
+	identityProvisioningV1 := Table{
+		Name: "identity_provisioning",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "identity_id", Type: DB_BigInt, Nullable: false},
+			{Name: "external_id", Type: DB_Text, Nullable: false},
+			{Name: "updated", Type: DB_Int, Default: "0", Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"kind", "identity_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create identity_provisioning table", NewAddTableMigration(identityProvisioningV1))
+	mg.AddMigration("add unique index identity_provisioning.kind_identity_id", NewAddIndexMigration(identityProvisioningV1, identityProvisioningV1.Indices[0]))
+}
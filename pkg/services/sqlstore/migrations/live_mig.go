@@ -22,3 +22,44 @@ func addLiveChannelMigrations(mg *migrator.Migrator) {
 	//mg.AddMigration("create live message table", migrator.NewAddTableMigration(liveMessage))
 	//mg.AddMigration("add index live_message.org_id_channel_unique", migrator.NewAddIndexMigration(liveMessage, liveMessage.Indices[0]))
 }
+
+func addLivePipelineMigrations(mg *migrator.Migrator) {
+	liveChannelRule := migrator.Table{
+		Name: "live_channel_rule",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "pattern", Type: migrator.DB_NVarchar, Length: 189, Nullable: false},
+			{Name: "settings", Type: migrator.DB_Text, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "pattern"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create live_channel_rule table", migrator.NewAddTableMigration(liveChannelRule))
+	mg.AddMigration("add index live_channel_rule.org_id_pattern", migrator.NewAddIndexMigration(liveChannelRule, liveChannelRule.Indices[0]))
+
+	liveWriteConfig := migrator.Table{
+		Name: "live_write_config",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "settings", Type: migrator.DB_Text, Nullable: false},
+			{Name: "secure_settings", Type: migrator.DB_Text, Nullable: true},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create live_write_config table", migrator.NewAddTableMigration(liveWriteConfig))
+	mg.AddMigration("add index live_write_config.org_id_uid", migrator.NewAddIndexMigration(liveWriteConfig, liveWriteConfig.Indices[0]))
+}
@@ -61,6 +61,9 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 		if mg.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagLiveConfig) {
 			addLiveChannelMigrations(mg)
 		}
+		if mg.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagLivePipeline) {
+			addLivePipelineMigrations(mg)
+		}
 		if mg.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagDashboardPreviews) {
 			addDashboardThumbsMigrations(mg)
 		}
@@ -80,6 +83,8 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 		}
 	}
 	addQueryHistoryStarMigrations(mg)
+	addIdentityProvisioningMigrations(mg)
+	addScheduledReportMigrations(mg)
 
 	if mg.Cfg != nil && mg.Cfg.IsFeatureToggleEnabled != nil {
 		if mg.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagDashboardComments) || mg.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagAnnotationComments) {
@@ -43,4 +43,21 @@ func addPlaylistMigrations(mg *Migrator) {
 		{Name: "value", Type: DB_Text, Nullable: false},
 		{Name: "title", Type: DB_Text, Nullable: false},
 	}))
+
+	mg.AddMigration("Add uid column to playlist", NewAddColumnMigration(playlistV2, &Column{
+		Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("Update uid column values in playlist", NewRawSQLMigration("").
+		SQLite("UPDATE playlist SET uid=printf('%09d',id);").
+		Postgres("UPDATE playlist SET uid=lpad('' || id::text,9,'0');").
+		Mysql("UPDATE playlist SET uid=lpad(id,9,'0');"))
+
+	mg.AddMigration("Add unique index playlist_org_id_uid", NewAddIndexMigration(playlistV2, &Index{
+		Cols: []string{"org_id", "uid"}, Type: UniqueIndex,
+	}))
+
+	mg.AddMigration("Add read_only column to playlist", NewAddColumnMigration(playlistV2, &Column{
+		Name: "read_only", Type: DB_Bool, Nullable: true,
+	}))
 }
@@ -0,0 +1,38 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addScheduledReportMigrations(mg *Migrator) {
+	scheduledReportV1 := Table{
+		Name: "scheduled_report",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "time_from", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "time_to", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "variables", Type: DB_Text, Nullable: true},
+			{Name: "recipients", Type: DB_Text, Nullable: false},
+			{Name: "schedule", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "timezone", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "enabled", Type: DB_Bool, Nullable: false},
+			{Name: "next_run_at", Type: DB_BigInt, Nullable: false},
+			{Name: "last_run_at", Type: DB_BigInt, Nullable: false},
+			{Name: "last_error", Type: DB_Text, Nullable: true},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "updated_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_BigInt, Nullable: false},
+			{Name: "updated", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"enabled", "next_run_at"}},
+		},
+	}
+
+	mg.AddMigration("create scheduled_report table", NewAddTableMigration(scheduledReportV1))
+	mg.AddMigration("add unique index scheduled_report.org_id_uid", NewAddIndexMigration(scheduledReportV1, scheduledReportV1.Indices[0]))
+	mg.AddMigration("add index scheduled_report.enabled_next_run_at", NewAddIndexMigration(scheduledReportV1, scheduledReportV1.Indices[1]))
+}
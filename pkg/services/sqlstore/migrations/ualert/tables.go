@@ -25,6 +25,12 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 
 	// Create provisioning data table
 	AddProvisioningMigrations(mg)
+
+	// Create alert_state_history table
+	AddAlertStateHistoryMigrations(mg)
+
+	// Create alert_scheduler_member table
+	AddAlertSchedulerMemberMigrations(mg)
 }
 
 // AddAlertDefinitionMigrations should not be modified.
@@ -353,3 +359,48 @@ func AddProvisioningMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("create provenance_type table", migrator.NewAddTableMigration(provisioningTable))
 	mg.AddMigration("add index to uniquify (record_key, record_type, org_id) columns", migrator.NewAddIndexMigration(provisioningTable, provisioningTable.Indices[0]))
 }
+
+func AddAlertStateHistoryMigrations(mg *migrator.Migrator) {
+	stateHistory := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "rule_org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "current_state", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "evaluation_values", Type: migrator.DB_Text, Nullable: true},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "evaluated_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"rule_org_id", "rule_uid", "evaluated_at"}, Type: migrator.IndexType},
+			{Cols: []string{"evaluated_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table", migrator.NewAddTableMigration(stateHistory))
+	mg.AddMigration("add index in alert_state_history on rule_org_id, rule_uid and evaluated_at columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[0]))
+	mg.AddMigration("add index in alert_state_history on evaluated_at column", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[1]))
+}
+
+func AddAlertSchedulerMemberMigrations(mg *migrator.Migrator) {
+	schedulerMember := migrator.Table{
+		Name: "alert_scheduler_member",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "member_id", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "heartbeat_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"member_id"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"heartbeat_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_scheduler_member table", migrator.NewAddTableMigration(schedulerMember))
+	mg.AddMigration("add unique index in alert_scheduler_member on member_id column", migrator.NewAddIndexMigration(schedulerMember, schedulerMember.Indices[0]))
+	mg.AddMigration("add index in alert_scheduler_member on heartbeat_at column", migrator.NewAddIndexMigration(schedulerMember, schedulerMember.Indices[1]))
+}
@@ -2,6 +2,7 @@ package mockstore
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -43,6 +44,7 @@ type SQLStoreMock struct {
 	ExpectedAPIKey                 *models.ApiKey
 	ExpectedUserStars              map[int64]bool
 	ExpectedLoginAttempts          int64
+	ExpectedIdentityProvisioned    bool
 
 	ExpectedError            error
 	ExpectedSetUsingOrgError error
@@ -277,6 +279,11 @@ func (m SQLStoreMock) GetUserTeamMemberships(ctx context.Context, orgID, userID
 	return nil, m.ExpectedError
 }
 
+func (m *SQLStoreMock) IsIdentityProvisioned(ctx context.Context, query *models.IsIdentityProvisionedQuery) error {
+	query.Result = m.ExpectedIdentityProvisioned
+	return m.ExpectedError
+}
+
 func (m SQLStoreMock) GetTeamMembers(ctx context.Context, query *models.GetTeamMembersQuery) error {
 	return m.ExpectedError
 }
@@ -412,6 +419,10 @@ func (m *SQLStoreMock) GetPlaylist(ctx context.Context, query *models.GetPlaylis
 	return m.ExpectedError
 }
 
+func (m *SQLStoreMock) GetPlaylistByUid(ctx context.Context, query *models.GetPlaylistByUidQuery) error {
+	return m.ExpectedError
+}
+
 func (m *SQLStoreMock) DeletePlaylist(ctx context.Context, cmd *models.DeletePlaylistCommand) error {
 	return m.ExpectedError
 }
@@ -420,6 +431,10 @@ func (m *SQLStoreMock) SearchPlaylists(ctx context.Context, query *models.GetPla
 	return m.ExpectedError
 }
 
+func (m *SQLStoreMock) GetProvisionedPlaylists(ctx context.Context, query *models.GetProvisionedPlaylistsQuery) error {
+	return m.ExpectedError
+}
+
 func (m *SQLStoreMock) GetPlaylistItem(ctx context.Context, query *models.GetPlaylistItemsByIdQuery) error {
 	return m.ExpectedError
 }
@@ -628,6 +643,10 @@ func (m *SQLStoreMock) GetApiKeyByName(ctx context.Context, query *models.GetApi
 	return m.ExpectedError
 }
 
+func (m *SQLStoreMock) UpdateAPIKeyLastUsed(ctx context.Context, id int64, lastUsedAt time.Time, ip string) error {
+	return m.ExpectedError
+}
+
 func (m *SQLStoreMock) UpdateTempUserStatus(ctx context.Context, cmd *models.UpdateTempUserStatusCommand) error {
 	return m.ExpectedError
 }
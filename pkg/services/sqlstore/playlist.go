@@ -4,14 +4,39 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func (ss *SQLStore) CreatePlaylist(ctx context.Context, cmd *models.CreatePlaylistCommand) error {
 	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
+		uid := cmd.UID
+		if uid == "" {
+			var err error
+			if uid, err = generateNewPlaylistUID(sess, cmd.OrgId); err != nil {
+				return err
+			}
+		} else {
+			if !util.IsValidShortUID(uid) {
+				return models.ErrPlaylistInvalidUID
+			}
+			if util.IsShortUIDTooLong(uid) {
+				return models.ErrPlaylistUIDTooLong
+			}
+			exists, err := sess.Where("org_id=? AND uid=?", cmd.OrgId, uid).Get(&models.Playlist{})
+			if err != nil {
+				return err
+			}
+			if exists {
+				return models.ErrPlaylistWithSameUIDExists
+			}
+		}
+
 		playlist := models.Playlist{
+			UID:      uid,
 			Name:     cmd.Name,
 			Interval: cmd.Interval,
 			OrgId:    cmd.OrgId,
+			ReadOnly: cmd.ReadOnly,
 		}
 
 		_, err := sess.Insert(&playlist)
@@ -30,9 +55,12 @@ func (ss *SQLStore) CreatePlaylist(ctx context.Context, cmd *models.CreatePlayli
 			})
 		}
 
-		_, err = sess.Insert(&playlistItems)
-
 		cmd.Result = &playlist
+		if len(playlistItems) == 0 {
+			return nil
+		}
+
+		_, err = sess.Insert(&playlistItems)
 		return err
 	})
 }
@@ -44,22 +72,28 @@ func (ss *SQLStore) UpdatePlaylist(ctx context.Context, cmd *models.UpdatePlayli
 			OrgId:    cmd.OrgId,
 			Name:     cmd.Name,
 			Interval: cmd.Interval,
+			ReadOnly: cmd.ReadOnly,
 		}
 
-		existingPlaylist := sess.Where("id = ? AND org_id = ?", cmd.Id, cmd.OrgId).Find(models.Playlist{})
-
-		if existingPlaylist == nil {
+		existingPlaylist := models.Playlist{}
+		exists, err := sess.Where("id = ? AND org_id = ?", cmd.Id, cmd.OrgId).Get(&existingPlaylist)
+		if err != nil {
+			return err
+		}
+		if !exists {
 			return models.ErrPlaylistNotFound
 		}
 
 		cmd.Result = &models.PlaylistDTO{
 			Id:       playlist.Id,
+			UID:      existingPlaylist.UID,
 			OrgId:    playlist.OrgId,
 			Name:     playlist.Name,
 			Interval: playlist.Interval,
+			ReadOnly: playlist.ReadOnly,
 		}
 
-		_, err := sess.ID(cmd.Id).Cols("name", "interval").Update(&playlist)
+		_, err = sess.ID(cmd.Id).Cols("name", "interval", "read_only").Update(&playlist)
 
 		if err != nil {
 			return err
@@ -84,6 +118,10 @@ func (ss *SQLStore) UpdatePlaylist(ctx context.Context, cmd *models.UpdatePlayli
 			})
 		}
 
+		if len(playlistItems) == 0 {
+			return nil
+		}
+
 		_, err = sess.Insert(&playlistItems)
 
 		return err
@@ -105,6 +143,26 @@ func (ss *SQLStore) GetPlaylist(ctx context.Context, query *models.GetPlaylistBy
 	})
 }
 
+func (ss *SQLStore) GetPlaylistByUid(ctx context.Context, query *models.GetPlaylistByUidQuery) error {
+	if query.UID == "" || query.OrgId == 0 {
+		return models.ErrCommandValidationFailed
+	}
+
+	return ss.WithDbSession(ctx, func(sess *DBSession) error {
+		playlist := models.Playlist{}
+		exists, err := sess.Where("org_id = ? AND uid = ?", query.OrgId, query.UID).Get(&playlist)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrPlaylistNotFound
+		}
+
+		query.Result = &playlist
+		return nil
+	})
+}
+
 func (ss *SQLStore) DeletePlaylist(ctx context.Context, cmd *models.DeletePlaylistCommand) error {
 	if cmd.Id == 0 || cmd.OrgId == 0 {
 		return models.ErrCommandValidationFailed
@@ -143,6 +201,15 @@ func (ss *SQLStore) SearchPlaylists(ctx context.Context, query *models.GetPlayli
 	})
 }
 
+func (ss *SQLStore) GetProvisionedPlaylists(ctx context.Context, query *models.GetProvisionedPlaylistsQuery) error {
+	return ss.WithDbSession(ctx, func(sess *DBSession) error {
+		playlists := make(models.Playlists, 0)
+		err := sess.Where("read_only = " + ss.Dialect.BooleanStr(true)).Find(&playlists)
+		query.Result = playlists
+		return err
+	})
+}
+
 func (ss *SQLStore) GetPlaylistItem(ctx context.Context, query *models.GetPlaylistItemsByIdQuery) error {
 	return ss.WithDbSession(ctx, func(sess *DBSession) error {
 		if query.PlaylistId == 0 {
@@ -157,3 +224,20 @@ func (ss *SQLStore) GetPlaylistItem(ctx context.Context, query *models.GetPlayli
 		return err
 	})
 }
+
+func generateNewPlaylistUID(sess *DBSession, orgID int64) (string, error) {
+	for i := 0; i < 3; i++ {
+		uid := util.GenerateShortUID()
+
+		exists, err := sess.Where("org_id=? AND uid=?", orgID, uid).Get(&models.Playlist{})
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return uid, nil
+		}
+	}
+
+	return "", models.ErrPlaylistFailedGenerateUniqueUID
+}
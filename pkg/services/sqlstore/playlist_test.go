@@ -22,6 +22,19 @@ func TestPlaylistDataAccess(t *testing.T) {
 		cmd := models.CreatePlaylistCommand{Name: "NYC office", Interval: "10m", OrgId: 1, Items: items}
 		err := ss.CreatePlaylist(context.Background(), &cmd)
 		require.NoError(t, err)
+		require.NotEmpty(t, cmd.Result.UID)
+
+		t.Run("Can get playlist by uid", func(t *testing.T) {
+			query := models.GetPlaylistByUidQuery{UID: cmd.Result.UID, OrgId: 1}
+			err := ss.GetPlaylistByUid(context.Background(), &query)
+			require.NoError(t, err)
+			require.Equal(t, cmd.Result.Id, query.Result.Id)
+			require.Equal(t, "NYC office", query.Result.Name)
+
+			query = models.GetPlaylistByUidQuery{UID: cmd.Result.UID, OrgId: 2}
+			err = ss.GetPlaylistByUid(context.Background(), &query)
+			require.ErrorIs(t, err, models.ErrPlaylistNotFound)
+		})
 
 		t.Run("Can update playlist", func(t *testing.T) {
 			items := []models.PlaylistItemDTO{
@@ -45,6 +58,43 @@ func TestPlaylistDataAccess(t *testing.T) {
 		})
 	})
 
+	t.Run("Can create playlist with uid", func(t *testing.T) {
+		cmd := models.CreatePlaylistCommand{UID: "noc-wall", Name: "NOC wall", Interval: "5m", OrgId: 1, ReadOnly: true}
+		err := ss.CreatePlaylist(context.Background(), &cmd)
+		require.NoError(t, err)
+
+		query := models.GetPlaylistByUidQuery{UID: "noc-wall", OrgId: 1}
+		err = ss.GetPlaylistByUid(context.Background(), &query)
+		require.NoError(t, err)
+		require.True(t, query.Result.ReadOnly)
+
+		t.Run("Can get provisioned playlists", func(t *testing.T) {
+			query := models.GetProvisionedPlaylistsQuery{}
+			err := ss.GetProvisionedPlaylists(context.Background(), &query)
+			require.NoError(t, err)
+			require.Len(t, query.Result, 1)
+			require.Equal(t, "noc-wall", query.Result[0].UID)
+		})
+
+		t.Run("Cannot create playlist with the same uid", func(t *testing.T) {
+			err := ss.CreatePlaylist(context.Background(), &models.CreatePlaylistCommand{UID: "noc-wall", Name: "other", OrgId: 1})
+			require.ErrorIs(t, err, models.ErrPlaylistWithSameUIDExists)
+
+			err = ss.CreatePlaylist(context.Background(), &models.CreatePlaylistCommand{UID: "noc-wall", Name: "other org", OrgId: 2})
+			require.NoError(t, err)
+		})
+
+		t.Run("Cannot create playlist with invalid uid", func(t *testing.T) {
+			err := ss.CreatePlaylist(context.Background(), &models.CreatePlaylistCommand{UID: "noc wall", Name: "other", OrgId: 1})
+			require.ErrorIs(t, err, models.ErrPlaylistInvalidUID)
+		})
+	})
+
+	t.Run("Update playlist that doesn't exist", func(t *testing.T) {
+		err := ss.UpdatePlaylist(context.Background(), &models.UpdatePlaylistCommand{Id: 1000, OrgId: 1, Name: "missing"})
+		require.ErrorIs(t, err, models.ErrPlaylistNotFound)
+	})
+
 	t.Run("Delete playlist that doesn't exist", func(t *testing.T) {
 		deleteQuery := models.DeletePlaylistCommand{Id: 1, OrgId: 1}
 		err := ss.DeletePlaylist(context.Background(), &deleteQuery)
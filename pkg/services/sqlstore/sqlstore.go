@@ -106,7 +106,11 @@ func newSQLStore(cfg *setting.Cfg, cacheService *localcache.CacheService, engine
 	dialect = ss.Dialect
 
 	// Init repo instances
-	annotations.SetRepository(&SQLAnnotationRepo{sql: ss})
+	annotationRepo, err := newAnnotationRepository(ss)
+	if err != nil {
+		return nil, err
+	}
+	annotations.SetRepository(annotationRepo)
 	annotations.SetAnnotationCleaner(&AnnotationCleanupService{batchSize: ss.Cfg.AnnotationCleanupJobBatchSize, log: log.New("annotationcleaner")})
 
 	// if err := ss.Reset(); err != nil {
@@ -137,6 +137,7 @@ func (ss *SQLStore) DeleteTeam(ctx context.Context, cmd *models.DeleteTeamComman
 			"DELETE FROM team WHERE org_id=? and id = ?",
 			"DELETE FROM dashboard_acl WHERE org_id=? and team_id = ?",
 			"DELETE FROM team_role WHERE org_id=? and team_id = ?",
+			"DELETE FROM identity_provisioning WHERE org_id=? and identity_id = ? and kind = '" + models.IdentityKindTeam + "'",
 		}
 
 		for _, sql := range deletes {
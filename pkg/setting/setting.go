@@ -241,6 +241,7 @@ type Cfg struct {
 	// Rendering
 	ImagesDir                      string
 	CSVsDir                        string
+	PDFsDir                        string
 	RendererUrl                    string
 	RendererCallbackUrl            string
 	RendererConcurrentRequestLimit int
@@ -331,6 +332,16 @@ type Cfg struct {
 	JWTAuthJWKSetFile    string
 	JWTAuthAutoSignUp    bool
 
+	// Service account token exchange
+	TokenExchangeEnabled      bool
+	TokenExchangeJWKSetURL    string
+	TokenExchangeJWKSetFile   string
+	TokenExchangeKeyFile      string
+	TokenExchangeCacheTTL     time.Duration
+	TokenExchangeExpectClaims string
+	TokenExchangeMappings     string
+	TokenExchangeTokenTTL     time.Duration
+
 	// Dataproxy
 	SendUserHeader                 bool
 	DataProxyLogging               bool
@@ -352,6 +363,10 @@ type Cfg struct {
 
 	ApiKeyMaxSecondsToLive int64
 
+	// Service account tokens
+	ServiceAccountTokenMaxUnused    time.Duration
+	ServiceAccountRevokeStaleTokens bool
+
 	// Check if a feature toggle is enabled
 	// @deprecated
 	IsFeatureToggleEnabled func(key string) bool // filled in dynamically
@@ -372,6 +387,7 @@ type Cfg struct {
 	AlertingAnnotationCleanupSetting   AnnotationCleanupSettings
 	DashboardAnnotationCleanupSettings AnnotationCleanupSettings
 	APIAnnotationCleanupSettings       AnnotationCleanupSettings
+	AnnotationStorage                  AnnotationStorageSettings
 
 	// Sentry config
 	Sentry Sentry
@@ -425,6 +441,15 @@ type Cfg struct {
 	// LiveAllowedOrigins is a set of origins accepted by Live. If not provided
 	// then Live uses AppURL as the only allowed origin.
 	LiveAllowedOrigins []string
+	// LiveHistoryMaxPoints is a maximum number of rows kept in the history of
+	// each managed stream channel. 0 disables history, only the last frame is kept.
+	LiveHistoryMaxPoints int
+	// LiveHistoryMaxAge is a maximum age of frames kept in the history of each
+	// managed stream channel. 0 means frames are kept regardless of their age.
+	LiveHistoryMaxAge time.Duration
+	// LivePipelineStorage is a type of storage for Live pipeline channel rules
+	// and write configs.
+	LivePipelineStorage string
 
 	// Grafana.com URL
 	GrafanaComURL string
@@ -438,6 +463,11 @@ type Cfg struct {
 
 	// Query history
 	QueryHistoryEnabled bool
+
+	// Scheduled reports
+	ReportsEnabled               bool
+	ReportsRenderingTimeout      time.Duration
+	ReportsConcurrentRenderLimit int
 }
 
 type CommandLineArgs struct {
@@ -600,6 +630,18 @@ func (cfg *Cfg) readAnnotationSettings() {
 	cfg.AlertingAnnotationCleanupSetting = newAnnotationCleanupSettings(alertingSection, "max_annotation_age")
 	cfg.DashboardAnnotationCleanupSettings = newAnnotationCleanupSettings(dashboardAnnotation, "max_age")
 	cfg.APIAnnotationCleanupSettings = newAnnotationCleanupSettings(apiIAnnotation, "max_age")
+
+	lokiSection := cfg.Raw.Section("annotations.loki")
+	cfg.AnnotationStorage = AnnotationStorageSettings{
+		Backend:           section.Key("backend").In(AnnotationBackendSQL, []string{AnnotationBackendSQL, AnnotationBackendLoki}),
+		LokiURL:           strings.TrimSuffix(lokiSection.Key("url").MustString(""), "/"),
+		LokiTenantID:      lokiSection.Key("tenant_id").MustString(""),
+		LokiBasicAuthUser: lokiSection.Key("basic_auth_user").MustString(""),
+		LokiBasicAuthPass: lokiSection.Key("basic_auth_password").MustString(""),
+		LokiTimeout:       lokiSection.Key("timeout").MustDuration(30 * time.Second),
+		LokiMaxLines:      lokiSection.Key("max_lines").MustInt(5000),
+		ReadSQL:           lokiSection.Key("read_sql").MustBool(true),
+	}
 }
 
 func (cfg *Cfg) readExpressionsSettings() {
@@ -607,6 +649,26 @@ func (cfg *Cfg) readExpressionsSettings() {
 	cfg.ExpressionsEnabled = expressions.Key("enabled").MustBool(true)
 }
 
+const (
+	AnnotationBackendSQL  = "sql"
+	AnnotationBackendLoki = "loki"
+)
+
+// AnnotationStorageSettings configures where the annotations are stored.
+type AnnotationStorageSettings struct {
+	// Backend is either AnnotationBackendSQL or AnnotationBackendLoki.
+	Backend string
+
+	LokiURL           string
+	LokiTenantID      string
+	LokiBasicAuthUser string
+	LokiBasicAuthPass string
+	LokiTimeout       time.Duration
+	LokiMaxLines      int
+	// ReadSQL keeps the annotations of the database visible when the annotations are written to Loki.
+	ReadSQL bool
+}
+
 type AnnotationCleanupSettings struct {
 	MaxAge   time.Duration
 	MaxCount int64
@@ -962,6 +1024,11 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	queryHistory := iniFile.Section("query_history")
 	cfg.QueryHistoryEnabled = queryHistory.Key("enabled").MustBool(false)
 
+	scheduledReports := iniFile.Section("scheduled_reports")
+	cfg.ReportsEnabled = scheduledReports.Key("enabled").MustBool(false)
+	cfg.ReportsRenderingTimeout = scheduledReports.Key("rendering_timeout").MustDuration(time.Minute)
+	cfg.ReportsConcurrentRenderLimit = scheduledReports.Key("concurrent_render_limit").MustInt(4)
+
 	panelsSection := iniFile.Section("panels")
 	cfg.DisableSanitizeHtml = panelsSection.Key("disable_sanitize_html").MustBool(false)
 
@@ -1260,6 +1327,12 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 
 	cfg.ApiKeyMaxSecondsToLive = auth.Key("api_key_max_seconds_to_live").MustInt64(-1)
 
+	cfg.ServiceAccountTokenMaxUnused, err = gtime.ParseDuration(valueAsString(auth, "service_account_token_max_unused", "0"))
+	if err != nil {
+		return err
+	}
+	cfg.ServiceAccountRevokeStaleTokens = auth.Key("service_account_revoke_stale_tokens").MustBool(false)
+
 	cfg.TokenRotationIntervalMinutes = auth.Key("token_rotation_interval_minutes").MustInt(10)
 	if cfg.TokenRotationIntervalMinutes < 2 {
 		cfg.TokenRotationIntervalMinutes = 2
@@ -1302,6 +1375,17 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	cfg.JWTAuthJWKSetFile = valueAsString(authJWT, "jwk_set_file", "")
 	cfg.JWTAuthAutoSignUp = authJWT.Key("auto_sign_up").MustBool(false)
 
+	// Service account token exchange
+	tokenExchange := iniFile.Section("auth.service_account_token_exchange")
+	cfg.TokenExchangeEnabled = tokenExchange.Key("enabled").MustBool(false)
+	cfg.TokenExchangeJWKSetURL = valueAsString(tokenExchange, "jwk_set_url", "")
+	cfg.TokenExchangeJWKSetFile = valueAsString(tokenExchange, "jwk_set_file", "")
+	cfg.TokenExchangeKeyFile = valueAsString(tokenExchange, "key_file", "")
+	cfg.TokenExchangeCacheTTL = tokenExchange.Key("cache_ttl").MustDuration(time.Minute * 60)
+	cfg.TokenExchangeExpectClaims = valueAsString(tokenExchange, "expect_claims", "{}")
+	cfg.TokenExchangeMappings = valueAsString(tokenExchange, "mappings", "[]")
+	cfg.TokenExchangeTokenTTL = tokenExchange.Key("token_ttl").MustDuration(time.Minute * 15)
+
 	authProxy := iniFile.Section("auth.proxy")
 	AuthProxyEnabled = authProxy.Key("enabled").MustBool(false)
 	cfg.AuthProxyEnabled = AuthProxyEnabled
@@ -1407,6 +1491,7 @@ func (cfg *Cfg) readRenderingSettings(iniFile *ini.File) error {
 	cfg.RendererConcurrentRequestLimit = renderSec.Key("concurrent_render_request_limit").MustInt(30)
 	cfg.ImagesDir = filepath.Join(cfg.DataPath, "png")
 	cfg.CSVsDir = filepath.Join(cfg.DataPath, "csv")
+	cfg.PDFsDir = filepath.Join(cfg.DataPath, "pdf")
 
 	return nil
 }
@@ -1550,6 +1635,20 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 		return fmt.Errorf("unsupported live HA engine type: %s", cfg.LiveHAEngine)
 	}
 	cfg.LiveHAEngineAddress = section.Key("ha_engine_address").MustString("127.0.0.1:6379")
+	cfg.LiveHistoryMaxPoints = section.Key("history_max_points").MustInt(1000)
+	if cfg.LiveHistoryMaxPoints < 0 {
+		return fmt.Errorf("unexpected value %d for [live] history_max_points", cfg.LiveHistoryMaxPoints)
+	}
+	cfg.LiveHistoryMaxAge = section.Key("history_max_age").MustDuration(5 * time.Minute)
+	if cfg.LiveHistoryMaxAge < 0 {
+		return fmt.Errorf("unexpected value %s for [live] history_max_age", cfg.LiveHistoryMaxAge)
+	}
+	cfg.LivePipelineStorage = section.Key("pipeline_storage").MustString("file")
+	switch cfg.LivePipelineStorage {
+	case "file", "database":
+	default:
+		return fmt.Errorf("unsupported live pipeline storage type: %s", cfg.LivePipelineStorage)
+	}
 
 	var originPatterns []string
 	allowedOrigins := section.Key("allowed_origins").MustString("")
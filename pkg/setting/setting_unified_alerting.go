@@ -21,6 +21,8 @@ const (
 	alertmanagerDefaultGossipInterval     = cluster.DefaultGossipInterval
 	alertmanagerDefaultPushPullInterval   = cluster.DefaultPushPullInterval
 	alertmanagerDefaultConfigPollInterval = 60 * time.Second
+	schedulerDefaultShardingHeartbeat     = 15 * time.Second
+	schedulerDefaultShardingMemberTimeout = time.Minute
 	// To start, the alertmanager needs at least one route defined.
 	// TODO: we should move this to Grafana settings and define this as the default.
 	alertmanagerDefaultConfiguration = `{
@@ -48,6 +50,7 @@ const (
 	schedulereDefaultExecuteAlerts          = true
 	schedulerDefaultMaxAttempts             = 3
 	schedulerDefaultLegacyMinInterval       = 1
+	stateHistoryDefaultRetention            = 30 * 24 * time.Hour
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -65,6 +68,9 @@ type UnifiedAlertingSettings struct {
 	HAPeerTimeout                  time.Duration
 	HAGossipInterval               time.Duration
 	HAPushPullInterval             time.Duration
+	HAShardedEvaluation            bool
+	HAShardingHeartbeatInterval    time.Duration
+	HAShardingMemberTimeout        time.Duration
 	MaxAttempts                    int64
 	MinInterval                    time.Duration
 	EvaluationTimeout              time.Duration
@@ -77,6 +83,8 @@ type UnifiedAlertingSettings struct {
 	BaseInterval time.Duration
 	// DefaultRuleEvaluationInterval default interval between evaluations of a rule.
 	DefaultRuleEvaluationInterval time.Duration
+	// StateHistoryRetention is for how long the state changes of alert instances are kept. Zero keeps them forever.
+	StateHistoryRetention time.Duration
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -180,6 +188,24 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		}
 	}
 
+	uaCfg.HAShardedEvaluation = ua.Key("ha_sharded_evaluation").MustBool(false)
+	uaCfg.HAShardingHeartbeatInterval, err = gtime.ParseDuration(valueAsString(ua, "ha_sharding_heartbeat_interval", schedulerDefaultShardingHeartbeat.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.HAShardingMemberTimeout, err = gtime.ParseDuration(valueAsString(ua, "ha_sharding_member_timeout", schedulerDefaultShardingMemberTimeout.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.HAShardedEvaluation {
+		if uaCfg.HAShardingHeartbeatInterval <= 0 {
+			return fmt.Errorf("value of setting 'ha_sharding_heartbeat_interval' should be greater than 0")
+		}
+		if uaCfg.HAShardingMemberTimeout <= uaCfg.HAShardingHeartbeatInterval {
+			return fmt.Errorf("value of setting 'ha_sharding_member_timeout' should be greater than 'ha_sharding_heartbeat_interval'")
+		}
+	}
+
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
 
@@ -244,6 +270,14 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		uaCfg.DefaultRuleEvaluationInterval = uaMinInterval
 	}
 
+	uaCfg.StateHistoryRetention, err = gtime.ParseDuration(valueAsString(ua, "state_history_retention", stateHistoryDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.StateHistoryRetention < 0 {
+		return fmt.Errorf("value of setting 'state_history_retention' should not be negative")
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
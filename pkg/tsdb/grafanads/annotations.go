@@ -0,0 +1,152 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+const (
+	annotationsModeList  = "list"
+	annotationsModeCount = "count"
+)
+
+func (s *Service) doAnnotationsQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &annotationsQueryModel{}
+	if err := json.Unmarshal(query.JSON, q); err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	user, err := s.getSignedInUser(ctx, req.PluginContext)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	itemQuery := annotations.ItemQuery{
+		OrgId:        req.PluginContext.OrgID,
+		From:         query.TimeRange.From.UnixNano() / int64(time.Millisecond),
+		To:           query.TimeRange.To.UnixNano() / int64(time.Millisecond),
+		DashboardId:  q.DashboardID,
+		PanelId:      q.PanelID,
+		Tags:         q.Tags,
+		MatchAny:     q.MatchAny,
+		Text:         q.Text,
+		Type:         q.Type,
+		Limit:        q.Limit,
+		SignedInUser: user,
+	}
+	for _, m := range q.Matchers {
+		matcher, err := annotations.ParseTagMatcher(m)
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+		itemQuery.TagMatchers = append(itemQuery.TagMatchers, matcher)
+	}
+
+	repo := annotations.GetRepository()
+	switch q.Mode {
+	case "", annotationsModeList:
+		items, err := repo.Find(ctx, &itemQuery)
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+		return backend.DataResponse{Frames: data.Frames{annotationsFrame(items)}}
+	case annotationsModeCount:
+		interval := query.Interval.Milliseconds()
+		if interval <= 0 && query.MaxDataPoints > 0 {
+			interval = (itemQuery.To - itemQuery.From) / query.MaxDataPoints
+		}
+		buckets, err := repo.Count(ctx, &annotations.CountQuery{ItemQuery: itemQuery, Interval: interval})
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+		return backend.DataResponse{Frames: data.Frames{countsFrame(buckets)}}
+	default:
+		return backend.DataResponse{Error: fmt.Errorf("unknown annotations query mode %q", q.Mode)}
+	}
+}
+
+// getSignedInUser returns the user of the request with the permissions to read the annotations.
+func (s *Service) getSignedInUser(ctx context.Context, pCtx backend.PluginContext) (*models.SignedInUser, error) {
+	if pCtx.User == nil {
+		return nil, errors.New("annotations can be queried only on behalf of a user")
+	}
+	query := &models.GetSignedInUserQuery{
+		Login: pCtx.User.Login,
+		Email: pCtx.User.Email,
+		OrgId: pCtx.OrgID,
+	}
+	if err := s.sql.GetSignedInUser(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	user := query.Result
+
+	if !s.ac.IsDisabled() {
+		// evaluating the permission loads the permissions of the user that are used to filter the annotations.
+		ok, err := s.ac.Evaluate(ctx, user, accesscontrol.EvalPermission(accesscontrol.ActionAnnotationsRead))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("user is not allowed to read annotations")
+		}
+	}
+	return user, nil
+}
+
+func annotationsFrame(items []*annotations.ItemDTO) *data.Frame {
+	ids := make([]int64, 0, len(items))
+	times := make([]time.Time, 0, len(items))
+	timeEnds := make([]time.Time, 0, len(items))
+	texts := make([]string, 0, len(items))
+	tags := make([]string, 0, len(items))
+	dashboardIDs := make([]int64, 0, len(items))
+	panelIDs := make([]int64, 0, len(items))
+	alertIDs := make([]int64, 0, len(items))
+	newStates := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.Id)
+		times = append(times, time.Unix(0, item.Time*int64(time.Millisecond)))
+		timeEnds = append(timeEnds, time.Unix(0, item.TimeEnd*int64(time.Millisecond)))
+		texts = append(texts, item.Text)
+		tags = append(tags, strings.Join(item.Tags, ","))
+		dashboardIDs = append(dashboardIDs, item.DashboardId)
+		panelIDs = append(panelIDs, item.PanelId)
+		alertIDs = append(alertIDs, item.AlertId)
+		newStates = append(newStates, item.NewState)
+	}
+	return data.NewFrame("annotations",
+		data.NewField("id", nil, ids),
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+		data.NewField("tags", nil, tags),
+		data.NewField("dashboardId", nil, dashboardIDs),
+		data.NewField("panelId", nil, panelIDs),
+		data.NewField("alertId", nil, alertIDs),
+		data.NewField("newState", nil, newStates),
+	)
+}
+
+func countsFrame(buckets []*annotations.Bucket) *data.Frame {
+	times := make([]time.Time, 0, len(buckets))
+	counts := make([]int64, 0, len(buckets))
+	for _, b := range buckets {
+		times = append(times, time.Unix(0, b.Time*int64(time.Millisecond)))
+		counts = append(counts, b.Count)
+	}
+	return data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("count", nil, counts),
+	)
+}
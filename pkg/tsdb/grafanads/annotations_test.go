@@ -0,0 +1,128 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func TestDoAnnotationsQuery(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := sqlstore.InitTestDB(t)
+	user, err := sqlStore.CreateUser(ctx, models.CreateUserCommand{Login: "viewer", Email: "viewer@test.com"})
+	require.NoError(t, err)
+
+	repo := annotations.GetRepository()
+	for _, item := range []*annotations.Item{
+		{OrgId: user.OrgId, Text: "deploy api", Epoch: 1000, EpochEnd: 1000, Tags: []string{"env:prod-eu", "team:api"}},
+		{OrgId: user.OrgId, Text: "deploy web", Epoch: 2000, EpochEnd: 2000, Tags: []string{"env:dev", "team:web"}},
+		{OrgId: user.OrgId, Text: "maintenance window", Epoch: 3000, EpochEnd: 5000, Tags: []string{"env:prod-us", "team:ops"}},
+	} {
+		require.NoError(t, repo.Save(item))
+	}
+
+	s := newService(nil, nil, nil, sqlStore, mock.New().WithDisabled())
+
+	testCases := []struct {
+		desc     string
+		model    annotationsQueryModel
+		expected []string
+		err      string
+	}{
+		{
+			desc:     "all the annotations in the time range",
+			model:    annotationsQueryModel{},
+			expected: []string{"maintenance window", "deploy web", "deploy api"},
+		},
+		{
+			desc:     "equality matcher",
+			model:    annotationsQueryModel{Matchers: []string{"team=web"}},
+			expected: []string{"deploy web"},
+		},
+		{
+			desc:     "regexp matcher",
+			model:    annotationsQueryModel{Matchers: []string{"env=~prod-.*"}},
+			expected: []string{"maintenance window", "deploy api"},
+		},
+		{
+			desc:     "negative matchers",
+			model:    annotationsQueryModel{Matchers: []string{"env!~prod-.*", "team!=api"}},
+			expected: []string{"deploy web"},
+		},
+		{
+			desc:     "matcher without matching tag",
+			model:    annotationsQueryModel{Matchers: []string{"team=db"}},
+			expected: []string{},
+		},
+		{
+			desc:  "invalid matcher",
+			model: annotationsQueryModel{Matchers: []string{"team"}},
+			err:   "invalid tag matcher",
+		},
+		{
+			desc:     "regions",
+			model:    annotationsQueryModel{Type: "region"},
+			expected: []string{"maintenance window"},
+		},
+		{
+			desc:     "text search",
+			model:    annotationsQueryModel{Text: "deploy"},
+			expected: []string{"deploy web", "deploy api"},
+		},
+		{
+			desc:     "text search with every word",
+			model:    annotationsQueryModel{Text: "api deploy"},
+			expected: []string{"deploy api"},
+		},
+		{
+			desc:     "text and matcher",
+			model:    annotationsQueryModel{Text: "deploy", Matchers: []string{"env=dev"}},
+			expected: []string{"deploy web"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			raw, err := json.Marshal(tc.model)
+			require.NoError(t, err)
+			req := &backend.QueryDataRequest{
+				PluginContext: backend.PluginContext{
+					OrgID: user.OrgId,
+					User:  &backend.User{Login: user.Login, Email: user.Email},
+				},
+			}
+			query := backend.DataQuery{
+				RefID:     "A",
+				QueryType: queryTypeAnnotations,
+				JSON:      raw,
+				TimeRange: backend.TimeRange{From: time.UnixMilli(500), To: time.UnixMilli(6000)},
+			}
+
+			resp := s.doAnnotationsQuery(ctx, req, query)
+			if tc.err != "" {
+				require.Error(t, resp.Error)
+				require.Contains(t, resp.Error.Error(), tc.err)
+				return
+			}
+			require.NoError(t, resp.Error)
+			require.Len(t, resp.Frames, 1)
+
+			texts := resp.Frames[0].Fields[3]
+			require.Equal(t, "text", texts.Name)
+			actual := make([]string, 0, texts.Len())
+			for i := 0; i < texts.Len(); i++ {
+				actual = append(actual, texts.At(i).(string))
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/searchV2"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/testdatasource"
@@ -37,14 +39,16 @@ var (
 	_ backend.CheckHealthHandler = (*Service)(nil)
 )
 
-func ProvideService(cfg *setting.Cfg, search searchV2.SearchService, store store.StorageService) *Service {
-	return newService(cfg, search, store)
+func ProvideService(cfg *setting.Cfg, search searchV2.SearchService, store store.StorageService, sql sqlstore.Store, ac accesscontrol.AccessControl) *Service {
+	return newService(cfg, search, store, sql, ac)
 }
 
-func newService(cfg *setting.Cfg, search searchV2.SearchService, store store.StorageService) *Service {
+func newService(cfg *setting.Cfg, search searchV2.SearchService, store store.StorageService, sql sqlstore.Store, ac accesscontrol.AccessControl) *Service {
 	s := &Service{
 		search: search,
 		store:  store,
+		sql:    sql,
+		ac:     ac,
 	}
 
 	return s
@@ -54,6 +58,8 @@ func newService(cfg *setting.Cfg, search searchV2.SearchService, store store.Sto
 type Service struct {
 	search searchV2.SearchService
 	store  store.StorageService
+	sql    sqlstore.Store
+	ac     accesscontrol.AccessControl
 }
 
 func DataSourceModel(orgId int64) *models.DataSource {
@@ -81,6 +87,8 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
 		case queryTypeSearch:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
+		case queryTypeAnnotations:
+			response.Responses[q.RefID] = s.doAnnotationsQuery(ctx, req, q)
 		default:
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
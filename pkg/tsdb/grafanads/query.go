@@ -14,6 +14,10 @@ const (
 	// currently only .csv files are supported,
 	// other file types will eventually be supported (parquet, etc)
 	queryTypeRead = "read"
+
+	// queryTypeAnnotations returns the annotations in the time range,
+	// or the number of annotations in each interval of the time range
+	queryTypeAnnotations = "annotations"
 )
 
 type listQueryModel struct {
@@ -22,3 +26,18 @@ type listQueryModel struct {
 type readQueryModel struct {
 	Path string `json:"path"`
 }
+
+type annotationsQueryModel struct {
+	DashboardID int64    `json:"dashboardId"`
+	PanelID     int64    `json:"panelId"`
+	Tags        []string `json:"tags"`
+	MatchAny    bool     `json:"matchAny"`
+	// Matchers are tag matchers of the form key=value, key!=value, key=~regexp or key!~regexp.
+	Matchers []string `json:"matchers"`
+	Text     string   `json:"text"`
+	// Type is "alert", "annotation" or "region".
+	Type  string `json:"type"`
+	Limit int64  `json:"limit"`
+	// Mode is "list" to return the annotations or "count" to return the number of annotations in each interval.
+	Mode string `json:"mode"`
+}
@@ -1,7 +1,13 @@
 import React, { ChangeEvent, FC } from 'react';
 import { SelectableValue } from '@grafana/data';
 import { InlineField, InlineFieldRow, Input, Select } from '@grafana/ui';
-import { downsamplingTypes, ExpressionQuery, upsamplingTypes } from '../types';
+import {
+  downsamplingTypes,
+  ExpressionQuery,
+  ResampleAlignment,
+  resampleAlignments,
+  upsamplingTypes,
+} from '../types';
 
 interface Props {
   refIds: Array<SelectableValue<string>>;
@@ -30,6 +36,14 @@ export const Resample: FC<Props> = ({ labelWidth, onChange, refIds, query }) =>
     onChange({ ...query, upsampler: value.value });
   };
 
+  const onMaxGapChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, maxGap: event.target.value });
+  };
+
+  const onSelectAlignment = (value: SelectableValue<ResampleAlignment>) => {
+    onChange({ ...query, alignment: value.value });
+  };
+
   return (
     <>
       <InlineFieldRow>
@@ -60,6 +74,24 @@ export const Resample: FC<Props> = ({ labelWidth, onChange, refIds, query }) =>
           />
         </InlineField>
       </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField
+          label="Max gap"
+          labelWidth={labelWidth}
+          tooltip="Longest gap between data points that upsampling fills, e.g. 5m. Leave empty for no limit"
+        >
+          <Input onChange={onMaxGapChange} value={query.maxGap} width={15} placeholder="none" />
+        </InlineField>
+        <InlineField label="Align to">
+          <Select
+            menuShouldPortal
+            options={resampleAlignments}
+            value={query.alignment ?? ResampleAlignment.From}
+            onChange={onSelectAlignment}
+            width={25}
+          />
+        </InlineField>
+      </InlineFieldRow>
     </>
   );
 };
@@ -0,0 +1,33 @@
+import { InlineField, TextArea } from '@grafana/ui';
+import { css } from '@emotion/css';
+import React, { ChangeEvent, FC } from 'react';
+import { ExpressionQuery } from '../types';
+
+interface Props {
+  labelWidth: number;
+  query: ExpressionQuery;
+  onChange: (query: ExpressionQuery) => void;
+}
+
+const sqlPlaceholder =
+  'SQL query over the results of other queries, you reference a query as a table by its refId ie. A, B, C etc\n' +
+  'Example: SELECT B.owner, max(A.value) AS value FROM A JOIN B ON A.host = B.host GROUP BY B.owner\n' +
+  'Time series and numbers have a "value" column, a "time" column for time series, and a column for each label';
+
+export const SqlExpr: FC<Props> = ({ labelWidth, onChange, query }) => {
+  const onExpressionChange = (event: ChangeEvent<HTMLTextAreaElement>) => {
+    onChange({ ...query, expression: event.target.value });
+  };
+
+  return (
+    <InlineField
+      label="Query"
+      labelWidth={labelWidth}
+      className={css`
+        align-items: baseline;
+      `}
+    >
+      <TextArea value={query.expression} onChange={onExpressionChange} rows={6} placeholder={sqlPlaceholder} />
+    </InlineField>
+  );
+};
@@ -0,0 +1,102 @@
+import React, { FC, FormEvent } from 'react';
+import { SelectableValue } from '@grafana/data';
+import { InlineField, InlineFieldRow, InlineSwitch, Input, Select } from '@grafana/ui';
+import { EvalFunction } from '../../alerting/state/alertDef';
+import { ExpressionQuery, thresholdFunctions, ThresholdEvaluator } from '../types';
+
+interface Props {
+  labelWidth: number;
+  refIds: Array<SelectableValue<string>>;
+  query: ExpressionQuery;
+  onChange: (query: ExpressionQuery) => void;
+}
+
+const isRange = (type: EvalFunction) => type === EvalFunction.IsWithinRange || type === EvalFunction.IsOutsideRange;
+
+export const Threshold: FC<Props> = ({ labelWidth, onChange, refIds, query }) => {
+  const evaluator = query.evaluator ?? { params: [0], type: EvalFunction.IsAbove };
+
+  const onRefIdChange = (value: SelectableValue<string>) => {
+    onChange({ ...query, expression: value.value });
+  };
+
+  const onRecoveryToggle = (event: FormEvent<HTMLInputElement>) => {
+    const recoveryEvaluator = event.currentTarget.checked ? { ...evaluator } : undefined;
+    onChange({ ...query, recoveryEvaluator });
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Input" labelWidth={labelWidth}>
+          <Select menuShouldPortal onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
+        </InlineField>
+        <ThresholdEvaluatorEditor
+          label="Fire when"
+          labelWidth={labelWidth}
+          evaluator={evaluator}
+          onChange={(e) => onChange({ ...query, evaluator: e })}
+        />
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField
+          label="Recovery"
+          labelWidth={labelWidth}
+          tooltip="Firing alerts only resolve once the recovery threshold is met"
+        >
+          <InlineSwitch value={!!query.recoveryEvaluator} onChange={onRecoveryToggle} />
+        </InlineField>
+        {query.recoveryEvaluator && (
+          <ThresholdEvaluatorEditor
+            label="Resolve when"
+            labelWidth={labelWidth}
+            evaluator={query.recoveryEvaluator}
+            onChange={(e) => onChange({ ...query, recoveryEvaluator: e })}
+          />
+        )}
+      </InlineFieldRow>
+    </>
+  );
+};
+
+interface EvaluatorProps {
+  label: string;
+  labelWidth: number;
+  evaluator: ThresholdEvaluator;
+  onChange: (evaluator: ThresholdEvaluator) => void;
+}
+
+const ThresholdEvaluatorEditor: FC<EvaluatorProps> = ({ label, labelWidth, evaluator, onChange }) => {
+  const onTypeChange = (value: SelectableValue<EvalFunction>) => {
+    const type = value.value!;
+    const params = isRange(type) ? [evaluator.params[0] ?? 0, evaluator.params[1] ?? 0] : [evaluator.params[0] ?? 0];
+    onChange({ type, params });
+  };
+
+  const onParamChange = (index: number) => (event: FormEvent<HTMLInputElement>) => {
+    const params = [...evaluator.params];
+    params[index] = event.currentTarget.valueAsNumber;
+    onChange({ ...evaluator, params });
+  };
+
+  return (
+    <>
+      <InlineField label={label} labelWidth={labelWidth}>
+        <Select
+          menuShouldPortal
+          options={thresholdFunctions}
+          value={evaluator.type}
+          onChange={onTypeChange}
+          width={20}
+        />
+      </InlineField>
+      <Input type="number" width={10} onChange={onParamChange(0)} value={evaluator.params[0]} />
+      {isRange(evaluator.type) && (
+        <>
+          <div className="gf-form-label">TO</div>
+          <Input type="number" width={10} onChange={onParamChange(1)} value={evaluator.params[1]} />
+        </>
+      )}
+    </>
+  );
+};
@@ -6,6 +6,8 @@ export enum ExpressionQueryType {
   reduce = 'reduce',
   resample = 'resample',
   classic = 'classic_conditions',
+  threshold = 'threshold',
+  sql = 'sql',
 }
 
 export const gelTypes: Array<SelectableValue<ExpressionQueryType>> = [
@@ -13,6 +15,8 @@ export const gelTypes: Array<SelectableValue<ExpressionQueryType>> = [
   { value: ExpressionQueryType.reduce, label: 'Reduce' },
   { value: ExpressionQueryType.resample, label: 'Resample' },
   { value: ExpressionQueryType.classic, label: 'Classic condition' },
+  { value: ExpressionQueryType.threshold, label: 'Threshold' },
+  { value: ExpressionQueryType.sql, label: 'SQL' },
 ];
 
 export const reducerTypes: Array<SelectableValue<string>> = [
@@ -22,6 +26,26 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: 'median', label: 'Median', description: 'Get the median value' },
+  { value: 'p90', label: '90th percentile', description: 'Get the 90th percentile' },
+  { value: 'p95', label: '95th percentile', description: 'Get the 95th percentile' },
+  { value: 'p99', label: '99th percentile', description: 'Get the 99th percentile' },
+  { value: 'stddev', label: 'Standard deviation', description: 'Get the population standard deviation' },
+  { value: 'variance', label: 'Variance', description: 'Get the population variance' },
+  { value: ReducerID.range, label: 'Range', description: 'Get the difference between the maximum and minimum values' },
+  { value: ReducerID.diff, label: 'Difference', description: 'Get the difference between the last and first values' },
+  {
+    value: 'percent_diff',
+    label: 'Percent difference',
+    description: 'Get the difference between the last and first values as a percentage of the first value',
+  },
+  { value: ReducerID.delta, label: 'Delta', description: 'Get the cumulative change, accounting for counter resets' },
+  {
+    value: 'count_non_null',
+    label: 'Count non-null',
+    description: 'Get the number of values that are not null or NaN',
+  },
 ];
 
 export enum ReducerMode {
@@ -48,19 +72,30 @@ export const reducerMode: Array<SelectableValue<ReducerMode>> = [
   },
 ];
 
-export const downsamplingTypes: Array<SelectableValue<string>> = [
-  { value: ReducerID.min, label: 'Min', description: 'Fill with the minimum value' },
-  { value: ReducerID.max, label: 'Max', description: 'Fill with the maximum value' },
-  { value: ReducerID.mean, label: 'Mean', description: 'Fill with the average value' },
-  { value: ReducerID.sum, label: 'Sum', description: 'Fill with the sum of all values' },
-];
+// Any reducer can be used to downsample the values within an interval
+export const downsamplingTypes: Array<SelectableValue<string>> = reducerTypes;
 
 export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'pad', label: 'pad', description: 'fill with the last known value' },
   { value: 'backfilling', label: 'backfilling', description: 'fill with the next known value' },
+  { value: 'linear', label: 'linear', description: 'interpolate between the last and the next known values' },
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
 ];
 
+export enum ResampleAlignment {
+  From = 'from',
+  Clock = 'clock',
+}
+
+export const resampleAlignments: Array<SelectableValue<ResampleAlignment>> = [
+  { value: ResampleAlignment.From, label: 'Time range', description: 'Samples start at the start of the time range' },
+  {
+    value: ResampleAlignment.Clock,
+    label: 'Clock',
+    description: 'Samples are aligned to multiples of the interval in UTC, e.g. on the hour',
+  },
+];
+
 /**
  * For now this is a single object to cover all the types.... would likely
  * want to split this up by type as the complexity increases
@@ -72,10 +107,26 @@ export interface ExpressionQuery extends DataQuery {
   window?: string;
   downsampler?: string;
   upsampler?: string;
+  maxGap?: string;
+  alignment?: ResampleAlignment;
   conditions?: ClassicCondition[];
   settings?: ExpressionQuerySettings;
+  evaluator?: ThresholdEvaluator;
+  recoveryEvaluator?: ThresholdEvaluator;
 }
 
+export interface ThresholdEvaluator {
+  params: number[];
+  type: EvalFunction;
+}
+
+export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
+  { value: EvalFunction.IsAbove, label: 'Is above' },
+  { value: EvalFunction.IsBelow, label: 'Is below' },
+  { value: EvalFunction.IsWithinRange, label: 'Is within range' },
+  { value: EvalFunction.IsOutsideRange, label: 'Is outside range' },
+];
+
 export interface ExpressionQuerySettings {
   mode?: ReducerMode;
   replaceWithValue?: number;
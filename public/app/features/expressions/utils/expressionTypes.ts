@@ -29,6 +29,13 @@ export const getDefaults = (query: ExpressionQuery) => {
       }
       break;
 
+    case ExpressionQueryType.threshold:
+      if (!query.evaluator) {
+        query.evaluator = { params: [0], type: EvalFunction.IsAbove };
+      }
+      query.reducer = undefined;
+      break;
+
     default:
       query.reducer = undefined;
   }
@@ -63,6 +63,21 @@ export interface MultipleFrameProcessorConfig {
 export interface KeepFieldsFrameProcessorConfig {
   fieldNames: string[];
 }
+export interface AggregateFrameProcessorConfig {
+  intervalMilliseconds: number;
+  function: string;
+}
+export interface LabelsFrameProcessorConfig {
+  labels: { [key: string]: string };
+  override?: boolean;
+}
+export interface ComputeFieldFrameProcessorConfig {
+  fieldName: string;
+  expression: string;
+}
+export interface RenameFieldsFrameProcessorConfig {
+  renames: { [key: string]: string };
+}
 export interface DropFieldsFrameProcessorConfig {
   fieldNames: string[];
 }
@@ -70,12 +85,22 @@ export interface FrameProcessorConfig {
   type: Omit<keyof FrameProcessorConfig, 'type'>;
   dropFields?: DropFieldsFrameProcessorConfig;
   keepFields?: KeepFieldsFrameProcessorConfig;
+  renameFields?: RenameFieldsFrameProcessorConfig;
+  computeField?: ComputeFieldFrameProcessorConfig;
+  labels?: LabelsFrameProcessorConfig;
+  aggregate?: AggregateFrameProcessorConfig;
   multiple?: MultipleFrameProcessorConfig;
 }
 export interface JsonFrameConverterConfig {}
 export interface AutoInfluxConverterConfig {
   frameFormat: string;
 }
+export interface AutoPrometheusConverterConfig {
+  frameFormat: string;
+}
+export interface AutoOTLPConverterConfig {
+  frameFormat: string;
+}
 export interface ExactJsonConverterConfig {
   fields: Field[];
 }
@@ -98,6 +123,8 @@ export interface ConverterConfig {
   jsonAuto?: AutoJsonConverterConfig;
   jsonExact?: ExactJsonConverterConfig;
   influxAuto?: AutoInfluxConverterConfig;
+  prometheusAuto?: AutoPrometheusConverterConfig;
+  otlpAuto?: AutoOTLPConverterConfig;
   jsonFrame?: JsonFrameConverterConfig;
 }
 export interface LokiOutputConfig {
@@ -128,5 +155,6 @@ export interface ChannelRuleSettings {
 }
 export interface ChannelRule {
   pattern: string;
+  version?: number;
   settings: ChannelRuleSettings;
 }
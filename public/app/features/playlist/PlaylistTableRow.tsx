@@ -33,7 +33,7 @@ export const PlaylistTableRow: FC<PlaylistTableRowProps> = ({ item, onDelete, on
 
   return (
     <tr aria-label={selectors.pages.PlaylistForm.itemRow} key={item.title}>
-      {item.type === 'dashboard_by_id' ? (
+      {item.type === 'dashboard_by_id' || item.type === 'dashboard_by_uid' ? (
         <td className={cx(styles.td, styles.item)}>
           <Icon name="apps" aria-label={selectors.pages.PlaylistForm.itemIdType} />
           <span>{item.title}</span>
@@ -21,8 +21,8 @@ export interface Playlist {
 
 export interface PlaylistItem {
   id?: number;
-  value: string; //tag or id.toString()
-  type: 'dashboard_by_id' | 'dashboard_by_tag';
+  value: string; //tag, uid or id.toString()
+  type: 'dashboard_by_id' | 'dashboard_by_uid' | 'dashboard_by_tag';
   order: number;
   title: string;
   playlistId?: number;
@@ -114,10 +114,17 @@ const ServiceAccountPageUnconnected = ({
           <h3 className="page-heading" style={{ marginBottom: '0px' }}>
             Tokens
           </h3>
-          <Button onClick={() => setIsModalOpen(true)}>Add token</Button>
+          <Button onClick={() => setIsModalOpen(true)} disabled={serviceAccount?.isProvisioned}>
+            Add token
+          </Button>
         </div>
         {tokens && (
-          <ServiceAccountTokensTable tokens={tokens} timeZone={timezone} onDelete={onDeleteServiceAccountToken} />
+          <ServiceAccountTokensTable
+            tokens={tokens}
+            timeZone={timezone}
+            onDelete={onDeleteServiceAccountToken}
+            readOnly={serviceAccount?.isProvisioned}
+          />
         )}
         <CreateTokenModal isOpen={isModalOpen} token={newToken} onCreateToken={onCreateToken} onClose={onModalClose} />
       </Page.Contents>
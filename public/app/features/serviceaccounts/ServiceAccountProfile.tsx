@@ -85,7 +85,7 @@ export function ServiceAccountProfile({
               <ServiceAccountProfileRow
                 label="Display Name"
                 value={serviceAccount.name}
-                onChange={onServiceAccountNameChange}
+                onChange={serviceAccount.isProvisioned ? undefined : onServiceAccountNameChange}
               />
               <ServiceAccountProfileRow label="ID" value={serviceAccount.login} />
               <ServiceAccountRoleRow
@@ -103,50 +103,54 @@ export function ServiceAccountProfile({
             </tbody>
           </table>
         </div>
-        <div className={styles.buttonRow}>
-          <>
-            <Button
-              type={'button'}
-              variant="destructive"
-              onClick={showDeleteServiceAccountModal(true)}
-              ref={deleteServiceAccountRef}
-            >
-              Delete service account
-            </Button>
-            <ConfirmModal
-              isOpen={showDeleteModal}
-              title="Delete service account"
-              body="Are you sure you want to delete this service account?"
-              confirmText="Delete service account"
-              onConfirm={handleServiceAccountDelete}
-              onDismiss={showDeleteServiceAccountModal(false)}
-            />
-          </>
-          {serviceAccount.isDisabled ? (
-            <Button type={'button'} variant="secondary" onClick={handleServiceAccountEnable}>
-              Enable service account
-            </Button>
-          ) : (
+        {serviceAccount.isProvisioned ? (
+          <p>This service account is provisioned and cannot be changed in the UI.</p>
+        ) : (
+          <div className={styles.buttonRow}>
             <>
               <Button
                 type={'button'}
-                variant="secondary"
-                onClick={showDisableServiceAccountModal(true)}
-                ref={disableServiceAccountRef}
+                variant="destructive"
+                onClick={showDeleteServiceAccountModal(true)}
+                ref={deleteServiceAccountRef}
               >
-                Disable service account
+                Delete service account
               </Button>
               <ConfirmModal
-                isOpen={showDisableModal}
-                title="Disable service account"
-                body="Are you sure you want to disable this service account?"
-                confirmText="Disable service account"
-                onConfirm={handleServiceAccountDisable}
-                onDismiss={showDisableServiceAccountModal(false)}
+                isOpen={showDeleteModal}
+                title="Delete service account"
+                body="Are you sure you want to delete this service account?"
+                confirmText="Delete service account"
+                onConfirm={handleServiceAccountDelete}
+                onDismiss={showDeleteServiceAccountModal(false)}
               />
             </>
-          )}
-        </div>
+            {serviceAccount.isDisabled ? (
+              <Button type={'button'} variant="secondary" onClick={handleServiceAccountEnable}>
+                Enable service account
+              </Button>
+            ) : (
+              <>
+                <Button
+                  type={'button'}
+                  variant="secondary"
+                  onClick={showDisableServiceAccountModal(true)}
+                  ref={disableServiceAccountRef}
+                >
+                  Disable service account
+                </Button>
+                <ConfirmModal
+                  isOpen={showDisableModal}
+                  title="Disable service account"
+                  body="Are you sure you want to disable this service account?"
+                  confirmText="Disable service account"
+                  onConfirm={handleServiceAccountDisable}
+                  onDismiss={showDisableServiceAccountModal(false)}
+                />
+              </>
+            )}
+          </div>
+        )}
       </div>
     </>
   );
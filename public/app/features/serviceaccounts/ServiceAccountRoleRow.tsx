@@ -16,7 +16,9 @@ interface Props {
 export class ServiceAccountRoleRow extends PureComponent<Props> {
   render() {
     const { label, serviceAccount, roleOptions, builtInRoles, onRoleChange } = this.props;
-    const canUpdateRole = contextSrv.hasPermissionInMetadata(AccessControlAction.ServiceAccountsWrite, serviceAccount);
+    const canUpdateRole =
+      contextSrv.hasPermissionInMetadata(AccessControlAction.ServiceAccountsWrite, serviceAccount) &&
+      !serviceAccount.isProvisioned;
     const rolePickerDisabled = !canUpdateRole;
     const labelClass = cx(
       'width-16',
@@ -9,9 +9,10 @@ interface Props {
   tokens: ApiKey[];
   timeZone: TimeZone;
   onDelete: (token: ApiKey) => void;
+  readOnly?: boolean;
 }
 
-export const ServiceAccountTokensTable: FC<Props> = ({ tokens, timeZone, onDelete }) => {
+export const ServiceAccountTokensTable: FC<Props> = ({ tokens, timeZone, onDelete, readOnly }) => {
   const theme = useTheme2();
   const styles = getStyles(theme);
 
@@ -36,7 +37,12 @@ export const ServiceAccountTokensTable: FC<Props> = ({ tokens, timeZone, onDelet
                 </td>
                 <td>{formatDate(timeZone, key.created)}</td>
                 <td>
-                  <DeleteButton aria-label="Delete API key" size="sm" onConfirm={() => onDelete(key)} />
+                  <DeleteButton
+                    aria-label="Delete API key"
+                    size="sm"
+                    onConfirm={() => onDelete(key)}
+                    disabled={readOnly}
+                  />
                 </td>
               </tr>
             );
@@ -33,6 +33,7 @@ const connector = connect(mapStateToProps, mapDispatchToProps);
 interface OwnProps {
   members: TeamMember[];
   syncEnabled: boolean;
+  isProvisioned?: boolean;
 }
 
 export type Props = ConnectedProps<typeof connector> & OwnProps;
@@ -81,8 +82,9 @@ export class TeamMembers extends PureComponent<Props, State> {
 
   render() {
     const { isAdding } = this.state;
-    const { searchMemberQuery, members, syncEnabled, editorsCanAdmin, signedInUser } = this.props;
-    const isTeamAdmin = isSignedInUserTeamAdmin({ members, editorsCanAdmin, signedInUser });
+    const { searchMemberQuery, members, syncEnabled, editorsCanAdmin, signedInUser, isProvisioned } = this.props;
+    // the members of provisioned teams can only be changed by provisioning
+    const isTeamAdmin = isSignedInUserTeamAdmin({ members, editorsCanAdmin, signedInUser }) && !isProvisioned;
 
     return (
       <div>
@@ -165,7 +165,7 @@ export class TeamPages extends PureComponent<Props, State> {
         if (contextSrv.accessControlEnabled()) {
           return <TeamPermissions team={team!} />;
         } else {
-          return <TeamMembers syncEnabled={isSyncEnabled} members={members} />;
+          return <TeamMembers syncEnabled={isSyncEnabled} members={members} isProvisioned={team!.isProvisioned} />;
         }
       case PageTypes.Settings:
         return canReadTeam && <TeamSettings team={team!} />;
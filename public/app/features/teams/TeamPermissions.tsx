@@ -10,10 +10,9 @@ type TeamPermissionsProps = {
 // TeamPermissions component replaces TeamMembers component when the accesscontrol feature flag is set
 const TeamPermissions = (props: TeamPermissionsProps) => {
   const canListUsers = contextSrv.hasPermission(AccessControlAction.OrgUsersRead);
-  const canSetPermissions = contextSrv.hasPermissionInMetadata(
-    AccessControlAction.ActionTeamsPermissionsWrite,
-    props.team
-  );
+  const canSetPermissions =
+    contextSrv.hasPermissionInMetadata(AccessControlAction.ActionTeamsPermissionsWrite, props.team) &&
+    !props.team.isProvisioned;
 
   return (
     <Permissions
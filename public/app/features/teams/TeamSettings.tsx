@@ -20,6 +20,8 @@ export type Props = ConnectedProps<typeof connector> & OwnProps;
 
 export const TeamSettings: FC<Props> = ({ team, updateTeam }) => {
   const canWriteTeamSettings = contextSrv.hasPermissionInMetadata(AccessControlAction.ActionTeamsWrite, team);
+  // provisioned teams can't be renamed, their preferences can still be changed
+  const canUpdateTeam = canWriteTeamSettings && !team.isProvisioned;
 
   return (
     <VerticalGroup>
@@ -29,22 +31,22 @@ export const TeamSettings: FC<Props> = ({ team, updateTeam }) => {
           onSubmit={(formTeam: Team) => {
             updateTeam(formTeam.name, formTeam.email);
           }}
-          disabled={!canWriteTeamSettings}
+          disabled={!canUpdateTeam}
         >
           {({ register }) => (
             <>
-              <Field label="Name" disabled={!canWriteTeamSettings}>
+              <Field label="Name" disabled={!canUpdateTeam}>
                 <Input {...register('name', { required: true })} id="name-input" />
               </Field>
 
               <Field
                 label="Email"
                 description="This is optional and is primarily used to set the team profile avatar (via gravatar service)."
-                disabled={!canWriteTeamSettings}
+                disabled={!canUpdateTeam}
               >
                 <Input {...register('email')} placeholder="team@email.com" type="email" id="email-input" />
               </Field>
-              <Button type="submit" disabled={!canWriteTeamSettings}>
+              <Button type="submit" disabled={!canUpdateTeam}>
                 Update
               </Button>
             </>
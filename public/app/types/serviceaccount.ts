@@ -35,6 +35,7 @@ export interface ServiceAccountDTO extends WithAccessControlMetadata {
   isDisabled: boolean;
   teams: string[];
   role: OrgRole;
+  isProvisioned?: boolean;
 }
 
 export interface ServiceAccountProfileState {
@@ -8,6 +8,7 @@ export interface Team extends WithAccessControlMetadata {
   email: string;
   memberCount: number;
   permission: TeamPermissionLevel;
+  isProvisioned?: boolean;
 }
 
 export interface TeamMember {